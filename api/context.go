@@ -2,6 +2,7 @@ package api
 
 import (
 	"net/http"
+	"slices"
 
 	"github.com/pdridh/k-line/db/sqlc"
 )
@@ -11,8 +12,12 @@ type contextKey string
 const ContextUserKey contextKey = "user"
 
 type CurrentUser struct {
-	ID   string
-	Type sqlc.UserType
+	ID          string
+	Type        sqlc.UserType
+	RoleID      int32
+	Superuser   bool
+	Permissions []string
+	Bearer      bool // Authenticated with an Authorization header instead of the jwt cookie
 }
 
 // Given a request extracts the value of the userID (string) from the context using the ContextUserKey
@@ -24,3 +29,10 @@ func CurrentUserID(r *http.Request) string {
 func CurrentUserType(r *http.Request) sqlc.UserType {
 	return r.Context().Value(ContextUserKey).(CurrentUser).Type
 }
+
+// Given a request reports whether the CurrentUser in the context has the permission.
+// Superusers have every permission.
+func CurrentUserCan(r *http.Request, permission string) bool {
+	u := r.Context().Value(ContextUserKey).(CurrentUser)
+	return u.Superuser || slices.Contains(u.Permissions, permission)
+}
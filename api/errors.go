@@ -33,16 +33,101 @@ var (
 	ErrUnexpectedJWTSigningMethod = NewError("ERR_AUTH_UNEXPECTED_JWT_SIGN_METHOD", "unexpected signing method")
 	ErrEmailAlreadyExists         = NewError("ERR_AUTH_EMAIL_CONFLICT", "email conflict")
 	ErrJWTInvalid                 = NewError("ERR_JWT_INVALID", "invalid jwt")
+	ErrCSRFInvalid                = NewError("ERR_AUTH_CSRF_INVALID", "missing or invalid csrf token")
+	ErrAPIKeyInvalid              = NewError("ERR_AUTH_APIKEY_INVALID", "invalid api key")
+	ErrUnknownAPIKey              = NewError("ERR_APIKEY_UNKNOWN", "api key does not exist")
 	ErrLoginInvalid               = NewError("ERR_LOGIN_INVALIDCREDS", "invalid credentials")
 	ErrRegistrationFailed         = NewError("ERR_REGISTER_FAILED", "registration failed")
+	ErrTooManyRequests            = NewError("ERR_HTTP_TOO_MANY_REQUESTS", "too many requests, try again later")
+	ErrLoginLocked                = NewError("ERR_AUTH_LOGIN_LOCKED", "too many failed logins, account is temporarily locked")
+	ErrBootstrapClosed            = NewError("ERR_AUTH_BOOTSTRAP_CLOSED", "an admin account already exists")
+	ErrUserInactive               = NewError("ERR_AUTH_USER_INACTIVE", "account is deactivated")
+	ErrUnknownUser                = NewError("ERR_USER_UNKNOWN", "user does not exist")
+	ErrDeviceUntrusted            = NewError("ERR_AUTH_DEVICE_UNTRUSTED", "this device is not registered for pin login")
+	ErrPINInvalid                 = NewError("ERR_AUTH_PIN_INVALID", "invalid pin")
+	ErrPINLocked                  = NewError("ERR_AUTH_PIN_LOCKED", "too many failed attempts, pin login is locked")
+	ErrUnknownDevice              = NewError("ERR_DEVICE_UNKNOWN", "device does not exist")
+	ErrUnknownRole                = NewError("ERR_ROLE_UNKNOWN", "role does not exist")
+	ErrRoleNameConflict           = NewError("ERR_ROLE_NAME_CONFLICT", "role with the same name already exists")
+	ErrRoleProtected              = NewError("ERR_ROLE_PROTECTED", "superuser roles cannot be changed")
+	ErrRoleInUse                  = NewError("ERR_ROLE_IN_USE", "role is still assigned to users")
+	ErrUnknownPermission          = NewError("ERR_ROLE_UNKNOWN_PERMISSION", "unknown permission")
+	ErrCannotModifySelf           = NewError("ERR_USER_MODIFY_SELF", "you cannot change the role or status of your own account")
+	ErrRoleNotAssignable          = NewError("ERR_ROLE_NOT_ASSIGNABLE", "you cannot assign the admin type or a role with permissions you dont have")
+	ErrUserProtected              = NewError("ERR_USER_PROTECTED", "only superusers can change superuser accounts")
+	ErrPermissionNotGranted       = NewError("ERR_PERMISSION_NOT_GRANTED", "you cannot grant permissions you dont have")
+	ErrAlreadyClockedIn           = NewError("ERR_SHIFT_ALREADY_CLOCKED_IN", "you are already clocked in")
+	ErrNotClockedIn               = NewError("ERR_SHIFT_NOT_CLOCKED_IN", "you are not clocked in")
+	ErrAlreadyOnBreak             = NewError("ERR_SHIFT_ALREADY_ON_BREAK", "you are already on a break")
+	ErrNotOnBreak                 = NewError("ERR_SHIFT_NOT_ON_BREAK", "you are not on a break")
+	ErrUnknownShift               = NewError("ERR_SHIFT_UNKNOWN", "shift does not exist")
+	ErrInvalidPeriod              = NewError("ERR_INVALID_PERIOD", "from and to must be dates (YYYY-MM-DD) and from cannot be after to")
+	ErrDrawerAlreadyOpen          = NewError("ERR_DRAWER_ALREADY_OPEN", "this drawer already has an open session")
+	ErrUnknownDrawerSession       = NewError("ERR_DRAWER_SESSION_UNKNOWN", "drawer session does not exist")
+	ErrDrawerSessionClosed        = NewError("ERR_DRAWER_SESSION_CLOSED", "drawer session is already closed")
+	ErrDrawersStillOpen           = NewError("ERR_ZREPORT_DRAWERS_OPEN", "every drawer session has to be closed first")
+	ErrZReportConflict            = NewError("ERR_ZREPORT_CONFLICT", "a z report was already closed for this period")
+	ErrUnknownZReport             = NewError("ERR_ZREPORT_UNKNOWN", "z report does not exist")
+	ErrInvalidCursor              = NewError("ERR_INVALID_CURSOR", "cursor is not one returned by this list")
 	ErrInvalidUUID                = NewError("ERR_INVALID_UUID", "invalid uuid")
 	ErrUnknownTable               = NewError("ERR_DINING_UNKNOWNTABLE", "table does not exist")
 	ErrTableNotAvaliable          = NewError("ERR_DINING_TABLE_UNAVAILABLE", "table is not available")
 	ErrUnknownOrder               = NewError("ERR_ORDER_UNKNOWN", "order does not exist")
 	ErrOrderNotOngoing            = NewError("ERR_ORDER_NOTONGOING", "order is not ongoing")
+	ErrInvalidDriver              = NewError("ERR_ORDER_DRIVER_INVALID", "driver does not exist or is not an active driver")
 	ErrUnknownOrderItem           = NewError("ERR_ORDER_UNKNOWNITEM", "order item does not exist")
 	ErrItemNameConflict           = NewError("ERR_MENU_ITEMNAME_CONFLICT", "menu item with the same name already exists")
 	ErrUnkownMenuItem             = NewError("ERR_MENU_ITEM_UNKOWN", "menu item with this id doesnt exist")
+	ErrItemEightySixed            = NewError("ERR_MENU_ITEM_86", "menu item is out of stock")
+	ErrItemNotAvailable           = NewError("ERR_MENU_ITEM_UNAVAILABLE", "menu item is not on a menu being served right now")
+	ErrImageTooLarge              = NewError("ERR_MENU_IMAGE_TOO_LARGE", "image cannot be bigger than 10MB or 40 megapixels")
+	ErrUnsupportedImage           = NewError("ERR_MENU_IMAGE_UNSUPPORTED", "image has to be a jpeg, png or gif")
+	ErrUnsupportedLanguage        = NewError("ERR_MENU_LANGUAGE_UNSUPPORTED", "menu cannot be translated to this language")
+	ErrUnknownTranslation         = NewError("ERR_MENU_TRANSLATION_UNKNOWN", "item has no translation in this language")
+	ErrUnknownMenu                = NewError("ERR_MENU_UNKNOWN", "menu does not exist")
+	ErrMenuNameConflict           = NewError("ERR_MENU_NAME_CONFLICT", "menu with the same name already exists")
+	ErrUnknownMenuSchedule        = NewError("ERR_MENU_SCHEDULE_UNKNOWN", "menu schedule does not exist")
+	ErrInvalidMenuSchedule        = NewError("ERR_MENU_SCHEDULE_INVALID", "times must be HH:MM and start_date cannot be after end_date")
+	ErrUnknownCombo               = NewError("ERR_MENU_COMBO_UNKNOWN", "combo does not exist")
+	ErrComboNameConflict          = NewError("ERR_MENU_COMBO_CONFLICT", "combo with the same name already exists")
+	ErrComboNotActive             = NewError("ERR_MENU_COMBO_INACTIVE", "combo is not being sold right now")
+	ErrInvalidComboChoice         = NewError("ERR_MENU_COMBO_CHOICE", "every slot of the combo needs exactly one of its options picked")
+	ErrAllergenConflict           = NewError("ERR_ORDER_ALLERGEN_CONFLICT", "items contain allergens the guests declared")
+	ErrUnknownIngredient          = NewError("ERR_INVENTORY_INGREDIENT_UNKNOWN", "ingredient does not exist")
+	ErrIngredientNameConflict     = NewError("ERR_INVENTORY_INGREDIENT_CONFLICT", "ingredient with the same name already exists")
+	ErrUnknownSupplier            = NewError("ERR_PURCHASING_SUPPLIER_UNKNOWN", "supplier does not exist")
+	ErrSupplierNameConflict       = NewError("ERR_PURCHASING_SUPPLIER_CONFLICT", "supplier with the same name already exists")
+	ErrUnknownPurchaseOrder       = NewError("ERR_PURCHASING_ORDER_UNKNOWN", "purchase order does not exist")
+	ErrUnknownPurchaseOrderItem   = NewError("ERR_PURCHASING_ORDER_ITEM_UNKNOWN", "item is not on this purchase order")
+	ErrPurchaseOrderStatus        = NewError("ERR_PURCHASING_ORDER_STATUS", "purchase order cannot do this in its current status")
+	ErrReceiveExceedsOrdered      = NewError("ERR_PURCHASING_RECEIVE_EXCEEDS", "cannot receive more than was ordered")
+	ErrUnknownCustomer            = NewError("ERR_CUSTOMER_UNKNOWN", "customer does not exist")
+	ErrCustomerPhoneConflict      = NewError("ERR_CUSTOMER_PHONE_CONFLICT", "phone number already belongs to another customer")
+	ErrInvalidPhone               = NewError("ERR_CUSTOMER_PHONE_INVALID", "phone number needs at least 3 digits")
+	ErrUnknownLoyaltyTier         = NewError("ERR_LOYALTY_TIER_UNKNOWN", "loyalty tier does not exist")
+	ErrLoyaltyTierConflict        = NewError("ERR_LOYALTY_TIER_CONFLICT", "loyalty tier with the same name or minimum points already exists")
+	ErrInsufficientPoints         = NewError("ERR_LOYALTY_INSUFFICIENT_POINTS", "customer does not have enough points")
+	ErrOrderNoCustomer            = NewError("ERR_ORDER_NO_CUSTOMER", "order is not linked to a customer")
+	ErrDiscountExceedsBill        = NewError("ERR_ORDER_DISCOUNT_EXCEEDS_BILL", "discount is more than what is left to pay on the order")
+	ErrTenderExceedsBill          = NewError("ERR_ORDER_TENDER_EXCEEDS_BILL", "amount is more than what is left to pay on the order")
+	ErrUnknownGiftCard            = NewError("ERR_GIFTCARD_UNKNOWN", "gift card does not exist")
+	ErrGiftCardExpired            = NewError("ERR_GIFTCARD_EXPIRED", "gift card has expired")
+	ErrGiftCardBalance            = NewError("ERR_GIFTCARD_INSUFFICIENT_BALANCE", "gift card balance is lower than the amount")
+	ErrGiftCardCodeConflict       = NewError("ERR_GIFTCARD_CODE_CONFLICT", "gift card with this code already exists")
+	ErrInvalidGiftCardCode        = NewError("ERR_GIFTCARD_CODE_INVALID", "gift card codes are 6 to 32 letters and digits")
+	ErrUnknownPromotion           = NewError("ERR_PROMOTION_UNKNOWN", "promotion does not exist")
+	ErrPromotionCodeConflict      = NewError("ERR_PROMOTION_CODE_CONFLICT", "promotion with this code already exists")
+	ErrInvalidPromotion           = NewError("ERR_PROMOTION_INVALID", "promotion rules are invalid")
+	ErrPromotionInactive          = NewError("ERR_PROMOTION_INACTIVE", "promotion is not active")
+	ErrPromotionCodeRequired      = NewError("ERR_PROMOTION_CODE_REQUIRED", "promotion can only be applied with its code")
+	ErrPromotionNotOn             = NewError("ERR_PROMOTION_NOT_ON", "promotion is not on at this time")
+	ErrPromotionOrderType         = NewError("ERR_PROMOTION_ORDER_TYPE", "promotion does not apply to this type of order")
+	ErrPromotionMinSpend          = NewError("ERR_PROMOTION_MIN_SPEND", "order is below the minimum spend of the promotion")
+	ErrPromotionItems             = NewError("ERR_PROMOTION_ITEMS", "order does not have the items the promotion needs")
+	ErrPromotionUsedUp            = NewError("ERR_PROMOTION_USED_UP", "promotion has reached its usage limit")
+	ErrPromotionCustomerLimit     = NewError("ERR_PROMOTION_CUSTOMER_LIMIT", "customer has used the promotion as many times as allowed")
+	ErrPromotionApplied           = NewError("ERR_PROMOTION_APPLIED", "promotion is already applied to the order")
+	ErrPromotionStacked           = NewError("ERR_PROMOTION_STACKED", "order already has a promotion, only one can be applied")
 )
 
 type ErrorResponse struct {
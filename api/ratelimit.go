@@ -0,0 +1,62 @@
+package api
+
+import (
+	"sync"
+	"time"
+)
+
+// A fixed window rate limiter keyed by any string (ip, email...).
+// It only lives in memory so every server instance counts on its own.
+type RateLimiter struct {
+	mu          sync.Mutex
+	limit       int
+	window      time.Duration
+	windows     map[string]*rateWindow
+	lastCleanup time.Time
+}
+
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
+	return &RateLimiter{
+		limit:       limit,
+		window:      window,
+		windows:     make(map[string]*rateWindow),
+		lastCleanup: time.Now(),
+	}
+}
+
+// Counts a hit for the key and reports whether it is still within the limit.
+// When it isnt, the time left until the window resets is returned as well.
+func (l *RateLimiter) Allow(key string) (bool, time.Duration) {
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Drop expired windows every now and then so the map doesnt grow forever
+	if now.Sub(l.lastCleanup) > l.window {
+		for k, w := range l.windows {
+			if now.Sub(w.start) >= l.window {
+				delete(l.windows, k)
+			}
+		}
+		l.lastCleanup = now
+	}
+
+	w, ok := l.windows[key]
+	if !ok || now.Sub(w.start) >= l.window {
+		w = &rateWindow{start: now}
+		l.windows[key] = w
+	}
+
+	w.count++
+	if w.count > l.limit {
+		return false, l.window - now.Sub(w.start)
+	}
+
+	return true, 0
+}
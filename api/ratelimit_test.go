@@ -0,0 +1,55 @@
+package api
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	l := NewRateLimiter(3, 50*time.Millisecond)
+
+	for n := range 5 {
+		ok, retryAfter := l.Allow("a")
+		if want := n < 3; ok != want {
+			t.Fatalf("hit %d allowed %v, want %v", n+1, ok, want)
+		}
+		if !ok && (retryAfter <= 0 || retryAfter > 50*time.Millisecond) {
+			t.Errorf("got retry after %v, want within the window", retryAfter)
+		}
+	}
+
+	if ok, _ := l.Allow("b"); !ok {
+		t.Error("other key limited, want it counted on its own")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if ok, _ := l.Allow("a"); !ok {
+		t.Error("still limited after the window, want a new window")
+	}
+}
+
+func TestRateLimiterConcurrent(t *testing.T) {
+	l := NewRateLimiter(10, time.Minute)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	allowed := 0
+	for n := range 100 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if ok, _ := l.Allow(fmt.Sprintf("key %d", n%2)); ok {
+				mu.Lock()
+				allowed++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if allowed != 20 {
+		t.Errorf("got %d allowed, want 10 for each of the 2 keys", allowed)
+	}
+}
@@ -1,14 +1,25 @@
 package api
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"io"
+	"net"
 	"net/http"
 	"net/url"
 	"reflect"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pdridh/k-line/config"
+	"github.com/pkg/errors"
 )
 
+// Dates in query params (like from/to of a report) are sent as plain days
+const DateLayout = "2006-01-02"
+
 // Read a request body and parse it.
 // The parsed json is loaded into v unless an error occurs
 func ParseJSON(r *http.Request, v any) error {
@@ -57,8 +68,138 @@ func ParseQueryParams(q url.Values, params any) {
 			if err == nil {
 				field.SetBool(val)
 			}
+		case reflect.Slice:
+			// Lists can be sent comma separated, repeated or both (?tag=a,b&tag=c)
+			if field.Type().Elem().Kind() != reflect.String {
+				continue
+			}
+
+			var vals []string
+			for _, p := range q[fieldType.Tag.Get("json")] {
+				for _, val := range strings.Split(p, ",") {
+					if val = strings.TrimSpace(val); val != "" {
+						vals = append(vals, val)
+					}
+				}
+			}
+			field.Set(reflect.ValueOf(vals).Convert(field.Type()))
 		default:
 			field.SetString(paramValue)
 		}
 	}
 }
+
+// Returns the ip address of the client that sent the request.
+// X-Forwarded-For is only used when the server is configured to trust its proxy,
+// otherwise anyone could pick their own ip by setting the header. Even then only the
+// rightmost entry is used, it is the one the proxy appended, everything left of it came
+// from the client and can be anything.
+func ClientIP(r *http.Request) string {
+	if config.Server().TrustProxy {
+		xff := strings.Join(r.Header.Values("X-Forwarded-For"), ",")
+		if i := strings.LastIndex(xff, ","); i >= 0 {
+			xff = xff[i+1:]
+		}
+		if ip := strings.TrimSpace(xff); ip != "" {
+			return ip
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+// Parses from and to (inclusive days in DateLayout) into the half open range [start, end) so the whole
+// "to" day is included. Missing bounds default to the last defaultDays days including today.
+func ParseDateRange(from string, to string, defaultDays int) (time.Time, time.Time, error) {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	start, end := today.AddDate(0, 0, 1-defaultDays), today
+	if to != "" {
+		t, err := time.Parse(DateLayout, to)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.Wrap(ErrInvalidPeriod.Error, "parse")
+		}
+		end = t
+	}
+
+	if from != "" {
+		t, err := time.Parse(DateLayout, from)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.Wrap(ErrInvalidPeriod.Error, "parse")
+		}
+		start = t
+	}
+
+	end = end.AddDate(0, 0, 1)
+	if !start.Before(end) {
+		return time.Time{}, time.Time{}, errors.Wrap(ErrInvalidPeriod.Error, "period")
+	}
+
+	return start, end, nil
+}
+
+// Picks the language to respond in from the lang query param or else the Accept-Language header,
+// falling back to the first of the supported languages. Only the primary subtag is used so en-GB is en.
+func PreferredLanguage(r *http.Request, supported []string) string {
+	if lang := strings.ToLower(r.URL.Query().Get("lang")); slices.Contains(supported, lang) {
+		return lang
+	}
+
+	best, bestQ := supported[0], 0.0
+	for _, part := range strings.Split(r.Header.Get("Accept-Language"), ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		lang, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+
+		// The first one listed wins a tie
+		if q > bestQ && slices.Contains(supported, lang) {
+			best, bestQ = lang, q
+		}
+	}
+
+	return best
+}
+
+// Position in a list sorted by time then id, used for keyset pagination where the next page
+// starts after the last row of the previous one instead of at an offset
+type Cursor struct {
+	Time time.Time
+	ID   string
+}
+
+// Encodes the cursor into an opaque string clients send back as is
+func (c Cursor) Encode() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(c.Time.UnixMicro(), 10) + ":" + c.ID))
+}
+
+func ParseCursor(s string) (Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor.Error
+	}
+
+	ts, id, ok := strings.Cut(string(b), ":")
+	if !ok || id == "" {
+		return Cursor{}, ErrInvalidCursor.Error
+	}
+
+	micros, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor.Error
+	}
+
+	return Cursor{Time: time.UnixMicro(micros).UTC(), ID: id}, nil
+}
@@ -0,0 +1,189 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pdridh/k-line/config"
+)
+
+func loadConfig(t *testing.T, env map[string]string) {
+	t.Helper()
+
+	t.Setenv("SERVER_ENV", "production")
+	for k, v := range env {
+		t.Setenv(k, v)
+	}
+	config.Load()
+}
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		name       string
+		trustProxy string
+		xff        []string
+		want       string
+	}{
+		{name: "remote address without a proxy", trustProxy: "false", want: "10.0.0.1"},
+		{name: "header ignored without a proxy", trustProxy: "false", xff: []string{"203.0.113.7"}, want: "10.0.0.1"},
+		{name: "header from the proxy", trustProxy: "true", xff: []string{"203.0.113.7"}, want: "203.0.113.7"},
+		{name: "spoofed entries left of the proxy one", trustProxy: "true", xff: []string{"1.2.3.4, 5.6.7.8, 203.0.113.7"}, want: "203.0.113.7"},
+		{name: "spoofed header line before the proxy one", trustProxy: "true", xff: []string{"1.2.3.4", "203.0.113.7"}, want: "203.0.113.7"},
+		{name: "remote address when the header is empty", trustProxy: "true", xff: []string{" "}, want: "10.0.0.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loadConfig(t, map[string]string{"TRUST_PROXY": tt.trustProxy})
+
+			r := httptest.NewRequest("POST", "/auth/login", nil)
+			r.RemoteAddr = "10.0.0.1:54321"
+			for _, v := range tt.xff {
+				r.Header.Add("X-Forwarded-For", v)
+			}
+
+			if got := ClientIP(r); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// A client making up a new X-Forwarded-For for every request still counts as the same ip
+func TestClientIPSpoofedRateLimit(t *testing.T) {
+	loadConfig(t, map[string]string{"TRUST_PROXY": "true"})
+
+	l := NewRateLimiter(3, time.Minute)
+	for n := range 10 {
+		r := httptest.NewRequest("POST", "/auth/login", nil)
+		r.RemoteAddr = "10.0.0.1:54321"
+		r.Header.Set("X-Forwarded-For", fmt.Sprintf("198.51.100.%d, 203.0.113.7", n))
+
+		ok, _ := l.Allow(ClientIP(r))
+		if want := n < 3; ok != want {
+			t.Fatalf("request %d allowed %v, want %v", n+1, ok, want)
+		}
+	}
+}
+
+func TestParseCursor(t *testing.T) {
+	c := Cursor{Time: time.Date(2025, 3, 14, 15, 9, 26, 535000, time.UTC), ID: "0195a3c2-7e1f-7000-8000-000000000001"}
+
+	got, err := ParseCursor(c.Encode())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !got.Time.Equal(c.Time) || got.ID != c.ID {
+		t.Errorf("got %+v, want %+v", got, c)
+	}
+
+	invalid := []struct {
+		name   string
+		cursor string
+	}{
+		{"not base64", "!!!"},
+		{"no separator", "MTIzNDU"},       // 12345
+		{"no id", "MTIzNDU6"},             // 12345:
+		{"time not a number", "YWJjOmlk"}, // abc:id
+		{"empty", ""},
+	}
+
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseCursor(tt.cursor); !errors.Is(err, ErrInvalidCursor.Error) {
+				t.Errorf("got error %v, want %v", err, ErrInvalidCursor.Error)
+			}
+		})
+	}
+}
+
+func TestPreferredLanguage(t *testing.T) {
+	supported := []string{"en", "fr", "de"}
+
+	tests := []struct {
+		name           string
+		query          string
+		acceptLanguage string
+		want           string
+	}{
+		{name: "nothing asked for", want: "en"},
+		{name: "query param", query: "lang=fr", want: "fr"},
+		{name: "query param in any case", query: "lang=DE", want: "de"},
+		{name: "unsupported query param falls back to the header", query: "lang=es", acceptLanguage: "fr", want: "fr"},
+		{name: "query param wins over the header", query: "lang=de", acceptLanguage: "fr", want: "de"},
+		{name: "region subtag dropped", acceptLanguage: "fr-CA", want: "fr"},
+		{name: "highest quality wins", acceptLanguage: "fr;q=0.5, de;q=0.8", want: "de"},
+		{name: "first listed wins a tie", acceptLanguage: "de, fr", want: "de"},
+		{name: "unsupported ones skipped", acceptLanguage: "es, it;q=0.9, fr;q=0.1", want: "fr"},
+		{name: "bad quality skipped", acceptLanguage: "fr;q=abc, de;q=0.2", want: "de"},
+		{name: "quality zero is never picked", acceptLanguage: "fr;q=0", want: "en"},
+		{name: "nothing supported", acceptLanguage: "es, it", want: "en"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/menu?"+tt.query, nil)
+			if tt.acceptLanguage != "" {
+				r.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+
+			if got := PreferredLanguage(r, supported); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDateRange(t *testing.T) {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	day := func(s string) time.Time {
+		d, err := time.Parse(DateLayout, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+
+	tests := []struct {
+		name  string
+		from  string
+		to    string
+		start time.Time
+		end   time.Time
+		err   bool
+	}{
+		{name: "defaults to the last days including today", start: today.AddDate(0, 0, -6), end: today.AddDate(0, 0, 1)},
+		{name: "both given", from: "2025-01-01", to: "2025-01-31", start: day("2025-01-01"), end: day("2025-02-01")},
+		{name: "single day", from: "2025-01-01", to: "2025-01-01", start: day("2025-01-01"), end: day("2025-01-02")},
+		{name: "only from runs up to today", from: "2025-01-01", start: day("2025-01-01"), end: today.AddDate(0, 0, 1)},
+		{name: "only to keeps the default start", to: today.AddDate(0, 0, 3).Format(DateLayout), start: today.AddDate(0, 0, -6), end: today.AddDate(0, 0, 4)},
+		{name: "only to before the default start", to: "2025-01-01", err: true},
+		{name: "from after to", from: "2025-02-01", to: "2025-01-31", err: true},
+		{name: "bad from", from: "01/01/2025", err: true},
+		{name: "bad to", to: "2025-13-01", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, err := ParseDateRange(tt.from, tt.to, 7)
+			if tt.err {
+				if !errors.Is(err, ErrInvalidPeriod.Error) {
+					t.Fatalf("got error %v, want %v", err, ErrInvalidPeriod.Error)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if !start.Equal(tt.start) || !end.Equal(tt.end) {
+				t.Errorf("got [%v, %v), want [%v, %v)", start, end, tt.start, tt.end)
+			}
+		})
+	}
+}
@@ -2,7 +2,9 @@ package api
 
 import (
 	"compress/gzip"
+	"encoding/csv"
 	"encoding/json"
+	"fmt"
 	"io"
 	"log"
 	"net/http"
@@ -81,3 +83,20 @@ func WriteValidationError(w http.ResponseWriter, r *http.Request, err error) {
 	v := FormatValidationErrors(err)
 	WriteError(w, r, http.StatusBadRequest, ErrJSONValidation, v)
 }
+
+// Writes the header and rows as a csv file download named filename
+func WriteCSV(w http.ResponseWriter, r *http.Request, filename string, header []string, rows [][]string) {
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.WriteHeader(http.StatusOK)
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		log.Println("failed to write to request")
+		return
+	}
+
+	if err := cw.WriteAll(rows); err != nil {
+		log.Println("failed to write to request")
+	}
+}
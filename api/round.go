@@ -0,0 +1,9 @@
+package api
+
+import "math"
+
+// Rounds money (and the stock quantities worked out next to it) to 2 decimal places so float sums
+// dont leave trailing noise
+func Round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -1,10 +1,11 @@
 package api
 
 type PaginationMeta struct {
-	Page       int `json:"page"`
-	Limit      int `json:"limit"`
-	Total      int `json:"total"`
-	TotalPages int `json:"total_pages"`
+	Page       int    `json:"page"`
+	Limit      int    `json:"limit"`
+	Total      int    `json:"total"`
+	TotalPages int    `json:"total_pages"`
+	NextCursor string `json:"next_cursor,omitempty"` // Only on lists that support keyset pagination
 }
 
 func CalculatePaginationMeta(total, page, limit int) *PaginationMeta {
@@ -22,6 +23,18 @@ func CalculatePaginationMeta(total, page, limit int) *PaginationMeta {
 	}
 }
 
+// Normalizes the page and limit of a list request, a limit thats missing or above maxLimit
+// becomes defaultLimit and pages start at 1
+func ClampPage(limit *int32, page *int32, maxLimit int32, defaultLimit int32) {
+	if *limit <= 0 || *limit > maxLimit {
+		*limit = defaultLimit
+	}
+
+	if *page < 1 {
+		*page = 1
+	}
+}
+
 type PaginatedResponse[T any] struct {
 	Data []T             `json:"data"`
 	Meta *PaginationMeta `json:"meta,omitempty"`
@@ -0,0 +1,57 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/pdridh/k-line/config"
+)
+
+const (
+	CSRFCookieName = "csrf_token"
+	CSRFHeader     = "X-CSRF-Token"
+)
+
+// Generates a new csrf token and sets it as a cookie readable by the frontend.
+// The frontend has to echo the token back in the CSRFHeader (double submit),
+// which another site cant do since it cant read our cookies.
+func SetCSRFCookie(w http.ResponseWriter) (string, error) {
+	token, err := GenerateOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+
+	cookie := http.Cookie{
+		Name:     CSRFCookieName,
+		Value:    token,
+		MaxAge:   int(config.Server().JWTExpiration.Seconds()),
+		HttpOnly: false,
+		Secure:   config.Server().Env == "production",
+		SameSite: http.SameSiteLaxMode,
+		Path:     "/",
+	}
+
+	http.SetCookie(w, &cookie)
+
+	return token, nil
+}
+
+// Reports whether the request has a csrf header matching its csrf cookie
+func validCSRF(r *http.Request) bool {
+	c, err := r.Cookie(CSRFCookieName)
+	if err != nil || c.Value == "" {
+		return false
+	}
+
+	h := r.Header.Get(CSRFHeader)
+	return subtle.ConstantTimeCompare([]byte(c.Value), []byte(h)) == 1
+}
+
+// Reports whether the method only reads, those dont need csrf protection
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
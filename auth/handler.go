@@ -1,7 +1,11 @@
 package auth
 
 import (
+	"log"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/pdridh/k-line/api"
@@ -10,20 +14,57 @@ import (
 )
 
 type handler struct {
-	Service *service
+	Service        *service
+	ipLimiter      *api.RateLimiter
+	accountLimiter *api.RateLimiter
 }
 
 func NewHandler(s *service) *handler {
 	return &handler{
-		Service: s,
+		Service:        s,
+		ipLimiter:      api.NewRateLimiter(LoginIPLimit, LoginLimitWindow),
+		accountLimiter: api.NewRateLimiter(LoginAccountLimit, LoginLimitWindow),
 	}
 }
 
+// Sends the logged in user back, either with the jwt set as a cookie (along with a fresh csrf token)
+// or, when the client asked for it, with the jwt in the body for use as a Bearer token.
+func writeLoginSuccess(w http.ResponseWriter, r *http.Request, token string, u *User, issueToken bool) {
+	type ResponsePayload struct {
+		*User
+		Token     string `json:"token,omitempty"`
+		CSRFToken string `json:"csrf_token,omitempty"`
+	}
+
+	res := ResponsePayload{User: u}
+	if issueToken {
+		res.Token = token
+	} else {
+		csrf, err := SetCSRFCookie(w)
+		if err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		SetJWTCookie(w, token)
+		res.CSRFToken = csrf
+	}
+
+	api.WriteSuccess(w, r, http.StatusOK, "Login successful", res)
+}
+
+// Writes a 429 telling the client how long to wait before retrying
+func writeRateLimited(w http.ResponseWriter, r *http.Request, retryAfter time.Duration) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+	api.WriteError(w, r, http.StatusTooManyRequests, api.ErrTooManyRequests, nil)
+}
+
 func (h *handler) Register() http.HandlerFunc {
 	type RequestPayload struct {
 		Name     string        `json:"name" validate:"required"`
 		Email    string        `json:"email" validate:"required,email"`
-		Type     sqlc.UserType `json:"type" validate:"required,oneof=admin waiter kitchen"`
+		Type     sqlc.UserType `json:"type" validate:"required,oneof=admin register kitchen waiter driver"`
+		RoleID   int32         `json:"role_id"` // Defaults to the role named after the type
 		Password string        `json:"password" validate:"required,min=8,max=32"`
 	}
 
@@ -32,6 +73,7 @@ func (h *handler) Register() http.HandlerFunc {
 		Email     string           `json:"email"`
 		Name      string           `json:"name"`
 		Type      sqlc.UserType    `json:"type"`
+		RoleID    int32            `json:"role_id"`
 		CreatedAt pgtype.Timestamp `json:"created_at"`
 	}
 
@@ -47,12 +89,28 @@ func (h *handler) Register() http.HandlerFunc {
 			return
 		}
 
-		u, err := h.Service.CreateUser(r.Context(), p.Email, p.Name, p.Type, p.Password)
+		var actorID pgtype.UUID
+		if err := actorID.Scan(api.CurrentUserID(r)); err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		u, err := h.Service.CreateUser(r.Context(), actorID, p.Email, p.Name, p.Type, p.RoleID, p.Password)
 		if errors.Is(err, api.ErrEmailAlreadyExists.Error) {
 			api.WriteError(w, r, http.StatusConflict, api.ErrRegistrationFailed, nil)
 			return
 		}
 
+		if errors.Is(err, api.ErrUnknownRole.Error) {
+			api.WriteError(w, r, http.StatusBadRequest, api.ErrUnknownRole, nil)
+			return
+		}
+
+		if errors.Is(err, api.ErrRoleNotAssignable.Error) {
+			api.WriteError(w, r, http.StatusForbidden, api.ErrRoleNotAssignable, nil)
+			return
+		}
+
 		if err != nil {
 			api.WriteInternalError(w, r)
 			return
@@ -63,6 +121,7 @@ func (h *handler) Register() http.HandlerFunc {
 			Email:     u.Email,
 			Name:      u.Name,
 			Type:      u.Type,
+			RoleID:    u.RoleID,
 			CreatedAt: u.CreatedAt,
 		}
 
@@ -70,13 +129,59 @@ func (h *handler) Register() http.HandlerFunc {
 	}
 }
 
-func (h *handler) Login() http.HandlerFunc {
+// Public route that creates the first admin account.
+// Once an admin exists this always fails and new accounts go through Register.
+func (h *handler) Bootstrap() http.HandlerFunc {
 	type RequestPayload struct {
+		Name     string `json:"name" validate:"required"`
 		Email    string `json:"email" validate:"required,email"`
 		Password string `json:"password" validate:"required,min=8,max=32"`
 	}
 
 	return func(w http.ResponseWriter, r *http.Request) {
+		var p RequestPayload
+		if err := api.ParseJSON(r, &p); err != nil {
+			api.WriteBadRequestError(w, r)
+			return
+		}
+
+		if err := h.Service.Validate.Struct(p); err != nil {
+			api.WriteValidationError(w, r, err)
+			return
+		}
+
+		u, err := h.Service.BootstrapAdmin(r.Context(), p.Email, p.Name, p.Password)
+		if err != nil {
+			switch {
+			case errors.Is(err, api.ErrBootstrapClosed.Error):
+				api.WriteError(w, r, http.StatusForbidden, api.ErrBootstrapClosed, nil)
+				return
+			case errors.Is(err, api.ErrEmailAlreadyExists.Error):
+				api.WriteError(w, r, http.StatusConflict, api.ErrRegistrationFailed, nil)
+				return
+			default:
+				api.WriteInternalError(w, r)
+				return
+			}
+		}
+
+		api.WriteSuccess(w, r, http.StatusCreated, "Successfully registered admin", u)
+	}
+}
+
+func (h *handler) Login() http.HandlerFunc {
+	type RequestPayload struct {
+		Email      string `json:"email" validate:"required,email"`
+		Password   string `json:"password" validate:"required,min=8,max=32"`
+		IssueToken bool   `json:"issue_token"` // Return the jwt in the body for Bearer auth instead of setting the cookie
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if ok, retryAfter := h.ipLimiter.Allow(api.ClientIP(r)); !ok {
+			writeRateLimited(w, r, retryAfter)
+			return
+		}
+
 		var p RequestPayload
 
 		if err := api.ParseJSON(r, &p); err != nil {
@@ -89,53 +194,765 @@ func (h *handler) Login() http.HandlerFunc {
 			return
 		}
 
+		if ok, retryAfter := h.accountLimiter.Allow(strings.ToLower(p.Email)); !ok {
+			writeRateLimited(w, r, retryAfter)
+			return
+		}
+
 		t, u, err := h.Service.AuthenticateUser(r.Context(), p.Email, p.Password)
 		if err != nil {
 			switch {
 			case errors.Is(err, api.ErrUnknownEmail.Error), errors.Is(err, api.ErrWrongPassword.Error):
 				api.WriteError(w, r, http.StatusUnauthorized, api.ErrLoginInvalid, nil)
 				return
+			case errors.Is(err, api.ErrLoginLocked.Error):
+				api.WriteError(w, r, http.StatusTooManyRequests, api.ErrLoginLocked, nil)
+				return
+			case errors.Is(err, api.ErrUserInactive.Error):
+				api.WriteError(w, r, http.StatusForbidden, api.ErrUserInactive, nil)
+				return
 			default:
 				api.WriteInternalError(w, r)
 				return
 			}
 		}
 
-		SetJWTCookie(w, t)
-
-		api.WriteSuccess(w, r, http.StatusOK, "Login successful", u)
+		writeLoginSuccess(w, r, t, u, p.IssueToken)
 	}
 }
 
 func (h *handler) GetAuth() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		jCookie, err := r.Cookie("jwt")
+		c, err := userClaimsFromRequest(r)
 		if err != nil {
 			api.WriteInvalidJWTError(w, r)
 			return
 		}
 
-		j := jCookie.Value
-
-		t, err := ValidateJWT(j)
-		if err != nil {
+		var userID pgtype.UUID
+		if err := userID.Scan(c.UserID); err != nil {
 			api.WriteInvalidJWTError(w, r)
 			return
 		}
 
-		c, err := UserClaimsFromJWT(t)
+		roleID, permissions, err := h.Service.GetUserPermissions(r.Context(), userID)
 		if err != nil {
-			api.WriteInvalidJWTError(w, r)
+			if errors.Is(err, api.ErrUnknownUser.Error) {
+				api.WriteInvalidJWTError(w, r)
+				return
+			}
+
+			api.WriteInternalError(w, r)
 			return
 		}
 
 		auth := UserAuth{
-			ID:    c.ID,
-			Email: c.UserEmail,
-			Name:  c.UserName,
-			Type:  c.UserType,
+			ID:          c.UserID,
+			Email:       c.UserEmail,
+			Name:        c.UserName,
+			Type:        c.UserType,
+			RoleID:      roleID,
+			Permissions: permissions,
 		}
 
 		api.WriteSuccess(w, r, http.StatusOK, "Auth is valid", auth)
 	}
 }
+
+func (h *handler) GetUsers() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var filters UserFilters
+
+		api.ParseQueryParams(r.URL.Query(), &filters)
+		filters.Validate(50, 20)
+
+		offset := (filters.Page - 1) * filters.Limit
+
+		u, total, err := h.Service.GetUsers(r.Context(), filters.Limit, offset)
+		if err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		meta := api.CalculatePaginationMeta(total, int(filters.Page), int(filters.Limit))
+		api.WriteSuccess(w, r, http.StatusOK, "Retrieval successful", api.NewPaginatedResponse(u, meta))
+	}
+}
+
+func (h *handler) GetUserByID() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := pgtype.UUID{}
+		if err := id.Scan(r.PathValue("id")); err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		u, err := h.Service.GetUserByID(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, api.ErrUnknownUser.Error) {
+				api.WriteNotFoundError(w, r)
+				return
+			}
+
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Retrieval successful", u)
+	}
+}
+
+func (h *handler) UpdateUser() http.HandlerFunc {
+	type RequestPayload struct {
+		Name   string        `json:"name" validate:"required"`
+		Type   sqlc.UserType `json:"type" validate:"required,oneof=admin register kitchen waiter driver"`
+		RoleID int32         `json:"role_id" validate:"required"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := pgtype.UUID{}
+		if err := id.Scan(r.PathValue("id")); err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		var actorID pgtype.UUID
+		if err := actorID.Scan(api.CurrentUserID(r)); err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		var p RequestPayload
+		if err := api.ParseJSON(r, &p); err != nil {
+			api.WriteBadRequestError(w, r)
+			return
+		}
+
+		if err := h.Service.Validate.Struct(p); err != nil {
+			api.WriteValidationError(w, r, err)
+			return
+		}
+
+		u, err := h.Service.UpdateUser(r.Context(), actorID, id, p.Name, p.Type, p.RoleID)
+		if err != nil {
+			switch {
+			case errors.Is(err, api.ErrUnknownUser.Error):
+				api.WriteNotFoundError(w, r)
+				return
+			case errors.Is(err, api.ErrUnknownRole.Error):
+				api.WriteError(w, r, http.StatusBadRequest, api.ErrUnknownRole, nil)
+				return
+			case errors.Is(err, api.ErrRoleNotAssignable.Error):
+				api.WriteError(w, r, http.StatusForbidden, api.ErrRoleNotAssignable, nil)
+				return
+			case errors.Is(err, api.ErrCannotModifySelf.Error):
+				api.WriteError(w, r, http.StatusConflict, api.ErrCannotModifySelf, nil)
+				return
+			case errors.Is(err, api.ErrUserProtected.Error):
+				api.WriteError(w, r, http.StatusForbidden, api.ErrUserProtected, nil)
+				return
+			default:
+				api.WriteInternalError(w, r)
+				return
+			}
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Successfully updated user", u)
+	}
+}
+
+// Returns a handler that activates the user in the path if active is true, deactivates otherwise
+func (h *handler) SetUserActive(active bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := pgtype.UUID{}
+		if err := id.Scan(r.PathValue("id")); err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		var actorID pgtype.UUID
+		if err := actorID.Scan(api.CurrentUserID(r)); err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		u, err := h.Service.SetUserActive(r.Context(), actorID, id, active)
+		if err != nil {
+			switch {
+			case errors.Is(err, api.ErrUnknownUser.Error):
+				api.WriteNotFoundError(w, r)
+				return
+			case errors.Is(err, api.ErrCannotModifySelf.Error):
+				api.WriteError(w, r, http.StatusConflict, api.ErrCannotModifySelf, nil)
+				return
+			case errors.Is(err, api.ErrUserProtected.Error):
+				api.WriteError(w, r, http.StatusForbidden, api.ErrUserProtected, nil)
+				return
+			default:
+				api.WriteInternalError(w, r)
+				return
+			}
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Successfully updated user status", u)
+	}
+}
+
+func (h *handler) ResetPassword() http.HandlerFunc {
+	type RequestPayload struct {
+		Password string `json:"password" validate:"required,min=8,max=32"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := pgtype.UUID{}
+		if err := id.Scan(r.PathValue("id")); err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		var p RequestPayload
+		if err := api.ParseJSON(r, &p); err != nil {
+			api.WriteBadRequestError(w, r)
+			return
+		}
+
+		if err := h.Service.Validate.Struct(p); err != nil {
+			api.WriteValidationError(w, r, err)
+			return
+		}
+
+		var actorID pgtype.UUID
+		if err := actorID.Scan(api.CurrentUserID(r)); err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		if err := h.Service.ResetPassword(r.Context(), actorID, id, p.Password); err != nil {
+			if errors.Is(err, api.ErrUnknownUser.Error) {
+				api.WriteNotFoundError(w, r)
+				return
+			}
+
+			if errors.Is(err, api.ErrUserProtected.Error) {
+				api.WriteError(w, r, http.StatusForbidden, api.ErrUserProtected, nil)
+				return
+			}
+
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Successfully reset password", nil)
+	}
+}
+
+// Lets the logged in user set their own pin for fast login on trusted devices
+func (h *handler) SetOwnPIN() http.HandlerFunc {
+	type RequestPayload struct {
+		PIN string `json:"pin" validate:"required,numeric,min=4,max=8"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var userID pgtype.UUID
+		if err := userID.Scan(api.CurrentUserID(r)); err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		var p RequestPayload
+		if err := api.ParseJSON(r, &p); err != nil {
+			api.WriteBadRequestError(w, r)
+			return
+		}
+
+		if err := h.Service.Validate.Struct(p); err != nil {
+			api.WriteValidationError(w, r, err)
+			return
+		}
+
+		if err := h.Service.SetPIN(r.Context(), userID, userID, p.PIN); err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Successfully set pin", nil)
+	}
+}
+
+// Lets an admin set the pin of any user, also unlocks the pin if it was locked
+func (h *handler) SetUserPIN() http.HandlerFunc {
+	type RequestPayload struct {
+		PIN string `json:"pin" validate:"required,numeric,min=4,max=8"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := pgtype.UUID{}
+		if err := id.Scan(r.PathValue("id")); err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		var p RequestPayload
+		if err := api.ParseJSON(r, &p); err != nil {
+			api.WriteBadRequestError(w, r)
+			return
+		}
+
+		if err := h.Service.Validate.Struct(p); err != nil {
+			api.WriteValidationError(w, r, err)
+			return
+		}
+
+		var actorID pgtype.UUID
+		if err := actorID.Scan(api.CurrentUserID(r)); err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		if err := h.Service.SetPIN(r.Context(), actorID, id, p.PIN); err != nil {
+			if errors.Is(err, api.ErrUnknownUser.Error) {
+				api.WriteNotFoundError(w, r)
+				return
+			}
+
+			if errors.Is(err, api.ErrUserProtected.Error) {
+				api.WriteError(w, r, http.StatusForbidden, api.ErrUserProtected, nil)
+				return
+			}
+
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Successfully set pin", nil)
+	}
+}
+
+func (h *handler) RegisterDevice() http.HandlerFunc {
+	type RequestPayload struct {
+		Name string `json:"name" validate:"required"`
+	}
+
+	type ResponsePayload struct {
+		Device *Device `json:"device"`
+		Token  string  `json:"token"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var adminID pgtype.UUID
+		if err := adminID.Scan(api.CurrentUserID(r)); err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		var p RequestPayload
+		if err := api.ParseJSON(r, &p); err != nil {
+			api.WriteBadRequestError(w, r)
+			return
+		}
+
+		if err := h.Service.Validate.Struct(p); err != nil {
+			api.WriteValidationError(w, r, err)
+			return
+		}
+
+		d, t, err := h.Service.RegisterDevice(r.Context(), p.Name, adminID)
+		if err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusCreated, "Successfully registered device", ResponsePayload{Device: d, Token: t})
+	}
+}
+
+func (h *handler) GetDevices() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		d, err := h.Service.GetDevices(r.Context())
+		if err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Retrieval successful", d)
+	}
+}
+
+func (h *handler) RevokeDevice() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := pgtype.UUID{}
+		if err := id.Scan(r.PathValue("id")); err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		if err := h.Service.RevokeDevice(r.Context(), id); err != nil {
+			if errors.Is(err, api.ErrUnknownDevice.Error) {
+				api.WriteNotFoundError(w, r)
+				return
+			}
+
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Successfully revoked device", nil)
+	}
+}
+
+// Lists the users that can switch in on this device, only answers trusted devices
+func (h *handler) GetPINUsers() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if _, err := h.Service.AuthenticateDevice(r.Context(), r.Header.Get(DeviceTokenHeader)); err != nil {
+			if errors.Is(err, api.ErrDeviceUntrusted.Error) {
+				api.WriteError(w, r, http.StatusUnauthorized, api.ErrDeviceUntrusted, nil)
+				return
+			}
+
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		u, err := h.Service.GetPINUsers(r.Context())
+		if err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Retrieval successful", u)
+	}
+}
+
+// Logs a user in with their pin on a trusted device.
+// Switching users on a device is just another pin login, the new cookie replaces the old one.
+func (h *handler) PINLogin() http.HandlerFunc {
+	type RequestPayload struct {
+		UserID     pgtype.UUID `json:"user_id" validate:"required"`
+		PIN        string      `json:"pin" validate:"required,numeric,min=4,max=8"`
+		IssueToken bool        `json:"issue_token"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if ok, retryAfter := h.ipLimiter.Allow(api.ClientIP(r)); !ok {
+			writeRateLimited(w, r, retryAfter)
+			return
+		}
+
+		if _, err := h.Service.AuthenticateDevice(r.Context(), r.Header.Get(DeviceTokenHeader)); err != nil {
+			if errors.Is(err, api.ErrDeviceUntrusted.Error) {
+				api.WriteError(w, r, http.StatusUnauthorized, api.ErrDeviceUntrusted, nil)
+				return
+			}
+
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		var p RequestPayload
+		if err := api.ParseJSON(r, &p); err != nil {
+			api.WriteBadRequestError(w, r)
+			return
+		}
+
+		if err := h.Service.Validate.Struct(p); err != nil {
+			api.WriteValidationError(w, r, err)
+			return
+		}
+
+		t, u, err := h.Service.AuthenticatePIN(r.Context(), p.UserID, p.PIN)
+		if err != nil {
+			switch {
+			case errors.Is(err, api.ErrPINInvalid.Error):
+				api.WriteError(w, r, http.StatusUnauthorized, api.ErrPINInvalid, nil)
+				return
+			case errors.Is(err, api.ErrPINLocked.Error):
+				api.WriteError(w, r, http.StatusTooManyRequests, api.ErrPINLocked, nil)
+				return
+			case errors.Is(err, api.ErrUserInactive.Error):
+				api.WriteError(w, r, http.StatusForbidden, api.ErrUserInactive, nil)
+				return
+			default:
+				api.WriteInternalError(w, r)
+				return
+			}
+		}
+
+		writeLoginSuccess(w, r, t, u, p.IssueToken)
+	}
+}
+
+func (h *handler) GetPermissions() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		api.WriteSuccess(w, r, http.StatusOK, "Retrieval successful", AllPermissions)
+	}
+}
+
+func (h *handler) GetRoles() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		roles, err := h.Service.GetRoles(r.Context())
+		if err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Retrieval successful", roles)
+	}
+}
+
+func (h *handler) CreateRole() http.HandlerFunc {
+	type RequestPayload struct {
+		Name        string      `json:"name" validate:"required"`
+		Description pgtype.Text `json:"description"`
+		Permissions []string    `json:"permissions" validate:"required,dive,required"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var p RequestPayload
+		if err := api.ParseJSON(r, &p); err != nil {
+			api.WriteBadRequestError(w, r)
+			return
+		}
+
+		if err := h.Service.Validate.Struct(p); err != nil {
+			api.WriteValidationError(w, r, err)
+			return
+		}
+
+		role, err := h.Service.CreateRole(r.Context(), p.Name, p.Description, p.Permissions)
+		if err != nil {
+			switch {
+			case errors.Is(err, api.ErrUnknownPermission.Error):
+				api.WriteError(w, r, http.StatusBadRequest, api.ErrUnknownPermission, nil)
+				return
+			case errors.Is(err, api.ErrRoleNameConflict.Error):
+				api.WriteError(w, r, http.StatusConflict, api.ErrRoleNameConflict, nil)
+				return
+			default:
+				api.WriteInternalError(w, r)
+				return
+			}
+		}
+
+		api.WriteSuccess(w, r, http.StatusCreated, "Created new role", role)
+	}
+}
+
+func (h *handler) UpdateRole() http.HandlerFunc {
+	type RequestPayload struct {
+		Name        string      `json:"name" validate:"required"`
+		Description pgtype.Text `json:"description"`
+		Permissions []string    `json:"permissions" validate:"required,dive,required"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		var p RequestPayload
+		if err := api.ParseJSON(r, &p); err != nil {
+			api.WriteBadRequestError(w, r)
+			return
+		}
+
+		if err := h.Service.Validate.Struct(p); err != nil {
+			api.WriteValidationError(w, r, err)
+			return
+		}
+
+		role, err := h.Service.UpdateRole(r.Context(), int32(id), p.Name, p.Description, p.Permissions)
+		if err != nil {
+			switch {
+			case errors.Is(err, api.ErrUnknownRole.Error):
+				api.WriteNotFoundError(w, r)
+				return
+			case errors.Is(err, api.ErrUnknownPermission.Error):
+				api.WriteError(w, r, http.StatusBadRequest, api.ErrUnknownPermission, nil)
+				return
+			case errors.Is(err, api.ErrRoleProtected.Error):
+				api.WriteError(w, r, http.StatusConflict, api.ErrRoleProtected, nil)
+				return
+			case errors.Is(err, api.ErrRoleNameConflict.Error):
+				api.WriteError(w, r, http.StatusConflict, api.ErrRoleNameConflict, nil)
+				return
+			default:
+				api.WriteInternalError(w, r)
+				return
+			}
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Successfully updated role", role)
+	}
+}
+
+func (h *handler) DeleteRole() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		if err := h.Service.DeleteRole(r.Context(), int32(id)); err != nil {
+			switch {
+			case errors.Is(err, api.ErrUnknownRole.Error):
+				api.WriteNotFoundError(w, r)
+				return
+			case errors.Is(err, api.ErrRoleProtected.Error):
+				api.WriteError(w, r, http.StatusConflict, api.ErrRoleProtected, nil)
+				return
+			case errors.Is(err, api.ErrRoleInUse.Error):
+				api.WriteError(w, r, http.StatusConflict, api.ErrRoleInUse, nil)
+				return
+			default:
+				api.WriteInternalError(w, r)
+				return
+			}
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Successfully deleted role", nil)
+	}
+}
+
+// Clears the login and pin lockouts of the user in the path
+func (h *handler) UnlockUser() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := pgtype.UUID{}
+		if err := id.Scan(r.PathValue("id")); err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		if err := h.Service.UnlockUser(r.Context(), id); err != nil {
+			if errors.Is(err, api.ErrUnknownUser.Error) {
+				api.WriteNotFoundError(w, r)
+				return
+			}
+
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Successfully unlocked user", nil)
+	}
+}
+
+// Serves the public keys that tokens are signed with so other services can verify them
+func (h *handler) JWKS() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "public, max-age=300")
+
+		if err := api.WriteJSON(w, r, http.StatusOK, PublicJWKS()); err != nil {
+			log.Println("failed to write to request")
+		}
+	}
+}
+
+func (h *handler) GetAPIKeys() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		k, err := h.Service.GetAPIKeys(r.Context())
+		if err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Retrieval successful", k)
+	}
+}
+
+func (h *handler) CreateAPIKey() http.HandlerFunc {
+	type RequestPayload struct {
+		Name          string      `json:"name" validate:"required"`
+		UserID        pgtype.UUID `json:"user_id" validate:"required"`
+		Permissions   []string    `json:"permissions" validate:"required,min=1,dive,required"`
+		ExpiresInDays int32       `json:"expires_in_days" validate:"min=0"` // 0 never expires
+	}
+
+	type ResponsePayload struct {
+		APIKey *APIKey `json:"api_key"`
+		Key    string  `json:"key"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var adminID pgtype.UUID
+		if err := adminID.Scan(api.CurrentUserID(r)); err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		var p RequestPayload
+		if err := api.ParseJSON(r, &p); err != nil {
+			api.WriteBadRequestError(w, r)
+			return
+		}
+
+		if err := h.Service.Validate.Struct(p); err != nil {
+			api.WriteValidationError(w, r, err)
+			return
+		}
+
+		k, key, err := h.Service.CreateAPIKey(r.Context(), p.Name, p.UserID, p.Permissions, p.ExpiresInDays, adminID)
+		if err != nil {
+			switch {
+			case errors.Is(err, api.ErrUnknownPermission.Error):
+				api.WriteError(w, r, http.StatusBadRequest, api.ErrUnknownPermission, nil)
+				return
+			case errors.Is(err, api.ErrUnknownUser.Error):
+				api.WriteError(w, r, http.StatusBadRequest, api.ErrUnknownUser, nil)
+				return
+			case errors.Is(err, api.ErrPermissionNotGranted.Error):
+				api.WriteError(w, r, http.StatusForbidden, api.ErrPermissionNotGranted, nil)
+				return
+			case errors.Is(err, api.ErrUserProtected.Error):
+				api.WriteError(w, r, http.StatusForbidden, api.ErrUserProtected, nil)
+				return
+			default:
+				api.WriteInternalError(w, r)
+				return
+			}
+		}
+
+		api.WriteSuccess(w, r, http.StatusCreated, "Created new api key", ResponsePayload{APIKey: k, Key: key})
+	}
+}
+
+func (h *handler) RevokeAPIKey() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := pgtype.UUID{}
+		if err := id.Scan(r.PathValue("id")); err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		if err := h.Service.RevokeAPIKey(r.Context(), id); err != nil {
+			if errors.Is(err, api.ErrUnknownAPIKey.Error) {
+				api.WriteNotFoundError(w, r)
+				return
+			}
+
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Successfully revoked api key", nil)
+	}
+}
+
+// Issues a new csrf token, cookie authenticated clients send it back in the CSRFHeader on requests that change something
+func (h *handler) CSRFToken() http.HandlerFunc {
+	type ResponsePayload struct {
+		Token string `json:"csrf_token"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		t, err := SetCSRFCookie(w)
+		if err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Issued csrf token", ResponsePayload{Token: t})
+	}
+}
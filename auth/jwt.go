@@ -24,21 +24,43 @@ func GenerateJWT(id string, email string, name string, userType sqlc.UserType, d
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(config.Server().JWTSecret))
+	k := loadedKeys().signing
+	if k == nil {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		return token.SignedString([]byte(config.Server().JWTSecret))
+	}
+
+	token := jwt.NewWithClaims(k.Method, claims)
+	token.Header["kid"] = k.ID
+	return token.SignedString(k.Private)
 }
 
 // Validate the tokenString jwt, returns a jwt.Token ptr which has the claims inside it.
-// Also checks if the signing method is the same as the generate.
+// The key is picked by the kid in the header and the signing method has to be the one of that key.
 // If its invalid then its returned as an error
 func ValidateJWT(tokenString string) (*jwt.Token, error) {
+	set := loadedKeys()
+
 	return jwt.ParseWithClaims(tokenString, &UserClaims{}, func(token *jwt.Token) (any, error) {
+		if set.signing == nil {
+			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, api.ErrUnexpectedJWTSigningMethod.Error
+			}
+
+			return []byte(config.Server().JWTSecret), nil
+		}
+
+		kid, _ := token.Header["kid"].(string)
+		k, ok := set.keys[kid]
+		if !ok {
+			return nil, jwt.ErrTokenUnverifiable
+		}
 
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		if token.Method.Alg() != k.Method.Alg() {
 			return nil, api.ErrUnexpectedJWTSigningMethod.Error
 		}
 
-		return []byte(config.Server().JWTSecret), nil
+		return k.Public, nil
 	})
 }
 
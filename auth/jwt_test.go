@@ -0,0 +1,182 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pdridh/k-line/config"
+	"github.com/pdridh/k-line/db/sqlc"
+)
+
+// Writes the key to <kid>.pem in dir, public keys are written without their private part
+func writeTestKey(t *testing.T, dir string, kid string, key any) {
+	t.Helper()
+
+	var block *pem.Block
+	switch k := key.(type) {
+	case crypto.Signer:
+		b, err := x509.MarshalPKCS8PrivateKey(k)
+		if err != nil {
+			t.Fatal(err)
+		}
+		block = &pem.Block{Type: "PRIVATE KEY", Bytes: b}
+	default:
+		b, err := x509.MarshalPKIXPublicKey(k)
+		if err != nil {
+			t.Fatal(err)
+		}
+		block = &pem.Block{Type: "PUBLIC KEY", Bytes: b}
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, kid+".pem"), pem.EncodeToMemory(block), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Signs claims for the user with the method and key, kid is left out of the header when empty
+func signTestJWT(t *testing.T, method jwt.SigningMethod, key any, kid string) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(method, UserClaims{
+		UserID:   "0195a3c2-7e1f-7000-8000-000000000001",
+		UserType: sqlc.UserTypeAdmin,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	})
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+
+	s, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestValidateJWT(t *testing.T) {
+	_, current, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	retired, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	writeTestKey(t, dir, "current", current)
+	writeTestKey(t, dir, "retired", &retired.PublicKey)
+
+	loadConfig(t, map[string]string{"JWT_KEYS_DIR": dir, "JWT_SIGNING_KID": "current"})
+
+	issued, err := GenerateJWT("0195a3c2-7e1f-7000-8000-000000000001", "a@example.com", "A", sqlc.UserTypeAdmin, time.Hour)
+	if err != nil {
+		t.Fatalf("cannot generate jwt: %v", err)
+	}
+
+	secret := []byte(config.Server().JWTSecret)
+
+	tests := []struct {
+		name  string
+		token string
+		valid bool
+	}{
+		{name: "issued by the server", token: issued, valid: true},
+		{name: "signed with a retired key", token: signTestJWT(t, jwt.SigningMethodRS256, retired, "retired"), valid: true},
+		{name: "no kid", token: signTestJWT(t, jwt.SigningMethodEdDSA, current, "")},
+		{name: "unknown kid", token: signTestJWT(t, jwt.SigningMethodEdDSA, current, "other")},
+		{name: "hs256 with the secret", token: signTestJWT(t, jwt.SigningMethodHS256, secret, "")},
+		{name: "hs256 with the secret and a kid", token: signTestJWT(t, jwt.SigningMethodHS256, secret, "current")},
+		{name: "method of another key", token: signTestJWT(t, jwt.SigningMethodEdDSA, current, "retired")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ValidateJWT(tt.token)
+			if tt.valid && err != nil {
+				t.Errorf("unexpected error %v", err)
+			}
+			if !tt.valid && err == nil {
+				t.Error("token accepted, want it rejected")
+			}
+		})
+	}
+}
+
+func TestLoadKeys(t *testing.T) {
+	_, first, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, second, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	small, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	one := t.TempDir()
+	writeTestKey(t, one, "first", first)
+
+	two := t.TempDir()
+	writeTestKey(t, two, "first", first)
+	writeTestKey(t, two, "second", second)
+
+	weak := t.TempDir()
+	writeTestKey(t, weak, "small", small)
+
+	public := t.TempDir()
+	writeTestKey(t, public, "first", first)
+	writeTestKey(t, public, "retired", first.Public())
+
+	tests := []struct {
+		name string
+		env  map[string]string
+		ok   bool
+	}{
+		{name: "secret outside production", ok: true},
+		{name: "secret in production", env: map[string]string{"SERVER_ENV": "production"}},
+		{name: "only private key", env: map[string]string{"JWT_KEYS_DIR": one}, ok: true},
+		{name: "two private keys without a kid", env: map[string]string{"JWT_KEYS_DIR": two}},
+		{name: "two private keys with a kid", env: map[string]string{"JWT_KEYS_DIR": two, "JWT_SIGNING_KID": "second"}, ok: true},
+		{name: "unknown kid", env: map[string]string{"JWT_KEYS_DIR": two, "JWT_SIGNING_KID": "third"}},
+		{name: "public key as the signing key", env: map[string]string{"JWT_KEYS_DIR": public, "JWT_SIGNING_KID": "retired"}},
+		{name: "rsa key too small", env: map[string]string{"JWT_KEYS_DIR": weak}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("SERVER_ENV", "test")
+			t.Setenv("JWT_KEYS_DIR", "")
+			t.Setenv("JWT_SIGNING_KID", "")
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			config.Load()
+
+			err := LoadKeys()
+			if tt.ok && err != nil {
+				t.Errorf("unexpected error %v", err)
+			}
+			if !tt.ok && err == nil {
+				t.Error("keys loaded, want an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pdridh/k-line/config"
+	"github.com/pkg/errors"
+)
+
+// A key used to sign and/or verify jwts. Keys with only a public part can still verify
+// tokens which is how retired keys are kept around while their tokens expire.
+type jwtKey struct {
+	ID      string
+	Method  jwt.SigningMethod
+	Private crypto.Signer
+	Public  crypto.PublicKey
+}
+
+type keySet struct {
+	signing *jwtKey
+	keys    map[string]*jwtKey
+}
+
+var keys *keySet
+
+// Loads the jwt keys from config.Server().JWTKeysDir. Every <kid>.pem file in the directory is
+// an RSA (RS256) or Ed25519 (EdDSA) private or public key, the file name being its kid.
+// The key with the kid JWTSigningKeyID signs new tokens, all of them verify tokens.
+// To rotate add a new key, point JWTSigningKeyID at it and keep the old one until its tokens expire.
+//
+// Without a keys dir tokens are signed with the JWTSecret, which is refused in production.
+func LoadKeys() error {
+	cfg := config.Server()
+
+	if cfg.JWTKeysDir == "" {
+		if cfg.Env == "production" {
+			return errors.New("JWT_KEYS_DIR must be set in production")
+		}
+
+		log.Println("JWT_KEYS_DIR is not set, signing jwts with JWT_SECRET")
+		keys = &keySet{keys: map[string]*jwtKey{}}
+		return nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(cfg.JWTKeysDir, "*.pem"))
+	if err != nil {
+		return errors.Wrap(err, "glob")
+	}
+
+	set := &keySet{keys: map[string]*jwtKey{}}
+	for _, f := range files {
+		k, err := parseKeyFile(f)
+		if err != nil {
+			return errors.Wrap(err, f)
+		}
+		set.keys[k.ID] = k
+	}
+
+	kid := cfg.JWTSigningKeyID
+	if kid == "" {
+		// Only guess the signing key when theres no choice to make
+		var private []*jwtKey
+		for _, k := range set.keys {
+			if k.Private != nil {
+				private = append(private, k)
+			}
+		}
+
+		if len(private) != 1 {
+			return errors.New("JWT_SIGNING_KID must be set when there isnt exactly one private key")
+		}
+		kid = private[0].ID
+	}
+
+	signing, ok := set.keys[kid]
+	if !ok || signing.Private == nil {
+		return errors.Errorf("no private key with kid %q", kid)
+	}
+	set.signing = signing
+
+	log.Printf("Loaded %d jwt keys, signing with %q", len(set.keys), kid)
+	keys = set
+	return nil
+}
+
+// Accessor for the loaded keys, exits the program if called before LoadKeys
+func loadedKeys() *keySet {
+	if keys == nil {
+		log.Fatal("Accessing jwt keys before loading")
+	}
+	return keys
+}
+
+func parseKeyFile(path string) (*jwtKey, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, errors.New("no pem block found")
+	}
+
+	k := &jwtKey{ID: strings.TrimSuffix(filepath.Base(path), ".pem")}
+
+	var parsed any
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return nil, errors.Errorf("unsupported pem block %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch key := parsed.(type) {
+	case *rsa.PrivateKey:
+		k.Method, k.Private, k.Public = jwt.SigningMethodRS256, key, &key.PublicKey
+	case *rsa.PublicKey:
+		k.Method, k.Public = jwt.SigningMethodRS256, key
+	case ed25519.PrivateKey:
+		k.Method, k.Private, k.Public = jwt.SigningMethodEdDSA, key, key.Public()
+	case ed25519.PublicKey:
+		k.Method, k.Public = jwt.SigningMethodEdDSA, key
+	default:
+		return nil, errors.Errorf("unsupported key type %T", parsed)
+	}
+
+	if pub, ok := k.Public.(*rsa.PublicKey); ok && pub.N.BitLen() < 2048 {
+		return nil, errors.New("rsa keys must be at least 2048 bits")
+	}
+
+	return k, nil
+}
+
+// A single public key in the JWKS format (RFC 7517)
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// Returns the public part of every loaded key so other services can verify our tokens
+func PublicJWKS() JWKS {
+	set := loadedKeys()
+
+	jwks := JWKS{Keys: []JWK{}}
+	for _, k := range set.keys {
+		jwk := JWK{Kid: k.ID, Use: "sig", Alg: k.Method.Alg()}
+
+		switch pub := k.Public.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		}
+
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+
+	sort.Slice(jwks.Keys, func(i, j int) bool { return jwks.Keys[i].Kid < jwks.Keys[j].Kid })
+
+	return jwks
+}
@@ -0,0 +1,30 @@
+package auth
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// Number of logins in a row without a right password before the account gets locked
+	MaxLoginAttempts = 5
+
+	// First lockout duration, every lockout after it doubles up to MaxLoginLockout
+	BaseLoginLockout = time.Minute
+	MaxLoginLockout  = 24 * time.Hour
+
+	// In memory limits on login requests, checked before touching the db
+	LoginIPLimit      = 30
+	LoginAccountLimit = 10
+	LoginLimitWindow  = 5 * time.Minute
+)
+
+// A real bcrypt hash that unknown emails are compared against,
+// so logging in with an unknown email takes as long as with a wrong password.
+var dummyHash = sync.OnceValue(func() string {
+	h, err := HashPassword("k-line-dummy-password")
+	if err != nil {
+		panic(err)
+	}
+	return h
+})
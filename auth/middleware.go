@@ -4,43 +4,169 @@ import (
 	"context"
 	"net/http"
 	"slices"
+	"strings"
 
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/pdridh/k-line/api"
-	"github.com/pdridh/k-line/db/sqlc"
+	"github.com/pdridh/k-line/db"
+	"github.com/pkg/errors"
 )
 
-// Takes a handler function and only calls it if
-// the jwt token it extracts from the request's is valid.
-// The next handler function is called with the userid in context
-func Middleware(next http.HandlerFunc, allowedTypes ...sqlc.UserType) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		jCookie, err := r.Cookie("jwt")
-		if err != nil {
+// Returns a middleware that takes a handler function and only calls it if
+// the request is authenticated (jwt cookie, Bearer jwt or Bearer api key), the user is still active and
+// has every one of the required permissions (superuser roles have all of them).
+// Requests that change something and are authenticated by the cookie also need a valid csrf token.
+// The permissions are looked up on every request so role changes apply immediately.
+// The next handler function is called with the CurrentUser in context
+func Middleware(store db.Store) func(next http.HandlerFunc, required ...Permission) http.HandlerFunc {
+	return func(next http.HandlerFunc, required ...Permission) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			user, err := currentUserFromRequest(r, store)
+			if err != nil {
+				switch {
+				case errors.Is(err, api.ErrJWTInvalid.Error):
+					api.WriteInvalidJWTError(w, r)
+					return
+				case errors.Is(err, api.ErrAPIKeyInvalid.Error):
+					api.WriteError(w, r, http.StatusUnauthorized, api.ErrAPIKeyInvalid, nil)
+					return
+				case errors.Is(err, api.ErrUserInactive.Error):
+					api.WriteError(w, r, http.StatusForbidden, api.ErrUserInactive, nil)
+					return
+				default:
+					api.WriteInternalError(w, r)
+					return
+				}
+			}
+
+			// Browsers attach the cookie to cross site requests, bearer tokens have to be added by the client
+			if !user.Bearer && !isSafeMethod(r.Method) && !validCSRF(r) {
+				api.WriteError(w, r, http.StatusForbidden, api.ErrCSRFInvalid, nil)
+				return
+			}
 
-			api.WriteInvalidJWTError(w, r)
-			return
+			if !user.Superuser {
+				for _, p := range required {
+					if !slices.Contains(user.Permissions, string(p)) {
+						api.WriteForbiddenError(w, r)
+						return
+					}
+				}
+			}
+
+			newCtx := context.WithValue(r.Context(), api.ContextUserKey, *user)
+			next(w, r.WithContext(newCtx))
 		}
+	}
+}
 
-		j := jCookie.Value
+// Works out who sent the request and what they are allowed to do
+func currentUserFromRequest(r *http.Request, store db.Store) (*api.CurrentUser, error) {
+	if key, ok := strings.CutPrefix(bearerToken(r), APIKeyPrefix); ok {
+		return currentUserFromAPIKey(r.Context(), store, key)
+	}
 
-		t, err := ValidateJWT(j)
-		if err != nil {
-			api.WriteInvalidJWTError(w, r)
-			return
+	c, err := userClaimsFromRequest(r)
+	if err != nil {
+		return nil, errors.Wrap(api.ErrJWTInvalid.Error, err.Error())
+	}
+
+	var userID pgtype.UUID
+	if err := userID.Scan(c.UserID); err != nil {
+		return nil, errors.Wrap(api.ErrJWTInvalid.Error, "claims")
+	}
+
+	a, err := store.GetUserAuthorization(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, errors.Wrap(api.ErrJWTInvalid.Error, "store")
 		}
+		return nil, errors.Wrap(err, "store")
+	}
 
-		c, err := UserClaimsFromJWT(t)
-		if err != nil {
-			api.WriteInvalidJWTError(w, r)
-			return
+	if !a.Active {
+		return nil, errors.Wrap(api.ErrUserInactive.Error, "store")
+	}
+
+	return &api.CurrentUser{
+		ID:          c.UserID,
+		Type:        a.Type,
+		RoleID:      a.RoleID,
+		Superuser:   a.Superuser,
+		Permissions: a.Permissions,
+		Bearer:      bearerToken(r) != "",
+	}, nil
+}
+
+// An api key acts as the user it was created for but only with the permissions
+// that both the key and the user's role have.
+func currentUserFromAPIKey(ctx context.Context, store db.Store, key string) (*api.CurrentUser, error) {
+	k, err := store.GetAPIKeyByHash(ctx, HashOpaqueToken(key))
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, errors.Wrap(api.ErrAPIKeyInvalid.Error, "store")
 		}
+		return nil, errors.Wrap(err, "store")
+	}
 
-		newCtx := context.WithValue(r.Context(), api.ContextUserKey, api.CurrentUser{ID: c.UserID, Type: c.UserType})
-		if c.UserType == sqlc.UserTypeAdmin || slices.Contains(allowedTypes, c.UserType) {
-			next(w, r.WithContext(newCtx))
-			return
+	a, err := store.GetUserAuthorization(ctx, k.UserID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, errors.Wrap(api.ErrAPIKeyInvalid.Error, "store")
 		}
+		return nil, errors.Wrap(err, "store")
+	}
+
+	if !a.Active {
+		return nil, errors.Wrap(api.ErrUserInactive.Error, "store")
+	}
+
+	if err := store.TouchAPIKey(ctx, k.ID); err != nil {
+		return nil, errors.Wrap(err, "store")
+	}
+
+	permissions := []string{}
+	for _, p := range k.Permissions {
+		if a.Superuser || slices.Contains(a.Permissions, p) {
+			permissions = append(permissions, p)
+		}
+	}
+
+	return &api.CurrentUser{
+		ID:          k.UserID.String(),
+		Type:        a.Type,
+		RoleID:      a.RoleID,
+		Permissions: permissions,
+		Bearer:      true,
+	}, nil
+}
 
-		api.WriteError(w, r, http.StatusForbidden, api.ErrHTTPForbidden, nil)
+// Returns the token from an "Authorization: Bearer <token>" header, or "" if there isnt one
+func bearerToken(r *http.Request) string {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
 	}
+
+	return strings.TrimSpace(token)
+}
+
+// Extracts the jwt from the request's Authorization header, or the jwt cookie if there is no header,
+// validates it and returns its claims
+func userClaimsFromRequest(r *http.Request) (*UserClaims, error) {
+	j := bearerToken(r)
+	if j == "" {
+		jCookie, err := r.Cookie("jwt")
+		if err != nil {
+			return nil, err
+		}
+		j = jCookie.Value
+	}
+
+	t, err := ValidateJWT(j)
+	if err != nil {
+		return nil, err
+	}
+
+	return UserClaimsFromJWT(t)
 }
@@ -0,0 +1,132 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pdridh/k-line/api"
+	"github.com/pdridh/k-line/db/sqlc"
+)
+
+// Sends r through the middleware requiring the permissions, returns the response and the user
+// the handler got or nil when it wasnt called
+func serveAuthorized(store *fakeStore, r *http.Request, required ...Permission) (*httptest.ResponseRecorder, *api.CurrentUser) {
+	var user *api.CurrentUser
+	next := func(w http.ResponseWriter, r *http.Request) {
+		u := r.Context().Value(api.ContextUserKey).(api.CurrentUser)
+		user = &u
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	w := httptest.NewRecorder()
+	Middleware(store)(next, required...)(w, r)
+	return w, user
+}
+
+func TestMiddlewareAPIKey(t *testing.T) {
+	loadConfig(t, nil)
+
+	store := testAuthorizations()
+	store.apiKeys = map[string]sqlc.ApiKey{
+		HashOpaqueToken("manager"):  {ID: testUUID(11), UserID: testUUID(2), Permissions: []string{"menu.read", "report.view"}},
+		HashOpaqueToken("admin"):    {ID: testUUID(12), UserID: testUUID(1), Permissions: []string{"menu.read"}},
+		HashOpaqueToken("inactive"): {ID: testUUID(13), UserID: testUUID(4), Permissions: []string{"menu.read"}},
+	}
+
+	tests := []struct {
+		name        string
+		method      string
+		key         string
+		required    []Permission
+		status      int
+		code        string
+		permissions []string
+	}{
+		{name: "permission of the key and the user", method: "GET", key: "manager", required: []Permission{PermMenuRead}, status: http.StatusNoContent, permissions: []string{"menu.read"}},
+		{name: "permission the user lost", method: "GET", key: "manager", required: []Permission{PermReportView}, status: http.StatusForbidden, code: api.ErrHTTPForbidden.Code},
+		{name: "permission the key wasnt given", method: "GET", key: "manager", required: []Permission{PermMenuWrite}, status: http.StatusForbidden, code: api.ErrHTTPForbidden.Code},
+		{name: "superuser key only has its permissions", method: "GET", key: "admin", required: []Permission{PermUserManage}, status: http.StatusForbidden, code: api.ErrHTTPForbidden.Code},
+		{name: "no csrf token needed", method: "POST", key: "manager", required: []Permission{PermMenuRead}, status: http.StatusNoContent, permissions: []string{"menu.read"}},
+		{name: "unknown key", method: "GET", key: "unknown", status: http.StatusUnauthorized, code: api.ErrAPIKeyInvalid.Code},
+		{name: "deactivated user", method: "GET", key: "inactive", status: http.StatusForbidden, code: api.ErrUserInactive.Code},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, "/menu", nil)
+			r.Header.Set("Authorization", "Bearer "+APIKeyPrefix+tt.key)
+
+			w, user := serveAuthorized(store, r, tt.required...)
+			if w.Code != tt.status {
+				t.Fatalf("got status %d, want %d", w.Code, tt.status)
+			}
+
+			if tt.code != "" && !strings.Contains(w.Body.String(), tt.code) {
+				t.Errorf("got body %s, want code %s", w.Body.String(), tt.code)
+			}
+
+			if tt.permissions != nil {
+				if user.Superuser || !user.Bearer || !slices.Equal(user.Permissions, tt.permissions) {
+					t.Errorf("got user %+v, want a bearer with permissions %v", user, tt.permissions)
+				}
+			}
+		})
+	}
+}
+
+func TestMiddlewareCSRF(t *testing.T) {
+	loadConfig(t, nil)
+
+	store := testAuthorizations()
+	token, err := GenerateJWT(testUUID(3).String(), "waiter@example.com", "Waiter", sqlc.UserTypeWaiter, time.Hour)
+	if err != nil {
+		t.Fatalf("cannot generate jwt: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		bearer bool
+		cookie string
+		header string
+		status int
+	}{
+		{name: "reading needs no token", method: "GET", status: http.StatusNoContent},
+		{name: "no token", method: "POST", status: http.StatusForbidden},
+		{name: "no header", method: "DELETE", cookie: "abc", status: http.StatusForbidden},
+		{name: "no cookie", method: "PUT", header: "abc", status: http.StatusForbidden},
+		{name: "header not matching the cookie", method: "POST", cookie: "abc", header: "abd", status: http.StatusForbidden},
+		{name: "header matching the cookie", method: "POST", cookie: "abc", header: "abc", status: http.StatusNoContent},
+		{name: "bearer jwt needs no token", method: "POST", bearer: true, status: http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, "/dining", nil)
+			if tt.bearer {
+				r.Header.Set("Authorization", "Bearer "+token)
+			} else {
+				r.AddCookie(&http.Cookie{Name: "jwt", Value: token})
+			}
+			if tt.cookie != "" {
+				r.AddCookie(&http.Cookie{Name: CSRFCookieName, Value: tt.cookie})
+			}
+			if tt.header != "" {
+				r.Header.Set(CSRFHeader, tt.header)
+			}
+
+			w, _ := serveAuthorized(store, r)
+			if w.Code != tt.status {
+				t.Fatalf("got status %d, want %d", w.Code, tt.status)
+			}
+
+			if tt.status == http.StatusForbidden && !strings.Contains(w.Body.String(), api.ErrCSRFInvalid.Code) {
+				t.Errorf("got body %s, want code %s", w.Body.String(), api.ErrCSRFInvalid.Code)
+			}
+		})
+	}
+}
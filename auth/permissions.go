@@ -0,0 +1,60 @@
+package auth
+
+import "slices"
+
+// A named permission that routes require, roles are granted a set of these.
+type Permission string
+
+const (
+	PermMenuRead        Permission = "menu.read"
+	PermMenuWrite       Permission = "menu.write"
+	PermTableRead       Permission = "table.read"
+	PermOrderRead       Permission = "order.read"
+	PermOrderCreate     Permission = "order.create"
+	PermOrderItemUpdate Permission = "order.item.update"
+	PermOrderVoid       Permission = "order.void"
+	PermReportView      Permission = "report.view"
+	PermUserManage      Permission = "user.manage"
+	PermDeviceManage    Permission = "device.manage"
+	PermRoleManage      Permission = "role.manage"
+	PermAPIKeyManage    Permission = "apikey.manage"
+	PermShiftManage     Permission = "shift.manage"
+	PermCashManage      Permission = "cash.manage"
+	PermInventoryManage Permission = "inventory.manage"
+	PermPurchaseManage  Permission = "purchase.manage"
+	PermCustomerManage  Permission = "customer.manage"
+	PermLoyaltyRedeem   Permission = "loyalty.redeem"
+	PermLoyaltyManage   Permission = "loyalty.manage"
+	PermGiftCardManage  Permission = "giftcard.manage"
+	PermPromotionManage Permission = "promotion.manage"
+)
+
+// Every permission known to the server, roles can only be granted these
+var AllPermissions = []Permission{
+	PermMenuRead,
+	PermMenuWrite,
+	PermTableRead,
+	PermOrderRead,
+	PermOrderCreate,
+	PermOrderItemUpdate,
+	PermOrderVoid,
+	PermReportView,
+	PermUserManage,
+	PermDeviceManage,
+	PermRoleManage,
+	PermAPIKeyManage,
+	PermShiftManage,
+	PermCashManage,
+	PermInventoryManage,
+	PermPurchaseManage,
+	PermCustomerManage,
+	PermLoyaltyRedeem,
+	PermLoyaltyManage,
+	PermGiftCardManage,
+	PermPromotionManage,
+}
+
+// Reports whether p is one of AllPermissions
+func IsValidPermission(p string) bool {
+	return slices.Contains(AllPermissions, Permission(p))
+}
@@ -0,0 +1,14 @@
+package auth
+
+import "time"
+
+const (
+	// Header that trusted terminals send their device token in
+	DeviceTokenHeader = "X-Device-Token"
+
+	// Number of wrong pins in a row before pin login is locked for the user
+	MaxPINAttempts = 5
+
+	// How long pin login stays locked after MaxPINAttempts wrong pins
+	PINLockoutDuration = 15 * time.Minute
+)
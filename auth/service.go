@@ -2,8 +2,11 @@ package auth
 
 import (
 	"context"
+	"slices"
+	"strings"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/pdridh/k-line/api"
 	"github.com/pdridh/k-line/config"
 	"github.com/pdridh/k-line/db"
@@ -25,7 +28,9 @@ func NewService(v *validator.Validate, u db.Store) *service {
 	}
 }
 
-func (s *service) CreateUser(ctx context.Context, email string, name string, userType sqlc.UserType, password string) (*User, error) {
+// Creates a user with the given role, if roleID is 0 the role named after the userType is used.
+// actorID is the user doing the registration, see checkAssignable for the roles they can give.
+func (s *service) CreateUser(ctx context.Context, actorID pgtype.UUID, email string, name string, userType sqlc.UserType, roleID int32, password string) (*User, error) {
 	// Hash password
 	var u sqlc.User
 	hashedPassword, err := HashPassword(password)
@@ -33,36 +38,103 @@ func (s *service) CreateUser(ctx context.Context, email string, name string, use
 		return nil, errors.Wrap(err, "hash")
 	}
 
+	if roleID == 0 {
+		r, err := s.Store.GetRoleByName(ctx, string(userType))
+		if err != nil {
+			if errors.Is(err, db.ErrRecordNotFound) {
+				return nil, errors.Wrap(api.ErrUnknownRole.Error, "store")
+			}
+			return nil, errors.Wrap(err, "store")
+		}
+		roleID = r.ID
+	}
+
+	if err := s.checkAssignable(ctx, actorID, roleID, userType); err != nil {
+		return nil, err
+	}
+
 	arg := sqlc.CreateUserParams{
 		Email:    email,
 		Name:     name,
 		Type:     userType,
 		Password: hashedPassword,
+		RoleID:   roleID,
 	}
 
 	u, err = s.Store.CreateUser(ctx, arg)
 	if err != nil {
-		errCode := db.GetSQLErrorCode(err)
-		if errCode == db.ForeignKeyViolation || errCode == db.UniqueViolation {
+		switch db.GetSQLErrorCode(err) {
+		case db.UniqueViolation:
 			return nil, errors.Wrap(api.ErrEmailAlreadyExists.Error, "store")
+		case db.ForeignKeyViolation:
+			return nil, errors.Wrap(api.ErrUnknownRole.Error, "store")
 		}
+		return nil, errors.Wrap(err, "store")
 	}
 
-	user := &User{
-		ID:        u.ID,
-		Email:     u.Email,
-		Name:      u.Name,
-		Type:      u.Type,
-		CreatedAt: u.CreatedAt,
+	return newUser(u), nil
+}
+
+// Creates the very first admin account. This only succeeds while no user has a superuser role,
+// after that every new account has to be registered by an admin.
+func (s *service) BootstrapAdmin(ctx context.Context, email string, name string, password string) (*User, error) {
+	hashedPassword, err := HashPassword(password)
+	if err != nil {
+		return nil, errors.Wrap(err, "hash")
 	}
 
-	return user, nil
+	arg := sqlc.CreateFirstAdminParams{
+		Email:    email,
+		Name:     name,
+		Password: hashedPassword,
+	}
+
+	u, err := s.Store.CreateFirstAdminTx(ctx, arg)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, errors.Wrap(api.ErrBootstrapClosed.Error, "store")
+		}
+		if db.GetSQLErrorCode(err) == db.UniqueViolation {
+			return nil, errors.Wrap(api.ErrEmailAlreadyExists.Error, "store")
+		}
+		return nil, errors.Wrap(err, "store")
+	}
+
+	return newUser(u), nil
 }
 
+// Checks the email and password and returns a jwt for the user.
+// Attempts are counted per email (known or not) and lock the email out after MaxLoginAttempts,
+// each lockout lasting twice as long as the previous one.
 func (s *service) AuthenticateUser(ctx context.Context, email string, password string) (string, *User, error) {
+	key := strings.ToLower(email)
+
+	// The attempt is counted before the slow compare, otherwise a burst of parallel guesses would all
+	// get checked before any of them was recorded
+	arg := sqlc.ClaimLoginAttemptParams{
+		Email:              key,
+		MaxAttempts:        MaxLoginAttempts,
+		BaseLockoutSeconds: int32(BaseLoginLockout.Seconds()),
+		MaxLockoutSeconds:  int32(MaxLoginLockout.Seconds()),
+	}
+
+	locked, err := s.Store.ClaimLoginAttempt(ctx, arg)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return "", nil, errors.Wrap(api.ErrLoginLocked.Error, "store")
+		}
+		return "", nil, errors.Wrap(err, "store")
+	}
+
 	u, err := s.Store.GetUserByEmail(ctx, email)
 	if err != nil {
 		if errors.Is(err, db.ErrRecordNotFound) {
+			// Still do the bcrypt work so the response time doesnt tell which emails exist
+			CompareHashedPasswords(dummyHash(), password)
+
+			if locked {
+				return "", nil, errors.Wrap(api.ErrLoginLocked.Error, "store")
+			}
 			return "", nil, errors.Wrap(api.ErrUnknownEmail.Error, "store")
 		}
 		return "", nil, errors.Wrap(err, "store")
@@ -72,24 +144,657 @@ func (s *service) AuthenticateUser(ctx context.Context, email string, password s
 	if err := CompareHashedPasswords(u.Password, password); err != nil {
 		switch err {
 		case bcrypt.ErrMismatchedHashAndPassword:
+			if locked {
+				return "", nil, errors.Wrap(api.ErrLoginLocked.Error, "hash")
+			}
 			return "", nil, errors.Wrap(api.ErrWrongPassword.Error, "hash")
 		default:
 			return "", nil, errors.Wrap(err, "hash")
 		}
 	}
 
+	if err := s.Store.ClearLoginLockout(ctx, key); err != nil {
+		return "", nil, errors.Wrap(err, "store")
+	}
+
+	// Only tell deactivated users about their status after they proved who they are
+	if !u.Active {
+		return "", nil, errors.Wrap(api.ErrUserInactive.Error, "store")
+	}
+
+	t, err := GenerateJWT(u.ID.String(), u.Email, u.Name, u.Type, config.Server().JWTExpiration)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "jwtgen")
+	}
+
+	return t, newUser(u), nil
+}
+
+// Returns a page of users and how many there are in total
+func (s *service) GetUsers(ctx context.Context, limit int32, offset int32) ([]User, int, error) {
+	u, err := s.Store.GetUsers(ctx, sqlc.GetUsersParams{Limit: limit, Offset: offset})
+	if err != nil {
+		return []User{}, 0, errors.Wrap(err, "store")
+	}
+
+	total, err := s.Store.CountUsers(ctx)
+	if err != nil {
+		return []User{}, 0, errors.Wrap(err, "store")
+	}
+
+	users := []User{}
+	for _, user := range u {
+		users = append(users, *newUser(user))
+	}
+
+	return users, int(total), nil
+}
+
+func (s *service) GetUserByID(ctx context.Context, id pgtype.UUID) (*User, error) {
+	u, err := s.Store.GetUserByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, errors.Wrap(api.ErrUnknownUser.Error, "store")
+		}
+		return nil, errors.Wrap(err, "store")
+	}
+
+	return newUser(u), nil
+}
+
+// Updates the name, type and role of the user with the given id.
+// actorID is the admin doing the change, admins cannot change their own type or role so they cant lock themselves out.
+func (s *service) UpdateUser(ctx context.Context, actorID pgtype.UUID, id pgtype.UUID, name string, userType sqlc.UserType, roleID int32) (*User, error) {
+	if actorID == id {
+		current, err := s.GetUserByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		if current.Type != userType || current.RoleID != roleID {
+			return nil, errors.Wrap(api.ErrCannotModifySelf.Error, "self")
+		}
+	}
+
+	if err := s.checkManageable(ctx, actorID, id); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkAssignable(ctx, actorID, roleID, userType); err != nil {
+		return nil, err
+	}
+
+	u, err := s.Store.UpdateUser(ctx, sqlc.UpdateUserParams{Name: name, Type: userType, RoleID: roleID, ID: id})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, errors.Wrap(api.ErrUnknownUser.Error, "store")
+		}
+		if db.GetSQLErrorCode(err) == db.ForeignKeyViolation {
+			return nil, errors.Wrap(api.ErrUnknownRole.Error, "store")
+		}
+		return nil, errors.Wrap(err, "store")
+	}
+
+	return newUser(u), nil
+}
+
+// Activates or deactivates the user with the given id. Deactivated users cannot login.
+func (s *service) SetUserActive(ctx context.Context, actorID pgtype.UUID, id pgtype.UUID, active bool) (*User, error) {
+	if actorID == id {
+		return nil, errors.Wrap(api.ErrCannotModifySelf.Error, "self")
+	}
+
+	if err := s.checkManageable(ctx, actorID, id); err != nil {
+		return nil, err
+	}
+
+	u, err := s.Store.SetUserActive(ctx, sqlc.SetUserActiveParams{Active: active, ID: id})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, errors.Wrap(api.ErrUnknownUser.Error, "store")
+		}
+		return nil, errors.Wrap(err, "store")
+	}
+
+	return newUser(u), nil
+}
+
+// Replaces the password of the user with the given id with the hash of password
+func (s *service) ResetPassword(ctx context.Context, actorID pgtype.UUID, id pgtype.UUID, password string) error {
+	if err := s.checkManageable(ctx, actorID, id); err != nil {
+		return err
+	}
+
+	hashedPassword, err := HashPassword(password)
+	if err != nil {
+		return errors.Wrap(err, "hash")
+	}
+
+	n, err := s.Store.UpdateUserPassword(ctx, sqlc.UpdateUserPasswordParams{Password: hashedPassword, ID: id})
+	if err != nil {
+		return errors.Wrap(err, "store")
+	}
+
+	if n == 0 {
+		return errors.Wrap(api.ErrUnknownUser.Error, "store")
+	}
+
+	return nil
+}
+
+// Sets (or replaces) the pin of the user, this also clears any pin lockout. actorID is the user
+// setting it, which is userID itself when users set their own pin.
+func (s *service) SetPIN(ctx context.Context, actorID pgtype.UUID, userID pgtype.UUID, pin string) error {
+	if err := s.checkManageable(ctx, actorID, userID); err != nil {
+		return err
+	}
+
+	hashedPIN, err := HashPassword(pin)
+	if err != nil {
+		return errors.Wrap(err, "hash")
+	}
+
+	if err := s.Store.UpsertUserPIN(ctx, sqlc.UpsertUserPINParams{UserID: userID, Pin: hashedPIN}); err != nil {
+		if db.GetSQLErrorCode(err) == db.ForeignKeyViolation {
+			return errors.Wrap(api.ErrUnknownUser.Error, "store")
+		}
+		return errors.Wrap(err, "store")
+	}
+
+	return nil
+}
+
+// Registers a new trusted device and returns it along with its token.
+// The token is only returned here, the store only keeps its hash.
+func (s *service) RegisterDevice(ctx context.Context, name string, registeredBy pgtype.UUID) (*Device, string, error) {
+	token, err := GenerateOpaqueToken()
+	if err != nil {
+		return nil, "", errors.Wrap(err, "token")
+	}
+
+	arg := sqlc.CreateDeviceParams{
+		Name:         name,
+		TokenHash:    HashOpaqueToken(token),
+		RegisteredBy: registeredBy,
+	}
+
+	d, err := s.Store.CreateDevice(ctx, arg)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "store")
+	}
+
+	return newDevice(d), token, nil
+}
+
+func (s *service) GetDevices(ctx context.Context) ([]Device, error) {
+	d, err := s.Store.GetDevices(ctx)
+	if err != nil {
+		return []Device{}, errors.Wrap(err, "store")
+	}
+
+	devices := []Device{}
+	for _, device := range d {
+		devices = append(devices, *newDevice(device))
+	}
+
+	return devices, nil
+}
+
+func (s *service) RevokeDevice(ctx context.Context, id pgtype.UUID) error {
+	n, err := s.Store.RevokeDevice(ctx, id)
+	if err != nil {
+		return errors.Wrap(err, "store")
+	}
+
+	if n == 0 {
+		return errors.Wrap(api.ErrUnknownDevice.Error, "store")
+	}
+
+	return nil
+}
+
+// Checks that the token belongs to a registered device that hasnt been revoked
+func (s *service) AuthenticateDevice(ctx context.Context, token string) (*Device, error) {
+	if token == "" {
+		return nil, errors.Wrap(api.ErrDeviceUntrusted.Error, "token")
+	}
+
+	d, err := s.Store.GetDeviceByTokenHash(ctx, HashOpaqueToken(token))
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, errors.Wrap(api.ErrDeviceUntrusted.Error, "store")
+		}
+		return nil, errors.Wrap(err, "store")
+	}
+
+	if err := s.Store.TouchDevice(ctx, d.ID); err != nil {
+		return nil, errors.Wrap(err, "store")
+	}
+
+	return newDevice(d), nil
+}
+
+// Returns the active users that have a pin set, used by devices to show who can switch in
+func (s *service) GetPINUsers(ctx context.Context) ([]PINUser, error) {
+	u, err := s.Store.GetPINUsers(ctx)
+	if err != nil {
+		return []PINUser{}, errors.Wrap(err, "store")
+	}
+
+	users := []PINUser{}
+	for _, user := range u {
+		users = append(users, PINUser{ID: user.ID, Name: user.Name, Type: user.Type})
+	}
+
+	return users, nil
+}
+
+// Same as AuthenticateUser but with the user id and pin instead of email and password.
+// Every attempt is counted and after MaxPINAttempts wrong ones the pin login is locked for PINLockoutDuration.
+func (s *service) AuthenticatePIN(ctx context.Context, userID pgtype.UUID, pin string) (string, *User, error) {
+	p, err := s.Store.GetUserPIN(ctx, userID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return "", nil, errors.Wrap(api.ErrPINInvalid.Error, "store")
+		}
+		return "", nil, errors.Wrap(err, "store")
+	}
+
+	if p.Locked {
+		return "", nil, errors.Wrap(api.ErrPINLocked.Error, "store")
+	}
+
+	// The attempt is counted before the slow compare, otherwise a burst of parallel guesses would all
+	// get checked before any of them was recorded
+	arg := sqlc.ClaimPINAttemptParams{
+		MaxAttempts:    MaxPINAttempts,
+		LockoutSeconds: int32(PINLockoutDuration.Seconds()),
+		UserID:         userID,
+	}
+
+	a, err := s.Store.ClaimPINAttempt(ctx, arg)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return "", nil, errors.Wrap(api.ErrPINLocked.Error, "store")
+		}
+		return "", nil, errors.Wrap(err, "store")
+	}
+
+	if err := CompareHashedPasswords(a.Pin, pin); err != nil {
+		if err != bcrypt.ErrMismatchedHashAndPassword {
+			return "", nil, errors.Wrap(err, "hash")
+		}
+
+		if a.Locked {
+			return "", nil, errors.Wrap(api.ErrPINLocked.Error, "hash")
+		}
+		return "", nil, errors.Wrap(api.ErrPINInvalid.Error, "hash")
+	}
+
+	if err := s.Store.ResetPINFailures(ctx, userID); err != nil {
+		return "", nil, errors.Wrap(err, "store")
+	}
+
+	u, err := s.Store.GetUserByID(ctx, userID)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "store")
+	}
+
+	if !u.Active {
+		return "", nil, errors.Wrap(api.ErrUserInactive.Error, "store")
+	}
+
 	t, err := GenerateJWT(u.ID.String(), u.Email, u.Name, u.Type, config.Server().JWTExpiration)
 	if err != nil {
 		return "", nil, errors.Wrap(err, "jwtgen")
 	}
 
-	user := &User{
+	return t, newUser(u), nil
+}
+
+// Returns the role and permissions of the user, used to tell clients what the user can do
+func (s *service) GetUserPermissions(ctx context.Context, id pgtype.UUID) (int32, []string, error) {
+	a, err := s.Store.GetUserAuthorization(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return 0, nil, errors.Wrap(api.ErrUnknownUser.Error, "store")
+		}
+		return 0, nil, errors.Wrap(err, "store")
+	}
+
+	if a.Superuser {
+		permissions := []string{}
+		for _, p := range AllPermissions {
+			permissions = append(permissions, string(p))
+		}
+		return a.RoleID, permissions, nil
+	}
+
+	return a.RoleID, a.Permissions, nil
+}
+
+func (s *service) GetRoles(ctx context.Context) ([]Role, error) {
+	r, err := s.Store.GetRoles(ctx)
+	if err != nil {
+		return []Role{}, errors.Wrap(err, "store")
+	}
+
+	roles := []Role{}
+	for _, role := range r {
+		roles = append(roles, Role{
+			ID:          role.ID,
+			Name:        role.Name,
+			Description: role.Description,
+			Superuser:   role.Superuser,
+			Permissions: role.Permissions,
+			CreatedAt:   role.CreatedAt,
+		})
+	}
+
+	return roles, nil
+}
+
+func (s *service) CreateRole(ctx context.Context, name string, description pgtype.Text, permissions []string) (*Role, error) {
+	if err := validatePermissions(permissions); err != nil {
+		return nil, err
+	}
+
+	r, err := s.Store.CreateRoleTx(ctx, sqlc.CreateRoleParams{Name: name, Description: description}, permissions)
+	if err != nil {
+		if db.GetSQLErrorCode(err) == db.UniqueViolation {
+			return nil, errors.Wrap(api.ErrRoleNameConflict.Error, "store")
+		}
+		return nil, errors.Wrap(err, "store")
+	}
+
+	return newRole(r, permissions), nil
+}
+
+// Updates the role and replaces its permissions, superuser roles cannot be updated.
+func (s *service) UpdateRole(ctx context.Context, id int32, name string, description pgtype.Text, permissions []string) (*Role, error) {
+	if err := validatePermissions(permissions); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkRoleEditable(ctx, id); err != nil {
+		return nil, err
+	}
+
+	arg := sqlc.UpdateRoleParams{
+		Name:        name,
+		Description: description,
+		ID:          id,
+	}
+
+	r, err := s.Store.UpdateRoleTx(ctx, arg, permissions)
+	if err != nil {
+		if db.GetSQLErrorCode(err) == db.UniqueViolation {
+			return nil, errors.Wrap(api.ErrRoleNameConflict.Error, "store")
+		}
+		return nil, errors.Wrap(err, "store")
+	}
+
+	return newRole(r, permissions), nil
+}
+
+// Deletes the role, fails if it is a superuser role or if users still have it.
+func (s *service) DeleteRole(ctx context.Context, id int32) error {
+	if err := s.checkRoleEditable(ctx, id); err != nil {
+		return err
+	}
+
+	if _, err := s.Store.DeleteRole(ctx, id); err != nil {
+		if db.GetSQLErrorCode(err) == db.ForeignKeyViolation {
+			return errors.Wrap(api.ErrRoleInUse.Error, "store")
+		}
+		return errors.Wrap(err, "store")
+	}
+
+	return nil
+}
+
+// Returns an error if the role doesnt exist or is a superuser role
+func (s *service) checkRoleEditable(ctx context.Context, id int32) error {
+	r, err := s.Store.GetRoleByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return errors.Wrap(api.ErrUnknownRole.Error, "store")
+		}
+		return errors.Wrap(err, "store")
+	}
+
+	if r.Superuser {
+		return errors.Wrap(api.ErrRoleProtected.Error, "store")
+	}
+
+	return nil
+}
+
+// Returns ErrRoleNotAssignable unless the actor may give someone roleID and userType. Superusers can
+// assign anything, everyone else only roles that grant a subset of their own permissions and never a
+// superuser role or the admin type.
+func (s *service) checkAssignable(ctx context.Context, actorID pgtype.UUID, roleID int32, userType sqlc.UserType) error {
+	actor, err := s.Store.GetUserAuthorization(ctx, actorID)
+	if err != nil {
+		return errors.Wrap(err, "store")
+	}
+
+	if actor.Superuser {
+		return nil
+	}
+
+	if userType == sqlc.UserTypeAdmin {
+		return errors.Wrap(api.ErrRoleNotAssignable.Error, "type")
+	}
+
+	r, err := s.Store.GetRoleByID(ctx, roleID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return errors.Wrap(api.ErrUnknownRole.Error, "store")
+		}
+		return errors.Wrap(err, "store")
+	}
+
+	if r.Superuser {
+		return errors.Wrap(api.ErrRoleNotAssignable.Error, "superuser")
+	}
+
+	permissions, err := s.Store.GetRolePermissions(ctx, roleID)
+	if err != nil {
+		return errors.Wrap(err, "store")
+	}
+
+	for _, p := range permissions {
+		if !slices.Contains(actor.Permissions, p) {
+			return errors.Wrap(api.ErrRoleNotAssignable.Error, p)
+		}
+	}
+
+	return nil
+}
+
+// Returns ErrUserProtected when the user with the given id is a superuser and the actor isnt, otherwise
+// anyone that can manage users could take over a superuser account by changing its password or pin
+func (s *service) checkManageable(ctx context.Context, actorID pgtype.UUID, id pgtype.UUID) error {
+	target, err := s.Store.GetUserAuthorization(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return errors.Wrap(api.ErrUnknownUser.Error, "store")
+		}
+		return errors.Wrap(err, "store")
+	}
+
+	if !target.Superuser || actorID == id {
+		return nil
+	}
+
+	actor, err := s.Store.GetUserAuthorization(ctx, actorID)
+	if err != nil {
+		return errors.Wrap(err, "store")
+	}
+
+	if !actor.Superuser {
+		return errors.Wrap(api.ErrUserProtected.Error, "store")
+	}
+
+	return nil
+}
+
+func validatePermissions(permissions []string) error {
+	for _, p := range permissions {
+		if !IsValidPermission(p) {
+			return errors.Wrap(api.ErrUnknownPermission.Error, p)
+		}
+	}
+
+	return nil
+}
+
+func newRole(r sqlc.Role, permissions []string) *Role {
+	return &Role{
+		ID:          r.ID,
+		Name:        r.Name,
+		Description: r.Description,
+		Superuser:   r.Superuser,
+		Permissions: permissions,
+		CreatedAt:   r.CreatedAt,
+	}
+}
+
+// Clears the login and pin lockouts of the user so they can try again right away
+func (s *service) UnlockUser(ctx context.Context, id pgtype.UUID) error {
+	u, err := s.Store.GetUserByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return errors.Wrap(api.ErrUnknownUser.Error, "store")
+		}
+		return errors.Wrap(err, "store")
+	}
+
+	if err := s.Store.ClearLoginLockout(ctx, strings.ToLower(u.Email)); err != nil {
+		return errors.Wrap(err, "store")
+	}
+
+	if err := s.Store.ResetPINFailures(ctx, id); err != nil {
+		return errors.Wrap(err, "store")
+	}
+
+	return nil
+}
+
+// Creates an api key that acts as the user with the given permissions (only the ones the user's role has apply).
+// Unless createdBy is a superuser the permissions have to be ones they have themselves and the user cant be a superuser.
+// The key is only returned here, the store only keeps its hash.
+func (s *service) CreateAPIKey(ctx context.Context, name string, userID pgtype.UUID, permissions []string, expiresInDays int32, createdBy pgtype.UUID) (*APIKey, string, error) {
+	if err := validatePermissions(permissions); err != nil {
+		return nil, "", err
+	}
+
+	creator, err := s.Store.GetUserAuthorization(ctx, createdBy)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "store")
+	}
+
+	if !creator.Superuser {
+		for _, p := range permissions {
+			if !slices.Contains(creator.Permissions, p) {
+				return nil, "", errors.Wrap(api.ErrPermissionNotGranted.Error, p)
+			}
+		}
+
+		if err := s.checkManageable(ctx, createdBy, userID); err != nil {
+			return nil, "", err
+		}
+	}
+
+	token, err := GenerateOpaqueToken()
+	if err != nil {
+		return nil, "", errors.Wrap(err, "token")
+	}
+
+	arg := sqlc.CreateAPIKeyParams{
+		Name:          name,
+		KeyHash:       HashOpaqueToken(token),
+		Prefix:        token[:8],
+		UserID:        userID,
+		Permissions:   permissions,
+		CreatedBy:     createdBy,
+		ExpiresInDays: expiresInDays,
+	}
+
+	k, err := s.Store.CreateAPIKey(ctx, arg)
+	if err != nil {
+		if db.GetSQLErrorCode(err) == db.ForeignKeyViolation {
+			return nil, "", errors.Wrap(api.ErrUnknownUser.Error, "store")
+		}
+		return nil, "", errors.Wrap(err, "store")
+	}
+
+	return newAPIKey(k), APIKeyPrefix + token, nil
+}
+
+func (s *service) GetAPIKeys(ctx context.Context) ([]APIKey, error) {
+	k, err := s.Store.GetAPIKeys(ctx)
+	if err != nil {
+		return []APIKey{}, errors.Wrap(err, "store")
+	}
+
+	keys := []APIKey{}
+	for _, key := range k {
+		keys = append(keys, *newAPIKey(key))
+	}
+
+	return keys, nil
+}
+
+func (s *service) RevokeAPIKey(ctx context.Context, id pgtype.UUID) error {
+	n, err := s.Store.RevokeAPIKey(ctx, id)
+	if err != nil {
+		return errors.Wrap(err, "store")
+	}
+
+	if n == 0 {
+		return errors.Wrap(api.ErrUnknownAPIKey.Error, "store")
+	}
+
+	return nil
+}
+
+func newAPIKey(k sqlc.ApiKey) *APIKey {
+	return &APIKey{
+		ID:          k.ID,
+		Name:        k.Name,
+		Prefix:      APIKeyPrefix + k.Prefix,
+		UserID:      k.UserID,
+		Permissions: k.Permissions,
+		CreatedBy:   k.CreatedBy,
+		ExpiresAt:   k.ExpiresAt,
+		CreatedAt:   k.CreatedAt,
+		LastUsedAt:  k.LastUsedAt,
+		RevokedAt:   k.RevokedAt,
+	}
+}
+
+func newDevice(d sqlc.Device) *Device {
+	return &Device{
+		ID:           d.ID,
+		Name:         d.Name,
+		RegisteredBy: d.RegisteredBy,
+		CreatedAt:    d.CreatedAt,
+		LastSeenAt:   d.LastSeenAt,
+		RevokedAt:    d.RevokedAt,
+	}
+}
+
+// Converts the db row into a User, leaving out the password hash
+func newUser(u sqlc.User) *User {
+	return &User{
 		ID:        u.ID,
 		Email:     u.Email,
 		Name:      u.Name,
 		Type:      u.Type,
+		RoleID:    u.RoleID,
+		Active:    u.Active,
 		CreatedAt: u.CreatedAt,
 	}
-
-	return t, user, nil
 }
@@ -0,0 +1,398 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pdridh/k-line/api"
+	"github.com/pdridh/k-line/config"
+	"github.com/pdridh/k-line/db"
+	"github.com/pdridh/k-line/db/sqlc"
+	"github.com/pkg/errors"
+)
+
+// Loads a config that signs jwts with the JWTSecret, env overrides the defaults
+func loadConfig(t *testing.T, env map[string]string) {
+	t.Helper()
+
+	t.Setenv("SERVER_ENV", "test")
+	for k, v := range env {
+		t.Setenv(k, v)
+	}
+	config.Load()
+
+	if err := LoadKeys(); err != nil {
+		t.Fatalf("cannot load keys: %v", err)
+	}
+}
+
+// Service tests run against a migrated database at TEST_DATABASE_URI and are skipped without one.
+// They leave their rows behind, every email they create is unique so they can be run again.
+func testService(t *testing.T) *service {
+	t.Helper()
+
+	uri := os.Getenv("TEST_DATABASE_URI")
+	if uri == "" {
+		t.Skip("TEST_DATABASE_URI is not set")
+	}
+
+	pool, err := pgxpool.New(context.Background(), uri)
+	if err != nil {
+		t.Fatalf("cannot create connection pool: %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	loadConfig(t, nil)
+
+	return NewService(validator.New(), db.NewPSQLStore(pool))
+}
+
+// A store with just the users, roles and api keys the auth checks look up, anything else panics
+type fakeStore struct {
+	db.Store
+	users   map[pgtype.UUID]sqlc.GetUserAuthorizationRow
+	roles   map[int32]sqlc.Role
+	grants  map[int32][]string
+	apiKeys map[string]sqlc.ApiKey
+}
+
+func (f *fakeStore) GetUserAuthorization(ctx context.Context, id pgtype.UUID) (sqlc.GetUserAuthorizationRow, error) {
+	u, ok := f.users[id]
+	if !ok {
+		return u, db.ErrRecordNotFound
+	}
+	return u, nil
+}
+
+func (f *fakeStore) GetRoleByID(ctx context.Context, id int32) (sqlc.Role, error) {
+	r, ok := f.roles[id]
+	if !ok {
+		return r, db.ErrRecordNotFound
+	}
+	return r, nil
+}
+
+func (f *fakeStore) GetRolePermissions(ctx context.Context, roleID int32) ([]string, error) {
+	return f.grants[roleID], nil
+}
+
+func (f *fakeStore) GetAPIKeyByHash(ctx context.Context, keyHash string) (sqlc.ApiKey, error) {
+	k, ok := f.apiKeys[keyHash]
+	if !ok {
+		return k, db.ErrRecordNotFound
+	}
+	return k, nil
+}
+
+func (f *fakeStore) CreateAPIKey(ctx context.Context, arg sqlc.CreateAPIKeyParams) (sqlc.ApiKey, error) {
+	k := sqlc.ApiKey{Name: arg.Name, KeyHash: arg.KeyHash, Prefix: arg.Prefix, UserID: arg.UserID, Permissions: arg.Permissions, CreatedBy: arg.CreatedBy}
+	if f.apiKeys == nil {
+		f.apiKeys = map[string]sqlc.ApiKey{}
+	}
+	f.apiKeys[arg.KeyHash] = k
+	return k, nil
+}
+
+func (f *fakeStore) TouchAPIKey(ctx context.Context, id pgtype.UUID) error {
+	return nil
+}
+
+// A uuid that only needs to be told apart from the other test ones
+func testUUID(n byte) pgtype.UUID {
+	return pgtype.UUID{Bytes: [16]byte{15: n}, Valid: true}
+}
+
+// A superuser admin, a manager that can manage users and menus, a waiter and a deactivated waiter with their roles
+func testAuthorizations() *fakeStore {
+	return &fakeStore{
+		users: map[pgtype.UUID]sqlc.GetUserAuthorizationRow{
+			testUUID(1): {ID: testUUID(1), Type: sqlc.UserTypeAdmin, Active: true, RoleID: 1, Superuser: true},
+			testUUID(2): {ID: testUUID(2), Type: sqlc.UserTypeRegister, Active: true, RoleID: 2, Permissions: []string{"user.manage", "menu.read", "menu.write"}},
+			testUUID(3): {ID: testUUID(3), Type: sqlc.UserTypeWaiter, Active: true, RoleID: 3, Permissions: []string{"menu.read"}},
+			testUUID(4): {ID: testUUID(4), Type: sqlc.UserTypeWaiter, RoleID: 3, Permissions: []string{"menu.read"}},
+		},
+		roles: map[int32]sqlc.Role{
+			1: {ID: 1, Name: "admin", Superuser: true},
+			2: {ID: 2, Name: "manager"},
+			3: {ID: 3, Name: "waiter"},
+			4: {ID: 4, Name: "reports"},
+		},
+		grants: map[int32][]string{
+			2: {"user.manage", "menu.read", "menu.write"},
+			3: {"menu.read"},
+			4: {"report.view"},
+		},
+	}
+}
+
+// An email nobody has used yet
+func testEmail() string {
+	return fmt.Sprintf("auth-test-%d@example.com", time.Now().UnixNano())
+}
+
+// Creates an active register user that logs in with password
+func createTestUser(t *testing.T, s *service, password string) sqlc.User {
+	t.Helper()
+
+	ctx := context.Background()
+	role, err := s.Store.GetRoleByName(ctx, "register")
+	if err != nil {
+		t.Fatalf("cannot get role: %v", err)
+	}
+
+	hash, err := HashPassword(password)
+	if err != nil {
+		t.Fatalf("cannot hash password: %v", err)
+	}
+
+	u, err := s.Store.CreateUser(ctx, sqlc.CreateUserParams{
+		Email:    testEmail(),
+		Name:     "Auth test",
+		Type:     sqlc.UserTypeRegister,
+		Password: hash,
+		RoleID:   role.ID,
+	})
+	if err != nil {
+		t.Fatalf("cannot create user: %v", err)
+	}
+
+	return u
+}
+
+// Counts the errors that are target, failing if any of them succeeded
+func countErrors(t *testing.T, errs []error, target error) int {
+	t.Helper()
+
+	n := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			t.Fatal("unexpected success")
+		case errors.Is(err, target):
+			n++
+		}
+	}
+
+	return n
+}
+
+// Runs f n times at once and returns the errors
+func runConcurrently(n int, f func(i int) error) []error {
+	errs := make([]error, n)
+
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			errs[i] = f(i)
+		}()
+	}
+
+	close(start)
+	wg.Wait()
+
+	return errs
+}
+
+// Whatever the database already has, parallel bootstraps create one admin at most
+func TestBootstrapAdminConcurrent(t *testing.T) {
+	s := testService(t)
+	ctx := context.Background()
+
+	errs := runConcurrently(5, func(i int) error {
+		_, err := s.BootstrapAdmin(ctx, testEmail(), "Auth test", "password")
+		return err
+	})
+
+	created := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			created++
+		case errors.Is(err, api.ErrBootstrapClosed.Error):
+		default:
+			t.Fatalf("unexpected error %v", err)
+		}
+	}
+
+	if created > 1 {
+		t.Errorf("got %d admins, want at most 1", created)
+	}
+
+	if _, err := s.BootstrapAdmin(ctx, testEmail(), "Auth test", "password"); !errors.Is(err, api.ErrBootstrapClosed.Error) {
+		t.Errorf("got error %v after bootstrapping, want %v", err, api.ErrBootstrapClosed.Error)
+	}
+}
+
+// A burst of wrong pins gets no more guesses than sequential ones and the pin stays locked after
+func TestAuthenticatePINConcurrent(t *testing.T) {
+	s := testService(t)
+	ctx := context.Background()
+
+	u := createTestUser(t, s, "password")
+	if err := s.SetPIN(ctx, u.ID, u.ID, "1234"); err != nil {
+		t.Fatalf("cannot set pin: %v", err)
+	}
+
+	errs := runConcurrently(3*MaxPINAttempts, func(i int) error {
+		_, _, err := s.AuthenticatePIN(ctx, u.ID, fmt.Sprintf("9%03d", i))
+		return err
+	})
+
+	invalid := countErrors(t, errs, api.ErrPINInvalid.Error)
+	locked := countErrors(t, errs, api.ErrPINLocked.Error)
+	if invalid != MaxPINAttempts-1 || invalid+locked != len(errs) {
+		t.Fatalf("got %d invalid and %d locked, want %d invalid and the rest locked", invalid, locked, MaxPINAttempts-1)
+	}
+
+	if _, _, err := s.AuthenticatePIN(ctx, u.ID, "1234"); !errors.Is(err, api.ErrPINLocked.Error) {
+		t.Errorf("got error %v with the right pin, want %v", err, api.ErrPINLocked.Error)
+	}
+}
+
+func TestCheckAssignable(t *testing.T) {
+	s := NewService(validator.New(), testAuthorizations())
+
+	tests := []struct {
+		name     string
+		actor    pgtype.UUID
+		roleID   int32
+		userType sqlc.UserType
+		err      error
+	}{
+		{name: "superuser gives a superuser role", actor: testUUID(1), roleID: 1, userType: sqlc.UserTypeAdmin},
+		{name: "superuser gives any role", actor: testUUID(1), roleID: 4, userType: sqlc.UserTypeRegister},
+		{name: "own permissions", actor: testUUID(2), roleID: 2, userType: sqlc.UserTypeRegister},
+		{name: "fewer permissions", actor: testUUID(2), roleID: 3, userType: sqlc.UserTypeWaiter},
+		{name: "superuser role", actor: testUUID(2), roleID: 1, userType: sqlc.UserTypeRegister, err: api.ErrRoleNotAssignable.Error},
+		{name: "admin type", actor: testUUID(2), roleID: 3, userType: sqlc.UserTypeAdmin, err: api.ErrRoleNotAssignable.Error},
+		{name: "permissions the actor lacks", actor: testUUID(2), roleID: 4, userType: sqlc.UserTypeRegister, err: api.ErrRoleNotAssignable.Error},
+		{name: "unknown role", actor: testUUID(2), roleID: 99, userType: sqlc.UserTypeRegister, err: api.ErrUnknownRole.Error},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.checkAssignable(context.Background(), tt.actor, tt.roleID, tt.userType)
+			if tt.err == nil {
+				if err != nil {
+					t.Fatalf("unexpected error %v", err)
+				}
+				return
+			}
+
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("got error %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestCheckManageable(t *testing.T) {
+	s := NewService(validator.New(), testAuthorizations())
+
+	tests := []struct {
+		name   string
+		actor  pgtype.UUID
+		target pgtype.UUID
+		err    error
+	}{
+		{name: "superuser manages themselves", actor: testUUID(1), target: testUUID(1)},
+		{name: "superuser manages anyone", actor: testUUID(1), target: testUUID(3)},
+		{name: "manager manages a waiter", actor: testUUID(2), target: testUUID(3)},
+		{name: "manager manages a superuser", actor: testUUID(2), target: testUUID(1), err: api.ErrUserProtected.Error},
+		{name: "unknown user", actor: testUUID(2), target: testUUID(99), err: api.ErrUnknownUser.Error},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.checkManageable(context.Background(), tt.actor, tt.target)
+			if tt.err == nil {
+				if err != nil {
+					t.Fatalf("unexpected error %v", err)
+				}
+				return
+			}
+
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("got error %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+// A burst of wrong passwords gets no more guesses than sequential ones and the account stays locked after
+func TestAuthenticateUserConcurrent(t *testing.T) {
+	s := testService(t)
+	ctx := context.Background()
+
+	u := createTestUser(t, s, "password")
+
+	errs := runConcurrently(3*MaxLoginAttempts, func(i int) error {
+		_, _, err := s.AuthenticateUser(ctx, u.Email, fmt.Sprintf("wrong %d", i))
+		return err
+	})
+
+	wrong := countErrors(t, errs, api.ErrWrongPassword.Error)
+	locked := countErrors(t, errs, api.ErrLoginLocked.Error)
+	if wrong != MaxLoginAttempts-1 || wrong+locked != len(errs) {
+		t.Fatalf("got %d wrong and %d locked, want %d wrong and the rest locked", wrong, locked, MaxLoginAttempts-1)
+	}
+
+	if _, _, err := s.AuthenticateUser(ctx, u.Email, "password"); !errors.Is(err, api.ErrLoginLocked.Error) {
+		t.Errorf("got error %v with the right password, want %v", err, api.ErrLoginLocked.Error)
+	}
+}
+
+func TestCreateAPIKey(t *testing.T) {
+	tests := []struct {
+		name        string
+		creator     pgtype.UUID
+		user        pgtype.UUID
+		permissions []string
+		err         error
+	}{
+		{name: "superuser gives anything", creator: testUUID(1), user: testUUID(1), permissions: []string{"report.view", "user.manage"}},
+		{name: "own permissions for someone else", creator: testUUID(2), user: testUUID(3), permissions: []string{"menu.read"}},
+		{name: "own permissions for themselves", creator: testUUID(2), user: testUUID(2), permissions: []string{"user.manage"}},
+		{name: "permission the creator lacks", creator: testUUID(2), user: testUUID(3), permissions: []string{"report.view"}, err: api.ErrPermissionNotGranted.Error},
+		{name: "key for a superuser", creator: testUUID(2), user: testUUID(1), permissions: []string{"menu.read"}, err: api.ErrUserProtected.Error},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := testAuthorizations()
+			s := NewService(validator.New(), store)
+
+			_, key, err := s.CreateAPIKey(context.Background(), "Test", tt.user, tt.permissions, 30, tt.creator)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("got error %v, want %v", err, tt.err)
+				}
+				if len(store.apiKeys) != 0 {
+					t.Error("key stored, want nothing stored")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			// Only the hash is kept, the key itself has to find it
+			if _, ok := store.apiKeys[HashOpaqueToken(strings.TrimPrefix(key, APIKeyPrefix))]; !ok {
+				t.Errorf("no key stored under the hash of %q", key)
+			}
+		})
+	}
+}
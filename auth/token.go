@@ -0,0 +1,29 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// Api keys are sent as "Authorization: Bearer kl_<token>", the prefix tells them apart from jwts
+const APIKeyPrefix = "kl_"
+
+// Generates a random opaque token (device tokens, api keys) that is shown once when created.
+// Only the hash of the token (HashOpaqueToken) is stored.
+func GenerateOpaqueToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Hashes an opaque token with sha256, tokens are random enough that bcrypt isnt needed
+// and a fast hash lets us look the token up by the hash.
+func HashOpaqueToken(token string) string {
+	h := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(h[:])
+}
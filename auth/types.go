@@ -3,6 +3,7 @@ package auth
 import (
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/pdridh/k-line/api"
 	"github.com/pdridh/k-line/db/sqlc"
 )
 
@@ -19,12 +20,64 @@ type User struct {
 	Email     string           `json:"email"`
 	Name      string           `json:"name"`
 	Type      sqlc.UserType    `json:"type"`
+	RoleID    int32            `json:"role_id"`
+	Active    bool             `json:"active"`
 	CreatedAt pgtype.Timestamp `json:"created_at,omitempty"`
 }
 
+type UserFilters struct {
+	Page  int32 `json:"page"` // The request is sent as page but converted to offset for db
+	Limit int32 `json:"limit"`
+}
+
+func (f *UserFilters) Validate(maxLimit int32, defaultLimit int32) {
+	api.ClampPage(&f.Limit, &f.Page, maxLimit, defaultLimit)
+}
+
 type UserAuth struct {
-	ID    string        `json:"id"`
-	Email string        `json:"email"`
-	Name  string        `json:"name"`
-	Type  sqlc.UserType `json:"type"`
+	ID          string        `json:"id"`
+	Email       string        `json:"email"`
+	Name        string        `json:"name"`
+	Type        sqlc.UserType `json:"type"`
+	RoleID      int32         `json:"role_id"`
+	Permissions []string      `json:"permissions"`
+}
+
+type Device struct {
+	ID           pgtype.UUID      `json:"id"`
+	Name         string           `json:"name"`
+	RegisteredBy pgtype.UUID      `json:"registered_by"`
+	CreatedAt    pgtype.Timestamp `json:"created_at"`
+	LastSeenAt   pgtype.Timestamp `json:"last_seen_at"`
+	RevokedAt    pgtype.Timestamp `json:"revoked_at"`
+}
+
+// A user that can be picked on a trusted device for pin login
+type PINUser struct {
+	ID   pgtype.UUID   `json:"id"`
+	Name string        `json:"name"`
+	Type sqlc.UserType `json:"type"`
+}
+
+type Role struct {
+	ID          int32            `json:"id"`
+	Name        string           `json:"name"`
+	Description pgtype.Text      `json:"description"`
+	Superuser   bool             `json:"superuser"`
+	Permissions []string         `json:"permissions"`
+	CreatedAt   pgtype.Timestamp `json:"created_at"`
+}
+
+// An api key without its secret part, Prefix is enough to tell keys apart
+type APIKey struct {
+	ID          pgtype.UUID      `json:"id"`
+	Name        string           `json:"name"`
+	Prefix      string           `json:"prefix"`
+	UserID      pgtype.UUID      `json:"user_id"`
+	Permissions []string         `json:"permissions"`
+	CreatedBy   pgtype.UUID      `json:"created_by"`
+	ExpiresAt   pgtype.Timestamp `json:"expires_at"`
+	CreatedAt   pgtype.Timestamp `json:"created_at"`
+	LastUsedAt  pgtype.Timestamp `json:"last_used_at"`
+	RevokedAt   pgtype.Timestamp `json:"revoked_at"`
 }
@@ -0,0 +1,106 @@
+package blob
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var ErrInvalidKey = errors.New("invalid blob key")
+
+// Where uploaded files (like menu images) are kept. Keys are slash separated paths
+// like menu/12/abc-full.jpg and URL turns one into a link clients can load it from.
+type BlobStore interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
+
+// Keeps the blobs as files under Dir, they are served by the store itself (see ServeHTTP)
+// at BaseURL so no other server is needed
+type localStore struct {
+	Dir     string
+	BaseURL string
+}
+
+func NewLocalStore(dir string, baseURL string) *localStore {
+	return &localStore{
+		Dir:     dir,
+		BaseURL: strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+func (s *localStore) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return errors.Wrap(err, "mkdir")
+	}
+
+	// Written next to the final file and renamed so a half written file is never served
+	tmp := p + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return errors.Wrap(err, "write")
+	}
+
+	if err := os.Rename(tmp, p); err != nil {
+		os.Remove(tmp)
+		return errors.Wrap(err, "rename")
+	}
+
+	return nil
+}
+
+func (s *localStore) Delete(ctx context.Context, key string) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "remove")
+	}
+
+	return nil
+}
+
+func (s *localStore) URL(key string) string {
+	return s.BaseURL + "/" + key
+}
+
+// Serves the blob whose key is the request path, the prefix of BaseURL has to be stripped first.
+// Directories are not listed.
+func (s *localStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p, err := s.path(strings.TrimPrefix(r.URL.Path, "/"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	info, err := os.Stat(p)
+	if err != nil || info.IsDir() {
+		http.NotFound(w, r)
+		return
+	}
+
+	// Keys change whenever the content does so the files can be cached for good
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	http.ServeFile(w, r, p)
+}
+
+// Turns the key into a path under Dir, keys that would escape it are rejected
+func (s *localStore) path(key string) (string, error) {
+	if key == "" || strings.Contains(key, "\\") || path.Clean("/"+key) != "/"+key {
+		return "", ErrInvalidKey
+	}
+
+	return filepath.Join(s.Dir, filepath.FromSlash(key)), nil
+}
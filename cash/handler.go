@@ -0,0 +1,339 @@
+package cash
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/pdridh/k-line/api"
+	"github.com/pdridh/k-line/db/sqlc"
+	"github.com/pkg/errors"
+)
+
+type handler struct {
+	Service *service
+}
+
+func NewHandler(s *service) *handler {
+	return &handler{
+		Service: s,
+	}
+}
+
+// Writes the response for errors about a drawer session that every session route can run into
+func writeSessionError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, api.ErrUnknownDrawerSession.Error):
+		api.WriteNotFoundError(w, r)
+	case errors.Is(err, api.ErrDrawerSessionClosed.Error):
+		api.WriteError(w, r, http.StatusConflict, api.ErrDrawerSessionClosed, nil)
+	default:
+		api.WriteInternalError(w, r)
+	}
+}
+
+func (h *handler) OpenSession() http.HandlerFunc {
+	type RequestPayload struct {
+		Drawer       string  `json:"drawer" validate:"required"`
+		OpeningFloat float64 `json:"opening_float" validate:"gte=0"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var userID pgtype.UUID
+		if err := userID.Scan(api.CurrentUserID(r)); err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		var p RequestPayload
+		if err := api.ParseJSON(r, &p); err != nil {
+			api.WriteBadRequestError(w, r)
+			return
+		}
+
+		if err := h.Service.Validate.Struct(p); err != nil {
+			api.WriteValidationError(w, r, err)
+			return
+		}
+
+		d, err := h.Service.OpenSession(r.Context(), p.Drawer, p.OpeningFloat, userID)
+		if err != nil {
+			if errors.Is(err, api.ErrDrawerAlreadyOpen.Error) {
+				api.WriteError(w, r, http.StatusConflict, api.ErrDrawerAlreadyOpen, nil)
+				return
+			}
+
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusCreated, "Successfully opened drawer", d)
+	}
+}
+
+func (h *handler) GetSessions() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var filters PageFilters
+
+		api.ParseQueryParams(r.URL.Query(), &filters)
+		filters.Validate(50, 20)
+
+		offset := (filters.Page - 1) * filters.Limit
+
+		d, err := h.Service.GetSessions(r.Context(), filters.Open, filters.Limit, offset)
+		if err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Retrieval successful", d)
+	}
+}
+
+func (h *handler) GetSession() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		d, err := h.Service.GetSession(r.Context(), int64(id))
+		if err != nil {
+			writeSessionError(w, r, err)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Retrieval successful", d)
+	}
+}
+
+func (h *handler) AddMovement() http.HandlerFunc {
+	type RequestPayload struct {
+		Type   sqlc.CashMovementType `json:"type" validate:"required,oneof=pay_in pay_out"`
+		Amount float64               `json:"amount" validate:"gt=0"`
+		Reason string                `json:"reason" validate:"required"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		var userID pgtype.UUID
+		if err := userID.Scan(api.CurrentUserID(r)); err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		var p RequestPayload
+		if err := api.ParseJSON(r, &p); err != nil {
+			api.WriteBadRequestError(w, r)
+			return
+		}
+
+		if err := h.Service.Validate.Struct(p); err != nil {
+			api.WriteValidationError(w, r, err)
+			return
+		}
+
+		arg := sqlc.CreateCashMovementParams{
+			SessionID: int64(id),
+			Type:      p.Type,
+			Amount:    p.Amount,
+			Reason:    p.Reason,
+			CreatedBy: userID,
+		}
+
+		m, err := h.Service.AddMovement(r.Context(), arg)
+		if err != nil {
+			writeSessionError(w, r, err)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusCreated, "Successfully recorded cash movement", m)
+	}
+}
+
+func (h *handler) AddTender() http.HandlerFunc {
+	type RequestPayload struct {
+		OrderID string            `json:"order_id" validate:"required,uuid"`
+		Method  sqlc.TenderMethod `json:"method" validate:"required,oneof=cash card other"`
+		Amount  float64           `json:"amount" validate:"gt=0"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		var userID pgtype.UUID
+		if err := userID.Scan(api.CurrentUserID(r)); err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		var p RequestPayload
+		if err := api.ParseJSON(r, &p); err != nil {
+			api.WriteBadRequestError(w, r)
+			return
+		}
+
+		if err := h.Service.Validate.Struct(p); err != nil {
+			api.WriteValidationError(w, r, err)
+			return
+		}
+
+		var orderID pgtype.UUID
+		if err := orderID.Scan(p.OrderID); err != nil {
+			api.WriteBadRequestError(w, r)
+			return
+		}
+
+		arg := sqlc.CreateTenderParams{
+			SessionID: int64(id),
+			OrderID:   orderID,
+			Method:    p.Method,
+			Amount:    p.Amount,
+			CreatedBy: userID,
+		}
+
+		t, err := h.Service.AddTender(r.Context(), arg)
+		if err != nil {
+			if errors.Is(err, api.ErrUnknownOrder.Error) {
+				api.WriteError(w, r, http.StatusBadRequest, api.ErrUnknownOrder, nil)
+				return
+			}
+
+			writeSessionError(w, r, err)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusCreated, "Successfully recorded tender", t)
+	}
+}
+
+func (h *handler) CloseSession() http.HandlerFunc {
+	type RequestPayload struct {
+		CountedAmount float64     `json:"counted_amount" validate:"gte=0"`
+		Notes         pgtype.Text `json:"notes"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		var userID pgtype.UUID
+		if err := userID.Scan(api.CurrentUserID(r)); err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		var p RequestPayload
+		if err := api.ParseJSON(r, &p); err != nil {
+			api.WriteBadRequestError(w, r)
+			return
+		}
+
+		if err := h.Service.Validate.Struct(p); err != nil {
+			api.WriteValidationError(w, r, err)
+			return
+		}
+
+		d, err := h.Service.CloseSession(r.Context(), int64(id), p.CountedAmount, userID, p.Notes)
+		if err != nil {
+			writeSessionError(w, r, err)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Successfully closed drawer", d)
+	}
+}
+
+func (h *handler) PreviewZReport() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		z, err := h.Service.PreviewZReport(r.Context())
+		if err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Retrieval successful", z)
+	}
+}
+
+func (h *handler) CloseZReport() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var userID pgtype.UUID
+		if err := userID.Scan(api.CurrentUserID(r)); err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		z, err := h.Service.CloseZReport(r.Context(), userID)
+		if err != nil {
+			switch {
+			case errors.Is(err, api.ErrDrawersStillOpen.Error):
+				api.WriteError(w, r, http.StatusConflict, api.ErrDrawersStillOpen, nil)
+				return
+			case errors.Is(err, api.ErrZReportConflict.Error):
+				api.WriteError(w, r, http.StatusConflict, api.ErrZReportConflict, nil)
+				return
+			default:
+				api.WriteInternalError(w, r)
+				return
+			}
+		}
+
+		api.WriteSuccess(w, r, http.StatusCreated, "Successfully closed z report", z)
+	}
+}
+
+func (h *handler) GetZReports() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var filters PageFilters
+
+		api.ParseQueryParams(r.URL.Query(), &filters)
+		filters.Validate(50, 20)
+
+		offset := (filters.Page - 1) * filters.Limit
+
+		z, err := h.Service.GetZReports(r.Context(), filters.Limit, offset)
+		if err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Retrieval successful", z)
+	}
+}
+
+func (h *handler) GetZReportByID() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		z, err := h.Service.GetZReportByID(r.Context(), int64(id))
+		if err != nil {
+			if errors.Is(err, api.ErrUnknownZReport.Error) {
+				api.WriteNotFoundError(w, r)
+				return
+			}
+
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Retrieval successful", z)
+	}
+}
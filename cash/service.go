@@ -0,0 +1,339 @@
+package cash
+
+import (
+	"context"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/pdridh/k-line/api"
+	"github.com/pdridh/k-line/config"
+	"github.com/pdridh/k-line/db"
+	"github.com/pdridh/k-line/db/sqlc"
+	"github.com/pkg/errors"
+)
+
+type service struct {
+	Validate *validator.Validate
+	store    db.Store
+}
+
+func NewService(v *validator.Validate, s db.Store) *service {
+	return &service{
+		Validate: v,
+		store:    s,
+	}
+}
+
+func (s *service) OpenSession(ctx context.Context, drawer string, openingFloat float64, openedBy pgtype.UUID) (*DrawerSession, error) {
+	d, err := s.store.OpenDrawerSession(ctx, sqlc.OpenDrawerSessionParams{Drawer: drawer, OpeningFloat: openingFloat, OpenedBy: openedBy})
+	if err != nil {
+		// Only one open session is allowed per drawer
+		if db.GetSQLErrorCode(err) == db.UniqueViolation {
+			return nil, errors.Wrap(api.ErrDrawerAlreadyOpen.Error, "store")
+		}
+		return nil, errors.Wrap(err, "store")
+	}
+
+	return newDrawerSession(d), nil
+}
+
+func (s *service) GetSessions(ctx context.Context, openOnly bool, limit int32, offset int32) ([]DrawerSession, error) {
+	rows, err := s.store.GetDrawerSessions(ctx, sqlc.GetDrawerSessionsParams{Limit: limit, Offset: offset, OpenOnly: openOnly})
+	if err != nil {
+		return []DrawerSession{}, errors.Wrap(err, "store")
+	}
+
+	sessions := []DrawerSession{}
+	for _, d := range rows {
+		sessions = append(sessions, *newDrawerSession(d))
+	}
+
+	return sessions, nil
+}
+
+func (s *service) GetSession(ctx context.Context, id int64) (*DrawerSessionDetail, error) {
+	d, err := s.getSession(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	expected, err := s.store.GetDrawerExpectedCash(ctx, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "store")
+	}
+
+	m, err := s.store.GetCashMovements(ctx, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "store")
+	}
+
+	t, err := s.store.GetTenders(ctx, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "store")
+	}
+
+	detail := &DrawerSessionDetail{
+		DrawerSession: *newDrawerSession(d),
+		ExpectedCash:  api.Round(expected),
+		Movements:     []CashMovement{},
+		Tenders:       []Tender{},
+	}
+
+	for _, movement := range m {
+		detail.Movements = append(detail.Movements, *newCashMovement(movement))
+	}
+
+	for _, tender := range t {
+		detail.Tenders = append(detail.Tenders, *newTender(tender))
+	}
+
+	return detail, nil
+}
+
+// Returns the session or ErrUnknownDrawerSession when there is none
+func (s *service) getSession(ctx context.Context, id int64) (sqlc.DrawerSession, error) {
+	d, err := s.store.GetDrawerSessionByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return d, errors.Wrap(api.ErrUnknownDrawerSession.Error, "store")
+		}
+		return d, errors.Wrap(err, "store")
+	}
+
+	return d, nil
+}
+
+func (s *service) AddMovement(ctx context.Context, arg sqlc.CreateCashMovementParams) (*CashMovement, error) {
+	m, err := s.store.AddCashMovementTx(ctx, arg)
+	if err != nil {
+		return nil, sessionError(err)
+	}
+
+	return newCashMovement(m), nil
+}
+
+func (s *service) AddTender(ctx context.Context, arg sqlc.CreateTenderParams) (*Tender, error) {
+	t, err := s.store.AddTenderTx(ctx, arg)
+	if err != nil {
+		if db.GetSQLErrorCode(err) == db.ForeignKeyViolation {
+			return nil, errors.Wrap(api.ErrUnknownOrder.Error, "store")
+		}
+		return nil, sessionError(err)
+	}
+
+	return newTender(t), nil
+}
+
+// Closes the session with the amount that was counted in the drawer, the expected amount is worked out
+// from the opening float, cash tenders and pay ins/outs so the variance can be seen.
+func (s *service) CloseSession(ctx context.Context, id int64, counted float64, closedBy pgtype.UUID, notes pgtype.Text) (*DrawerSession, error) {
+	d, err := s.store.CloseDrawerSessionTx(ctx, id, func(expected float64) sqlc.CloseDrawerSessionParams {
+		return sqlc.CloseDrawerSessionParams{
+			ExpectedAmount: pgtype.Float8{Float64: api.Round(expected), Valid: true},
+			CountedAmount:  pgtype.Float8{Float64: api.Round(counted), Valid: true},
+			ClosedBy:       closedBy,
+			Notes:          notes,
+		}
+	})
+	if err != nil {
+		return nil, sessionError(err)
+	}
+
+	return newDrawerSession(d), nil
+}
+
+// Maps the store errors of writing to a drawer session to the api ones
+func sessionError(err error) error {
+	switch {
+	case errors.Is(err, db.ErrRecordNotFound):
+		return errors.Wrap(api.ErrUnknownDrawerSession.Error, "store")
+	case errors.Is(err, db.ErrDrawerClosed):
+		return errors.Wrap(api.ErrDrawerSessionClosed.Error, "store")
+	default:
+		return errors.Wrap(err, "store")
+	}
+}
+
+// Works out the z report for everything since the last one without closing it (an X report)
+func (s *service) PreviewZReport(ctx context.Context) (*ZReport, error) {
+	p, err := s.store.GetZReportPeriod(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "store")
+	}
+
+	t, err := s.store.GetZReportTotals(ctx, sqlc.GetZReportTotalsParams{PeriodStart: p.PeriodStart, PeriodEnd: p.PeriodEnd})
+	if err != nil {
+		return nil, errors.Wrap(err, "store")
+	}
+
+	arg := buildZReport(p, t, pgtype.UUID{})
+
+	return newZReport(sqlc.ZReport{
+		PeriodStart:     arg.PeriodStart,
+		PeriodEnd:       arg.PeriodEnd,
+		OrderCount:      arg.OrderCount,
+		Covers:          arg.Covers,
+		GrossSales:      arg.GrossSales,
+		Discounts:       arg.Discounts,
+		NetSales:        arg.NetSales,
+		TaxRate:         arg.TaxRate,
+		Tax:             arg.Tax,
+		VoidCount:       arg.VoidCount,
+		VoidAmount:      arg.VoidAmount,
+		CashTenders:     arg.CashTenders,
+		CardTenders:     arg.CardTenders,
+		OtherTenders:    arg.OtherTenders,
+		GiftCardTenders: arg.GiftCardTenders,
+		DrawerSessions:  arg.DrawerSessions,
+		ExpectedCash:    arg.ExpectedCash,
+		CountedCash:     arg.CountedCash,
+		CashVariance:    arg.CashVariance,
+	}), nil
+}
+
+// Closes the business day. Every drawer has to be closed first, once saved the report cannot change.
+func (s *service) CloseZReport(ctx context.Context, closedBy pgtype.UUID) (*ZReport, error) {
+	z, err := s.store.CloseZReportTx(ctx, func(p sqlc.GetZReportPeriodRow, t sqlc.GetZReportTotalsRow) sqlc.CreateZReportParams {
+		return buildZReport(p, t, closedBy)
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrDrawersOpen) {
+			return nil, errors.Wrap(api.ErrDrawersStillOpen.Error, "drawers")
+		}
+		// period_start is unique so closing the same day twice at once fails for one of them
+		if db.GetSQLErrorCode(err) == db.UniqueViolation {
+			return nil, errors.Wrap(api.ErrZReportConflict.Error, "store")
+		}
+		return nil, errors.Wrap(err, "store")
+	}
+
+	return newZReport(z), nil
+}
+
+func buildZReport(p sqlc.GetZReportPeriodRow, t sqlc.GetZReportTotalsRow, closedBy pgtype.UUID) sqlc.CreateZReportParams {
+	discounts := t.Discounts
+	net := t.GrossSales - discounts
+	rate := config.Server().TaxRate
+
+	return sqlc.CreateZReportParams{
+		PeriodStart:     p.PeriodStart,
+		PeriodEnd:       p.PeriodEnd,
+		OrderCount:      t.OrderCount,
+		Covers:          t.Covers,
+		GrossSales:      api.Round(t.GrossSales),
+		Discounts:       api.Round(discounts),
+		NetSales:        api.Round(net),
+		TaxRate:         rate,
+		Tax:             api.Round(net * rate),
+		VoidCount:       t.VoidCount,
+		VoidAmount:      api.Round(t.VoidAmount),
+		CashTenders:     api.Round(t.CashTenders),
+		CardTenders:     api.Round(t.CardTenders),
+		OtherTenders:    api.Round(t.OtherTenders),
+		GiftCardTenders: api.Round(t.GiftCardTenders),
+		DrawerSessions:  t.DrawerSessions,
+		ExpectedCash:    api.Round(t.ExpectedCash),
+		CountedCash:     api.Round(t.CountedCash),
+		CashVariance:    api.Round(t.CountedCash - t.ExpectedCash),
+		ClosedBy:        closedBy,
+	}
+}
+
+func (s *service) GetZReports(ctx context.Context, limit int32, offset int32) ([]ZReport, error) {
+	rows, err := s.store.GetZReports(ctx, sqlc.GetZReportsParams{Limit: limit, Offset: offset})
+	if err != nil {
+		return []ZReport{}, errors.Wrap(err, "store")
+	}
+
+	reports := []ZReport{}
+	for _, z := range rows {
+		reports = append(reports, *newZReport(z))
+	}
+
+	return reports, nil
+}
+
+func (s *service) GetZReportByID(ctx context.Context, id int64) (*ZReport, error) {
+	z, err := s.store.GetZReportByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, errors.Wrap(api.ErrUnknownZReport.Error, "store")
+		}
+		return nil, errors.Wrap(err, "store")
+	}
+
+	return newZReport(z), nil
+}
+
+func newDrawerSession(d sqlc.DrawerSession) *DrawerSession {
+	session := &DrawerSession{
+		ID:             d.ID,
+		Drawer:         d.Drawer,
+		OpeningFloat:   d.OpeningFloat,
+		OpenedBy:       d.OpenedBy,
+		OpenedAt:       d.OpenedAt,
+		ExpectedAmount: d.ExpectedAmount,
+		CountedAmount:  d.CountedAmount,
+		ClosedBy:       d.ClosedBy,
+		ClosedAt:       d.ClosedAt,
+		Notes:          d.Notes,
+		ZReportID:      d.ZReportID,
+	}
+
+	if d.ExpectedAmount.Valid && d.CountedAmount.Valid {
+		session.Variance = pgtype.Float8{Float64: api.Round(d.CountedAmount.Float64 - d.ExpectedAmount.Float64), Valid: true}
+	}
+
+	return session
+}
+
+func newCashMovement(m sqlc.CashMovement) *CashMovement {
+	return &CashMovement{
+		ID:        m.ID,
+		SessionID: m.SessionID,
+		Type:      m.Type,
+		Amount:    m.Amount,
+		Reason:    m.Reason,
+		CreatedBy: m.CreatedBy,
+		CreatedAt: m.CreatedAt,
+	}
+}
+
+func newTender(t sqlc.Tender) *Tender {
+	return &Tender{
+		ID:        t.ID,
+		SessionID: t.SessionID,
+		OrderID:   t.OrderID,
+		Method:    t.Method,
+		Amount:    t.Amount,
+		CreatedBy: t.CreatedBy,
+		CreatedAt: t.CreatedAt,
+	}
+}
+
+func newZReport(z sqlc.ZReport) *ZReport {
+	return &ZReport{
+		ID:              z.ID,
+		PeriodStart:     z.PeriodStart,
+		PeriodEnd:       z.PeriodEnd,
+		OrderCount:      z.OrderCount,
+		Covers:          z.Covers,
+		GrossSales:      z.GrossSales,
+		Discounts:       z.Discounts,
+		NetSales:        z.NetSales,
+		TaxRate:         z.TaxRate,
+		Tax:             z.Tax,
+		VoidCount:       z.VoidCount,
+		VoidAmount:      z.VoidAmount,
+		CashTenders:     z.CashTenders,
+		CardTenders:     z.CardTenders,
+		OtherTenders:    z.OtherTenders,
+		GiftCardTenders: z.GiftCardTenders,
+		DrawerSessions:  z.DrawerSessions,
+		ExpectedCash:    z.ExpectedCash,
+		CountedCash:     z.CountedCash,
+		CashVariance:    z.CashVariance,
+		ClosedBy:        z.ClosedBy,
+		ClosedAt:        z.ClosedAt,
+	}
+}
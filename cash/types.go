@@ -0,0 +1,91 @@
+package cash
+
+import (
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/pdridh/k-line/api"
+	"github.com/pdridh/k-line/db/sqlc"
+)
+
+type PageFilters struct {
+	Open  bool  `json:"open"` // Only list sessions that are still open
+	Page  int32 `json:"page"` // The request is sent as page but converted to offset for db
+	Limit int32 `json:"limit"`
+}
+
+func (f *PageFilters) Validate(maxLimit int32, defaultLimit int32) {
+	api.ClampPage(&f.Limit, &f.Page, maxLimit, defaultLimit)
+}
+
+// A till being used from opening float to its closing count.
+// ExpectedAmount, CountedAmount and Variance are only set once the session is closed.
+type DrawerSession struct {
+	ID             int64            `json:"id"`
+	Drawer         string           `json:"drawer"`
+	OpeningFloat   float64          `json:"opening_float"`
+	OpenedBy       pgtype.UUID      `json:"opened_by"`
+	OpenedAt       pgtype.Timestamp `json:"opened_at"`
+	ExpectedAmount pgtype.Float8    `json:"expected_amount"`
+	CountedAmount  pgtype.Float8    `json:"counted_amount"`
+	Variance       pgtype.Float8    `json:"variance"` // Counted minus expected, negative when cash is missing
+	ClosedBy       pgtype.UUID      `json:"closed_by"`
+	ClosedAt       pgtype.Timestamp `json:"closed_at"`
+	Notes          pgtype.Text      `json:"notes"`
+	ZReportID      pgtype.Int8      `json:"z_report_id"`
+}
+
+// A session along with everything that moved through it
+type DrawerSessionDetail struct {
+	DrawerSession
+	ExpectedCash float64        `json:"expected_cash"` // What should be in the drawer right now
+	Movements    []CashMovement `json:"movements"`
+	Tenders      []Tender       `json:"tenders"`
+}
+
+// Cash put into or taken out of the drawer that isnt a sale, like change top ups or paying a supplier
+type CashMovement struct {
+	ID        int64                 `json:"id"`
+	SessionID int64                 `json:"session_id"`
+	Type      sqlc.CashMovementType `json:"type"`
+	Amount    float64               `json:"amount"`
+	Reason    string                `json:"reason"`
+	CreatedBy pgtype.UUID           `json:"created_by"`
+	CreatedAt pgtype.Timestamp      `json:"created_at"`
+}
+
+// A payment taken for an order, only cash tenders change what is expected in the drawer
+type Tender struct {
+	ID        int64             `json:"id"`
+	SessionID int64             `json:"session_id"`
+	OrderID   pgtype.UUID       `json:"order_id"`
+	Method    sqlc.TenderMethod `json:"method"`
+	Amount    float64           `json:"amount"`
+	CreatedBy pgtype.UUID       `json:"created_by"`
+	CreatedAt pgtype.Timestamp  `json:"created_at"`
+}
+
+// End of day summary of everything since the previous z report.
+// A report that has not been closed yet (an X report) has no ID or ClosedBy.
+type ZReport struct {
+	ID              int64            `json:"id"`
+	PeriodStart     pgtype.Timestamp `json:"period_start"`
+	PeriodEnd       pgtype.Timestamp `json:"period_end"`
+	OrderCount      int32            `json:"order_count"`
+	Covers          int32            `json:"covers"`
+	GrossSales      float64          `json:"gross_sales"`
+	Discounts       float64          `json:"discounts"`
+	NetSales        float64          `json:"net_sales"`
+	TaxRate         float64          `json:"tax_rate"`
+	Tax             float64          `json:"tax"`
+	VoidCount       int32            `json:"void_count"`
+	VoidAmount      float64          `json:"void_amount"`
+	CashTenders     float64          `json:"cash_tenders"`
+	CardTenders     float64          `json:"card_tenders"`
+	OtherTenders    float64          `json:"other_tenders"`
+	GiftCardTenders float64          `json:"gift_card_tenders"`
+	DrawerSessions  int32            `json:"drawer_sessions"`
+	ExpectedCash    float64          `json:"expected_cash"`
+	CountedCash     float64          `json:"counted_cash"`
+	CashVariance    float64          `json:"cash_variance"`
+	ClosedBy        pgtype.UUID      `json:"closed_by"`
+	ClosedAt        pgtype.Timestamp `json:"closed_at"`
+}
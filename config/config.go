@@ -2,20 +2,39 @@ package config
 
 import (
 	"log"
+	"net/url"
 	"os"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
 )
 
+// Languages are ISO 639 codes like en or fil, they end up in the queries that pick translations
+var languageCode = regexp.MustCompile(`^[a-z]{2,3}$`)
+
 type ServerConfig struct {
-	Env            string
-	Host           string
-	Port           string
-	DatabaseURI    string
-	JWTSecret      string
-	JWTExpiration  time.Duration
-	FrontendOrigin string
+	Env               string
+	Host              string
+	Port              string
+	DatabaseURI       string
+	JWTSecret         string
+	JWTKeysDir        string
+	JWTSigningKeyID   string
+	JWTExpiration     time.Duration
+	FrontendOrigin    string
+	TrustProxy        bool
+	RequireClockIn    bool     // Waiters must be clocked in before they can open orders
+	TaxRate           float64  // Sales tax as a fraction (0.13 for 13%), added on top of menu prices
+	UploadDir         string   // Where uploaded files like menu images are stored
+	UploadURL         string   // Base url of the uploaded files
+	UploadPath        string   // Path of UploadURL, where the server itself serves uploads kept on disk
+	Languages         []string // Languages the menu can be shown in, the first is the default
+	LoyaltyEarnRate   float64  // Loyalty points earned per unit paid for a completed order
+	LoyaltyPointValue float64  // How much one loyalty point takes off the bill when redeemed
+	GiftCardValidity  int      // Days a gift card can be used for after it was issued or last reloaded, 0 never expires
 }
 
 var server *ServerConfig
@@ -36,14 +55,67 @@ func Load() {
 		}
 	}
 	server = &ServerConfig{
-		Env:            env,
-		Host:           getEnvOrDefault("HOST", "localhost"),
-		Port:           getEnvOrDefault("PORT", "8080"),
-		DatabaseURI:    getEnvOrDefault("DATABASE_URI", ""),
-		JWTSecret:      getEnvOrDefault("JWT_SECRET", "secret:)"),
-		JWTExpiration:  time.Hour * 24, // TODO change this to something better
-		FrontendOrigin: getEnvOrDefault("FRONTEND_ORIGIN", "http://localhost:5173"),
+		Env:             env,
+		Host:            getEnvOrDefault("HOST", "localhost"),
+		Port:            getEnvOrDefault("PORT", "8080"),
+		DatabaseURI:     getEnvOrDefault("DATABASE_URI", ""),
+		JWTSecret:       getEnvOrDefault("JWT_SECRET", "secret:)"),
+		JWTKeysDir:      getEnvOrDefault("JWT_KEYS_DIR", ""),
+		JWTSigningKeyID: getEnvOrDefault("JWT_SIGNING_KID", ""),
+		JWTExpiration:   time.Hour * 24, // TODO change this to something better
+		FrontendOrigin:  getEnvOrDefault("FRONTEND_ORIGIN", "http://localhost:5173"),
+		TrustProxy:      getEnvOrDefault("TRUST_PROXY", "false") == "true",
+		RequireClockIn:  getEnvOrDefault("REQUIRE_CLOCK_IN", "false") == "true",
+		UploadDir:       getEnvOrDefault("UPLOAD_DIR", "uploads"),
+		UploadURL:       getEnvOrDefault("UPLOAD_URL", "/uploads"),
+	}
+
+	uploadURL, err := url.Parse(server.UploadURL)
+	if err != nil || strings.Trim(uploadURL.Path, "/") == "" {
+		log.Fatal("UPLOAD_URL must be a url with a path other than /")
+	}
+	server.UploadPath = "/" + strings.Trim(uploadURL.Path, "/")
+
+	taxRate, err := strconv.ParseFloat(getEnvOrDefault("TAX_RATE", "0"), 64)
+	if err != nil || taxRate < 0 {
+		log.Fatal("TAX_RATE must be a non negative number")
+	}
+	server.TaxRate = taxRate
+
+	for _, lang := range strings.Split(getEnvOrDefault("LANGUAGES", "en"), ",") {
+		if lang = strings.ToLower(strings.TrimSpace(lang)); lang != "" {
+			if !languageCode.MatchString(lang) {
+				log.Fatalf("LANGUAGES has %q which is not a 2 or 3 letter language code", lang)
+			}
+			server.Languages = append(server.Languages, lang)
+		}
+	}
+	if len(server.Languages) == 0 {
+		log.Fatal("LANGUAGES must list at least one language")
 	}
+
+	earnRate, err := strconv.ParseFloat(getEnvOrDefault("LOYALTY_EARN_RATE", "1"), 64)
+	if err != nil || earnRate < 0 {
+		log.Fatal("LOYALTY_EARN_RATE must be a non negative number")
+	}
+	server.LoyaltyEarnRate = earnRate
+
+	pointValue, err := strconv.ParseFloat(getEnvOrDefault("LOYALTY_POINT_VALUE", "0.01"), 64)
+	if err != nil || pointValue <= 0 {
+		log.Fatal("LOYALTY_POINT_VALUE must be a positive number")
+	}
+	server.LoyaltyPointValue = pointValue
+
+	validity, err := strconv.Atoi(getEnvOrDefault("GIFT_CARD_VALIDITY_DAYS", "365"))
+	if err != nil || validity < 0 {
+		log.Fatal("GIFT_CARD_VALIDITY_DAYS must be a non negative number of days")
+	}
+	server.GiftCardValidity = validity
+}
+
+// The language the menu is written in, translations are only needed for the others
+func (c *ServerConfig) DefaultLanguage() string {
+	return c.Languages[0]
 }
 
 // Wrapper around os.LookupEnv() that returns the default value if not the environment var is not set
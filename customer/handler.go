@@ -0,0 +1,193 @@
+package customer
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/pdridh/k-line/api"
+	"github.com/pdridh/k-line/db/sqlc"
+	"github.com/pkg/errors"
+)
+
+type handler struct {
+	Service *service
+}
+
+func NewHandler(s *service) *handler {
+	return &handler{
+		Service: s,
+	}
+}
+
+func writeCustomerError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, api.ErrUnknownCustomer.Error):
+		api.WriteNotFoundError(w, r)
+	case errors.Is(err, api.ErrInvalidPhone.Error):
+		api.WriteError(w, r, http.StatusBadRequest, api.ErrInvalidPhone, nil)
+	case errors.Is(err, api.ErrCustomerPhoneConflict.Error):
+		api.WriteError(w, r, http.StatusConflict, api.ErrCustomerPhoneConflict, nil)
+	default:
+		api.WriteInternalError(w, r)
+	}
+}
+
+func (h *handler) CreateCustomer() http.HandlerFunc {
+	type RequestPayload struct {
+		Name        string         `json:"name" validate:"required"`
+		Email       pgtype.Text    `json:"email"`
+		Notes       pgtype.Text    `json:"notes"`
+		Preferences pgtype.Text    `json:"preferences"`
+		Phones      []PhoneInput   `json:"phones" validate:"dive"`
+		Addresses   []AddressInput `json:"addresses" validate:"dive"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var p RequestPayload
+		if err := api.ParseJSON(r, &p); err != nil {
+			api.WriteBadRequestError(w, r)
+			return
+		}
+
+		if err := h.Service.Validate.Struct(p); err != nil {
+			api.WriteValidationError(w, r, err)
+			return
+		}
+
+		arg := sqlc.CreateCustomerParams{
+			Name:        p.Name,
+			Email:       p.Email,
+			Notes:       p.Notes,
+			Preferences: p.Preferences,
+		}
+
+		c, err := h.Service.CreateCustomer(r.Context(), arg, p.Phones, p.Addresses)
+		if err != nil {
+			writeCustomerError(w, r, err)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusCreated, "Successfully created customer", c)
+	}
+}
+
+// Replaces the details of the customer, phones and addresses left out of the payload are removed
+func (h *handler) UpdateCustomer() http.HandlerFunc {
+	type RequestPayload struct {
+		Name        string         `json:"name" validate:"required"`
+		Email       pgtype.Text    `json:"email"`
+		Notes       pgtype.Text    `json:"notes"`
+		Preferences pgtype.Text    `json:"preferences"`
+		Phones      []PhoneInput   `json:"phones" validate:"dive"`
+		Addresses   []AddressInput `json:"addresses" validate:"dive"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		var p RequestPayload
+		if err := api.ParseJSON(r, &p); err != nil {
+			api.WriteBadRequestError(w, r)
+			return
+		}
+
+		if err := h.Service.Validate.Struct(p); err != nil {
+			api.WriteValidationError(w, r, err)
+			return
+		}
+
+		arg := sqlc.UpdateCustomerParams{
+			ID:          int32(id),
+			Name:        p.Name,
+			Email:       p.Email,
+			Notes:       p.Notes,
+			Preferences: p.Preferences,
+		}
+
+		c, err := h.Service.UpdateCustomer(r.Context(), arg, p.Phones, p.Addresses)
+		if err != nil {
+			writeCustomerError(w, r, err)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Successfully updated customer", c)
+	}
+}
+
+func (h *handler) GetCustomers() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var filters CustomerFilters
+
+		api.ParseQueryParams(r.URL.Query(), &filters)
+		filters.Validate(100, 50)
+
+		c, total, err := h.Service.GetCustomers(r.Context(), filters.Search, filters.Limit, (filters.Page-1)*filters.Limit)
+		if err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		meta := api.CalculatePaginationMeta(total, int(filters.Page), int(filters.Limit))
+		api.WriteSuccess(w, r, http.StatusOK, "Retrieval successful", api.NewPaginatedResponse(c, meta))
+	}
+}
+
+func (h *handler) GetCustomerByID() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		c, err := h.Service.GetCustomerByID(r.Context(), int32(id))
+		if err != nil {
+			writeCustomerError(w, r, err)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Retrieval successful", c)
+	}
+}
+
+// Finds the customer by the phone number they are calling from, however it is typed
+func (h *handler) LookupByPhone() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		c, err := h.Service.LookupByPhone(r.Context(), r.URL.Query().Get("phone"))
+		if err != nil {
+			writeCustomerError(w, r, err)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Retrieval successful", c)
+	}
+}
+
+func (h *handler) GetCustomerOrders() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		var filters OrderFilters
+
+		api.ParseQueryParams(r.URL.Query(), &filters)
+		filters.Validate(100, 50)
+
+		o, total, err := h.Service.GetCustomerOrders(r.Context(), int32(id), filters.Limit, (filters.Page-1)*filters.Limit)
+		if err != nil {
+			writeCustomerError(w, r, err)
+			return
+		}
+
+		meta := api.CalculatePaginationMeta(total, int(filters.Page), int(filters.Limit))
+		api.WriteSuccess(w, r, http.StatusOK, "Retrieval successful", api.NewPaginatedResponse(o, meta))
+	}
+}
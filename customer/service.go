@@ -0,0 +1,269 @@
+package customer
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/pdridh/k-line/api"
+	"github.com/pdridh/k-line/db"
+	"github.com/pdridh/k-line/db/sqlc"
+	"github.com/pkg/errors"
+)
+
+type service struct {
+	Validate *validator.Validate
+	store    db.Store
+}
+
+func NewService(v *validator.Validate, s db.Store) *service {
+	return &service{
+		Validate: v,
+		store:    s,
+	}
+}
+
+// Creates the customer with their phones and addresses, a phone can only belong to one customer
+func (s *service) CreateCustomer(ctx context.Context, arg sqlc.CreateCustomerParams, phones []PhoneInput, addresses []AddressInput) (*Customer, error) {
+	phoneArg, err := phoneParams(phones)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := s.store.CreateCustomerTx(ctx, arg, phoneArg, addressParams(addresses))
+	if err != nil {
+		if db.GetSQLErrorCode(err) == db.UniqueViolation {
+			return nil, errors.Wrap(api.ErrCustomerPhoneConflict.Error, "store")
+		}
+		return nil, errors.Wrap(err, "store")
+	}
+
+	return s.GetCustomerByID(ctx, c.ID)
+}
+
+// Updates the customer and replaces all of their phones and addresses
+func (s *service) UpdateCustomer(ctx context.Context, arg sqlc.UpdateCustomerParams, phones []PhoneInput, addresses []AddressInput) (*Customer, error) {
+	phoneArg, err := phoneParams(phones)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := s.store.UpdateCustomerTx(ctx, arg, phoneArg, addressParams(addresses))
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, errors.Wrap(api.ErrUnknownCustomer.Error, "store")
+		}
+		if db.GetSQLErrorCode(err) == db.UniqueViolation {
+			return nil, errors.Wrap(api.ErrCustomerPhoneConflict.Error, "store")
+		}
+		return nil, errors.Wrap(err, "store")
+	}
+
+	return s.GetCustomerByID(ctx, c.ID)
+}
+
+// Returns a page of the customers whose name or phone number contains search, and how many there are in total
+func (s *service) GetCustomers(ctx context.Context, search string, limit int32, offset int32) ([]Customer, int, error) {
+	// Phones are stored as digits so only the digits of the search can match them
+	phone := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, search)
+
+	rows, err := s.store.GetCustomers(ctx, sqlc.GetCustomersParams{Limit: limit, Offset: offset, Search: search, Phone: phone})
+	if err != nil {
+		return []Customer{}, 0, errors.Wrap(err, "store")
+	}
+
+	total, err := s.store.CountCustomers(ctx, sqlc.CountCustomersParams{Search: search, Phone: phone})
+	if err != nil {
+		return []Customer{}, 0, errors.Wrap(err, "store")
+	}
+
+	customers := []Customer{}
+	for _, c := range rows {
+		customers = append(customers, *newCustomer(c))
+	}
+
+	return customers, int(total), nil
+}
+
+// Returns the customer with their phones, addresses and order stats
+func (s *service) GetCustomerByID(ctx context.Context, id int32) (*Customer, error) {
+	c, err := s.store.GetCustomerByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, errors.Wrap(api.ErrUnknownCustomer.Error, "store")
+		}
+		return nil, errors.Wrap(err, "store")
+	}
+
+	return s.loadCustomer(ctx, c)
+}
+
+// Finds the customer a phone number belongs to, used when taking an order to recognise the caller
+func (s *service) LookupByPhone(ctx context.Context, phone string) (*Customer, error) {
+	normalized, ok := NormalizePhone(phone)
+	if !ok {
+		return nil, errors.Wrap(api.ErrInvalidPhone.Error, "phone")
+	}
+
+	c, err := s.store.GetCustomerByPhone(ctx, normalized)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, errors.Wrap(api.ErrUnknownCustomer.Error, "store")
+		}
+		return nil, errors.Wrap(err, "store")
+	}
+
+	return s.loadCustomer(ctx, c)
+}
+
+func (s *service) loadCustomer(ctx context.Context, c sqlc.Customer) (*Customer, error) {
+	customer := newCustomer(c)
+
+	phones, err := s.store.GetCustomerPhones(ctx, c.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "store")
+	}
+
+	customer.Phones = []Phone{}
+	for _, p := range phones {
+		customer.Phones = append(customer.Phones, Phone{Phone: p.Phone, Label: p.Label})
+	}
+
+	addresses, err := s.store.GetCustomerAddresses(ctx, c.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "store")
+	}
+
+	customer.Addresses = []Address{}
+	for _, a := range addresses {
+		customer.Addresses = append(customer.Addresses, Address{ID: a.ID, Label: a.Label, Address: a.Address, Notes: a.Notes})
+	}
+
+	stats, err := s.store.GetCustomerStats(ctx, pgtype.Int4{Int32: c.ID, Valid: true})
+	if err != nil {
+		return nil, errors.Wrap(err, "store")
+	}
+
+	customer.Stats = &CustomerStats{
+		OrderCount:    stats.OrderCount,
+		LifetimeValue: api.Round(stats.LifetimeValue),
+		FirstOrderAt:  stats.FirstOrderAt,
+		LastOrderAt:   stats.LastOrderAt,
+	}
+	if stats.OrderCount > 0 {
+		customer.Stats.AverageOrderValue = api.Round(stats.LifetimeValue / float64(stats.OrderCount))
+	}
+
+	return customer, nil
+}
+
+// Returns a page of the orders of the customer newest first and how many they have in total
+func (s *service) GetCustomerOrders(ctx context.Context, id int32, limit int32, offset int32) ([]CustomerOrder, int, error) {
+	if _, err := s.store.GetCustomerByID(ctx, id); err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return []CustomerOrder{}, 0, errors.Wrap(api.ErrUnknownCustomer.Error, "store")
+		}
+		return []CustomerOrder{}, 0, errors.Wrap(err, "store")
+	}
+
+	rows, err := s.store.SearchOrders(ctx, sqlc.SearchOrdersParams{
+		Limit:      limit,
+		Offset:     offset,
+		CustomerID: id,
+		Sort:       "created_at",
+		SortDesc:   true,
+	})
+	if err != nil {
+		return []CustomerOrder{}, 0, errors.Wrap(err, "store")
+	}
+
+	total, err := s.store.CountSearchOrders(ctx, sqlc.CountSearchOrdersParams{CustomerID: id})
+	if err != nil {
+		return []CustomerOrder{}, 0, errors.Wrap(err, "store")
+	}
+
+	orders := []CustomerOrder{}
+	for _, o := range rows {
+		orders = append(orders, CustomerOrder{
+			ID:          o.ID,
+			Type:        o.Type,
+			Status:      o.Status,
+			TableID:     o.TableID,
+			Covers:      o.Covers,
+			Total:       api.Round(o.Total),
+			CreatedAt:   o.CreatedAt,
+			CompletedAt: o.CompletedAt,
+		})
+	}
+
+	return orders, int(total), nil
+}
+
+// Normalizes the phones and drops repeats of the same number, the labels are kept in step with them
+func phoneParams(phones []PhoneInput) (sqlc.AddCustomerPhonesParams, error) {
+	arg := sqlc.AddCustomerPhonesParams{Phones: []string{}, Labels: []string{}}
+	seen := map[string]bool{}
+	for _, p := range phones {
+		phone, ok := NormalizePhone(p.Phone)
+		if !ok {
+			return arg, errors.Wrap(api.ErrInvalidPhone.Error, "phone")
+		}
+
+		if seen[phone] {
+			continue
+		}
+		seen[phone] = true
+
+		arg.Phones = append(arg.Phones, phone)
+		arg.Labels = append(arg.Labels, strings.TrimSpace(p.Label))
+	}
+
+	return arg, nil
+}
+
+func addressParams(addresses []AddressInput) sqlc.AddCustomerAddressesParams {
+	arg := sqlc.AddCustomerAddressesParams{Labels: []string{}, Addresses: []string{}, Notes: []string{}}
+	for _, a := range addresses {
+		arg.Labels = append(arg.Labels, strings.TrimSpace(a.Label))
+		arg.Addresses = append(arg.Addresses, strings.TrimSpace(a.Address))
+		arg.Notes = append(arg.Notes, strings.TrimSpace(a.Notes))
+	}
+
+	return arg
+}
+
+// Strips the spaces, dashes, dots and brackets people type phone numbers with so
+// "+44 (0)20 7946-0958" and "+4402079460958" are the same number. A leading + is kept.
+func NormalizePhone(phone string) (string, bool) {
+	phone = strings.TrimSpace(phone)
+	plus := strings.HasPrefix(phone, "+")
+
+	var b strings.Builder
+	if plus {
+		b.WriteByte('+')
+	}
+
+	digits := 0
+	for _, r := range strings.TrimPrefix(phone, "+") {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+			digits++
+		case r == ' ' || r == '-' || r == '.' || r == '(' || r == ')':
+		default:
+			return "", false
+		}
+	}
+
+	if digits < 3 {
+		return "", false
+	}
+
+	return b.String(), true
+}
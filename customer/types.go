@@ -0,0 +1,98 @@
+package customer
+
+import (
+	"github.com/pdridh/k-line/api"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/pdridh/k-line/db/sqlc"
+)
+
+type CustomerFilters struct {
+	Search string `json:"search"` // Part of a name or phone number
+	Page   int32  `json:"page"`   // The request is sent as page but converted to offset for db
+	Limit  int32  `json:"limit"`
+}
+
+func (f *CustomerFilters) Validate(maxLimit int32, defaultLimit int32) {
+	api.ClampPage(&f.Limit, &f.Page, maxLimit, defaultLimit)
+
+	f.Search = strings.TrimSpace(f.Search)
+}
+
+type OrderFilters struct {
+	Page  int32 `json:"page"` // The request is sent as page but converted to offset for db
+	Limit int32 `json:"limit"`
+}
+
+func (f *OrderFilters) Validate(maxLimit int32, defaultLimit int32) {
+	api.ClampPage(&f.Limit, &f.Page, maxLimit, defaultLimit)
+}
+
+// A phone number as it is sent, it can be typed with spaces, dashes, dots or brackets
+type PhoneInput struct {
+	Phone string `json:"phone" validate:"required"`
+	Label string `json:"label"` // Like mobile or work
+}
+
+type AddressInput struct {
+	Label   string `json:"label"` // Like home or work
+	Address string `json:"address" validate:"required"`
+	Notes   string `json:"notes"` // Like the gate code
+}
+
+type Customer struct {
+	ID          int32            `json:"id"`
+	Name        string           `json:"name"`
+	Email       pgtype.Text      `json:"email"`
+	Notes       pgtype.Text      `json:"notes"`
+	Preferences pgtype.Text      `json:"preferences"` // Like a favourite table or how they take their steak
+	Phones      []Phone          `json:"phones,omitempty"`
+	Addresses   []Address        `json:"addresses,omitempty"`
+	Stats       *CustomerStats   `json:"stats,omitempty"`
+	CreatedAt   pgtype.Timestamp `json:"created_at"`
+}
+
+func newCustomer(c sqlc.Customer) *Customer {
+	return &Customer{
+		ID:          c.ID,
+		Name:        c.Name,
+		Email:       c.Email,
+		Notes:       c.Notes,
+		Preferences: c.Preferences,
+		CreatedAt:   c.CreatedAt,
+	}
+}
+
+type Phone struct {
+	Phone string      `json:"phone"`
+	Label pgtype.Text `json:"label"`
+}
+
+type Address struct {
+	ID      int32       `json:"id"`
+	Label   pgtype.Text `json:"label"`
+	Address string      `json:"address"`
+	Notes   pgtype.Text `json:"notes"`
+}
+
+// Only completed orders count towards the value, cancelled items are left out
+type CustomerStats struct {
+	OrderCount        int32            `json:"order_count"`
+	LifetimeValue     float64          `json:"lifetime_value"`
+	AverageOrderValue float64          `json:"average_order_value"`
+	FirstOrderAt      pgtype.Timestamp `json:"first_order_at"`
+	LastOrderAt       pgtype.Timestamp `json:"last_order_at"`
+}
+
+// An order in the history of a customer, Total leaves out cancelled items
+type CustomerOrder struct {
+	ID          pgtype.UUID      `json:"id"`
+	Type        sqlc.OrderType   `json:"type"`
+	Status      sqlc.OrderStatus `json:"status"`
+	TableID     pgtype.Text      `json:"table_id"`
+	Covers      pgtype.Int2      `json:"covers"`
+	Total       float64          `json:"total"`
+	CreatedAt   pgtype.Timestamp `json:"created_at"`
+	CompletedAt pgtype.Timestamp `json:"completed_at"`
+}
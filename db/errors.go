@@ -9,10 +9,20 @@ import (
 const (
 	ForeignKeyViolation = "23503"
 	UniqueViolation     = "23505"
+	CheckViolation      = "23514"
 )
 
 var (
-	ErrRecordNotFound = pgx.ErrNoRows
+	ErrRecordNotFound       = pgx.ErrNoRows
+	ErrInsufficientBalance  = errors.New("balance is too low")
+	ErrDiscountExceedsBill  = errors.New("discount is more than what is left to pay")
+	ErrUsageLimitReached    = errors.New("usage limit reached")
+	ErrCustomerLimitReached = errors.New("customer usage limit reached")
+	ErrDrawersOpen          = errors.New("drawer sessions are still open")
+	ErrDrawerClosed         = errors.New("drawer session is closed")
+	ErrOrderNotOngoing      = errors.New("order is not ongoing")
+	ErrOrderCustomer        = errors.New("order is not linked to the customer")
+	ErrTenderExceedsBill    = errors.New("tender is more than what is left to pay")
 )
 
 func GetSQLErrorCode(err error) string {
@@ -0,0 +1,3 @@
+ALTER TABLE "users" ADD COLUMN "active" bool NOT NULL DEFAULT true;
+
+CREATE INDEX ON "users" ("type");
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "user_pins" CASCADE;
+DROP TABLE IF EXISTS "devices" CASCADE;
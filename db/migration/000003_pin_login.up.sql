@@ -0,0 +1,21 @@
+CREATE TABLE "devices" (
+  "id" uuid PRIMARY KEY DEFAULT (gen_random_uuid()),
+  "name" text NOT NULL,
+  "token_hash" text UNIQUE NOT NULL,
+  "registered_by" uuid NOT NULL,
+  "created_at" timestamp DEFAULT (now()),
+  "last_seen_at" timestamp,
+  "revoked_at" timestamp
+);
+
+CREATE TABLE "user_pins" (
+  "user_id" uuid PRIMARY KEY,
+  "pin" text NOT NULL,
+  "failed_attempts" int NOT NULL DEFAULT 0,
+  "locked_until" timestamp,
+  "updated_at" timestamp DEFAULT (now())
+);
+
+ALTER TABLE "devices" ADD FOREIGN KEY ("registered_by") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "user_pins" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
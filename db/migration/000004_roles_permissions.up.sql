@@ -0,0 +1,48 @@
+CREATE TABLE "roles" (
+  "id" serial PRIMARY KEY,
+  "name" text UNIQUE NOT NULL,
+  "description" text,
+  "superuser" bool NOT NULL DEFAULT false,
+  "created_at" timestamp DEFAULT (now())
+);
+
+CREATE TABLE "role_permissions" (
+  "role_id" int NOT NULL,
+  "permission" text NOT NULL,
+  PRIMARY KEY ("role_id", "permission")
+);
+
+ALTER TABLE "role_permissions" ADD FOREIGN KEY ("role_id") REFERENCES "roles" ("id") ON DELETE CASCADE;
+
+-- Seed a role for every user type so existing accounts keep the access they had
+INSERT INTO "roles" ("name", "description", "superuser") VALUES
+  ('admin', 'Full access to everything', true),
+  ('register', 'Cashier at the register', false),
+  ('kitchen', 'Kitchen staff', false),
+  ('waiter', 'Front of house staff', false),
+  ('driver', 'Delivery drivers', false);
+
+INSERT INTO "role_permissions" ("role_id", "permission")
+SELECT r.id, p.permission FROM "roles" r
+JOIN (VALUES
+  ('waiter', 'menu.read'),
+  ('waiter', 'table.read'),
+  ('waiter', 'order.read'),
+  ('waiter', 'order.create'),
+  ('waiter', 'order.item.update'),
+  ('waiter', 'order.void'),
+  ('kitchen', 'menu.read'),
+  ('kitchen', 'order.read'),
+  ('kitchen', 'order.item.update'),
+  ('kitchen', 'order.void')
+) AS p (role_name, permission) ON p.role_name = r.name;
+
+ALTER TABLE "users" ADD COLUMN "role_id" int;
+
+UPDATE "users" u SET "role_id" = r.id FROM "roles" r WHERE r.name = u.type::text;
+
+ALTER TABLE "users" ALTER COLUMN "role_id" SET NOT NULL;
+
+ALTER TABLE "users" ADD FOREIGN KEY ("role_id") REFERENCES "roles" ("id");
+
+CREATE INDEX ON "users" ("role_id");
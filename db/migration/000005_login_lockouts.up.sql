@@ -0,0 +1,7 @@
+CREATE TABLE "login_lockouts" (
+  "email" text PRIMARY KEY,
+  "failed_attempts" int NOT NULL DEFAULT 0,
+  "lockouts" int NOT NULL DEFAULT 0,
+  "locked_until" timestamp,
+  "last_failed_at" timestamp
+);
@@ -0,0 +1,19 @@
+CREATE TABLE "api_keys" (
+  "id" uuid PRIMARY KEY DEFAULT (gen_random_uuid()),
+  "name" text NOT NULL,
+  "key_hash" text UNIQUE NOT NULL,
+  "prefix" text NOT NULL,
+  "user_id" uuid NOT NULL,
+  "permissions" text[] NOT NULL DEFAULT '{}',
+  "created_by" uuid NOT NULL,
+  "expires_at" timestamp,
+  "created_at" timestamp DEFAULT (now()),
+  "last_used_at" timestamp,
+  "revoked_at" timestamp
+);
+
+CREATE INDEX ON "api_keys" ("user_id");
+
+ALTER TABLE "api_keys" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "api_keys" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("id") ON DELETE CASCADE;
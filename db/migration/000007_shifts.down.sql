@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "breaks" CASCADE;
+DROP TABLE IF EXISTS "time_entries" CASCADE;
+DROP TABLE IF EXISTS "scheduled_shifts" CASCADE;
@@ -0,0 +1,43 @@
+CREATE TABLE "time_entries" (
+  "id" bigserial PRIMARY KEY,
+  "user_id" uuid NOT NULL,
+  "clock_in" timestamp NOT NULL DEFAULT (now()),
+  "clock_out" timestamp,
+  "notes" text
+);
+
+CREATE TABLE "breaks" (
+  "id" bigserial PRIMARY KEY,
+  "time_entry_id" bigint NOT NULL,
+  "paid" bool NOT NULL DEFAULT false,
+  "started_at" timestamp NOT NULL DEFAULT (now()),
+  "ended_at" timestamp
+);
+
+CREATE TABLE "scheduled_shifts" (
+  "id" bigserial PRIMARY KEY,
+  "user_id" uuid NOT NULL,
+  "starts_at" timestamp NOT NULL,
+  "ends_at" timestamp NOT NULL,
+  "notes" text,
+  "created_by" uuid NOT NULL,
+  "created_at" timestamp DEFAULT (now()),
+  CHECK ("ends_at" > "starts_at")
+);
+
+CREATE INDEX ON "time_entries" ("user_id", "clock_in");
+
+-- A user can only be clocked in once and on one break at a time
+CREATE UNIQUE INDEX ON "time_entries" ("user_id") WHERE "clock_out" IS NULL;
+
+CREATE UNIQUE INDEX ON "breaks" ("time_entry_id") WHERE "ended_at" IS NULL;
+
+CREATE INDEX ON "scheduled_shifts" ("user_id", "starts_at");
+
+ALTER TABLE "time_entries" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "breaks" ADD FOREIGN KEY ("time_entry_id") REFERENCES "time_entries" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "scheduled_shifts" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "scheduled_shifts" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("id") ON DELETE CASCADE;
@@ -0,0 +1,13 @@
+DELETE FROM "role_permissions" WHERE "permission" = 'cash.manage';
+
+ALTER TABLE "orders" DROP COLUMN IF EXISTS "covers";
+
+DROP INDEX IF EXISTS "orders_created_at_idx";
+
+DROP TABLE IF EXISTS "tenders" CASCADE;
+DROP TABLE IF EXISTS "cash_movements" CASCADE;
+DROP TABLE IF EXISTS "drawer_sessions" CASCADE;
+DROP TABLE IF EXISTS "z_reports" CASCADE;
+DROP FUNCTION IF EXISTS "z_reports_locked"();
+DROP TYPE IF EXISTS "tender_method";
+DROP TYPE IF EXISTS "cash_movement_type";
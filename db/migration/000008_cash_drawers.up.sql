@@ -0,0 +1,116 @@
+CREATE TYPE "cash_movement_type" AS ENUM (
+  'pay_in',
+  'pay_out'
+);
+
+CREATE TYPE "tender_method" AS ENUM (
+  'cash',
+  'card',
+  'other'
+);
+
+CREATE TABLE "drawer_sessions" (
+  "id" bigserial PRIMARY KEY,
+  "drawer" text NOT NULL,
+  "opening_float" float NOT NULL CHECK ("opening_float" >= 0),
+  "opened_by" uuid NOT NULL,
+  "opened_at" timestamp NOT NULL DEFAULT (now()),
+  "expected_amount" float,
+  "counted_amount" float,
+  "closed_by" uuid,
+  "closed_at" timestamp,
+  "notes" text,
+  "z_report_id" bigint
+);
+
+CREATE TABLE "cash_movements" (
+  "id" bigserial PRIMARY KEY,
+  "session_id" bigint NOT NULL,
+  "type" cash_movement_type NOT NULL,
+  "amount" float NOT NULL CHECK ("amount" > 0),
+  "reason" text NOT NULL,
+  "created_by" uuid NOT NULL,
+  "created_at" timestamp NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "tenders" (
+  "id" bigserial PRIMARY KEY,
+  "session_id" bigint NOT NULL,
+  "order_id" uuid NOT NULL,
+  "method" tender_method NOT NULL,
+  "amount" float NOT NULL CHECK ("amount" > 0),
+  "created_by" uuid NOT NULL,
+  "created_at" timestamp NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "z_reports" (
+  "id" bigserial PRIMARY KEY,
+  "period_start" timestamp UNIQUE NOT NULL,
+  "period_end" timestamp NOT NULL,
+  "order_count" int NOT NULL,
+  "covers" int NOT NULL,
+  "gross_sales" float NOT NULL,
+  "discounts" float NOT NULL,
+  "net_sales" float NOT NULL,
+  "tax_rate" float NOT NULL,
+  "tax" float NOT NULL,
+  "void_count" int NOT NULL,
+  "void_amount" float NOT NULL,
+  "cash_tenders" float NOT NULL,
+  "card_tenders" float NOT NULL,
+  "other_tenders" float NOT NULL,
+  "drawer_sessions" int NOT NULL,
+  "expected_cash" float NOT NULL,
+  "counted_cash" float NOT NULL,
+  "cash_variance" float NOT NULL,
+  "closed_by" uuid NOT NULL,
+  "closed_at" timestamp NOT NULL DEFAULT (now())
+);
+
+-- Guests seated with a dining order, counted as covers in the z report
+ALTER TABLE "orders" ADD COLUMN "covers" smallint;
+
+-- Only one open session per drawer
+CREATE UNIQUE INDEX ON "drawer_sessions" ("drawer") WHERE "closed_at" IS NULL;
+
+CREATE INDEX ON "drawer_sessions" ("z_report_id");
+
+CREATE INDEX ON "cash_movements" ("session_id");
+
+CREATE INDEX ON "tenders" ("session_id");
+
+CREATE INDEX ON "tenders" ("order_id");
+
+CREATE INDEX ON "orders" ("created_at");
+
+ALTER TABLE "drawer_sessions" ADD FOREIGN KEY ("opened_by") REFERENCES "users" ("id");
+
+ALTER TABLE "drawer_sessions" ADD FOREIGN KEY ("closed_by") REFERENCES "users" ("id");
+
+ALTER TABLE "drawer_sessions" ADD FOREIGN KEY ("z_report_id") REFERENCES "z_reports" ("id");
+
+ALTER TABLE "cash_movements" ADD FOREIGN KEY ("session_id") REFERENCES "drawer_sessions" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "cash_movements" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("id");
+
+ALTER TABLE "tenders" ADD FOREIGN KEY ("session_id") REFERENCES "drawer_sessions" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "tenders" ADD FOREIGN KEY ("order_id") REFERENCES "orders" ("id");
+
+ALTER TABLE "tenders" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("id");
+
+ALTER TABLE "z_reports" ADD FOREIGN KEY ("closed_by") REFERENCES "users" ("id");
+
+-- Closed z reports are final, nothing is allowed to change them afterwards
+CREATE FUNCTION "z_reports_locked"() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'z report % is closed and cannot be changed', OLD.id;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "z_reports_locked" BEFORE UPDATE OR DELETE ON "z_reports"
+FOR EACH ROW EXECUTE FUNCTION "z_reports_locked"();
+
+-- The register finally has something to do
+INSERT INTO "role_permissions" ("role_id", "permission")
+SELECT r.id, 'cash.manage' FROM "roles" r WHERE r.name = 'register';
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS "order_items_item_id_added_at_idx";
+
+DROP TABLE IF EXISTS "menu_item_costs" CASCADE;
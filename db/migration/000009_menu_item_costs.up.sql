@@ -0,0 +1,16 @@
+-- Cost history of menu items, a sale is costed with the cost that was in effect when it was ordered
+CREATE TABLE "menu_item_costs" (
+  "id" bigserial PRIMARY KEY,
+  "item_id" int NOT NULL,
+  "cost" float NOT NULL CHECK ("cost" >= 0),
+  "effective_from" timestamp NOT NULL DEFAULT (now()),
+  "created_by" uuid NOT NULL
+);
+
+CREATE INDEX ON "menu_item_costs" ("item_id", "effective_from");
+
+CREATE INDEX ON "order_items" ("item_id", "added_at");
+
+ALTER TABLE "menu_item_costs" ADD FOREIGN KEY ("item_id") REFERENCES "menu_items" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "menu_item_costs" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("id");
@@ -0,0 +1,7 @@
+ALTER TABLE "menu_items" DROP COLUMN IF EXISTS "eighty_sixed";
+ALTER TABLE "order_items" DROP COLUMN IF EXISTS "depleted_at";
+
+DROP TABLE IF EXISTS "stock_movements" CASCADE;
+DROP TABLE IF EXISTS "recipes" CASCADE;
+DROP TABLE IF EXISTS "ingredients" CASCADE;
+DROP TYPE IF EXISTS "stock_movement_type";
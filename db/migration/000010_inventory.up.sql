@@ -0,0 +1,53 @@
+CREATE TYPE "stock_movement_type" AS ENUM (
+  'sale',
+  'adjustment',
+  'waste'
+);
+
+CREATE TABLE "ingredients" (
+  "id" serial PRIMARY KEY,
+  "name" text UNIQUE NOT NULL,
+  "unit" text NOT NULL,
+  "stock" float NOT NULL DEFAULT 0,
+  "low_stock_level" float NOT NULL DEFAULT 0 CHECK ("low_stock_level" >= 0),
+  "created_at" timestamp DEFAULT (now())
+);
+
+CREATE TABLE "recipes" (
+  "item_id" int NOT NULL,
+  "ingredient_id" int NOT NULL,
+  "quantity" float NOT NULL CHECK ("quantity" > 0),
+  PRIMARY KEY ("item_id", "ingredient_id")
+);
+
+-- Every change to stock, quantity is negative when stock goes down
+CREATE TABLE "stock_movements" (
+  "id" bigserial PRIMARY KEY,
+  "ingredient_id" int NOT NULL,
+  "type" stock_movement_type NOT NULL,
+  "quantity" float NOT NULL,
+  "reason" text,
+  "order_item_id" bigint,
+  "created_by" uuid,
+  "created_at" timestamp NOT NULL DEFAULT (now())
+);
+
+-- Set once the ingredients of the order item are taken out of stock so it only happens once
+ALTER TABLE "order_items" ADD COLUMN "depleted_at" timestamp;
+
+-- Items are 86'd (cant be ordered) while an ingredient doesnt have enough stock for one portion
+ALTER TABLE "menu_items" ADD COLUMN "eighty_sixed" bool NOT NULL DEFAULT false;
+
+CREATE INDEX ON "recipes" ("ingredient_id");
+
+CREATE INDEX ON "stock_movements" ("ingredient_id", "created_at");
+
+ALTER TABLE "recipes" ADD FOREIGN KEY ("item_id") REFERENCES "menu_items" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "recipes" ADD FOREIGN KEY ("ingredient_id") REFERENCES "ingredients" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "stock_movements" ADD FOREIGN KEY ("ingredient_id") REFERENCES "ingredients" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "stock_movements" ADD FOREIGN KEY ("order_item_id") REFERENCES "order_items" ("id") ON DELETE SET NULL;
+
+ALTER TABLE "stock_movements" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("id");
@@ -0,0 +1,19 @@
+ALTER TABLE "stock_movements" DROP COLUMN IF EXISTS "purchase_order_id";
+ALTER TABLE "ingredients" DROP COLUMN IF EXISTS "supplier_id";
+ALTER TABLE "ingredients" DROP COLUMN IF EXISTS "par_level";
+
+DROP TABLE IF EXISTS "purchase_order_items" CASCADE;
+DROP TABLE IF EXISTS "purchase_orders" CASCADE;
+DROP TABLE IF EXISTS "suppliers" CASCADE;
+DROP TYPE IF EXISTS "purchase_order_status";
+
+-- Enum values cant be dropped, so rebuild the type without 'received'
+DELETE FROM "stock_movements" WHERE "type" = 'received';
+ALTER TYPE "stock_movement_type" RENAME TO "stock_movement_type_old";
+CREATE TYPE "stock_movement_type" AS ENUM (
+  'sale',
+  'adjustment',
+  'waste'
+);
+ALTER TABLE "stock_movements" ALTER COLUMN "type" TYPE "stock_movement_type" USING "type"::text::"stock_movement_type";
+DROP TYPE "stock_movement_type_old";
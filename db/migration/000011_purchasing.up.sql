@@ -0,0 +1,65 @@
+ALTER TYPE "stock_movement_type" ADD VALUE 'received';
+
+CREATE TYPE "purchase_order_status" AS ENUM (
+  'draft',
+  'ordered',
+  'partially_received',
+  'received',
+  'cancelled'
+);
+
+CREATE TABLE "suppliers" (
+  "id" serial PRIMARY KEY,
+  "name" text UNIQUE NOT NULL,
+  "contact_name" text,
+  "phone" text,
+  "email" text,
+  "notes" text,
+  "created_at" timestamp DEFAULT (now())
+);
+
+CREATE TABLE "purchase_orders" (
+  "id" bigserial PRIMARY KEY,
+  "supplier_id" int NOT NULL,
+  "status" purchase_order_status NOT NULL DEFAULT 'draft',
+  "notes" text,
+  "created_by" uuid NOT NULL,
+  "created_at" timestamp NOT NULL DEFAULT (now()),
+  "ordered_at" timestamp,
+  "received_at" timestamp
+);
+
+CREATE TABLE "purchase_order_items" (
+  "id" bigserial PRIMARY KEY,
+  "purchase_order_id" bigint NOT NULL,
+  "ingredient_id" int NOT NULL,
+  "quantity" float NOT NULL CHECK ("quantity" > 0),
+  "received_quantity" float NOT NULL DEFAULT 0 CHECK ("received_quantity" >= 0 AND "received_quantity" <= "quantity"),
+  "unit_cost" float NOT NULL CHECK ("unit_cost" >= 0),
+  UNIQUE ("purchase_order_id", "ingredient_id")
+);
+
+-- Stock to get back up to when restocking, and who it is usually bought from
+ALTER TABLE "ingredients" ADD COLUMN "par_level" float NOT NULL DEFAULT 0 CHECK ("par_level" >= 0);
+
+ALTER TABLE "ingredients" ADD COLUMN "supplier_id" int;
+
+ALTER TABLE "stock_movements" ADD COLUMN "purchase_order_id" bigint;
+
+CREATE INDEX ON "purchase_orders" ("status");
+
+CREATE INDEX ON "purchase_orders" ("supplier_id");
+
+CREATE INDEX ON "purchase_order_items" ("ingredient_id");
+
+ALTER TABLE "purchase_orders" ADD FOREIGN KEY ("supplier_id") REFERENCES "suppliers" ("id");
+
+ALTER TABLE "purchase_orders" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("id");
+
+ALTER TABLE "purchase_order_items" ADD FOREIGN KEY ("purchase_order_id") REFERENCES "purchase_orders" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "purchase_order_items" ADD FOREIGN KEY ("ingredient_id") REFERENCES "ingredients" ("id");
+
+ALTER TABLE "ingredients" ADD FOREIGN KEY ("supplier_id") REFERENCES "suppliers" ("id") ON DELETE SET NULL;
+
+ALTER TABLE "stock_movements" ADD FOREIGN KEY ("purchase_order_id") REFERENCES "purchase_orders" ("id") ON DELETE SET NULL;
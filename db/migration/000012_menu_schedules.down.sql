@@ -0,0 +1,10 @@
+DROP FUNCTION IF EXISTS "menu_item_price"(int, float, timestamp);
+DROP FUNCTION IF EXISTS "menu_item_available"(int, timestamp);
+DROP FUNCTION IF EXISTS "menu_schedule_open"("menu_schedules", timestamp);
+
+ALTER TABLE "order_items" DROP COLUMN IF EXISTS "unit_price";
+
+DROP TABLE IF EXISTS "schedule_prices" CASCADE;
+DROP TABLE IF EXISTS "menu_schedules" CASCADE;
+DROP TABLE IF EXISTS "menu_entries" CASCADE;
+DROP TABLE IF EXISTS "menus" CASCADE;
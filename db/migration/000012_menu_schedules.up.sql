@@ -0,0 +1,104 @@
+CREATE TABLE "menus" (
+  "id" serial PRIMARY KEY,
+  "name" text UNIQUE NOT NULL,
+  "active" bool NOT NULL DEFAULT true,
+  "created_at" timestamp DEFAULT (now())
+);
+
+CREATE TABLE "menu_entries" (
+  "menu_id" int NOT NULL,
+  "item_id" int NOT NULL,
+  PRIMARY KEY ("menu_id", "item_id")
+);
+
+-- When a menu is served. Days are 0 (sunday) to 6 and empty means every day, a window that ends
+-- before it starts runs past midnight and belongs to the day it started on.
+CREATE TABLE "menu_schedules" (
+  "id" serial PRIMARY KEY,
+  "menu_id" int NOT NULL,
+  "name" text NOT NULL,
+  "days" smallint[] NOT NULL DEFAULT '{}',
+  "start_time" time NOT NULL DEFAULT '00:00',
+  "end_time" time NOT NULL DEFAULT '24:00',
+  "start_date" date,
+  "end_date" date,
+  "created_at" timestamp DEFAULT (now()),
+  CHECK ("days" <@ '{0,1,2,3,4,5,6}'::smallint[]),
+  CHECK ("start_date" IS NULL OR "end_date" IS NULL OR "start_date" <= "end_date")
+);
+
+-- Prices that replace the menu item price while the schedule is on (happy hour)
+CREATE TABLE "schedule_prices" (
+  "schedule_id" int NOT NULL,
+  "item_id" int NOT NULL,
+  "price" float NOT NULL CHECK ("price" >= 0),
+  PRIMARY KEY ("schedule_id", "item_id")
+);
+
+-- The price the item was ordered at, menu prices and schedules can change afterwards
+ALTER TABLE "order_items" ADD COLUMN "unit_price" float;
+
+UPDATE "order_items" oi SET "unit_price" = m."price" FROM "menu_items" m WHERE m."id" = oi."item_id";
+
+ALTER TABLE "order_items" ALTER COLUMN "unit_price" SET NOT NULL;
+
+CREATE INDEX ON "menu_entries" ("item_id");
+
+CREATE INDEX ON "menu_schedules" ("menu_id");
+
+CREATE INDEX ON "schedule_prices" ("item_id");
+
+ALTER TABLE "menu_entries" ADD FOREIGN KEY ("menu_id") REFERENCES "menus" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "menu_entries" ADD FOREIGN KEY ("item_id") REFERENCES "menu_items" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "menu_schedules" ADD FOREIGN KEY ("menu_id") REFERENCES "menus" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "schedule_prices" ADD FOREIGN KEY ("schedule_id") REFERENCES "menu_schedules" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "schedule_prices" ADD FOREIGN KEY ("item_id") REFERENCES "menu_items" ("id") ON DELETE CASCADE;
+
+CREATE FUNCTION "menu_schedule_open"(s "menu_schedules", ts timestamp) RETURNS bool AS $$
+  SELECT w.open
+    AND (cardinality(s.days) = 0 OR EXTRACT(DOW FROM w.day)::smallint = ANY(s.days))
+    AND (s.start_date IS NULL OR w.day >= s.start_date)
+    AND (s.end_date IS NULL OR w.day <= s.end_date)
+  FROM (
+    SELECT
+      CASE WHEN s.start_time < s.end_time
+        THEN ts::time >= s.start_time AND ts::time < s.end_time
+        ELSE ts::time >= s.start_time OR ts::time < s.end_time
+      END AS open,
+      CASE WHEN s.start_time >= s.end_time AND ts::time < s.end_time
+        THEN ts::date - 1
+        ELSE ts::date
+      END AS day
+  ) w
+$$ LANGUAGE sql STABLE;
+
+-- Items that are on no active menu can always be ordered, otherwise one of their menus has to be
+-- served at the time. A menu without schedules is served all the time.
+CREATE FUNCTION "menu_item_available"(item_id int, ts timestamp) RETURNS bool AS $$
+  SELECT NOT EXISTS (
+    SELECT 1 FROM "menu_entries" e
+    JOIN "menus" mn ON mn.id = e.menu_id
+    WHERE e.item_id = $1 AND mn.active
+  ) OR EXISTS (
+    SELECT 1 FROM "menu_entries" e
+    JOIN "menus" mn ON mn.id = e.menu_id
+    WHERE e.item_id = $1 AND mn.active AND (
+      NOT EXISTS (SELECT 1 FROM "menu_schedules" s WHERE s.menu_id = mn.id)
+      OR EXISTS (SELECT 1 FROM "menu_schedules" s WHERE s.menu_id = mn.id AND menu_schedule_open(s, $2))
+    )
+  )
+$$ LANGUAGE sql STABLE;
+
+-- The lowest price of the item among the schedules that are on, or its menu price
+CREATE FUNCTION "menu_item_price"(item_id int, price float, ts timestamp) RETURNS float AS $$
+  SELECT COALESCE((
+    SELECT min(p.price) FROM "schedule_prices" p
+    JOIN "menu_schedules" s ON s.id = p.schedule_id
+    JOIN "menus" mn ON mn.id = s.menu_id
+    WHERE p.item_id = $1 AND mn.active AND menu_schedule_open(s, $3)
+  ), $2)
+$$ LANGUAGE sql STABLE;
@@ -0,0 +1,6 @@
+ALTER TABLE "order_items" DROP COLUMN IF EXISTS "order_combo_id";
+
+DROP TABLE IF EXISTS "order_combos" CASCADE;
+DROP TABLE IF EXISTS "combo_slot_options" CASCADE;
+DROP TABLE IF EXISTS "combo_slots" CASCADE;
+DROP TABLE IF EXISTS "combos" CASCADE;
@@ -0,0 +1,57 @@
+CREATE TABLE "combos" (
+  "id" serial PRIMARY KEY,
+  "name" text UNIQUE NOT NULL,
+  "description" text,
+  "price" float NOT NULL CHECK ("price" >= 0),
+  "active" bool NOT NULL DEFAULT true,
+  "created_at" timestamp DEFAULT (now())
+);
+
+-- A choice the guest makes when ordering the combo, like the starter or the drink
+CREATE TABLE "combo_slots" (
+  "id" serial PRIMARY KEY,
+  "combo_id" int NOT NULL,
+  "name" text NOT NULL,
+  "position" smallint NOT NULL,
+  UNIQUE ("combo_id", "name")
+);
+
+-- Items that can be picked for a slot, some cost extra on top of the combo price
+CREATE TABLE "combo_slot_options" (
+  "slot_id" int NOT NULL,
+  "item_id" int NOT NULL,
+  "upcharge" float NOT NULL DEFAULT 0 CHECK ("upcharge" >= 0),
+  PRIMARY KEY ("slot_id", "item_id")
+);
+
+-- A combo as it was ordered, its items are added to order_items with the price split between them
+CREATE TABLE "order_combos" (
+  "id" bigserial PRIMARY KEY,
+  "order_id" uuid NOT NULL,
+  "combo_id" int NOT NULL,
+  "quantity" int NOT NULL,
+  "unit_price" float NOT NULL,
+  "added_at" timestamp DEFAULT (now())
+);
+
+ALTER TABLE "order_items" ADD COLUMN "order_combo_id" bigint;
+
+CREATE INDEX ON "combo_slots" ("combo_id");
+
+CREATE INDEX ON "combo_slot_options" ("item_id");
+
+CREATE INDEX ON "order_combos" ("order_id");
+
+CREATE INDEX ON "order_items" ("order_combo_id");
+
+ALTER TABLE "combo_slots" ADD FOREIGN KEY ("combo_id") REFERENCES "combos" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "combo_slot_options" ADD FOREIGN KEY ("slot_id") REFERENCES "combo_slots" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "combo_slot_options" ADD FOREIGN KEY ("item_id") REFERENCES "menu_items" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "order_combos" ADD FOREIGN KEY ("order_id") REFERENCES "orders" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "order_combos" ADD FOREIGN KEY ("combo_id") REFERENCES "combos" ("id");
+
+ALTER TABLE "order_items" ADD FOREIGN KEY ("order_combo_id") REFERENCES "order_combos" ("id") ON DELETE CASCADE;
@@ -0,0 +1,7 @@
+DROP FUNCTION IF EXISTS "menu_item_allergens"(int);
+
+ALTER TABLE "orders" DROP COLUMN IF EXISTS "block_allergens";
+ALTER TABLE "orders" DROP COLUMN IF EXISTS "allergies";
+ALTER TABLE "ingredients" DROP COLUMN IF EXISTS "allergens";
+ALTER TABLE "menu_items" DROP COLUMN IF EXISTS "dietary_tags";
+ALTER TABLE "menu_items" DROP COLUMN IF EXISTS "allergens";
@@ -0,0 +1,30 @@
+-- The 14 major allergens and the dietary tags items can be marked with
+ALTER TABLE "menu_items" ADD COLUMN "allergens" text[] NOT NULL DEFAULT '{}'
+  CHECK ("allergens" <@ '{celery,gluten,crustaceans,eggs,fish,lupin,milk,molluscs,mustard,tree_nuts,peanuts,sesame,soya,sulphites}'::text[]);
+
+ALTER TABLE "menu_items" ADD COLUMN "dietary_tags" text[] NOT NULL DEFAULT '{}'
+  CHECK ("dietary_tags" <@ '{vegan,vegetarian,gluten_free,halal}'::text[]);
+
+ALTER TABLE "ingredients" ADD COLUMN "allergens" text[] NOT NULL DEFAULT '{}'
+  CHECK ("allergens" <@ '{celery,gluten,crustaceans,eggs,fish,lupin,milk,molluscs,mustard,tree_nuts,peanuts,sesame,soya,sulphites}'::text[]);
+
+-- What the guests at the order declared they are allergic to, conflicting items are either
+-- blocked or added with a warning
+ALTER TABLE "orders" ADD COLUMN "allergies" text[] NOT NULL DEFAULT '{}'
+  CHECK ("allergies" <@ '{celery,gluten,crustaceans,eggs,fish,lupin,milk,molluscs,mustard,tree_nuts,peanuts,sesame,soya,sulphites}'::text[]);
+
+ALTER TABLE "orders" ADD COLUMN "block_allergens" bool NOT NULL DEFAULT false;
+
+CREATE INDEX ON "menu_items" USING GIN ("dietary_tags");
+
+-- Allergens of the item itself and of every ingredient in its recipe
+CREATE FUNCTION "menu_item_allergens"(item_id int) RETURNS text[] AS $$
+  SELECT ARRAY(
+    SELECT unnest(m.allergens) FROM "menu_items" m WHERE m.id = $1
+    UNION
+    SELECT unnest(i.allergens) FROM "recipes" r
+    JOIN "ingredients" i ON i.id = r.ingredient_id
+    WHERE r.item_id = $1
+    ORDER BY 1
+  )
+$$ LANGUAGE sql STABLE;
@@ -0,0 +1,3 @@
+-- Keys of the resized images in the blob store, urls are built from them so the storage can move
+ALTER TABLE "menu_items" ADD COLUMN "image_key" text;
+ALTER TABLE "menu_items" ADD COLUMN "thumbnail_key" text;
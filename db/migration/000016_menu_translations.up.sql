@@ -0,0 +1,11 @@
+-- Item names and descriptions in other languages, the ones on menu_items are in the default language
+CREATE TABLE "menu_item_translations" (
+  "item_id" int NOT NULL,
+  "lang" text NOT NULL CHECK ("lang" ~ '^[a-z]{2,3}$'),
+  "name" text NOT NULL,
+  "description" text,
+  "updated_at" timestamp NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("item_id", "lang")
+);
+
+ALTER TABLE "menu_item_translations" ADD FOREIGN KEY ("item_id") REFERENCES "menu_items" ("id") ON DELETE CASCADE;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS "menu_item_translations_name_idx";
+DROP INDEX IF EXISTS "menu_item_translations_menu_search_document_idx";
+DROP INDEX IF EXISTS "menu_items_name_idx";
+DROP INDEX IF EXISTS "menu_items_menu_search_document_idx";
+DROP FUNCTION IF EXISTS "menu_search_document"(text, text);
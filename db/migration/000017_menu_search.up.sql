@@ -0,0 +1,15 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- What menu searches match against, the name counts more than the description.
+-- The simple config doesnt stem so it works the same for every language the menu is translated to,
+-- typos and partial words are caught by trigram similarity on the name instead.
+CREATE FUNCTION "menu_search_document"(name text, description text) RETURNS tsvector AS $$
+  SELECT setweight(to_tsvector('simple', coalesce($1, '')), 'A') ||
+         setweight(to_tsvector('simple', coalesce($2, '')), 'B')
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE INDEX ON "menu_items" USING GIN (menu_search_document("name", "description"));
+CREATE INDEX ON "menu_items" USING GIN ("name" gin_trgm_ops);
+
+CREATE INDEX ON "menu_item_translations" USING GIN (menu_search_document("name", "description"));
+CREATE INDEX ON "menu_item_translations" USING GIN ("name" gin_trgm_ops);
@@ -0,0 +1,6 @@
+DELETE FROM "role_permissions" WHERE "permission" = 'customer.manage';
+
+ALTER TABLE "orders" DROP COLUMN IF EXISTS "customer_id";
+DROP TABLE IF EXISTS "customer_addresses";
+DROP TABLE IF EXISTS "customer_phones";
+DROP TABLE IF EXISTS "customers";
@@ -0,0 +1,43 @@
+CREATE TABLE "customers" (
+  "id" serial PRIMARY KEY,
+  "name" text NOT NULL,
+  "email" text,
+  "notes" text,
+  "preferences" text,
+  "created_at" timestamp DEFAULT (now())
+);
+
+-- Phones are stored normalized (digits with an optional leading +) so lookups match however they were typed
+CREATE TABLE "customer_phones" (
+  "phone" text PRIMARY KEY CHECK ("phone" ~ '^\+?[0-9]{3,}$'),
+  "customer_id" int NOT NULL,
+  "label" text
+);
+
+CREATE TABLE "customer_addresses" (
+  "id" serial PRIMARY KEY,
+  "customer_id" int NOT NULL,
+  "label" text,
+  "address" text NOT NULL,
+  "notes" text
+);
+
+ALTER TABLE "orders" ADD COLUMN "customer_id" int;
+
+CREATE INDEX ON "customers" ("name");
+
+CREATE INDEX ON "customer_phones" ("customer_id");
+
+CREATE INDEX ON "customer_addresses" ("customer_id");
+
+CREATE INDEX ON "orders" ("customer_id");
+
+ALTER TABLE "customer_phones" ADD FOREIGN KEY ("customer_id") REFERENCES "customers" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "customer_addresses" ADD FOREIGN KEY ("customer_id") REFERENCES "customers" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "orders" ADD FOREIGN KEY ("customer_id") REFERENCES "customers" ("id") ON DELETE SET NULL;
+
+-- The register takes the phone orders so it keeps the customer book
+INSERT INTO "role_permissions" ("role_id", "permission")
+SELECT r.id, 'customer.manage' FROM "roles" r WHERE r.name = 'register';
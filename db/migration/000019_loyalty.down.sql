@@ -0,0 +1,8 @@
+DELETE FROM "role_permissions" WHERE "permission" IN ('loyalty.redeem', 'loyalty.manage');
+
+DROP TABLE IF EXISTS "order_discounts" CASCADE;
+DROP TABLE IF EXISTS "loyalty_transactions" CASCADE;
+DROP TABLE IF EXISTS "loyalty_tiers" CASCADE;
+DROP FUNCTION IF EXISTS "loyalty_transactions_locked"();
+DROP TYPE IF EXISTS "discount_type";
+DROP TYPE IF EXISTS "loyalty_transaction_type";
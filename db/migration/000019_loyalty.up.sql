@@ -0,0 +1,70 @@
+CREATE TYPE "loyalty_transaction_type" AS ENUM (
+  'earn',
+  'redeem',
+  'adjust'
+);
+
+CREATE TYPE "discount_type" AS ENUM (
+  'loyalty'
+);
+
+-- Customers reach a tier by the points they earned in total, redeeming never drops them a tier
+CREATE TABLE "loyalty_tiers" (
+  "id" serial PRIMARY KEY,
+  "name" text UNIQUE NOT NULL,
+  "min_points" int UNIQUE NOT NULL CHECK ("min_points" >= 0),
+  "multiplier" float NOT NULL DEFAULT 1 CHECK ("multiplier" > 0),
+  "created_at" timestamp NOT NULL DEFAULT (now())
+);
+
+-- The balance of a customer is the sum of their points, it is never stored anywhere else
+CREATE TABLE "loyalty_transactions" (
+  "id" bigserial PRIMARY KEY,
+  "customer_id" int NOT NULL,
+  "type" loyalty_transaction_type NOT NULL,
+  "points" int NOT NULL CHECK ("points" <> 0),
+  "order_id" uuid,
+  "note" text,
+  "created_by" uuid,
+  "created_at" timestamp NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "order_discounts" (
+  "id" bigserial PRIMARY KEY,
+  "order_id" uuid NOT NULL,
+  "type" discount_type NOT NULL,
+  "description" text NOT NULL,
+  "amount" float NOT NULL CHECK ("amount" > 0),
+  "created_by" uuid NOT NULL,
+  "created_at" timestamp NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "loyalty_transactions" ("customer_id", "created_at");
+
+-- An order only ever earns points once
+CREATE UNIQUE INDEX "loyalty_transactions_earn_order_idx" ON "loyalty_transactions" ("order_id") WHERE "type" = 'earn';
+
+CREATE INDEX ON "order_discounts" ("order_id");
+
+ALTER TABLE "loyalty_transactions" ADD FOREIGN KEY ("customer_id") REFERENCES "customers" ("id");
+
+ALTER TABLE "loyalty_transactions" ADD FOREIGN KEY ("order_id") REFERENCES "orders" ("id");
+
+ALTER TABLE "loyalty_transactions" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("id");
+
+ALTER TABLE "order_discounts" ADD FOREIGN KEY ("order_id") REFERENCES "orders" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "order_discounts" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("id");
+
+-- The ledger is append only so balances can always be worked out again from it, mistakes are fixed with adjustments
+CREATE FUNCTION "loyalty_transactions_locked"() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'loyalty transaction % cannot be changed', OLD.id;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "loyalty_transactions_locked" BEFORE UPDATE OR DELETE ON "loyalty_transactions"
+FOR EACH ROW EXECUTE FUNCTION "loyalty_transactions_locked"();
+
+INSERT INTO "role_permissions" ("role_id", "permission")
+SELECT r.id, 'loyalty.redeem' FROM "roles" r WHERE r.name = 'register';
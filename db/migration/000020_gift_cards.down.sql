@@ -0,0 +1,17 @@
+DELETE FROM "role_permissions" WHERE "permission" = 'giftcard.manage';
+
+DROP TABLE IF EXISTS "gift_card_transactions" CASCADE;
+DROP TABLE IF EXISTS "gift_cards" CASCADE;
+DROP FUNCTION IF EXISTS "gift_card_transactions_locked"();
+DROP TYPE IF EXISTS "gift_card_transaction_type";
+
+-- Enum values cant be dropped, so rebuild the type without 'gift_card'
+DELETE FROM "tenders" WHERE "method" = 'gift_card';
+ALTER TYPE "tender_method" RENAME TO "tender_method_old";
+CREATE TYPE "tender_method" AS ENUM (
+  'cash',
+  'card',
+  'other'
+);
+ALTER TABLE "tenders" ALTER COLUMN "method" TYPE "tender_method" USING "method"::text::"tender_method";
+DROP TYPE "tender_method_old";
@@ -0,0 +1,60 @@
+ALTER TYPE "tender_method" ADD VALUE 'gift_card';
+
+CREATE TYPE "gift_card_transaction_type" AS ENUM (
+  'issue',
+  'reload',
+  'redeem'
+);
+
+-- balance is kept on the card so a redemption is a single conditional update that cant overspend,
+-- the transactions are the history it can always be checked against
+CREATE TABLE "gift_cards" (
+  "id" serial PRIMARY KEY,
+  "code" text UNIQUE NOT NULL,
+  "balance" float NOT NULL CHECK ("balance" >= 0),
+  "expires_at" timestamp,
+  "customer_id" int,
+  "issued_by" uuid NOT NULL,
+  "created_at" timestamp NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "gift_card_transactions" (
+  "id" bigserial PRIMARY KEY,
+  "gift_card_id" int NOT NULL,
+  "type" gift_card_transaction_type NOT NULL,
+  "amount" float NOT NULL CHECK ("amount" <> 0),
+  "balance_after" float NOT NULL,
+  "order_id" uuid,
+  "tender_id" bigint,
+  "created_by" uuid NOT NULL,
+  "created_at" timestamp NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "gift_cards" ("customer_id");
+
+CREATE INDEX ON "gift_card_transactions" ("gift_card_id", "created_at");
+
+ALTER TABLE "gift_cards" ADD FOREIGN KEY ("customer_id") REFERENCES "customers" ("id") ON DELETE SET NULL;
+
+ALTER TABLE "gift_cards" ADD FOREIGN KEY ("issued_by") REFERENCES "users" ("id");
+
+ALTER TABLE "gift_card_transactions" ADD FOREIGN KEY ("gift_card_id") REFERENCES "gift_cards" ("id");
+
+ALTER TABLE "gift_card_transactions" ADD FOREIGN KEY ("order_id") REFERENCES "orders" ("id");
+
+ALTER TABLE "gift_card_transactions" ADD FOREIGN KEY ("tender_id") REFERENCES "tenders" ("id");
+
+ALTER TABLE "gift_card_transactions" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("id");
+
+CREATE FUNCTION "gift_card_transactions_locked"() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'gift card transaction % cannot be changed', OLD.id;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "gift_card_transactions_locked" BEFORE UPDATE OR DELETE ON "gift_card_transactions"
+FOR EACH ROW EXECUTE FUNCTION "gift_card_transactions_locked"();
+
+-- Cards are sold at the register
+INSERT INTO "role_permissions" ("role_id", "permission")
+SELECT r.id, 'giftcard.manage' FROM "roles" r WHERE r.name = 'register';
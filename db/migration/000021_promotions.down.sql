@@ -0,0 +1,16 @@
+DELETE FROM "role_permissions" WHERE "permission" = 'promotion.manage';
+
+DELETE FROM "order_discounts" WHERE "type" = 'promotion';
+DROP INDEX IF EXISTS "order_discounts_promotion_idx";
+ALTER TABLE "order_discounts" DROP COLUMN IF EXISTS "promotion_id";
+
+DROP TABLE IF EXISTS "promotions" CASCADE;
+DROP TYPE IF EXISTS "promotion_type";
+
+-- Enum values cant be dropped, so rebuild the type without 'promotion'
+ALTER TYPE "discount_type" RENAME TO "discount_type_old";
+CREATE TYPE "discount_type" AS ENUM (
+  'loyalty'
+);
+ALTER TABLE "order_discounts" ALTER COLUMN "type" TYPE "discount_type" USING "type"::text::"discount_type";
+DROP TYPE "discount_type_old";
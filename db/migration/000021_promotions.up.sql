@@ -0,0 +1,54 @@
+ALTER TYPE "discount_type" ADD VALUE 'promotion';
+
+CREATE TYPE "promotion_type" AS ENUM (
+  'percentage',
+  'fixed',
+  'buy_x_get_y'
+);
+
+-- Promotions without a code are offered on every order they hold for, the ones with a code only when it
+-- is entered. value is the percent off for percentage, the amount off for fixed and the percent off the
+-- get items for buy_x_get_y (100 makes them free). The time window works like menu_schedules.
+CREATE TABLE "promotions" (
+  "id" serial PRIMARY KEY,
+  "name" text NOT NULL,
+  "code" text UNIQUE,
+  "type" promotion_type NOT NULL,
+  "value" float NOT NULL CHECK ("value" > 0),
+  "buy_item_id" int,
+  "buy_quantity" int NOT NULL DEFAULT 1 CHECK ("buy_quantity" > 0),
+  "get_item_id" int,
+  "get_quantity" int NOT NULL DEFAULT 1 CHECK ("get_quantity" > 0),
+  "min_spend" float NOT NULL DEFAULT 0 CHECK ("min_spend" >= 0),
+  "order_types" text[] NOT NULL DEFAULT '{}',
+  "days" smallint[] NOT NULL DEFAULT '{}',
+  "start_time" time NOT NULL DEFAULT '00:00',
+  "end_time" time NOT NULL DEFAULT '24:00',
+  "start_date" date,
+  "end_date" date,
+  "usage_limit" int CHECK ("usage_limit" > 0),
+  "customer_limit" int CHECK ("customer_limit" > 0),
+  "active" bool NOT NULL DEFAULT true,
+  "created_by" uuid NOT NULL,
+  "created_at" timestamp NOT NULL DEFAULT (now()),
+  CHECK ("type" = 'fixed' OR "value" <= 100),
+  CHECK ("type" <> 'buy_x_get_y' OR ("buy_item_id" IS NOT NULL AND "get_item_id" IS NOT NULL)),
+  CHECK ("order_types" <@ '{dining,delivery,takeaway}'::text[]),
+  CHECK ("days" <@ '{0,1,2,3,4,5,6}'::smallint[]),
+  CHECK ("start_date" IS NULL OR "end_date" IS NULL OR "start_date" <= "end_date")
+);
+
+-- The discount keeps the description and amount worked out when it was applied, editing the promotion
+-- afterwards does not change orders it was already applied to
+ALTER TABLE "order_discounts" ADD COLUMN "promotion_id" int;
+
+-- A promotion is applied to an order at most once
+CREATE UNIQUE INDEX "order_discounts_promotion_idx" ON "order_discounts" ("promotion_id", "order_id");
+
+ALTER TABLE "promotions" ADD FOREIGN KEY ("buy_item_id") REFERENCES "menu_items" ("id");
+
+ALTER TABLE "promotions" ADD FOREIGN KEY ("get_item_id") REFERENCES "menu_items" ("id");
+
+ALTER TABLE "promotions" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("id");
+
+ALTER TABLE "order_discounts" ADD FOREIGN KEY ("promotion_id") REFERENCES "promotions" ("id");
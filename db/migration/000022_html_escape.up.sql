@@ -0,0 +1,5 @@
+-- Escapes the characters that are special in html, search highlights escape the item text with it
+-- before wrapping the matches in <mark> so the only markup in them is the one the server added.
+CREATE FUNCTION "html_escape"(t text) RETURNS text AS $$
+  SELECT replace(replace(replace(replace(replace($1, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;'), '''', '&#39;')
+$$ LANGUAGE sql IMMUTABLE;
@@ -0,0 +1 @@
+ALTER TABLE "z_reports" DROP COLUMN IF EXISTS "gift_card_tenders";
@@ -0,0 +1,2 @@
+-- Gift card tenders used to be counted with the other tenders
+ALTER TABLE "z_reports" ADD COLUMN "gift_card_tenders" float NOT NULL DEFAULT 0;
@@ -0,0 +1,21 @@
+DROP FUNCTION IF EXISTS "promotion_on"("promotions", timestamp);
+
+CREATE OR REPLACE FUNCTION "menu_schedule_open"(s "menu_schedules", ts timestamp) RETURNS bool AS $$
+  SELECT w.open
+    AND (cardinality(s.days) = 0 OR EXTRACT(DOW FROM w.day)::smallint = ANY(s.days))
+    AND (s.start_date IS NULL OR w.day >= s.start_date)
+    AND (s.end_date IS NULL OR w.day <= s.end_date)
+  FROM (
+    SELECT
+      CASE WHEN s.start_time < s.end_time
+        THEN ts::time >= s.start_time AND ts::time < s.end_time
+        ELSE ts::time >= s.start_time OR ts::time < s.end_time
+      END AS open,
+      CASE WHEN s.start_time >= s.end_time AND ts::time < s.end_time
+        THEN ts::date - 1
+        ELSE ts::date
+      END AS day
+  ) w
+$$ LANGUAGE sql STABLE;
+
+DROP FUNCTION IF EXISTS "schedule_window_open"(smallint[], time, time, date, date, timestamp);
@@ -0,0 +1,28 @@
+-- Whether a weekly time window, limited to the days and dates given, is open at ts. A window that ends
+-- before it starts runs past midnight and belongs to the day it started on. Menu schedules and
+-- promotions both use it so they open and close the same way.
+CREATE FUNCTION "schedule_window_open"(days smallint[], start_time time, end_time time, start_date date, end_date date, ts timestamp) RETURNS bool AS $$
+  SELECT w.open
+    AND (cardinality(days) = 0 OR EXTRACT(DOW FROM w.day)::smallint = ANY(days))
+    AND (start_date IS NULL OR w.day >= start_date)
+    AND (end_date IS NULL OR w.day <= end_date)
+  FROM (
+    SELECT
+      CASE WHEN start_time < end_time
+        THEN ts::time >= start_time AND ts::time < end_time
+        ELSE ts::time >= start_time OR ts::time < end_time
+      END AS open,
+      CASE WHEN start_time >= end_time AND ts::time < end_time
+        THEN ts::date - 1
+        ELSE ts::date
+      END AS day
+  ) w
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE OR REPLACE FUNCTION "menu_schedule_open"(s "menu_schedules", ts timestamp) RETURNS bool AS $$
+  SELECT schedule_window_open(s.days, s.start_time, s.end_time, s.start_date, s.end_date, ts)
+$$ LANGUAGE sql STABLE;
+
+CREATE FUNCTION "promotion_on"(p "promotions", ts timestamp) RETURNS bool AS $$
+  SELECT schedule_window_open(p.days, p.start_time, p.end_time, p.start_date, p.end_date, ts)
+$$ LANGUAGE sql STABLE;
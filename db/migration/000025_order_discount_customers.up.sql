@@ -0,0 +1,9 @@
+-- The customer the discount was given to. Promotion customer limits are counted on it so linking the
+-- order to someone else afterwards doesnt give the use back.
+ALTER TABLE "order_discounts" ADD COLUMN "customer_id" int;
+
+UPDATE "order_discounts" d SET "customer_id" = o."customer_id" FROM "orders" o WHERE o."id" = d."order_id";
+
+CREATE INDEX ON "order_discounts" ("promotion_id", "customer_id");
+
+ALTER TABLE "order_discounts" ADD FOREIGN KEY ("customer_id") REFERENCES "customers" ("id") ON DELETE SET NULL;
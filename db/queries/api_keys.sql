@@ -0,0 +1,34 @@
+-- name: CreateAPIKey :one
+INSERT INTO api_keys (
+  name,
+  key_hash,
+  prefix,
+  user_id,
+  permissions,
+  created_by,
+  expires_at
+) VALUES (
+  @name, @key_hash, @prefix, @user_id, @permissions, @created_by,
+  CASE WHEN @expires_in_days::int > 0 THEN now() + make_interval(days => @expires_in_days::int) END
+) RETURNING *;
+
+-- name: GetAPIKeyByHash :one
+SELECT * FROM api_keys
+WHERE key_hash = $1
+  AND revoked_at IS NULL
+  AND (expires_at IS NULL OR expires_at > now())
+LIMIT 1;
+
+-- name: GetAPIKeys :many
+SELECT * FROM api_keys
+ORDER BY created_at DESC;
+
+-- name: TouchAPIKey :exec
+UPDATE api_keys
+SET last_used_at = now()
+WHERE id = $1;
+
+-- name: RevokeAPIKey :execrows
+UPDATE api_keys
+SET revoked_at = now()
+WHERE id = $1 AND revoked_at IS NULL;
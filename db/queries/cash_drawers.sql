@@ -0,0 +1,86 @@
+-- name: OpenDrawerSession :one
+INSERT INTO drawer_sessions (
+  drawer,
+  opening_float,
+  opened_by
+) VALUES (
+  $1, $2, $3
+) RETURNING *;
+
+-- name: GetDrawerSessionByID :one
+SELECT * FROM drawer_sessions
+WHERE id = $1;
+
+-- name: GetDrawerSessions :many
+SELECT * FROM drawer_sessions
+WHERE (NOT @open_only::bool OR closed_at IS NULL)
+ORDER BY opened_at DESC
+LIMIT $1 OFFSET $2;
+
+-- name: GetDrawerSessionForShare :one
+-- Closing the session waits for whoever holds the lock, tenders can still be added alongside
+SELECT * FROM drawer_sessions
+WHERE id = $1
+FOR SHARE;
+
+-- name: GetDrawerSessionForUpdate :one
+-- Held while the session is closed so no tender or movement lands after the expected amount is worked out
+SELECT * FROM drawer_sessions
+WHERE id = $1
+FOR UPDATE;
+
+-- name: CountOpenDrawerSessions :one
+SELECT count(*) FROM drawer_sessions
+WHERE closed_at IS NULL;
+
+-- name: CloseDrawerSession :one
+UPDATE drawer_sessions
+SET expected_amount = $2,
+    counted_amount = $3,
+    closed_by = $4,
+    notes = $5,
+    closed_at = now()
+WHERE id = $1 AND closed_at IS NULL
+RETURNING *;
+
+-- name: GetDrawerExpectedCash :one
+SELECT (
+  d.opening_float
+  + COALESCE((SELECT sum(t.amount) FROM tenders t WHERE t.session_id = d.id AND t.method = 'cash'), 0)
+  + COALESCE((SELECT sum(m.amount) FROM cash_movements m WHERE m.session_id = d.id AND m.type = 'pay_in'), 0)
+  - COALESCE((SELECT sum(m.amount) FROM cash_movements m WHERE m.session_id = d.id AND m.type = 'pay_out'), 0)
+)::float AS expected
+FROM drawer_sessions d
+WHERE d.id = $1;
+
+-- name: CreateCashMovement :one
+INSERT INTO cash_movements (
+  session_id,
+  type,
+  amount,
+  reason,
+  created_by
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: GetCashMovements :many
+SELECT * FROM cash_movements
+WHERE session_id = $1
+ORDER BY created_at;
+
+-- name: CreateTender :one
+INSERT INTO tenders (
+  session_id,
+  order_id,
+  method,
+  amount,
+  created_by
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: GetTenders :many
+SELECT * FROM tenders
+WHERE session_id = $1
+ORDER BY created_at;
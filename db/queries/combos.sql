@@ -0,0 +1,72 @@
+-- name: CreateCombo :one
+INSERT INTO combos (
+  name,
+  description,
+  price,
+  active
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: UpdateCombo :one
+UPDATE combos
+SET name = $2, description = $3, price = $4, active = $5
+WHERE id = $1
+RETURNING *;
+
+-- name: GetComboByID :one
+SELECT * FROM combos
+WHERE id = $1;
+
+-- name: GetCombos :many
+SELECT * FROM combos
+WHERE (NOT @active_only::bool OR active)
+ORDER BY name;
+
+-- name: GetCombosByIDs :many
+SELECT * FROM combos
+WHERE id = ANY(@ids::int[]);
+
+-- name: DeleteComboSlots :exec
+DELETE FROM combo_slots
+WHERE combo_id = $1;
+
+-- name: CreateComboSlot :one
+INSERT INTO combo_slots (
+  combo_id,
+  name,
+  position
+) VALUES (
+  $1, $2, $3
+) RETURNING *;
+
+-- name: AddComboSlotOptions :exec
+INSERT INTO combo_slot_options (slot_id, item_id, upcharge)
+SELECT @slot_id::int, unnest(@item_ids::int[]), unnest(@upcharges::float[]);
+
+-- name: GetComboSlots :many
+SELECT * FROM combo_slots
+WHERE combo_id = ANY(@combo_ids::int[])
+ORDER BY combo_id, position;
+
+-- name: GetComboSlotOptions :many
+SELECT o.slot_id, o.item_id, o.upcharge, m.name, m.price
+FROM combo_slot_options o
+JOIN combo_slots s ON s.id = o.slot_id
+JOIN menu_items m ON m.id = o.item_id
+WHERE s.combo_id = ANY(@combo_ids::int[])
+ORDER BY o.slot_id, m.name;
+
+-- name: CreateOrderCombo :one
+INSERT INTO order_combos (
+  order_id,
+  combo_id,
+  quantity,
+  unit_price
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: AddOrderComboItems :exec
+INSERT INTO order_items (order_id, order_combo_id, quantity, item_id, notes, unit_price)
+SELECT @order_id::uuid, @order_combo_id::bigint, @quantity::int, unnest(@item_ids::int[]), unnest(@notes::text[]), unnest(@unit_prices::float[]);
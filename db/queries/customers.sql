@@ -0,0 +1,84 @@
+-- name: CreateCustomer :one
+INSERT INTO customers (
+  name,
+  email,
+  notes,
+  preferences
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: UpdateCustomer :one
+UPDATE customers
+SET name = $2, email = $3, notes = $4, preferences = $5
+WHERE id = $1
+RETURNING *;
+
+-- name: GetCustomerByID :one
+SELECT * FROM customers
+WHERE id = $1;
+
+-- name: GetCustomerByPhone :one
+SELECT * FROM customers
+WHERE id = (SELECT customer_id FROM customer_phones WHERE phone = $1);
+
+-- name: GetCustomers :many
+-- search matches names, phone matches any part of a phone number (the digits of the search)
+SELECT * FROM customers c
+WHERE @search::text = ''
+  OR c.name ILIKE '%' || @search::text || '%'
+  OR (@phone::text <> '' AND EXISTS (
+    SELECT 1 FROM customer_phones p WHERE p.customer_id = c.id AND p.phone LIKE '%' || @phone::text || '%'
+  ))
+ORDER BY name, id
+LIMIT $1
+OFFSET $2;
+
+-- name: CountCustomers :one
+SELECT count(*) FROM customers c
+WHERE @search::text = ''
+  OR c.name ILIKE '%' || @search::text || '%'
+  OR (@phone::text <> '' AND EXISTS (
+    SELECT 1 FROM customer_phones p WHERE p.customer_id = c.id AND p.phone LIKE '%' || @phone::text || '%'
+  ));
+
+-- name: GetCustomerPhones :many
+SELECT * FROM customer_phones
+WHERE customer_id = $1
+ORDER BY phone;
+
+-- name: AddCustomerPhones :exec
+INSERT INTO customer_phones (customer_id, phone, label)
+SELECT @customer_id::int, unnest(@phones::text[]), nullif(unnest(@labels::text[]), '');
+
+-- name: DeleteCustomerPhones :exec
+DELETE FROM customer_phones
+WHERE customer_id = $1;
+
+-- name: GetCustomerAddresses :many
+SELECT * FROM customer_addresses
+WHERE customer_id = $1
+ORDER BY id;
+
+-- name: AddCustomerAddresses :exec
+INSERT INTO customer_addresses (customer_id, label, address, notes)
+SELECT @customer_id::int, nullif(unnest(@labels::text[]), ''), unnest(@addresses::text[]), nullif(unnest(@notes::text[]), '');
+
+-- name: DeleteCustomerAddresses :exec
+DELETE FROM customer_addresses
+WHERE customer_id = $1;
+
+-- name: GetCustomerStats :one
+-- Only completed orders count towards the lifetime value, cancelled items are left out
+SELECT
+  count(*) FILTER (WHERE o.status = 'completed')::int AS order_count,
+  coalesce(sum(t.total) FILTER (WHERE o.status = 'completed'), 0)::float AS lifetime_value,
+  min(o.created_at)::timestamp AS first_order_at,
+  max(o.created_at)::timestamp AS last_order_at
+FROM orders o
+CROSS JOIN LATERAL (
+  SELECT coalesce(sum(oi.quantity * oi.unit_price), 0)::float AS total
+  FROM order_items oi
+  WHERE oi.order_id = o.id AND oi.status <> 'cancelled'
+) t
+WHERE o.customer_id = $1;
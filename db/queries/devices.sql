@@ -0,0 +1,27 @@
+-- name: CreateDevice :one
+INSERT INTO devices (
+  name,
+  token_hash,
+  registered_by
+) VALUES (
+  $1, $2, $3
+) RETURNING *;
+
+-- name: GetDeviceByTokenHash :one
+SELECT * FROM devices
+WHERE token_hash = $1 AND revoked_at IS NULL
+LIMIT 1;
+
+-- name: GetDevices :many
+SELECT * FROM devices
+ORDER BY created_at DESC;
+
+-- name: TouchDevice :exec
+UPDATE devices
+SET last_seen_at = now()
+WHERE id = $1;
+
+-- name: RevokeDevice :execrows
+UPDATE devices
+SET revoked_at = now()
+WHERE id = $1 AND revoked_at IS NULL;
@@ -0,0 +1,64 @@
+-- name: CreateGiftCard :one
+INSERT INTO gift_cards (
+  code,
+  balance,
+  expires_at,
+  customer_id,
+  issued_by
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: GetGiftCardByCode :one
+SELECT * FROM gift_cards
+WHERE code = $1;
+
+-- name: GetGiftCards :many
+SELECT * FROM gift_cards
+WHERE (@customer_id::int = 0 OR customer_id = @customer_id::int)
+ORDER BY created_at DESC, id DESC
+LIMIT $1
+OFFSET $2;
+
+-- name: CountGiftCards :one
+SELECT count(*) FROM gift_cards
+WHERE (@customer_id::int = 0 OR customer_id = @customer_id::int);
+
+-- name: CreditGiftCard :one
+-- Fails with no rows if the card expired, a null expires_at keeps the expiry it had
+UPDATE gift_cards
+SET balance = balance + @amount::float, expires_at = coalesce(@expires_at::timestamp, expires_at)
+WHERE id = @id AND (expires_at IS NULL OR expires_at > now())
+RETURNING *;
+
+-- name: DebitGiftCard :one
+-- Fails with no rows if the card expired or its balance is too low, the row lock it takes makes
+-- concurrent redemptions wait for each other so the same balance cant be spent twice
+UPDATE gift_cards
+SET balance = balance - @amount::float
+WHERE id = @id AND balance >= @amount::float AND (expires_at IS NULL OR expires_at > now())
+RETURNING *;
+
+-- name: CreateGiftCardTransaction :one
+INSERT INTO gift_card_transactions (
+  gift_card_id,
+  type,
+  amount,
+  balance_after,
+  order_id,
+  tender_id,
+  created_by
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: GetGiftCardTransactions :many
+SELECT * FROM gift_card_transactions
+WHERE gift_card_id = $1
+ORDER BY created_at DESC, id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: CountGiftCardTransactions :one
+SELECT count(*) FROM gift_card_transactions
+WHERE gift_card_id = $1;
@@ -0,0 +1,90 @@
+-- name: CreateIngredient :one
+INSERT INTO ingredients (
+  name,
+  unit,
+  low_stock_level,
+  par_level,
+  supplier_id,
+  allergens
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetIngredientByID :one
+SELECT * FROM ingredients
+WHERE id = $1;
+
+-- name: GetIngredients :many
+SELECT * FROM ingredients
+WHERE (NOT @low_stock_only::bool OR stock <= low_stock_level)
+ORDER BY name
+LIMIT $1
+OFFSET $2;
+
+-- name: UpdateIngredient :one
+UPDATE ingredients
+SET name = $2, unit = $3, low_stock_level = $4, par_level = $5, supplier_id = $6, allergens = $7
+WHERE id = $1
+RETURNING *;
+
+-- name: GetLowStockIngredients :many
+SELECT * FROM ingredients
+WHERE stock <= low_stock_level
+ORDER BY stock - low_stock_level, name;
+
+-- name: AdjustIngredientStock :one
+UPDATE ingredients
+SET stock = stock + @delta::float
+WHERE id = @id
+RETURNING *;
+
+-- name: CreateStockMovement :one
+INSERT INTO stock_movements (
+  ingredient_id,
+  type,
+  quantity,
+  reason,
+  order_item_id,
+  purchase_order_id,
+  created_by
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: GetStockMovements :many
+SELECT * FROM stock_movements
+WHERE ingredient_id = $1
+ORDER BY created_at DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: GetRecipe :many
+SELECT r.item_id, r.ingredient_id, r.quantity, i.name, i.unit
+FROM recipes r
+JOIN ingredients i ON i.id = r.ingredient_id
+WHERE r.item_id = $1
+ORDER BY i.name;
+
+-- name: DeleteRecipe :exec
+DELETE FROM recipes
+WHERE item_id = $1;
+
+-- name: AddRecipeIngredients :exec
+INSERT INTO recipes (item_id, ingredient_id, quantity)
+SELECT @item_id::int, unnest(@ingredient_ids::int[]), unnest(@quantities::float[]);
+
+-- name: MarkOrderItemDepleted :one
+UPDATE order_items
+SET depleted_at = now()
+WHERE id = $1 AND depleted_at IS NULL
+RETURNING item_id, quantity;
+
+-- name: RefreshEightySixed :exec
+UPDATE menu_items m
+SET eighty_sixed = EXISTS (
+  SELECT 1 FROM recipes r
+  JOIN ingredients i ON i.id = r.ingredient_id
+  WHERE r.item_id = m.id AND i.stock < r.quantity
+)
+WHERE m.id = ANY(@item_ids::int[])
+  OR m.id IN (SELECT r.item_id FROM recipes r WHERE r.ingredient_id = ANY(@ingredient_ids::int[]));
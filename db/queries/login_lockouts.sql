@@ -0,0 +1,37 @@
+-- name: ClaimLoginAttempt :one
+-- Counts an attempt before the password is compared so parallel guesses cant get past max_attempts. The attempt
+-- that uses up the last one locks the email right away, for base_lockout_seconds doubled for every lockout
+-- before it up to max_lockout_seconds (a right password clears it again). Attempts and lockouts are
+-- forgotten a day after the last attempt. No rows when the email is already locked.
+INSERT INTO login_lockouts (
+  email,
+  failed_attempts,
+  last_failed_at
+) VALUES (
+  @email, 1, now()
+)
+ON CONFLICT (email) DO UPDATE
+SET
+  failed_attempts = CASE
+    WHEN login_lockouts.last_failed_at < now() - interval '1 day' THEN 1
+    WHEN login_lockouts.failed_attempts + 1 >= @max_attempts::int THEN 0
+    ELSE login_lockouts.failed_attempts + 1
+  END,
+  lockouts = CASE
+    WHEN login_lockouts.last_failed_at < now() - interval '1 day' THEN 0
+    WHEN login_lockouts.failed_attempts + 1 >= @max_attempts::int THEN login_lockouts.lockouts + 1
+    ELSE login_lockouts.lockouts
+  END,
+  locked_until = CASE
+    WHEN login_lockouts.last_failed_at < now() - interval '1 day' THEN NULL
+    WHEN login_lockouts.failed_attempts + 1 >= @max_attempts::int
+      THEN now() + make_interval(secs => least(@base_lockout_seconds::int * power(2, login_lockouts.lockouts), @max_lockout_seconds::int))
+    ELSE login_lockouts.locked_until
+  END,
+  last_failed_at = now()
+WHERE login_lockouts.locked_until IS NULL OR login_lockouts.locked_until <= now()
+RETURNING (locked_until IS NOT NULL AND locked_until > now())::bool AS locked;
+
+-- name: ClearLoginLockout :exec
+DELETE FROM login_lockouts
+WHERE email = $1;
@@ -0,0 +1,90 @@
+-- name: CreateLoyaltyTier :one
+INSERT INTO loyalty_tiers (
+  name,
+  min_points,
+  multiplier
+) VALUES (
+  $1, $2, $3
+) RETURNING *;
+
+-- name: UpdateLoyaltyTier :one
+UPDATE loyalty_tiers
+SET name = $2, min_points = $3, multiplier = $4
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteLoyaltyTier :execrows
+DELETE FROM loyalty_tiers
+WHERE id = $1;
+
+-- name: GetLoyaltyTiers :many
+SELECT * FROM loyalty_tiers
+ORDER BY min_points;
+
+-- name: GetLoyaltyBalance :one
+-- earned is every point the customer ever earned, it decides their tier
+SELECT
+  coalesce(sum(points), 0)::int AS balance,
+  coalesce(sum(points) FILTER (WHERE type = 'earn'), 0)::int AS earned
+FROM loyalty_transactions
+WHERE customer_id = $1;
+
+-- name: GetLoyaltyTransactions :many
+SELECT * FROM loyalty_transactions
+WHERE customer_id = $1
+ORDER BY created_at DESC, id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: CountLoyaltyTransactions :one
+SELECT count(*) FROM loyalty_transactions
+WHERE customer_id = $1;
+
+-- name: CreateLoyaltyTransaction :one
+INSERT INTO loyalty_transactions (
+  customer_id,
+  type,
+  points,
+  order_id,
+  note,
+  created_by
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: EarnLoyaltyPoints :execrows
+-- Awards the customer of the order rate points per unit of what they paid (after discounts), multiplied by
+-- their tier. Does nothing when the order has no customer, earns nothing or already earned.
+INSERT INTO loyalty_transactions (customer_id, type, points, order_id)
+SELECT o.customer_id, 'earn', p.points, o.id
+FROM orders o
+CROSS JOIN LATERAL (
+  SELECT coalesce(sum(oi.quantity * oi.unit_price), 0)::float AS subtotal
+  FROM order_items oi
+  WHERE oi.order_id = o.id AND oi.status <> 'cancelled'
+) s
+CROSS JOIN LATERAL (
+  SELECT coalesce(sum(od.amount), 0)::float AS discounts
+  FROM order_discounts od
+  WHERE od.order_id = o.id
+) d
+LEFT JOIN LATERAL (
+  SELECT lt.multiplier
+  FROM loyalty_tiers lt
+  WHERE lt.min_points <= (
+    SELECT coalesce(sum(l.points), 0) FROM loyalty_transactions l WHERE l.customer_id = o.customer_id AND l.type = 'earn'
+  )
+  ORDER BY lt.min_points DESC
+  LIMIT 1
+) t ON true
+CROSS JOIN LATERAL (
+  SELECT floor(greatest(s.subtotal - d.discounts, 0) * @rate::float * coalesce(t.multiplier, 1))::int AS points
+) p
+WHERE o.id = @order_id AND o.customer_id IS NOT NULL AND p.points > 0
+ON CONFLICT ("order_id") WHERE "type" = 'earn' DO NOTHING;
+
+-- name: LockCustomer :exec
+-- Held until the end of the transaction so concurrent redemptions cant spend the same points
+SELECT id FROM customers
+WHERE id = $1
+FOR UPDATE;
@@ -0,0 +1,13 @@
+-- name: AddMenuItemCost :one
+INSERT INTO menu_item_costs (
+  item_id,
+  cost,
+  created_by
+) VALUES (
+  $1, $2, $3
+) RETURNING *;
+
+-- name: GetMenuItemCosts :many
+SELECT * FROM menu_item_costs
+WHERE item_id = $1
+ORDER BY effective_from DESC;
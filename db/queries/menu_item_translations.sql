@@ -0,0 +1,25 @@
+-- name: UpsertMenuItemTranslation :one
+INSERT INTO menu_item_translations (
+  item_id,
+  lang,
+  name,
+  description
+) VALUES (
+  $1, $2, $3, $4
+)
+ON CONFLICT (item_id, lang) DO UPDATE
+SET name = EXCLUDED.name, description = EXCLUDED.description, updated_at = now()
+RETURNING *;
+
+-- name: GetMenuItemTranslations :many
+SELECT * FROM menu_item_translations
+WHERE item_id = $1
+ORDER BY lang;
+
+-- name: GetMenuItemTranslation :one
+SELECT * FROM menu_item_translations
+WHERE item_id = $1 AND lang = $2;
+
+-- name: DeleteMenuItemTranslation :execrows
+DELETE FROM menu_item_translations
+WHERE item_id = $1 AND lang = $2;
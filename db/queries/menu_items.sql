@@ -3,21 +3,101 @@ INSERT INTO menu_items (
   name,
   description,
   price,
-  requires_ticket
+  requires_ticket,
+  allergens,
+  dietary_tags
 ) VALUES (
-  $1, $2, $3, $4
+  $1, $2, $3, $4, $5, $6
 ) RETURNING *;
 
 -- name: GetMenuItems :many
-SELECT * FROM menu_items
-WHERE (@search::text IS NULL OR name ILIKE '%' || @search::text || '%')
-ORDER BY name
+-- Without a search the items are listed by name, with one they are ranked by how well they match.
+-- The highlights are the html escaped item text with the matched words wrapped in <mark>.
+WITH q AS (
+  SELECT websearch_to_tsquery('simple', @search::text) AS query
+)
+SELECT
+  m.id,
+  coalesce(t.name, m.name)::text AS name,
+  coalesce(t.description, m.description) AS description,
+  m.price,
+  m.requires_ticket,
+  m.created_at,
+  m.eighty_sixed,
+  menu_item_price(m.id, m.price, localtimestamp)::float AS current_price,
+  menu_item_available(m.id, localtimestamp)::bool AS available,
+  menu_item_allergens(m.id)::text[] AS allergens,
+  m.dietary_tags,
+  m.image_key,
+  m.thumbnail_key,
+  CASE WHEN @search::text <> '' THEN
+    ts_headline('simple', html_escape(coalesce(t.name, m.name)), q.query, 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true')
+  END::text AS name_highlight,
+  CASE WHEN @search::text <> '' THEN
+    ts_headline('simple', html_escape(coalesce(t.description, m.description, '')), q.query, 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2')
+  END::text AS description_highlight
+FROM menu_items m
+CROSS JOIN q
+LEFT JOIN menu_item_translations t ON t.item_id = m.id AND t.lang = @lang::text
+WHERE (
+    @search::text = ''
+    OR menu_search_document(m.name, m.description) @@ q.query
+    OR menu_search_document(t.name, t.description) @@ q.query
+    OR @search::text <% m.name
+    OR @search::text <% t.name
+  )
+  AND (@include_unavailable::bool OR menu_item_available(m.id, localtimestamp))
+  AND NOT (menu_item_allergens(m.id) && coalesce(@exclude_allergens::text[], '{}'))
+  AND m.dietary_tags @> coalesce(@dietary_tags::text[], '{}')
+ORDER BY
+  CASE WHEN @search::text <> '' THEN
+    ts_rank(menu_search_document(coalesce(t.name, m.name), coalesce(t.description, m.description)), q.query)
+    + word_similarity(@search::text, coalesce(t.name, m.name))
+  END DESC NULLS LAST,
+  coalesce(t.name, m.name),
+  m.id
 LIMIT $1
 OFFSET $2;
 
+-- name: CountMenuItems :one
+-- Same filters as GetMenuItems
+SELECT count(*)
+FROM menu_items m
+LEFT JOIN menu_item_translations t ON t.item_id = m.id AND t.lang = @lang::text
+WHERE (
+    @search::text = ''
+    OR menu_search_document(m.name, m.description) @@ websearch_to_tsquery('simple', @search::text)
+    OR menu_search_document(t.name, t.description) @@ websearch_to_tsquery('simple', @search::text)
+    OR @search::text <% m.name
+    OR @search::text <% t.name
+  )
+  AND (@include_unavailable::bool OR menu_item_available(m.id, localtimestamp))
+  AND NOT (menu_item_allergens(m.id) && coalesce(@exclude_allergens::text[], '{}'))
+  AND m.dietary_tags @> coalesce(@dietary_tags::text[], '{}');
+
 -- name: GetItemByID :one
 SELECT * FROM menu_items
 WHERE id = $1 
 LIMIT 1;
 
+-- name: GetOrderableItems :many
+SELECT
+  id,
+  name,
+  eighty_sixed,
+  menu_item_available(id, localtimestamp)::bool AS available,
+  menu_item_price(id, price, localtimestamp)::float AS current_price,
+  menu_item_allergens(id)::text[] AS allergens
+FROM menu_items
+WHERE id = ANY(@ids::int[]);
+
+-- name: UpdateMenuItemTags :one
+UPDATE menu_items
+SET allergens = $2, dietary_tags = $3
+WHERE id = $1
+RETURNING *;
 
+-- name: UpdateMenuItemImage :execrows
+UPDATE menu_items
+SET image_key = $2, thumbnail_key = $3
+WHERE id = $1;
@@ -0,0 +1,71 @@
+-- name: CreateMenu :one
+INSERT INTO menus (
+  name,
+  active
+) VALUES (
+  $1, $2
+) RETURNING *;
+
+-- name: GetMenus :many
+SELECT * FROM menus
+ORDER BY name;
+
+-- name: GetMenuByID :one
+SELECT * FROM menus
+WHERE id = $1;
+
+-- name: UpdateMenu :one
+UPDATE menus
+SET name = $2, active = $3
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteMenu :execrows
+DELETE FROM menus
+WHERE id = $1;
+
+-- name: GetMenuEntries :many
+SELECT item_id FROM menu_entries
+WHERE menu_id = $1
+ORDER BY item_id;
+
+-- name: DeleteMenuEntries :exec
+DELETE FROM menu_entries
+WHERE menu_id = $1;
+
+-- name: AddMenuEntries :exec
+INSERT INTO menu_entries (menu_id, item_id)
+SELECT @menu_id::int, unnest(@item_ids::int[]);
+
+-- name: CreateMenuSchedule :one
+INSERT INTO menu_schedules (
+  menu_id,
+  name,
+  days,
+  start_time,
+  end_time,
+  start_date,
+  end_date
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: GetMenuSchedules :many
+SELECT * FROM menu_schedules
+WHERE menu_id = $1
+ORDER BY start_time, id;
+
+-- name: DeleteMenuSchedule :execrows
+DELETE FROM menu_schedules
+WHERE id = $1 AND menu_id = $2;
+
+-- name: AddSchedulePrices :exec
+INSERT INTO schedule_prices (schedule_id, item_id, price)
+SELECT @schedule_id::int, unnest(@item_ids::int[]), unnest(@prices::float[]);
+
+-- name: GetSchedulePrices :many
+SELECT p.schedule_id, p.item_id, p.price
+FROM schedule_prices p
+JOIN menu_schedules s ON s.id = p.schedule_id
+WHERE s.menu_id = $1
+ORDER BY p.schedule_id, p.item_id;
@@ -2,11 +2,32 @@
 INSERT INTO orders (
   type,
   employee_id,
-  table_id
+  table_id,
+  covers,
+  customer_id
 ) VALUES (
-  $1, $2, $3
+  $1, $2, $3, $4, $5
 ) RETURNING id;
 
+-- name: CreateTakeawayDetails :exec
+INSERT INTO takeaway_details (
+  order_id,
+  contact,
+  notes
+) VALUES (
+  $1, $2, $3
+);
+
+-- name: CreateDeliveryDetails :exec
+INSERT INTO delivery_details (
+  order_id,
+  address,
+  contact,
+  driver_id
+) VALUES (
+  $1, $2, $3, $4
+);
+
 -- name: GetOrderByID :one
 SELECT * FROM orders 
 WHERE id = $1;
@@ -14,12 +35,32 @@ WHERE id = $1;
 
 -- name: GetOrders :many
 SELECT * FROM orders
+WHERE status = $1 AND type = $2
+ORDER BY created_at DESC, id DESC
+LIMIT $3
+OFFSET $4;
+
+-- name: GetOrdersBefore :many
+-- Keyset page of GetOrders, the orders after the last one of the previous page
+SELECT * FROM orders
+WHERE status = $1 AND type = $2
+  AND (created_at, id) < (@cursor_created_at::timestamp, @cursor_id::uuid)
+ORDER BY created_at DESC, id DESC
+LIMIT $3;
+
+-- name: CountOrders :one
+SELECT count(*) FROM orders
 WHERE status = $1 AND type = $2;
 
 
 -- name: AddOrderItemsBulk :exec
-INSERT INTO order_items (order_id, item_id, quantity, notes)
-SELECT $1, unnest(@item_ids::int[]), unnest(@quantity::int[]), unnest(@notes::text[]);
+INSERT INTO order_items (order_id, item_id, quantity, notes, unit_price)
+SELECT $1, unnest(@item_ids::int[]), unnest(@quantity::int[]), unnest(@notes::text[]), unnest(@unit_prices::float[]);
+
+-- name: GetOrderItems :many
+SELECT * FROM order_items
+WHERE order_id = $1
+ORDER BY id;
 
 -- name: GetOrderItemByID :one
 SELECT * FROM order_items
@@ -29,3 +70,123 @@ WHERE order_id = $1 AND id = $2;
 UPDATE order_items
 SET status = $1
 WHERE order_id = $2 AND id = $3;
+
+-- name: SetOrderAllergies :execrows
+-- Only ongoing orders, the kitchen has nothing left to cook for the others
+UPDATE orders
+SET allergies = $2, block_allergens = $3
+WHERE id = $1 AND status = 'ongoing';
+
+-- name: SearchOrders :many
+-- Every filter is optional, empty strings, zeros and nulls match everything.
+-- total is what the order comes to without the cancelled items.
+SELECT
+  o.id,
+  o.type,
+  o.employee_id,
+  u.name AS employee_name,
+  o.status,
+  o.table_id,
+  o.covers,
+  o.customer_id,
+  o.created_at,
+  o.completed_at,
+  t.total
+FROM orders o
+JOIN users u ON u.id = o.employee_id
+CROSS JOIN LATERAL (
+  SELECT coalesce(sum(oi.quantity * oi.unit_price), 0)::float AS total
+  FROM order_items oi
+  WHERE oi.order_id = o.id AND oi.status <> 'cancelled'
+) t
+WHERE (@period_start::timestamp IS NULL OR o.created_at >= @period_start::timestamp)
+  AND (@period_end::timestamp IS NULL OR o.created_at < @period_end::timestamp)
+  AND (@status::text = '' OR o.status::text = @status::text)
+  AND (@type::text = '' OR o.type::text = @type::text)
+  AND (@table_id::text = '' OR o.table_id = @table_id::text)
+  AND (@employee_id::uuid IS NULL OR o.employee_id = @employee_id::uuid)
+  AND (@item_id::int = 0 OR EXISTS (
+    SELECT 1 FROM order_items x WHERE x.order_id = o.id AND x.item_id = @item_id::int
+  ))
+  AND (@min_total::float IS NULL OR t.total >= @min_total::float)
+  AND (@max_total::float IS NULL OR t.total <= @max_total::float)
+  AND (@customer_id::int = 0 OR o.customer_id = @customer_id::int)
+ORDER BY
+  CASE WHEN @sort::text = 'created_at' AND NOT @sort_desc::bool THEN o.created_at END ASC,
+  CASE WHEN @sort::text = 'created_at' AND @sort_desc::bool THEN o.created_at END DESC,
+  CASE WHEN @sort::text = 'completed_at' AND NOT @sort_desc::bool THEN o.completed_at END ASC NULLS LAST,
+  CASE WHEN @sort::text = 'completed_at' AND @sort_desc::bool THEN o.completed_at END DESC NULLS LAST,
+  CASE WHEN @sort::text = 'total' AND NOT @sort_desc::bool THEN t.total END ASC,
+  CASE WHEN @sort::text = 'total' AND @sort_desc::bool THEN t.total END DESC,
+  o.created_at DESC,
+  o.id DESC
+LIMIT $1
+OFFSET $2;
+
+-- name: CountSearchOrders :one
+-- Same filters as SearchOrders
+SELECT count(*)
+FROM orders o
+CROSS JOIN LATERAL (
+  SELECT coalesce(sum(oi.quantity * oi.unit_price), 0)::float AS total
+  FROM order_items oi
+  WHERE oi.order_id = o.id AND oi.status <> 'cancelled'
+) t
+WHERE (@period_start::timestamp IS NULL OR o.created_at >= @period_start::timestamp)
+  AND (@period_end::timestamp IS NULL OR o.created_at < @period_end::timestamp)
+  AND (@status::text = '' OR o.status::text = @status::text)
+  AND (@type::text = '' OR o.type::text = @type::text)
+  AND (@table_id::text = '' OR o.table_id = @table_id::text)
+  AND (@employee_id::uuid IS NULL OR o.employee_id = @employee_id::uuid)
+  AND (@item_id::int = 0 OR EXISTS (
+    SELECT 1 FROM order_items x WHERE x.order_id = o.id AND x.item_id = @item_id::int
+  ))
+  AND (@min_total::float IS NULL OR t.total >= @min_total::float)
+  AND (@max_total::float IS NULL OR t.total <= @max_total::float)
+  AND (@customer_id::int = 0 OR o.customer_id = @customer_id::int);
+
+-- name: SetOrderCustomer :execrows
+-- Only ongoing orders, completed ones already earned their customer loyalty points
+UPDATE orders
+SET customer_id = $2
+WHERE id = $1 AND status = 'ongoing';
+
+-- name: CompleteOrder :one
+UPDATE orders
+SET status = 'completed', completed_at = now()
+WHERE id = $1 AND status = 'ongoing'
+RETURNING *;
+
+-- name: GetOrderForUpdate :one
+SELECT * FROM orders
+WHERE id = $1
+FOR UPDATE;
+
+-- name: GetOrderBill :one
+-- subtotal leaves out cancelled items, discounts is everything taken off the order
+SELECT
+  (SELECT coalesce(sum(oi.quantity * oi.unit_price), 0) FROM order_items oi WHERE oi.order_id = $1 AND oi.status <> 'cancelled')::float AS subtotal,
+  (SELECT coalesce(sum(d.amount), 0) FROM order_discounts d WHERE d.order_id = $1)::float AS discounts;
+
+-- name: GetOrderTendered :one
+-- What has been paid towards the order so far, in every tender method
+SELECT coalesce(sum(amount), 0)::float AS tendered FROM tenders
+WHERE order_id = $1;
+
+-- name: CreateOrderDiscount :one
+INSERT INTO order_discounts (
+  order_id,
+  type,
+  description,
+  amount,
+  created_by,
+  promotion_id,
+  customer_id
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: GetOrderDiscounts :many
+SELECT * FROM order_discounts
+WHERE order_id = $1
+ORDER BY created_at, id;
@@ -0,0 +1,94 @@
+-- name: CreatePromotion :one
+INSERT INTO promotions (
+  name,
+  code,
+  type,
+  value,
+  buy_item_id,
+  buy_quantity,
+  get_item_id,
+  get_quantity,
+  min_spend,
+  order_types,
+  days,
+  start_time,
+  end_time,
+  start_date,
+  end_date,
+  usage_limit,
+  customer_limit,
+  active,
+  created_by
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19
+) RETURNING *;
+
+-- name: UpdatePromotion :one
+UPDATE promotions
+SET
+  name = $2,
+  code = $3,
+  type = $4,
+  value = $5,
+  buy_item_id = $6,
+  buy_quantity = $7,
+  get_item_id = $8,
+  get_quantity = $9,
+  min_spend = $10,
+  order_types = $11,
+  days = $12,
+  start_time = $13,
+  end_time = $14,
+  start_date = $15,
+  end_date = $16,
+  usage_limit = $17,
+  customer_limit = $18,
+  active = $19
+WHERE id = $1
+RETURNING *;
+
+-- name: GetPromotionByID :one
+SELECT * FROM promotions
+WHERE id = $1;
+
+-- name: GetPromotionByCode :one
+SELECT * FROM promotions
+WHERE code = $1;
+
+-- name: GetPromotions :many
+SELECT * FROM promotions
+WHERE (NOT @active_only::bool OR active)
+ORDER BY id DESC
+LIMIT $1
+OFFSET $2;
+
+-- name: CountPromotions :one
+SELECT count(*) FROM promotions
+WHERE (NOT @active_only::bool OR active);
+
+-- name: GetAutomaticPromotions :many
+-- The active promotions that dont need a code, in the order they were created
+SELECT * FROM promotions
+WHERE active AND code IS NULL
+ORDER BY id;
+
+-- name: IsPromotionOn :one
+-- Whether the time window of the promotion is open right now
+SELECT promotion_on(p, localtimestamp)::bool AS is_on
+FROM promotions p
+WHERE p.id = $1;
+
+-- name: GetPromotionForUpdate :one
+-- Applying a promotion locks it first so two orders cant both take its last use
+SELECT * FROM promotions
+WHERE id = $1
+FOR UPDATE;
+
+-- name: GetPromotionUsage :one
+-- uses is how many orders the promotion was applied to, customer_uses how many of them it was given to
+-- the customer on
+SELECT
+  count(*) AS uses,
+  count(*) FILTER (WHERE d.customer_id = @customer_id::int) AS customer_uses
+FROM order_discounts d
+WHERE d.promotion_id = @promotion_id::int;
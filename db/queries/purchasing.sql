@@ -0,0 +1,98 @@
+-- name: CreateSupplier :one
+INSERT INTO suppliers (
+  name,
+  contact_name,
+  phone,
+  email,
+  notes
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: GetSupplierByID :one
+SELECT * FROM suppliers
+WHERE id = $1;
+
+-- name: GetSuppliers :many
+SELECT * FROM suppliers
+ORDER BY name;
+
+-- name: UpdateSupplier :one
+UPDATE suppliers
+SET name = $2, contact_name = $3, phone = $4, email = $5, notes = $6
+WHERE id = $1
+RETURNING *;
+
+-- name: CreatePurchaseOrder :one
+INSERT INTO purchase_orders (
+  supplier_id,
+  notes,
+  created_by
+) VALUES (
+  $1, $2, $3
+) RETURNING *;
+
+-- name: AddPurchaseOrderItems :exec
+INSERT INTO purchase_order_items (purchase_order_id, ingredient_id, quantity, unit_cost)
+SELECT @purchase_order_id::bigint, unnest(@ingredient_ids::int[]), unnest(@quantities::float[]), unnest(@unit_costs::float[]);
+
+-- name: GetPurchaseOrderByID :one
+SELECT * FROM purchase_orders
+WHERE id = $1;
+
+-- name: GetPurchaseOrders :many
+SELECT * FROM purchase_orders
+WHERE (sqlc.narg(status)::purchase_order_status IS NULL OR status = sqlc.narg(status)::purchase_order_status)
+ORDER BY created_at DESC
+LIMIT $1
+OFFSET $2;
+
+-- name: GetPurchaseOrderItems :many
+SELECT poi.id, poi.purchase_order_id, poi.ingredient_id, poi.quantity, poi.received_quantity, poi.unit_cost, i.name, i.unit
+FROM purchase_order_items poi
+JOIN ingredients i ON i.id = poi.ingredient_id
+WHERE poi.purchase_order_id = $1
+ORDER BY i.name;
+
+-- name: UpdatePurchaseOrderStatus :one
+UPDATE purchase_orders
+SET status = @status,
+    ordered_at = CASE WHEN @status = 'ordered' THEN now() ELSE ordered_at END,
+    received_at = CASE WHEN @status = 'received' THEN now() ELSE received_at END
+WHERE id = @id AND status::text = ANY(@from_statuses::text[])
+RETURNING *;
+
+-- name: ReceivePurchaseOrderItem :one
+UPDATE purchase_order_items
+SET received_quantity = received_quantity + @quantity::float
+WHERE id = @id AND purchase_order_id = @purchase_order_id
+RETURNING *;
+
+-- name: GetReorderSuggestions :many
+SELECT
+  i.id,
+  i.name,
+  i.unit,
+  i.stock,
+  i.par_level,
+  i.supplier_id,
+  s.name AS supplier_name,
+  COALESCE(u.used, 0)::float AS recent_usage,
+  COALESCE(po.on_order, 0)::float AS on_order
+FROM ingredients i
+LEFT JOIN suppliers s ON s.id = i.supplier_id
+LEFT JOIN (
+  SELECT r.ingredient_id, sum(r.quantity * oi.quantity) AS used
+  FROM order_items oi
+  JOIN recipes r ON r.item_id = oi.item_id
+  WHERE oi.status <> 'cancelled' AND oi.added_at >= @usage_since::timestamp
+  GROUP BY r.ingredient_id
+) u ON u.ingredient_id = i.id
+LEFT JOIN (
+  SELECT poi.ingredient_id, sum(poi.quantity - poi.received_quantity) AS on_order
+  FROM purchase_order_items poi
+  JOIN purchase_orders p ON p.id = poi.purchase_order_id
+  WHERE p.status IN ('ordered', 'partially_received')
+  GROUP BY poi.ingredient_id
+) po ON po.ingredient_id = i.id
+ORDER BY s.name NULLS LAST, i.name;
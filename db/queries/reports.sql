@@ -0,0 +1,84 @@
+-- name: GetSalesReport :many
+SELECT
+  (CASE @group_by::text
+    WHEN 'hour' THEN to_char(date_trunc('hour', o.created_at), 'YYYY-MM-DD HH24:00')
+    WHEN 'day' THEN to_char(date_trunc('day', o.created_at), 'YYYY-MM-DD')
+    WHEN 'week' THEN to_char(date_trunc('week', o.created_at), 'YYYY-MM-DD')
+    WHEN 'order_type' THEN o.type::text
+    WHEN 'employee' THEN o.employee_id::text
+    WHEN 'table' THEN COALESCE(o.table_id, '')
+    WHEN 'item' THEN oi.item_id::text
+  END)::text AS bucket,
+  (CASE @group_by::text
+    WHEN 'employee' THEN u.name
+    WHEN 'item' THEN m.name
+    ELSE ''
+  END)::text AS label,
+  count(DISTINCT o.id)::int AS order_count,
+  COALESCE(sum(oi.quantity), 0)::int AS quantity,
+  COALESCE(sum(oi.quantity * oi.unit_price), 0)::float AS revenue
+FROM orders o
+JOIN users u ON u.id = o.employee_id
+LEFT JOIN order_items oi ON oi.order_id = o.id AND oi.status <> 'cancelled'
+LEFT JOIN menu_items m ON m.id = oi.item_id
+WHERE o.created_at >= @period_start::timestamp
+  AND o.created_at < @period_end::timestamp
+  AND o.status <> 'cancelled'
+  AND (@group_by::text <> 'item' OR oi.id IS NOT NULL)
+GROUP BY 1, 2
+ORDER BY 1;
+
+-- name: GetSalesTotals :one
+SELECT
+  count(DISTINCT o.id)::int AS order_count,
+  COALESCE(sum(oi.quantity), 0)::int AS quantity,
+  COALESCE(sum(oi.quantity * oi.unit_price), 0)::float AS revenue
+FROM orders o
+LEFT JOIN order_items oi ON oi.order_id = o.id AND oi.status <> 'cancelled'
+WHERE o.created_at >= @period_start::timestamp
+  AND o.created_at < @period_end::timestamp
+  AND o.status <> 'cancelled';
+
+-- name: GetMenuEngineeringStats :many
+-- current_cost is the latest cost recorded before the period ends, cost_known is false when there is none.
+-- Items sold before any cost was recorded for them have no cost, they are left out of costed_sold,
+-- costed_revenue and food_cost instead of counting as free.
+SELECT
+  m.id,
+  m.name,
+  m.price,
+  EXISTS (
+    SELECT 1 FROM menu_item_costs c WHERE c.item_id = m.id AND c.effective_from < @period_end::timestamp
+  ) AS cost_known,
+  COALESCE((
+    SELECT c.cost FROM menu_item_costs c
+    WHERE c.item_id = m.id AND c.effective_from < @period_end::timestamp
+    ORDER BY c.effective_from DESC
+    LIMIT 1
+  ), 0)::float AS current_cost,
+  COALESCE(sum(s.quantity), 0)::int AS quantity_sold,
+  COALESCE(sum(s.quantity) FILTER (WHERE s.cost IS NOT NULL), 0)::int AS costed_sold,
+  COALESCE(sum(s.quantity * s.unit_price) FILTER (WHERE s.cost IS NOT NULL), 0)::float AS costed_revenue,
+  COALESCE(sum(s.quantity * s.cost), 0)::float AS food_cost
+FROM menu_items m
+LEFT JOIN (
+  SELECT
+    oi.item_id,
+    oi.quantity,
+    oi.unit_price,
+    -- Latest cost in effect when the item was ordered
+    (
+      SELECT c.cost FROM menu_item_costs c
+      WHERE c.item_id = oi.item_id AND c.effective_from <= oi.added_at
+      ORDER BY c.effective_from DESC
+      LIMIT 1
+    ) AS cost
+  FROM order_items oi
+  JOIN orders o ON o.id = oi.order_id
+  WHERE oi.status <> 'cancelled'
+    AND o.status <> 'cancelled'
+    AND o.created_at >= @period_start::timestamp
+    AND o.created_at < @period_end::timestamp
+) s ON s.item_id = m.id
+GROUP BY m.id
+ORDER BY m.name;
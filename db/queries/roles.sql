@@ -0,0 +1,51 @@
+-- name: CreateRole :one
+INSERT INTO roles (
+  name,
+  description
+) VALUES (
+  $1, $2
+) RETURNING *;
+
+-- name: GetRoleByID :one
+SELECT * FROM roles
+WHERE id = $1 LIMIT 1;
+
+-- name: GetRoleByName :one
+SELECT * FROM roles
+WHERE name = $1 LIMIT 1;
+
+-- name: GetRoles :many
+SELECT
+  r.id,
+  r.name,
+  r.description,
+  r.superuser,
+  r.created_at,
+  COALESCE(array_agg(rp.permission ORDER BY rp.permission) FILTER (WHERE rp.permission IS NOT NULL), '{}')::text[] AS permissions
+FROM roles r
+LEFT JOIN role_permissions rp ON rp.role_id = r.id
+GROUP BY r.id
+ORDER BY r.name;
+
+-- name: GetRolePermissions :many
+SELECT permission FROM role_permissions
+WHERE role_id = $1
+ORDER BY permission;
+
+-- name: UpdateRole :one
+UPDATE roles
+SET name = $1, description = $2
+WHERE id = $3
+RETURNING *;
+
+-- name: DeleteRole :execrows
+DELETE FROM roles
+WHERE id = $1;
+
+-- name: AddRolePermissions :exec
+INSERT INTO role_permissions (role_id, permission)
+SELECT @role_id::int, unnest(@permissions::text[]);
+
+-- name: DeleteRolePermissions :exec
+DELETE FROM role_permissions
+WHERE role_id = $1;
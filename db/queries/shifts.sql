@@ -0,0 +1,74 @@
+-- name: ClockIn :one
+INSERT INTO time_entries (
+  user_id,
+  notes
+) VALUES (
+  $1, $2
+) RETURNING *;
+
+-- name: GetOpenTimeEntry :one
+SELECT * FROM time_entries
+WHERE user_id = $1 AND clock_out IS NULL
+LIMIT 1;
+
+-- name: ClockOut :one
+UPDATE time_entries
+SET clock_out = now()
+WHERE user_id = $1 AND clock_out IS NULL
+RETURNING *;
+
+-- name: StartBreak :one
+INSERT INTO breaks (
+  time_entry_id,
+  paid
+) VALUES (
+  $1, $2
+) RETURNING *;
+
+-- name: GetOpenBreak :one
+SELECT * FROM breaks
+WHERE time_entry_id = $1 AND ended_at IS NULL
+LIMIT 1;
+
+-- name: EndBreak :one
+UPDATE breaks
+SET ended_at = now()
+WHERE time_entry_id = $1 AND ended_at IS NULL
+RETURNING *;
+
+-- name: CreateScheduledShift :one
+INSERT INTO scheduled_shifts (
+  user_id,
+  starts_at,
+  ends_at,
+  notes,
+  created_by
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: GetScheduledShifts :many
+SELECT * FROM scheduled_shifts
+WHERE starts_at < @range_end::timestamp
+  AND ends_at > @range_start::timestamp
+  AND (sqlc.narg(user_id)::uuid IS NULL OR user_id = sqlc.narg(user_id)::uuid)
+ORDER BY starts_at;
+
+-- name: DeleteScheduledShift :execrows
+DELETE FROM scheduled_shifts
+WHERE id = $1;
+
+-- name: GetTimesheet :many
+SELECT
+  t.id,
+  t.clock_in,
+  t.clock_out,
+  EXTRACT(EPOCH FROM (COALESCE(t.clock_out, now()) - t.clock_in))::float AS total_seconds,
+  COALESCE(SUM(EXTRACT(EPOCH FROM (COALESCE(b.ended_at, t.clock_out, now()) - b.started_at))) FILTER (WHERE NOT b.paid), 0)::float AS unpaid_break_seconds
+FROM time_entries t
+LEFT JOIN breaks b ON b.time_entry_id = t.id
+WHERE t.user_id = @user_id
+  AND t.clock_in >= @period_start::timestamp
+  AND t.clock_in < @period_end::timestamp
+GROUP BY t.id
+ORDER BY t.clock_in;
@@ -11,5 +11,12 @@ WHERE id = $2;
 
 -- name: GetTables :many
 SELECT * FROM tables
+WHERE status = $1
+ORDER BY id
+LIMIT $2
+OFFSET $3;
+
+-- name: CountTables :one
+SELECT count(*) FROM tables
 WHERE status = $1;
 
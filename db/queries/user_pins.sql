@@ -0,0 +1,36 @@
+-- name: UpsertUserPIN :exec
+INSERT INTO user_pins (
+  user_id,
+  pin
+) VALUES (
+  $1, $2
+)
+ON CONFLICT (user_id) DO UPDATE
+SET pin = EXCLUDED.pin, failed_attempts = 0, locked_until = NULL, updated_at = now();
+
+-- name: GetUserPIN :one
+SELECT *, (locked_until IS NOT NULL AND locked_until > now())::bool AS locked FROM user_pins
+WHERE user_id = $1
+LIMIT 1;
+
+-- name: ClaimPINAttempt :one
+-- Counts an attempt before the pin is compared so parallel guesses cant get past max_attempts, the
+-- attempt that uses up the last one locks the pin right away (a right pin unlocks it again).
+-- No rows when the pin is already locked.
+UPDATE user_pins
+SET
+  failed_attempts = CASE WHEN failed_attempts + 1 >= @max_attempts::int THEN 0 ELSE failed_attempts + 1 END,
+  locked_until = CASE WHEN failed_attempts + 1 >= @max_attempts::int THEN now() + make_interval(secs => @lockout_seconds::int) ELSE locked_until END
+WHERE user_id = @user_id AND (locked_until IS NULL OR locked_until <= now())
+RETURNING pin, (locked_until IS NOT NULL AND locked_until > now())::bool AS locked;
+
+-- name: ResetPINFailures :exec
+UPDATE user_pins
+SET failed_attempts = 0, locked_until = NULL
+WHERE user_id = $1;
+
+-- name: GetPINUsers :many
+SELECT u.id, u.name, u.type FROM users u
+JOIN user_pins p ON p.user_id = u.id
+WHERE u.active
+ORDER BY u.name;
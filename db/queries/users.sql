@@ -3,17 +3,77 @@ INSERT INTO users (
   email,
   name,
   type,
-  password
+  password,
+  role_id
 ) VALUES (
-  $1, $2, $3, $4
+  $1, $2, $3, $4, $5
 ) RETURNING *;
 
+-- name: LockBootstrap :exec
+-- Held until the transaction ends so concurrent bootstraps run one after the other
+SELECT pg_advisory_xact_lock(hashtext('bootstrap_admin'));
+
+-- name: CreateFirstAdmin :one
+-- Inserts nothing while any user has a superuser role
+INSERT INTO users (
+  email,
+  name,
+  type,
+  password,
+  role_id
+)
+SELECT @email::text, @name::text, 'admin', @password::text, (SELECT id FROM roles WHERE roles.name = 'admin')
+WHERE NOT EXISTS (
+  SELECT 1 FROM users u
+  JOIN roles r ON r.id = u.role_id
+  WHERE r.superuser
+)
+RETURNING *;
+
 -- name: GetUserByEmail :one
 SELECT * FROM users 
 WHERE email = $1 LIMIT 1;
 
+-- name: GetUserByID :one
+SELECT * FROM users
+WHERE id = $1 LIMIT 1;
+
 -- name: GetUsers :many
 SELECT * FROM users
 ORDER BY name
 LIMIT $1
-OFFSET $2;
\ No newline at end of file
+OFFSET $2;
+
+-- name: CountUsers :one
+SELECT count(*) FROM users;
+
+-- name: UpdateUser :one
+UPDATE users
+SET name = $1, type = $2, role_id = $3
+WHERE id = $4
+RETURNING *;
+
+-- name: SetUserActive :one
+UPDATE users
+SET active = $1
+WHERE id = $2
+RETURNING *;
+
+-- name: UpdateUserPassword :execrows
+UPDATE users
+SET password = $1
+WHERE id = $2;
+
+-- name: GetUserAuthorization :one
+SELECT
+  u.id,
+  u.type,
+  u.active,
+  u.role_id,
+  r.superuser,
+  COALESCE(array_agg(rp.permission) FILTER (WHERE rp.permission IS NOT NULL), '{}')::text[] AS permissions
+FROM users u
+JOIN roles r ON r.id = u.role_id
+LEFT JOIN role_permissions rp ON rp.role_id = r.id
+WHERE u.id = $1
+GROUP BY u.id, r.id;
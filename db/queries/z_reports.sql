@@ -0,0 +1,95 @@
+-- name: GetZReportPeriod :one
+SELECT
+  COALESCE(max(period_end), '1970-01-01'::timestamp)::timestamp AS period_start,
+  now()::timestamp AS period_end
+FROM z_reports;
+
+-- name: GetZReportTotals :one
+-- Sales, covers, discounts and voids are counted for the orders completed in the period, the tenders
+-- and cash for the closed drawer sessions that are not on a z report yet.
+SELECT
+  (SELECT count(*) FROM orders o
+    WHERE o.completed_at >= @period_start::timestamp AND o.completed_at < @period_end::timestamp
+      AND o.status = 'completed')::int AS order_count,
+  (SELECT COALESCE(sum(o.covers), 0) FROM orders o
+    WHERE o.completed_at >= @period_start::timestamp AND o.completed_at < @period_end::timestamp
+      AND o.status = 'completed' AND o.type = 'dining')::int AS covers,
+  (SELECT COALESCE(sum(oi.quantity * oi.unit_price), 0) FROM order_items oi
+    JOIN orders o ON o.id = oi.order_id
+    WHERE o.completed_at >= @period_start::timestamp AND o.completed_at < @period_end::timestamp
+      AND o.status = 'completed' AND oi.status <> 'cancelled')::float AS gross_sales,
+  (SELECT COALESCE(sum(od.amount), 0) FROM order_discounts od
+    JOIN orders o ON o.id = od.order_id
+    WHERE o.completed_at >= @period_start::timestamp AND o.completed_at < @period_end::timestamp
+      AND o.status = 'completed')::float AS discounts,
+  (SELECT count(*) FROM order_items oi
+    JOIN orders o ON o.id = oi.order_id
+    WHERE o.completed_at >= @period_start::timestamp AND o.completed_at < @period_end::timestamp
+      AND o.status = 'completed' AND oi.status = 'cancelled')::int AS void_count,
+  (SELECT COALESCE(sum(oi.quantity * oi.unit_price), 0) FROM order_items oi
+    JOIN orders o ON o.id = oi.order_id
+    WHERE o.completed_at >= @period_start::timestamp AND o.completed_at < @period_end::timestamp
+      AND o.status = 'completed' AND oi.status = 'cancelled')::float AS void_amount,
+  (SELECT COALESCE(sum(t.amount) FILTER (WHERE t.method = 'cash'), 0) FROM tenders t
+    JOIN drawer_sessions d ON d.id = t.session_id
+    WHERE d.z_report_id IS NULL AND d.closed_at < @period_end::timestamp)::float AS cash_tenders,
+  (SELECT COALESCE(sum(t.amount) FILTER (WHERE t.method = 'card'), 0) FROM tenders t
+    JOIN drawer_sessions d ON d.id = t.session_id
+    WHERE d.z_report_id IS NULL AND d.closed_at < @period_end::timestamp)::float AS card_tenders,
+  (SELECT COALESCE(sum(t.amount) FILTER (WHERE t.method = 'other'), 0) FROM tenders t
+    JOIN drawer_sessions d ON d.id = t.session_id
+    WHERE d.z_report_id IS NULL AND d.closed_at < @period_end::timestamp)::float AS other_tenders,
+  (SELECT COALESCE(sum(t.amount) FILTER (WHERE t.method = 'gift_card'), 0) FROM tenders t
+    JOIN drawer_sessions d ON d.id = t.session_id
+    WHERE d.z_report_id IS NULL AND d.closed_at < @period_end::timestamp)::float AS gift_card_tenders,
+  (SELECT count(*) FROM drawer_sessions d
+    WHERE d.z_report_id IS NULL AND d.closed_at < @period_end::timestamp)::int AS drawer_sessions,
+  (SELECT COALESCE(sum(d.expected_amount), 0) FROM drawer_sessions d
+    WHERE d.z_report_id IS NULL AND d.closed_at < @period_end::timestamp)::float AS expected_cash,
+  (SELECT COALESCE(sum(d.counted_amount), 0) FROM drawer_sessions d
+    WHERE d.z_report_id IS NULL AND d.closed_at < @period_end::timestamp)::float AS counted_cash;
+
+-- name: LockDrawerSessions :exec
+-- Keeps drawers from being opened or closed until the transaction ends, closing z reports also
+-- wait on each other through it.
+LOCK TABLE drawer_sessions IN SHARE ROW EXCLUSIVE MODE;
+
+-- name: CreateZReport :one
+INSERT INTO z_reports (
+  period_start,
+  period_end,
+  order_count,
+  covers,
+  gross_sales,
+  discounts,
+  net_sales,
+  tax_rate,
+  tax,
+  void_count,
+  void_amount,
+  cash_tenders,
+  card_tenders,
+  other_tenders,
+  gift_card_tenders,
+  drawer_sessions,
+  expected_cash,
+  counted_cash,
+  cash_variance,
+  closed_by
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20
+) RETURNING *;
+
+-- name: AttachDrawerSessionsToZReport :execrows
+UPDATE drawer_sessions
+SET z_report_id = $1
+WHERE z_report_id IS NULL AND closed_at < $2;
+
+-- name: GetZReportByID :one
+SELECT * FROM z_reports
+WHERE id = $1;
+
+-- name: GetZReports :many
+SELECT * FROM z_reports
+ORDER BY closed_at DESC
+LIMIT $1 OFFSET $2;
@@ -11,15 +11,55 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const countMenuItems = `-- name: CountMenuItems :one
+-- Same filters as GetMenuItems
+SELECT count(*)
+FROM menu_items m
+LEFT JOIN menu_item_translations t ON t.item_id = m.id AND t.lang = $1::text
+WHERE (
+    $2::text = ''
+    OR menu_search_document(m.name, m.description) @@ websearch_to_tsquery('simple', $2::text)
+    OR menu_search_document(t.name, t.description) @@ websearch_to_tsquery('simple', $2::text)
+    OR $2::text <% m.name
+    OR $2::text <% t.name
+  )
+  AND ($3::bool OR menu_item_available(m.id, localtimestamp))
+  AND NOT (menu_item_allergens(m.id) && coalesce($4::text[], '{}'))
+  AND m.dietary_tags @> coalesce($5::text[], '{}')
+`
+
+type CountMenuItemsParams struct {
+	Lang               string   `db:"lang"`
+	Search             string   `db:"search"`
+	IncludeUnavailable bool     `db:"include_unavailable"`
+	ExcludeAllergens   []string `db:"exclude_allergens"`
+	DietaryTags        []string `db:"dietary_tags"`
+}
+
+func (q *Queries) CountMenuItems(ctx context.Context, arg CountMenuItemsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countMenuItems,
+		arg.Lang,
+		arg.Search,
+		arg.IncludeUnavailable,
+		arg.ExcludeAllergens,
+		arg.DietaryTags,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createMenuItem = `-- name: CreateMenuItem :one
 INSERT INTO menu_items (
   name,
   description,
   price,
-  requires_ticket
+  requires_ticket,
+  allergens,
+  dietary_tags
 ) VALUES (
-  $1, $2, $3, $4
-) RETURNING id, name, description, price, requires_ticket, created_at
+  $1, $2, $3, $4, $5, $6
+) RETURNING id, name, description, price, requires_ticket, created_at, eighty_sixed, allergens, dietary_tags, image_key, thumbnail_key
 `
 
 type CreateMenuItemParams struct {
@@ -27,6 +67,8 @@ type CreateMenuItemParams struct {
 	Description    pgtype.Text `db:"description"`
 	Price          float64     `db:"price"`
 	RequiresTicket bool        `db:"requires_ticket"`
+	Allergens      []string    `db:"allergens"`
+	DietaryTags    []string    `db:"dietary_tags"`
 }
 
 func (q *Queries) CreateMenuItem(ctx context.Context, arg CreateMenuItemParams) (MenuItem, error) {
@@ -35,6 +77,8 @@ func (q *Queries) CreateMenuItem(ctx context.Context, arg CreateMenuItemParams)
 		arg.Description,
 		arg.Price,
 		arg.RequiresTicket,
+		arg.Allergens,
+		arg.DietaryTags,
 	)
 	var i MenuItem
 	err := row.Scan(
@@ -44,12 +88,17 @@ func (q *Queries) CreateMenuItem(ctx context.Context, arg CreateMenuItemParams)
 		&i.Price,
 		&i.RequiresTicket,
 		&i.CreatedAt,
+		&i.EightySixed,
+		&i.Allergens,
+		&i.DietaryTags,
+		&i.ImageKey,
+		&i.ThumbnailKey,
 	)
 	return i, err
 }
 
 const getItemByID = `-- name: GetItemByID :one
-SELECT id, name, description, price, requires_ticket, created_at FROM menu_items
+SELECT id, name, description, price, requires_ticket, created_at, eighty_sixed, allergens, dietary_tags, image_key, thumbnail_key FROM menu_items
 WHERE id = $1 
 LIMIT 1
 `
@@ -64,33 +113,110 @@ func (q *Queries) GetItemByID(ctx context.Context, id int32) (MenuItem, error) {
 		&i.Price,
 		&i.RequiresTicket,
 		&i.CreatedAt,
+		&i.EightySixed,
+		&i.Allergens,
+		&i.DietaryTags,
+		&i.ImageKey,
+		&i.ThumbnailKey,
 	)
 	return i, err
 }
 
 const getMenuItems = `-- name: GetMenuItems :many
-SELECT id, name, description, price, requires_ticket, created_at FROM menu_items
-WHERE ($3::text IS NULL OR name ILIKE '%' || $3::text || '%')
-ORDER BY name
+-- Without a search the items are listed by name, with one they are ranked by how well they match.
+-- The highlights are the html escaped item text with the matched words wrapped in <mark>.
+WITH q AS (
+  SELECT websearch_to_tsquery('simple', $3::text) AS query
+)
+SELECT
+  m.id,
+  coalesce(t.name, m.name)::text AS name,
+  coalesce(t.description, m.description) AS description,
+  m.price,
+  m.requires_ticket,
+  m.created_at,
+  m.eighty_sixed,
+  menu_item_price(m.id, m.price, localtimestamp)::float AS current_price,
+  menu_item_available(m.id, localtimestamp)::bool AS available,
+  menu_item_allergens(m.id)::text[] AS allergens,
+  m.dietary_tags,
+  m.image_key,
+  m.thumbnail_key,
+  CASE WHEN $3::text <> '' THEN
+    ts_headline('simple', html_escape(coalesce(t.name, m.name)), q.query, 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true')
+  END::text AS name_highlight,
+  CASE WHEN $3::text <> '' THEN
+    ts_headline('simple', html_escape(coalesce(t.description, m.description, '')), q.query, 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2')
+  END::text AS description_highlight
+FROM menu_items m
+CROSS JOIN q
+LEFT JOIN menu_item_translations t ON t.item_id = m.id AND t.lang = $4::text
+WHERE (
+    $3::text = ''
+    OR menu_search_document(m.name, m.description) @@ q.query
+    OR menu_search_document(t.name, t.description) @@ q.query
+    OR $3::text <% m.name
+    OR $3::text <% t.name
+  )
+  AND ($5::bool OR menu_item_available(m.id, localtimestamp))
+  AND NOT (menu_item_allergens(m.id) && coalesce($6::text[], '{}'))
+  AND m.dietary_tags @> coalesce($7::text[], '{}')
+ORDER BY
+  CASE WHEN $3::text <> '' THEN
+    ts_rank(menu_search_document(coalesce(t.name, m.name), coalesce(t.description, m.description)), q.query)
+    + word_similarity($3::text, coalesce(t.name, m.name))
+  END DESC NULLS LAST,
+  coalesce(t.name, m.name),
+  m.id
 LIMIT $1
 OFFSET $2
 `
 
+type GetMenuItemsRow struct {
+	ID                   int32            `db:"id"`
+	Name                 string           `db:"name"`
+	Description          pgtype.Text      `db:"description"`
+	Price                float64          `db:"price"`
+	RequiresTicket       bool             `db:"requires_ticket"`
+	CreatedAt            pgtype.Timestamp `db:"created_at"`
+	EightySixed          bool             `db:"eighty_sixed"`
+	CurrentPrice         float64          `db:"current_price"`
+	Available            bool             `db:"available"`
+	Allergens            []string         `db:"allergens"`
+	DietaryTags          []string         `db:"dietary_tags"`
+	ImageKey             pgtype.Text      `db:"image_key"`
+	ThumbnailKey         pgtype.Text      `db:"thumbnail_key"`
+	NameHighlight        pgtype.Text      `db:"name_highlight"`
+	DescriptionHighlight pgtype.Text      `db:"description_highlight"`
+}
+
 type GetMenuItemsParams struct {
-	Limit  int32  `db:"limit"`
-	Offset int32  `db:"offset"`
-	Search string `db:"search"`
+	Limit              int32    `db:"limit"`
+	Offset             int32    `db:"offset"`
+	Search             string   `db:"search"`
+	Lang               string   `db:"lang"`
+	IncludeUnavailable bool     `db:"include_unavailable"`
+	ExcludeAllergens   []string `db:"exclude_allergens"`
+	DietaryTags        []string `db:"dietary_tags"`
 }
 
-func (q *Queries) GetMenuItems(ctx context.Context, arg GetMenuItemsParams) ([]MenuItem, error) {
-	rows, err := q.db.Query(ctx, getMenuItems, arg.Limit, arg.Offset, arg.Search)
+func (q *Queries) GetMenuItems(ctx context.Context, arg GetMenuItemsParams) ([]GetMenuItemsRow, error) {
+	rows, err := q.db.Query(ctx, getMenuItems,
+		arg.Limit,
+		arg.Offset,
+		arg.Search,
+		arg.Lang,
+		arg.IncludeUnavailable,
+		arg.ExcludeAllergens,
+		arg.DietaryTags,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []MenuItem
+	var items []GetMenuItemsRow
 	for rows.Next() {
-		var i MenuItem
+		var i GetMenuItemsRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.Name,
@@ -98,6 +224,15 @@ func (q *Queries) GetMenuItems(ctx context.Context, arg GetMenuItemsParams) ([]M
 			&i.Price,
 			&i.RequiresTicket,
 			&i.CreatedAt,
+			&i.EightySixed,
+			&i.CurrentPrice,
+			&i.Available,
+			&i.Allergens,
+			&i.DietaryTags,
+			&i.ImageKey,
+			&i.ThumbnailKey,
+			&i.NameHighlight,
+			&i.DescriptionHighlight,
 		); err != nil {
 			return nil, err
 		}
@@ -108,3 +243,103 @@ func (q *Queries) GetMenuItems(ctx context.Context, arg GetMenuItemsParams) ([]M
 	}
 	return items, nil
 }
+
+const getOrderableItems = `-- name: GetOrderableItems :many
+SELECT
+  id,
+  name,
+  eighty_sixed,
+  menu_item_available(id, localtimestamp)::bool AS available,
+  menu_item_price(id, price, localtimestamp)::float AS current_price,
+  menu_item_allergens(id)::text[] AS allergens
+FROM menu_items
+WHERE id = ANY($1::int[])
+`
+
+type GetOrderableItemsRow struct {
+	ID           int32    `db:"id"`
+	Name         string   `db:"name"`
+	EightySixed  bool     `db:"eighty_sixed"`
+	Available    bool     `db:"available"`
+	CurrentPrice float64  `db:"current_price"`
+	Allergens    []string `db:"allergens"`
+}
+
+func (q *Queries) GetOrderableItems(ctx context.Context, ids []int32) ([]GetOrderableItemsRow, error) {
+	rows, err := q.db.Query(ctx, getOrderableItems, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetOrderableItemsRow
+	for rows.Next() {
+		var i GetOrderableItemsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.EightySixed,
+			&i.Available,
+			&i.CurrentPrice,
+			&i.Allergens,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateMenuItemImage = `-- name: UpdateMenuItemImage :execrows
+UPDATE menu_items
+SET image_key = $2, thumbnail_key = $3
+WHERE id = $1
+`
+
+type UpdateMenuItemImageParams struct {
+	ID           int32       `db:"id"`
+	ImageKey     pgtype.Text `db:"image_key"`
+	ThumbnailKey pgtype.Text `db:"thumbnail_key"`
+}
+
+func (q *Queries) UpdateMenuItemImage(ctx context.Context, arg UpdateMenuItemImageParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateMenuItemImage, arg.ID, arg.ImageKey, arg.ThumbnailKey)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateMenuItemTags = `-- name: UpdateMenuItemTags :one
+UPDATE menu_items
+SET allergens = $2, dietary_tags = $3
+WHERE id = $1
+RETURNING id, name, description, price, requires_ticket, created_at, eighty_sixed, allergens, dietary_tags, image_key, thumbnail_key
+`
+
+type UpdateMenuItemTagsParams struct {
+	ID          int32    `db:"id"`
+	Allergens   []string `db:"allergens"`
+	DietaryTags []string `db:"dietary_tags"`
+}
+
+func (q *Queries) UpdateMenuItemTags(ctx context.Context, arg UpdateMenuItemTagsParams) (MenuItem, error) {
+	row := q.db.QueryRow(ctx, updateMenuItemTags, arg.ID, arg.Allergens, arg.DietaryTags)
+	var i MenuItem
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Price,
+		&i.RequiresTicket,
+		&i.CreatedAt,
+		&i.EightySixed,
+		&i.Allergens,
+		&i.DietaryTags,
+		&i.ImageKey,
+		&i.ThumbnailKey,
+	)
+	return i, err
+}
@@ -11,6 +11,48 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type CashMovementType string
+
+const (
+	CashMovementTypePayIn  CashMovementType = "pay_in"
+	CashMovementTypePayOut CashMovementType = "pay_out"
+)
+
+func (e *CashMovementType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = CashMovementType(s)
+	case string:
+		*e = CashMovementType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for CashMovementType: %T", src)
+	}
+	return nil
+}
+
+type NullCashMovementType struct {
+	CashMovementType CashMovementType
+	Valid            bool // Valid is true if CashMovementType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullCashMovementType) Scan(value interface{}) error {
+	if value == nil {
+		ns.CashMovementType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.CashMovementType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullCashMovementType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.CashMovementType), nil
+}
+
 type DeliveryStatus string
 
 const (
@@ -58,6 +100,134 @@ func (ns NullDeliveryStatus) Value() (driver.Value, error) {
 	return string(ns.DeliveryStatus), nil
 }
 
+type DiscountType string
+
+const (
+	DiscountTypeLoyalty   DiscountType = "loyalty"
+	DiscountTypePromotion DiscountType = "promotion"
+)
+
+func (e *DiscountType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = DiscountType(s)
+	case string:
+		*e = DiscountType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for DiscountType: %T", src)
+	}
+	return nil
+}
+
+type NullDiscountType struct {
+	DiscountType DiscountType
+	Valid        bool // Valid is true if DiscountType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullDiscountType) Scan(value interface{}) error {
+	if value == nil {
+		ns.DiscountType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.DiscountType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullDiscountType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.DiscountType), nil
+}
+
+type GiftCardTransactionType string
+
+const (
+	GiftCardTransactionTypeIssue  GiftCardTransactionType = "issue"
+	GiftCardTransactionTypeReload GiftCardTransactionType = "reload"
+	GiftCardTransactionTypeRedeem GiftCardTransactionType = "redeem"
+)
+
+func (e *GiftCardTransactionType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = GiftCardTransactionType(s)
+	case string:
+		*e = GiftCardTransactionType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for GiftCardTransactionType: %T", src)
+	}
+	return nil
+}
+
+type NullGiftCardTransactionType struct {
+	GiftCardTransactionType GiftCardTransactionType
+	Valid                   bool // Valid is true if GiftCardTransactionType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullGiftCardTransactionType) Scan(value interface{}) error {
+	if value == nil {
+		ns.GiftCardTransactionType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.GiftCardTransactionType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullGiftCardTransactionType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.GiftCardTransactionType), nil
+}
+
+type LoyaltyTransactionType string
+
+const (
+	LoyaltyTransactionTypeEarn   LoyaltyTransactionType = "earn"
+	LoyaltyTransactionTypeRedeem LoyaltyTransactionType = "redeem"
+	LoyaltyTransactionTypeAdjust LoyaltyTransactionType = "adjust"
+)
+
+func (e *LoyaltyTransactionType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = LoyaltyTransactionType(s)
+	case string:
+		*e = LoyaltyTransactionType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for LoyaltyTransactionType: %T", src)
+	}
+	return nil
+}
+
+type NullLoyaltyTransactionType struct {
+	LoyaltyTransactionType LoyaltyTransactionType
+	Valid                  bool // Valid is true if LoyaltyTransactionType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullLoyaltyTransactionType) Scan(value interface{}) error {
+	if value == nil {
+		ns.LoyaltyTransactionType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.LoyaltyTransactionType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullLoyaltyTransactionType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.LoyaltyTransactionType), nil
+}
+
 type OrderItemStatus string
 
 const (
@@ -190,6 +360,138 @@ func (ns NullOrderType) Value() (driver.Value, error) {
 	return string(ns.OrderType), nil
 }
 
+type PromotionType string
+
+const (
+	PromotionTypePercentage PromotionType = "percentage"
+	PromotionTypeFixed      PromotionType = "fixed"
+	PromotionTypeBuyXGetY   PromotionType = "buy_x_get_y"
+)
+
+func (e *PromotionType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = PromotionType(s)
+	case string:
+		*e = PromotionType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for PromotionType: %T", src)
+	}
+	return nil
+}
+
+type NullPromotionType struct {
+	PromotionType PromotionType
+	Valid         bool // Valid is true if PromotionType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullPromotionType) Scan(value interface{}) error {
+	if value == nil {
+		ns.PromotionType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.PromotionType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullPromotionType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.PromotionType), nil
+}
+
+type PurchaseOrderStatus string
+
+const (
+	PurchaseOrderStatusDraft             PurchaseOrderStatus = "draft"
+	PurchaseOrderStatusOrdered           PurchaseOrderStatus = "ordered"
+	PurchaseOrderStatusPartiallyReceived PurchaseOrderStatus = "partially_received"
+	PurchaseOrderStatusReceived          PurchaseOrderStatus = "received"
+	PurchaseOrderStatusCancelled         PurchaseOrderStatus = "cancelled"
+)
+
+func (e *PurchaseOrderStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = PurchaseOrderStatus(s)
+	case string:
+		*e = PurchaseOrderStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for PurchaseOrderStatus: %T", src)
+	}
+	return nil
+}
+
+type NullPurchaseOrderStatus struct {
+	PurchaseOrderStatus PurchaseOrderStatus
+	Valid               bool // Valid is true if PurchaseOrderStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullPurchaseOrderStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.PurchaseOrderStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.PurchaseOrderStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullPurchaseOrderStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.PurchaseOrderStatus), nil
+}
+
+type StockMovementType string
+
+const (
+	StockMovementTypeSale       StockMovementType = "sale"
+	StockMovementTypeAdjustment StockMovementType = "adjustment"
+	StockMovementTypeWaste      StockMovementType = "waste"
+	StockMovementTypeReceived   StockMovementType = "received"
+)
+
+func (e *StockMovementType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = StockMovementType(s)
+	case string:
+		*e = StockMovementType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for StockMovementType: %T", src)
+	}
+	return nil
+}
+
+type NullStockMovementType struct {
+	StockMovementType StockMovementType
+	Valid             bool // Valid is true if StockMovementType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullStockMovementType) Scan(value interface{}) error {
+	if value == nil {
+		ns.StockMovementType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.StockMovementType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullStockMovementType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.StockMovementType), nil
+}
+
 type TableStatus string
 
 const (
@@ -278,6 +580,50 @@ func (ns NullTakeawayStatus) Value() (driver.Value, error) {
 	return string(ns.TakeawayStatus), nil
 }
 
+type TenderMethod string
+
+const (
+	TenderMethodCash     TenderMethod = "cash"
+	TenderMethodCard     TenderMethod = "card"
+	TenderMethodOther    TenderMethod = "other"
+	TenderMethodGiftCard TenderMethod = "gift_card"
+)
+
+func (e *TenderMethod) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = TenderMethod(s)
+	case string:
+		*e = TenderMethod(s)
+	default:
+		return fmt.Errorf("unsupported scan type for TenderMethod: %T", src)
+	}
+	return nil
+}
+
+type NullTenderMethod struct {
+	TenderMethod TenderMethod
+	Valid        bool // Valid is true if TenderMethod is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullTenderMethod) Scan(value interface{}) error {
+	if value == nil {
+		ns.TenderMethod, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.TenderMethod.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullTenderMethod) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.TenderMethod), nil
+}
+
 type UserType string
 
 const (
@@ -323,6 +669,83 @@ func (ns NullUserType) Value() (driver.Value, error) {
 	return string(ns.UserType), nil
 }
 
+type ApiKey struct {
+	ID          pgtype.UUID      `db:"id"`
+	Name        string           `db:"name"`
+	KeyHash     string           `db:"key_hash"`
+	Prefix      string           `db:"prefix"`
+	UserID      pgtype.UUID      `db:"user_id"`
+	Permissions []string         `db:"permissions"`
+	CreatedBy   pgtype.UUID      `db:"created_by"`
+	ExpiresAt   pgtype.Timestamp `db:"expires_at"`
+	CreatedAt   pgtype.Timestamp `db:"created_at"`
+	LastUsedAt  pgtype.Timestamp `db:"last_used_at"`
+	RevokedAt   pgtype.Timestamp `db:"revoked_at"`
+}
+
+type Break struct {
+	ID          int64            `db:"id"`
+	TimeEntryID int64            `db:"time_entry_id"`
+	Paid        bool             `db:"paid"`
+	StartedAt   pgtype.Timestamp `db:"started_at"`
+	EndedAt     pgtype.Timestamp `db:"ended_at"`
+}
+
+type CashMovement struct {
+	ID        int64            `db:"id"`
+	SessionID int64            `db:"session_id"`
+	Type      CashMovementType `db:"type"`
+	Amount    float64          `db:"amount"`
+	Reason    string           `db:"reason"`
+	CreatedBy pgtype.UUID      `db:"created_by"`
+	CreatedAt pgtype.Timestamp `db:"created_at"`
+}
+
+type Combo struct {
+	ID          int32            `db:"id"`
+	Name        string           `db:"name"`
+	Description pgtype.Text      `db:"description"`
+	Price       float64          `db:"price"`
+	Active      bool             `db:"active"`
+	CreatedAt   pgtype.Timestamp `db:"created_at"`
+}
+
+type ComboSlot struct {
+	ID       int32  `db:"id"`
+	ComboID  int32  `db:"combo_id"`
+	Name     string `db:"name"`
+	Position int16  `db:"position"`
+}
+
+type ComboSlotOption struct {
+	SlotID   int32   `db:"slot_id"`
+	ItemID   int32   `db:"item_id"`
+	Upcharge float64 `db:"upcharge"`
+}
+
+type Customer struct {
+	ID          int32            `db:"id"`
+	Name        string           `db:"name"`
+	Email       pgtype.Text      `db:"email"`
+	Notes       pgtype.Text      `db:"notes"`
+	Preferences pgtype.Text      `db:"preferences"`
+	CreatedAt   pgtype.Timestamp `db:"created_at"`
+}
+
+type CustomerAddress struct {
+	ID         int32       `db:"id"`
+	CustomerID int32       `db:"customer_id"`
+	Label      pgtype.Text `db:"label"`
+	Address    string      `db:"address"`
+	Notes      pgtype.Text `db:"notes"`
+}
+
+type CustomerPhone struct {
+	Phone      string      `db:"phone"`
+	CustomerID int32       `db:"customer_id"`
+	Label      pgtype.Text `db:"label"`
+}
+
 type DeliveryDetail struct {
 	OrderID      pgtype.UUID      `db:"order_id"`
 	Address      string           `db:"address"`
@@ -333,6 +756,103 @@ type DeliveryDetail struct {
 	DeliveredAt  pgtype.Timestamp `db:"delivered_at"`
 }
 
+type Device struct {
+	ID           pgtype.UUID      `db:"id"`
+	Name         string           `db:"name"`
+	TokenHash    string           `db:"token_hash"`
+	RegisteredBy pgtype.UUID      `db:"registered_by"`
+	CreatedAt    pgtype.Timestamp `db:"created_at"`
+	LastSeenAt   pgtype.Timestamp `db:"last_seen_at"`
+	RevokedAt    pgtype.Timestamp `db:"revoked_at"`
+}
+
+type DrawerSession struct {
+	ID             int64            `db:"id"`
+	Drawer         string           `db:"drawer"`
+	OpeningFloat   float64          `db:"opening_float"`
+	OpenedBy       pgtype.UUID      `db:"opened_by"`
+	OpenedAt       pgtype.Timestamp `db:"opened_at"`
+	ExpectedAmount pgtype.Float8    `db:"expected_amount"`
+	CountedAmount  pgtype.Float8    `db:"counted_amount"`
+	ClosedBy       pgtype.UUID      `db:"closed_by"`
+	ClosedAt       pgtype.Timestamp `db:"closed_at"`
+	Notes          pgtype.Text      `db:"notes"`
+	ZReportID      pgtype.Int8      `db:"z_report_id"`
+}
+
+type GiftCard struct {
+	ID         int32            `db:"id"`
+	Code       string           `db:"code"`
+	Balance    float64          `db:"balance"`
+	ExpiresAt  pgtype.Timestamp `db:"expires_at"`
+	CustomerID pgtype.Int4      `db:"customer_id"`
+	IssuedBy   pgtype.UUID      `db:"issued_by"`
+	CreatedAt  pgtype.Timestamp `db:"created_at"`
+}
+
+type GiftCardTransaction struct {
+	ID           int64                   `db:"id"`
+	GiftCardID   int32                   `db:"gift_card_id"`
+	Type         GiftCardTransactionType `db:"type"`
+	Amount       float64                 `db:"amount"`
+	BalanceAfter float64                 `db:"balance_after"`
+	OrderID      pgtype.UUID             `db:"order_id"`
+	TenderID     pgtype.Int8             `db:"tender_id"`
+	CreatedBy    pgtype.UUID             `db:"created_by"`
+	CreatedAt    pgtype.Timestamp        `db:"created_at"`
+}
+
+type Ingredient struct {
+	ID            int32            `db:"id"`
+	Name          string           `db:"name"`
+	Unit          string           `db:"unit"`
+	Stock         float64          `db:"stock"`
+	LowStockLevel float64          `db:"low_stock_level"`
+	CreatedAt     pgtype.Timestamp `db:"created_at"`
+	ParLevel      float64          `db:"par_level"`
+	SupplierID    pgtype.Int4      `db:"supplier_id"`
+	Allergens     []string         `db:"allergens"`
+}
+
+type LoginLockout struct {
+	Email          string           `db:"email"`
+	FailedAttempts int32            `db:"failed_attempts"`
+	Lockouts       int32            `db:"lockouts"`
+	LockedUntil    pgtype.Timestamp `db:"locked_until"`
+	LastFailedAt   pgtype.Timestamp `db:"last_failed_at"`
+}
+
+type LoyaltyTier struct {
+	ID         int32            `db:"id"`
+	Name       string           `db:"name"`
+	MinPoints  int32            `db:"min_points"`
+	Multiplier float64          `db:"multiplier"`
+	CreatedAt  pgtype.Timestamp `db:"created_at"`
+}
+
+type LoyaltyTransaction struct {
+	ID         int64                  `db:"id"`
+	CustomerID int32                  `db:"customer_id"`
+	Type       LoyaltyTransactionType `db:"type"`
+	Points     int32                  `db:"points"`
+	OrderID    pgtype.UUID            `db:"order_id"`
+	Note       pgtype.Text            `db:"note"`
+	CreatedBy  pgtype.UUID            `db:"created_by"`
+	CreatedAt  pgtype.Timestamp       `db:"created_at"`
+}
+
+type Menu struct {
+	ID        int32            `db:"id"`
+	Name      string           `db:"name"`
+	Active    bool             `db:"active"`
+	CreatedAt pgtype.Timestamp `db:"created_at"`
+}
+
+type MenuEntry struct {
+	MenuID int32 `db:"menu_id"`
+	ItemID int32 `db:"item_id"`
+}
+
 type MenuItem struct {
 	ID             int32            `db:"id"`
 	Name           string           `db:"name"`
@@ -340,26 +860,188 @@ type MenuItem struct {
 	Price          float64          `db:"price"`
 	RequiresTicket bool             `db:"requires_ticket"`
 	CreatedAt      pgtype.Timestamp `db:"created_at"`
+	EightySixed    bool             `db:"eighty_sixed"`
+	Allergens      []string         `db:"allergens"`
+	DietaryTags    []string         `db:"dietary_tags"`
+	ImageKey       pgtype.Text      `db:"image_key"`
+	ThumbnailKey   pgtype.Text      `db:"thumbnail_key"`
+}
+
+type MenuItemCost struct {
+	ID            int64            `db:"id"`
+	ItemID        int32            `db:"item_id"`
+	Cost          float64          `db:"cost"`
+	EffectiveFrom pgtype.Timestamp `db:"effective_from"`
+	CreatedBy     pgtype.UUID      `db:"created_by"`
+}
+
+type MenuItemTranslation struct {
+	ItemID      int32            `db:"item_id"`
+	Lang        string           `db:"lang"`
+	Name        string           `db:"name"`
+	Description pgtype.Text      `db:"description"`
+	UpdatedAt   pgtype.Timestamp `db:"updated_at"`
+}
+
+type MenuSchedule struct {
+	ID        int32            `db:"id"`
+	MenuID    int32            `db:"menu_id"`
+	Name      string           `db:"name"`
+	Days      []int16          `db:"days"`
+	StartTime pgtype.Time      `db:"start_time"`
+	EndTime   pgtype.Time      `db:"end_time"`
+	StartDate pgtype.Date      `db:"start_date"`
+	EndDate   pgtype.Date      `db:"end_date"`
+	CreatedAt pgtype.Timestamp `db:"created_at"`
 }
 
 type Order struct {
-	ID          pgtype.UUID      `db:"id"`
-	Type        OrderType        `db:"type"`
-	EmployeeID  pgtype.UUID      `db:"employee_id"`
-	Status      OrderStatus      `db:"status"`
-	TableID     pgtype.Text      `db:"table_id"`
+	ID             pgtype.UUID      `db:"id"`
+	Type           OrderType        `db:"type"`
+	EmployeeID     pgtype.UUID      `db:"employee_id"`
+	Status         OrderStatus      `db:"status"`
+	TableID        pgtype.Text      `db:"table_id"`
+	CreatedAt      pgtype.Timestamp `db:"created_at"`
+	CompletedAt    pgtype.Timestamp `db:"completed_at"`
+	Covers         pgtype.Int2      `db:"covers"`
+	Allergies      []string         `db:"allergies"`
+	BlockAllergens bool             `db:"block_allergens"`
+	CustomerID     pgtype.Int4      `db:"customer_id"`
+}
+
+type OrderCombo struct {
+	ID        int64            `db:"id"`
+	OrderID   pgtype.UUID      `db:"order_id"`
+	ComboID   int32            `db:"combo_id"`
+	Quantity  int32            `db:"quantity"`
+	UnitPrice float64          `db:"unit_price"`
+	AddedAt   pgtype.Timestamp `db:"added_at"`
+}
+
+type OrderDiscount struct {
+	ID          int64            `db:"id"`
+	OrderID     pgtype.UUID      `db:"order_id"`
+	Type        DiscountType     `db:"type"`
+	Description string           `db:"description"`
+	Amount      float64          `db:"amount"`
+	CreatedBy   pgtype.UUID      `db:"created_by"`
 	CreatedAt   pgtype.Timestamp `db:"created_at"`
-	CompletedAt pgtype.Timestamp `db:"completed_at"`
+	PromotionID pgtype.Int4      `db:"promotion_id"`
+	CustomerID  pgtype.Int4      `db:"customer_id"`
 }
 
 type OrderItem struct {
-	ID       int64            `db:"id"`
-	OrderID  pgtype.UUID      `db:"order_id"`
-	ItemID   int32            `db:"item_id"`
-	Quantity int32            `db:"quantity"`
-	Notes    pgtype.Text      `db:"notes"`
-	Status   OrderItemStatus  `db:"status"`
-	AddedAt  pgtype.Timestamp `db:"added_at"`
+	ID           int64            `db:"id"`
+	OrderID      pgtype.UUID      `db:"order_id"`
+	ItemID       int32            `db:"item_id"`
+	Quantity     int32            `db:"quantity"`
+	Notes        pgtype.Text      `db:"notes"`
+	Status       OrderItemStatus  `db:"status"`
+	AddedAt      pgtype.Timestamp `db:"added_at"`
+	DepletedAt   pgtype.Timestamp `db:"depleted_at"`
+	UnitPrice    float64          `db:"unit_price"`
+	OrderComboID pgtype.Int8      `db:"order_combo_id"`
+}
+
+type Promotion struct {
+	ID            int32            `db:"id"`
+	Name          string           `db:"name"`
+	Code          pgtype.Text      `db:"code"`
+	Type          PromotionType    `db:"type"`
+	Value         float64          `db:"value"`
+	BuyItemID     pgtype.Int4      `db:"buy_item_id"`
+	BuyQuantity   int32            `db:"buy_quantity"`
+	GetItemID     pgtype.Int4      `db:"get_item_id"`
+	GetQuantity   int32            `db:"get_quantity"`
+	MinSpend      float64          `db:"min_spend"`
+	OrderTypes    []string         `db:"order_types"`
+	Days          []int16          `db:"days"`
+	StartTime     pgtype.Time      `db:"start_time"`
+	EndTime       pgtype.Time      `db:"end_time"`
+	StartDate     pgtype.Date      `db:"start_date"`
+	EndDate       pgtype.Date      `db:"end_date"`
+	UsageLimit    pgtype.Int4      `db:"usage_limit"`
+	CustomerLimit pgtype.Int4      `db:"customer_limit"`
+	Active        bool             `db:"active"`
+	CreatedBy     pgtype.UUID      `db:"created_by"`
+	CreatedAt     pgtype.Timestamp `db:"created_at"`
+}
+
+type PurchaseOrder struct {
+	ID         int64               `db:"id"`
+	SupplierID int32               `db:"supplier_id"`
+	Status     PurchaseOrderStatus `db:"status"`
+	Notes      pgtype.Text         `db:"notes"`
+	CreatedBy  pgtype.UUID         `db:"created_by"`
+	CreatedAt  pgtype.Timestamp    `db:"created_at"`
+	OrderedAt  pgtype.Timestamp    `db:"ordered_at"`
+	ReceivedAt pgtype.Timestamp    `db:"received_at"`
+}
+
+type PurchaseOrderItem struct {
+	ID               int64   `db:"id"`
+	PurchaseOrderID  int64   `db:"purchase_order_id"`
+	IngredientID     int32   `db:"ingredient_id"`
+	Quantity         float64 `db:"quantity"`
+	ReceivedQuantity float64 `db:"received_quantity"`
+	UnitCost         float64 `db:"unit_cost"`
+}
+
+type Recipe struct {
+	ItemID       int32   `db:"item_id"`
+	IngredientID int32   `db:"ingredient_id"`
+	Quantity     float64 `db:"quantity"`
+}
+
+type Role struct {
+	ID          int32            `db:"id"`
+	Name        string           `db:"name"`
+	Description pgtype.Text      `db:"description"`
+	Superuser   bool             `db:"superuser"`
+	CreatedAt   pgtype.Timestamp `db:"created_at"`
+}
+
+type RolePermission struct {
+	RoleID     int32  `db:"role_id"`
+	Permission string `db:"permission"`
+}
+
+type SchedulePrice struct {
+	ScheduleID int32   `db:"schedule_id"`
+	ItemID     int32   `db:"item_id"`
+	Price      float64 `db:"price"`
+}
+
+type ScheduledShift struct {
+	ID        int64            `db:"id"`
+	UserID    pgtype.UUID      `db:"user_id"`
+	StartsAt  pgtype.Timestamp `db:"starts_at"`
+	EndsAt    pgtype.Timestamp `db:"ends_at"`
+	Notes     pgtype.Text      `db:"notes"`
+	CreatedBy pgtype.UUID      `db:"created_by"`
+	CreatedAt pgtype.Timestamp `db:"created_at"`
+}
+
+type StockMovement struct {
+	ID              int64             `db:"id"`
+	IngredientID    int32             `db:"ingredient_id"`
+	Type            StockMovementType `db:"type"`
+	Quantity        float64           `db:"quantity"`
+	Reason          pgtype.Text       `db:"reason"`
+	OrderItemID     pgtype.Int8       `db:"order_item_id"`
+	CreatedBy       pgtype.UUID       `db:"created_by"`
+	CreatedAt       pgtype.Timestamp  `db:"created_at"`
+	PurchaseOrderID pgtype.Int8       `db:"purchase_order_id"`
+}
+
+type Supplier struct {
+	ID          int32            `db:"id"`
+	Name        string           `db:"name"`
+	ContactName pgtype.Text      `db:"contact_name"`
+	Phone       pgtype.Text      `db:"phone"`
+	Email       pgtype.Text      `db:"email"`
+	Notes       pgtype.Text      `db:"notes"`
+	CreatedAt   pgtype.Timestamp `db:"created_at"`
 }
 
 type Table struct {
@@ -377,6 +1059,24 @@ type TakeawayDetail struct {
 	PickedAt pgtype.Timestamp `db:"picked_at"`
 }
 
+type Tender struct {
+	ID        int64            `db:"id"`
+	SessionID int64            `db:"session_id"`
+	OrderID   pgtype.UUID      `db:"order_id"`
+	Method    TenderMethod     `db:"method"`
+	Amount    float64          `db:"amount"`
+	CreatedBy pgtype.UUID      `db:"created_by"`
+	CreatedAt pgtype.Timestamp `db:"created_at"`
+}
+
+type TimeEntry struct {
+	ID       int64            `db:"id"`
+	UserID   pgtype.UUID      `db:"user_id"`
+	ClockIn  pgtype.Timestamp `db:"clock_in"`
+	ClockOut pgtype.Timestamp `db:"clock_out"`
+	Notes    pgtype.Text      `db:"notes"`
+}
+
 type User struct {
 	ID        pgtype.UUID      `db:"id"`
 	Email     string           `db:"email"`
@@ -384,4 +1084,39 @@ type User struct {
 	Type      UserType         `db:"type"`
 	Password  string           `db:"password"`
 	CreatedAt pgtype.Timestamp `db:"created_at"`
+	Active    bool             `db:"active"`
+	RoleID    int32            `db:"role_id"`
+}
+
+type UserPin struct {
+	UserID         pgtype.UUID      `db:"user_id"`
+	Pin            string           `db:"pin"`
+	FailedAttempts int32            `db:"failed_attempts"`
+	LockedUntil    pgtype.Timestamp `db:"locked_until"`
+	UpdatedAt      pgtype.Timestamp `db:"updated_at"`
+}
+
+type ZReport struct {
+	ID              int64            `db:"id"`
+	PeriodStart     pgtype.Timestamp `db:"period_start"`
+	PeriodEnd       pgtype.Timestamp `db:"period_end"`
+	OrderCount      int32            `db:"order_count"`
+	Covers          int32            `db:"covers"`
+	GrossSales      float64          `db:"gross_sales"`
+	Discounts       float64          `db:"discounts"`
+	NetSales        float64          `db:"net_sales"`
+	TaxRate         float64          `db:"tax_rate"`
+	Tax             float64          `db:"tax"`
+	VoidCount       int32            `db:"void_count"`
+	VoidAmount      float64          `db:"void_amount"`
+	CashTenders     float64          `db:"cash_tenders"`
+	CardTenders     float64          `db:"card_tenders"`
+	OtherTenders    float64          `db:"other_tenders"`
+	DrawerSessions  int32            `db:"drawer_sessions"`
+	ExpectedCash    float64          `db:"expected_cash"`
+	CountedCash     float64          `db:"counted_cash"`
+	CashVariance    float64          `db:"cash_variance"`
+	ClosedBy        pgtype.UUID      `db:"closed_by"`
+	ClosedAt        pgtype.Timestamp `db:"closed_at"`
+	GiftCardTenders float64          `db:"gift_card_tenders"`
 }
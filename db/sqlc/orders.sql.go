@@ -12,15 +12,16 @@ import (
 )
 
 const addOrderItemsBulk = `-- name: AddOrderItemsBulk :exec
-INSERT INTO order_items (order_id, item_id, quantity, notes)
-SELECT $1, unnest($2::int[]), unnest($3::int[]), unnest($4::text[])
+INSERT INTO order_items (order_id, item_id, quantity, notes, unit_price)
+SELECT $1, unnest($2::int[]), unnest($3::int[]), unnest($4::text[]), unnest($5::float[])
 `
 
 type AddOrderItemsBulkParams struct {
-	OrderID  pgtype.UUID `db:"order_id"`
-	ItemIds  []int32     `db:"item_ids"`
-	Quantity []int32     `db:"quantity"`
-	Notes    []string    `db:"notes"`
+	OrderID    pgtype.UUID `db:"order_id"`
+	ItemIds    []int32     `db:"item_ids"`
+	Quantity   []int32     `db:"quantity"`
+	Notes      []string    `db:"notes"`
+	UnitPrices []float64   `db:"unit_prices"`
 }
 
 func (q *Queries) AddOrderItemsBulk(ctx context.Context, arg AddOrderItemsBulkParams) error {
@@ -29,6 +30,132 @@ func (q *Queries) AddOrderItemsBulk(ctx context.Context, arg AddOrderItemsBulkPa
 		arg.ItemIds,
 		arg.Quantity,
 		arg.Notes,
+		arg.UnitPrices,
+	)
+	return err
+}
+
+const completeOrder = `-- name: CompleteOrder :one
+UPDATE orders
+SET status = 'completed', completed_at = now()
+WHERE id = $1 AND status = 'ongoing'
+RETURNING id, type, employee_id, status, table_id, created_at, completed_at, covers, allergies, block_allergens, customer_id
+`
+
+func (q *Queries) CompleteOrder(ctx context.Context, id pgtype.UUID) (Order, error) {
+	row := q.db.QueryRow(ctx, completeOrder, id)
+	var i Order
+	err := row.Scan(
+		&i.ID,
+		&i.Type,
+		&i.EmployeeID,
+		&i.Status,
+		&i.TableID,
+		&i.CreatedAt,
+		&i.CompletedAt,
+		&i.Covers,
+		&i.Allergies,
+		&i.BlockAllergens,
+		&i.CustomerID,
+	)
+	return i, err
+}
+
+const countOrders = `-- name: CountOrders :one
+SELECT count(*) FROM orders
+WHERE status = $1 AND type = $2
+`
+
+type CountOrdersParams struct {
+	Status OrderStatus `db:"status"`
+	Type   OrderType   `db:"type"`
+}
+
+func (q *Queries) CountOrders(ctx context.Context, arg CountOrdersParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countOrders, arg.Status, arg.Type)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countSearchOrders = `-- name: CountSearchOrders :one
+-- Same filters as SearchOrders
+SELECT count(*)
+FROM orders o
+CROSS JOIN LATERAL (
+  SELECT coalesce(sum(oi.quantity * oi.unit_price), 0)::float AS total
+  FROM order_items oi
+  WHERE oi.order_id = o.id AND oi.status <> 'cancelled'
+) t
+WHERE ($1::timestamp IS NULL OR o.created_at >= $1::timestamp)
+  AND ($2::timestamp IS NULL OR o.created_at < $2::timestamp)
+  AND ($3::text = '' OR o.status::text = $3::text)
+  AND ($4::text = '' OR o.type::text = $4::text)
+  AND ($5::text = '' OR o.table_id = $5::text)
+  AND ($6::uuid IS NULL OR o.employee_id = $6::uuid)
+  AND ($7::int = 0 OR EXISTS (
+    SELECT 1 FROM order_items x WHERE x.order_id = o.id AND x.item_id = $7::int
+  ))
+  AND ($8::float IS NULL OR t.total >= $8::float)
+  AND ($9::float IS NULL OR t.total <= $9::float)
+  AND ($10::int = 0 OR o.customer_id = $10::int)
+`
+
+type CountSearchOrdersParams struct {
+	PeriodStart pgtype.Timestamp `db:"period_start"`
+	PeriodEnd   pgtype.Timestamp `db:"period_end"`
+	Status      string           `db:"status"`
+	Type        string           `db:"type"`
+	TableID     string           `db:"table_id"`
+	EmployeeID  pgtype.UUID      `db:"employee_id"`
+	ItemID      int32            `db:"item_id"`
+	MinTotal    pgtype.Float8    `db:"min_total"`
+	MaxTotal    pgtype.Float8    `db:"max_total"`
+	CustomerID  int32            `db:"customer_id"`
+}
+
+func (q *Queries) CountSearchOrders(ctx context.Context, arg CountSearchOrdersParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countSearchOrders,
+		arg.PeriodStart,
+		arg.PeriodEnd,
+		arg.Status,
+		arg.Type,
+		arg.TableID,
+		arg.EmployeeID,
+		arg.ItemID,
+		arg.MinTotal,
+		arg.MaxTotal,
+		arg.CustomerID,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createDeliveryDetails = `-- name: CreateDeliveryDetails :exec
+INSERT INTO delivery_details (
+  order_id,
+  address,
+  contact,
+  driver_id
+) VALUES (
+  $1, $2, $3, $4
+)
+`
+
+type CreateDeliveryDetailsParams struct {
+	OrderID  pgtype.UUID `db:"order_id"`
+	Address  string      `db:"address"`
+	Contact  string      `db:"contact"`
+	DriverID pgtype.UUID `db:"driver_id"`
+}
+
+func (q *Queries) CreateDeliveryDetails(ctx context.Context, arg CreateDeliveryDetailsParams) error {
+	_, err := q.db.Exec(ctx, createDeliveryDetails,
+		arg.OrderID,
+		arg.Address,
+		arg.Contact,
+		arg.DriverID,
 	)
 	return err
 }
@@ -37,9 +164,11 @@ const createOrder = `-- name: CreateOrder :one
 INSERT INTO orders (
   type,
   employee_id,
-  table_id
+  table_id,
+  covers,
+  customer_id
 ) VALUES (
-  $1, $2, $3
+  $1, $2, $3, $4, $5
 ) RETURNING id
 `
 
@@ -47,17 +176,114 @@ type CreateOrderParams struct {
 	Type       OrderType   `db:"type"`
 	EmployeeID pgtype.UUID `db:"employee_id"`
 	TableID    pgtype.Text `db:"table_id"`
+	Covers     pgtype.Int2 `db:"covers"`
+	CustomerID pgtype.Int4 `db:"customer_id"`
 }
 
 func (q *Queries) CreateOrder(ctx context.Context, arg CreateOrderParams) (pgtype.UUID, error) {
-	row := q.db.QueryRow(ctx, createOrder, arg.Type, arg.EmployeeID, arg.TableID)
+	row := q.db.QueryRow(ctx, createOrder,
+		arg.Type,
+		arg.EmployeeID,
+		arg.TableID,
+		arg.Covers,
+		arg.CustomerID,
+	)
 	var id pgtype.UUID
 	err := row.Scan(&id)
 	return id, err
 }
 
+const createOrderDiscount = `-- name: CreateOrderDiscount :one
+INSERT INTO order_discounts (
+  order_id,
+  type,
+  description,
+  amount,
+  created_by,
+  promotion_id,
+  customer_id
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+) RETURNING id, order_id, type, description, amount, created_by, created_at, promotion_id, customer_id
+`
+
+type CreateOrderDiscountParams struct {
+	OrderID     pgtype.UUID  `db:"order_id"`
+	Type        DiscountType `db:"type"`
+	Description string       `db:"description"`
+	Amount      float64      `db:"amount"`
+	CreatedBy   pgtype.UUID  `db:"created_by"`
+	PromotionID pgtype.Int4  `db:"promotion_id"`
+	CustomerID  pgtype.Int4  `db:"customer_id"`
+}
+
+func (q *Queries) CreateOrderDiscount(ctx context.Context, arg CreateOrderDiscountParams) (OrderDiscount, error) {
+	row := q.db.QueryRow(ctx, createOrderDiscount,
+		arg.OrderID,
+		arg.Type,
+		arg.Description,
+		arg.Amount,
+		arg.CreatedBy,
+		arg.PromotionID,
+		arg.CustomerID,
+	)
+	var i OrderDiscount
+	err := row.Scan(
+		&i.ID,
+		&i.OrderID,
+		&i.Type,
+		&i.Description,
+		&i.Amount,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.PromotionID,
+		&i.CustomerID,
+	)
+	return i, err
+}
+
+const createTakeawayDetails = `-- name: CreateTakeawayDetails :exec
+INSERT INTO takeaway_details (
+  order_id,
+  contact,
+  notes
+) VALUES (
+  $1, $2, $3
+)
+`
+
+type CreateTakeawayDetailsParams struct {
+	OrderID pgtype.UUID `db:"order_id"`
+	Contact string      `db:"contact"`
+	Notes   pgtype.Text `db:"notes"`
+}
+
+func (q *Queries) CreateTakeawayDetails(ctx context.Context, arg CreateTakeawayDetailsParams) error {
+	_, err := q.db.Exec(ctx, createTakeawayDetails, arg.OrderID, arg.Contact, arg.Notes)
+	return err
+}
+
+const getOrderBill = `-- name: GetOrderBill :one
+-- subtotal leaves out cancelled items, discounts is everything taken off the order
+SELECT
+  (SELECT coalesce(sum(oi.quantity * oi.unit_price), 0) FROM order_items oi WHERE oi.order_id = $1 AND oi.status <> 'cancelled')::float AS subtotal,
+  (SELECT coalesce(sum(d.amount), 0) FROM order_discounts d WHERE d.order_id = $1)::float AS discounts
+`
+
+type GetOrderBillRow struct {
+	Subtotal  float64 `db:"subtotal"`
+	Discounts float64 `db:"discounts"`
+}
+
+func (q *Queries) GetOrderBill(ctx context.Context, orderID pgtype.UUID) (GetOrderBillRow, error) {
+	row := q.db.QueryRow(ctx, getOrderBill, orderID)
+	var i GetOrderBillRow
+	err := row.Scan(&i.Subtotal, &i.Discounts)
+	return i, err
+}
+
 const getOrderByID = `-- name: GetOrderByID :one
-SELECT id, type, employee_id, status, table_id, created_at, completed_at FROM orders 
+SELECT id, type, employee_id, status, table_id, created_at, completed_at, covers, allergies, block_allergens, customer_id FROM orders 
 WHERE id = $1
 `
 
@@ -72,12 +298,77 @@ func (q *Queries) GetOrderByID(ctx context.Context, id pgtype.UUID) (Order, erro
 		&i.TableID,
 		&i.CreatedAt,
 		&i.CompletedAt,
+		&i.Covers,
+		&i.Allergies,
+		&i.BlockAllergens,
+		&i.CustomerID,
+	)
+	return i, err
+}
+
+const getOrderDiscounts = `-- name: GetOrderDiscounts :many
+SELECT id, order_id, type, description, amount, created_by, created_at, promotion_id, customer_id FROM order_discounts
+WHERE order_id = $1
+ORDER BY created_at, id
+`
+
+func (q *Queries) GetOrderDiscounts(ctx context.Context, orderID pgtype.UUID) ([]OrderDiscount, error) {
+	rows, err := q.db.Query(ctx, getOrderDiscounts, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []OrderDiscount
+	for rows.Next() {
+		var i OrderDiscount
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrderID,
+			&i.Type,
+			&i.Description,
+			&i.Amount,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.PromotionID,
+			&i.CustomerID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getOrderForUpdate = `-- name: GetOrderForUpdate :one
+SELECT id, type, employee_id, status, table_id, created_at, completed_at, covers, allergies, block_allergens, customer_id FROM orders
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetOrderForUpdate(ctx context.Context, id pgtype.UUID) (Order, error) {
+	row := q.db.QueryRow(ctx, getOrderForUpdate, id)
+	var i Order
+	err := row.Scan(
+		&i.ID,
+		&i.Type,
+		&i.EmployeeID,
+		&i.Status,
+		&i.TableID,
+		&i.CreatedAt,
+		&i.CompletedAt,
+		&i.Covers,
+		&i.Allergies,
+		&i.BlockAllergens,
+		&i.CustomerID,
 	)
 	return i, err
 }
 
 const getOrderItemByID = `-- name: GetOrderItemByID :one
-SELECT id, order_id, item_id, quantity, notes, status, added_at FROM order_items
+SELECT id, order_id, item_id, quantity, notes, status, added_at, depleted_at, unit_price, order_combo_id FROM order_items
 WHERE order_id = $1 AND id = $2
 `
 
@@ -97,22 +388,140 @@ func (q *Queries) GetOrderItemByID(ctx context.Context, arg GetOrderItemByIDPara
 		&i.Notes,
 		&i.Status,
 		&i.AddedAt,
+		&i.DepletedAt,
+		&i.UnitPrice,
+		&i.OrderComboID,
 	)
 	return i, err
 }
 
+const getOrderItems = `-- name: GetOrderItems :many
+SELECT id, order_id, item_id, quantity, notes, status, added_at, depleted_at, unit_price, order_combo_id FROM order_items
+WHERE order_id = $1
+ORDER BY id
+`
+
+func (q *Queries) GetOrderItems(ctx context.Context, orderID pgtype.UUID) ([]OrderItem, error) {
+	rows, err := q.db.Query(ctx, getOrderItems, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []OrderItem
+	for rows.Next() {
+		var i OrderItem
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrderID,
+			&i.ItemID,
+			&i.Quantity,
+			&i.Notes,
+			&i.Status,
+			&i.AddedAt,
+			&i.DepletedAt,
+			&i.UnitPrice,
+			&i.OrderComboID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getOrderTendered = `-- name: GetOrderTendered :one
+-- What has been paid towards the order so far, in every tender method
+SELECT coalesce(sum(amount), 0)::float AS tendered FROM tenders
+WHERE order_id = $1
+`
+
+func (q *Queries) GetOrderTendered(ctx context.Context, orderID pgtype.UUID) (float64, error) {
+	row := q.db.QueryRow(ctx, getOrderTendered, orderID)
+	var tendered float64
+	err := row.Scan(&tendered)
+	return tendered, err
+}
+
 const getOrders = `-- name: GetOrders :many
-SELECT id, type, employee_id, status, table_id, created_at, completed_at FROM orders
+SELECT id, type, employee_id, status, table_id, created_at, completed_at, covers, allergies, block_allergens, customer_id FROM orders
 WHERE status = $1 AND type = $2
+ORDER BY created_at DESC, id DESC
+LIMIT $3
+OFFSET $4
 `
 
 type GetOrdersParams struct {
 	Status OrderStatus `db:"status"`
 	Type   OrderType   `db:"type"`
+	Limit  int32       `db:"limit"`
+	Offset int32       `db:"offset"`
 }
 
 func (q *Queries) GetOrders(ctx context.Context, arg GetOrdersParams) ([]Order, error) {
-	rows, err := q.db.Query(ctx, getOrders, arg.Status, arg.Type)
+	rows, err := q.db.Query(ctx, getOrders,
+		arg.Status,
+		arg.Type,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Order
+	for rows.Next() {
+		var i Order
+		if err := rows.Scan(
+			&i.ID,
+			&i.Type,
+			&i.EmployeeID,
+			&i.Status,
+			&i.TableID,
+			&i.CreatedAt,
+			&i.CompletedAt,
+			&i.Covers,
+			&i.Allergies,
+			&i.BlockAllergens,
+			&i.CustomerID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getOrdersBefore = `-- name: GetOrdersBefore :many
+-- Keyset page of GetOrders, the orders after the last one of the previous page
+SELECT id, type, employee_id, status, table_id, created_at, completed_at, covers, allergies, block_allergens, customer_id FROM orders
+WHERE status = $1 AND type = $2
+  AND (created_at, id) < ($4::timestamp, $5::uuid)
+ORDER BY created_at DESC, id DESC
+LIMIT $3
+`
+
+type GetOrdersBeforeParams struct {
+	Status          OrderStatus      `db:"status"`
+	Type            OrderType        `db:"type"`
+	Limit           int32            `db:"limit"`
+	CursorCreatedAt pgtype.Timestamp `db:"cursor_created_at"`
+	CursorID        pgtype.UUID      `db:"cursor_id"`
+}
+
+func (q *Queries) GetOrdersBefore(ctx context.Context, arg GetOrdersBeforeParams) ([]Order, error) {
+	rows, err := q.db.Query(ctx, getOrdersBefore,
+		arg.Status,
+		arg.Type,
+		arg.Limit,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -128,6 +537,135 @@ func (q *Queries) GetOrders(ctx context.Context, arg GetOrdersParams) ([]Order,
 			&i.TableID,
 			&i.CreatedAt,
 			&i.CompletedAt,
+			&i.Covers,
+			&i.Allergies,
+			&i.BlockAllergens,
+			&i.CustomerID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchOrders = `-- name: SearchOrders :many
+-- Every filter is optional, empty strings, zeros and nulls match everything.
+-- total is what the order comes to without the cancelled items.
+SELECT
+  o.id,
+  o.type,
+  o.employee_id,
+  u.name AS employee_name,
+  o.status,
+  o.table_id,
+  o.covers,
+  o.customer_id,
+  o.created_at,
+  o.completed_at,
+  t.total
+FROM orders o
+JOIN users u ON u.id = o.employee_id
+CROSS JOIN LATERAL (
+  SELECT coalesce(sum(oi.quantity * oi.unit_price), 0)::float AS total
+  FROM order_items oi
+  WHERE oi.order_id = o.id AND oi.status <> 'cancelled'
+) t
+WHERE ($3::timestamp IS NULL OR o.created_at >= $3::timestamp)
+  AND ($4::timestamp IS NULL OR o.created_at < $4::timestamp)
+  AND ($5::text = '' OR o.status::text = $5::text)
+  AND ($6::text = '' OR o.type::text = $6::text)
+  AND ($7::text = '' OR o.table_id = $7::text)
+  AND ($8::uuid IS NULL OR o.employee_id = $8::uuid)
+  AND ($9::int = 0 OR EXISTS (
+    SELECT 1 FROM order_items x WHERE x.order_id = o.id AND x.item_id = $9::int
+  ))
+  AND ($10::float IS NULL OR t.total >= $10::float)
+  AND ($11::float IS NULL OR t.total <= $11::float)
+  AND ($12::int = 0 OR o.customer_id = $12::int)
+ORDER BY
+  CASE WHEN $13::text = 'created_at' AND NOT $14::bool THEN o.created_at END ASC,
+  CASE WHEN $13::text = 'created_at' AND $14::bool THEN o.created_at END DESC,
+  CASE WHEN $13::text = 'completed_at' AND NOT $14::bool THEN o.completed_at END ASC NULLS LAST,
+  CASE WHEN $13::text = 'completed_at' AND $14::bool THEN o.completed_at END DESC NULLS LAST,
+  CASE WHEN $13::text = 'total' AND NOT $14::bool THEN t.total END ASC,
+  CASE WHEN $13::text = 'total' AND $14::bool THEN t.total END DESC,
+  o.created_at DESC,
+  o.id DESC
+LIMIT $1
+OFFSET $2
+`
+
+type SearchOrdersRow struct {
+	ID           pgtype.UUID      `db:"id"`
+	Type         OrderType        `db:"type"`
+	EmployeeID   pgtype.UUID      `db:"employee_id"`
+	EmployeeName string           `db:"employee_name"`
+	Status       OrderStatus      `db:"status"`
+	TableID      pgtype.Text      `db:"table_id"`
+	Covers       pgtype.Int2      `db:"covers"`
+	CustomerID   pgtype.Int4      `db:"customer_id"`
+	CreatedAt    pgtype.Timestamp `db:"created_at"`
+	CompletedAt  pgtype.Timestamp `db:"completed_at"`
+	Total        float64          `db:"total"`
+}
+
+type SearchOrdersParams struct {
+	Limit       int32            `db:"limit"`
+	Offset      int32            `db:"offset"`
+	PeriodStart pgtype.Timestamp `db:"period_start"`
+	PeriodEnd   pgtype.Timestamp `db:"period_end"`
+	Status      string           `db:"status"`
+	Type        string           `db:"type"`
+	TableID     string           `db:"table_id"`
+	EmployeeID  pgtype.UUID      `db:"employee_id"`
+	ItemID      int32            `db:"item_id"`
+	MinTotal    pgtype.Float8    `db:"min_total"`
+	MaxTotal    pgtype.Float8    `db:"max_total"`
+	CustomerID  int32            `db:"customer_id"`
+	Sort        string           `db:"sort"`
+	SortDesc    bool             `db:"sort_desc"`
+}
+
+func (q *Queries) SearchOrders(ctx context.Context, arg SearchOrdersParams) ([]SearchOrdersRow, error) {
+	rows, err := q.db.Query(ctx, searchOrders,
+		arg.Limit,
+		arg.Offset,
+		arg.PeriodStart,
+		arg.PeriodEnd,
+		arg.Status,
+		arg.Type,
+		arg.TableID,
+		arg.EmployeeID,
+		arg.ItemID,
+		arg.MinTotal,
+		arg.MaxTotal,
+		arg.CustomerID,
+		arg.Sort,
+		arg.SortDesc,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchOrdersRow
+	for rows.Next() {
+		var i SearchOrdersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Type,
+			&i.EmployeeID,
+			&i.EmployeeName,
+			&i.Status,
+			&i.TableID,
+			&i.Covers,
+			&i.CustomerID,
+			&i.CreatedAt,
+			&i.CompletedAt,
+			&i.Total,
 		); err != nil {
 			return nil, err
 		}
@@ -139,6 +677,47 @@ func (q *Queries) GetOrders(ctx context.Context, arg GetOrdersParams) ([]Order,
 	return items, nil
 }
 
+const setOrderAllergies = `-- name: SetOrderAllergies :execrows
+-- Only ongoing orders, the kitchen has nothing left to cook for the others
+UPDATE orders
+SET allergies = $2, block_allergens = $3
+WHERE id = $1 AND status = 'ongoing'
+`
+
+type SetOrderAllergiesParams struct {
+	ID             pgtype.UUID `db:"id"`
+	Allergies      []string    `db:"allergies"`
+	BlockAllergens bool        `db:"block_allergens"`
+}
+
+func (q *Queries) SetOrderAllergies(ctx context.Context, arg SetOrderAllergiesParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setOrderAllergies, arg.ID, arg.Allergies, arg.BlockAllergens)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const setOrderCustomer = `-- name: SetOrderCustomer :execrows
+-- Only ongoing orders, completed ones already earned their customer loyalty points
+UPDATE orders
+SET customer_id = $2
+WHERE id = $1 AND status = 'ongoing'
+`
+
+type SetOrderCustomerParams struct {
+	ID         pgtype.UUID `db:"id"`
+	CustomerID pgtype.Int4 `db:"customer_id"`
+}
+
+func (q *Queries) SetOrderCustomer(ctx context.Context, arg SetOrderCustomerParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setOrderCustomer, arg.ID, arg.CustomerID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const updateOrderItemStatus = `-- name: UpdateOrderItemStatus :exec
 UPDATE order_items
 SET status = $1
@@ -11,21 +11,205 @@ import (
 )
 
 type Querier interface {
+	AddComboSlotOptions(ctx context.Context, arg AddComboSlotOptionsParams) error
+	AddCustomerAddresses(ctx context.Context, arg AddCustomerAddressesParams) error
+	AddCustomerPhones(ctx context.Context, arg AddCustomerPhonesParams) error
+	AddMenuEntries(ctx context.Context, arg AddMenuEntriesParams) error
+	AddMenuItemCost(ctx context.Context, arg AddMenuItemCostParams) (MenuItemCost, error)
+	AddOrderComboItems(ctx context.Context, arg AddOrderComboItemsParams) error
 	AddOrderItemsBulk(ctx context.Context, arg AddOrderItemsBulkParams) error
+	AddPurchaseOrderItems(ctx context.Context, arg AddPurchaseOrderItemsParams) error
+	AddRecipeIngredients(ctx context.Context, arg AddRecipeIngredientsParams) error
+	AddRolePermissions(ctx context.Context, arg AddRolePermissionsParams) error
+	AddSchedulePrices(ctx context.Context, arg AddSchedulePricesParams) error
+	AdjustIngredientStock(ctx context.Context, arg AdjustIngredientStockParams) (Ingredient, error)
+	AttachDrawerSessionsToZReport(ctx context.Context, arg AttachDrawerSessionsToZReportParams) (int64, error)
+	ClaimLoginAttempt(ctx context.Context, arg ClaimLoginAttemptParams) (bool, error)
+	ClaimPINAttempt(ctx context.Context, arg ClaimPINAttemptParams) (ClaimPINAttemptRow, error)
+	ClearLoginLockout(ctx context.Context, email string) error
+	ClockIn(ctx context.Context, arg ClockInParams) (TimeEntry, error)
+	ClockOut(ctx context.Context, userID pgtype.UUID) (TimeEntry, error)
+	CloseDrawerSession(ctx context.Context, arg CloseDrawerSessionParams) (DrawerSession, error)
+	CompleteOrder(ctx context.Context, id pgtype.UUID) (Order, error)
+	CountCustomers(ctx context.Context, arg CountCustomersParams) (int64, error)
+	CountGiftCardTransactions(ctx context.Context, giftCardID int32) (int64, error)
+	CountGiftCards(ctx context.Context, customerID int32) (int64, error)
+	CountLoyaltyTransactions(ctx context.Context, customerID int32) (int64, error)
+	CountMenuItems(ctx context.Context, arg CountMenuItemsParams) (int64, error)
+	CountOpenDrawerSessions(ctx context.Context) (int64, error)
+	CountOrders(ctx context.Context, arg CountOrdersParams) (int64, error)
+	CountPromotions(ctx context.Context, activeOnly bool) (int64, error)
+	CountSearchOrders(ctx context.Context, arg CountSearchOrdersParams) (int64, error)
+	CountTables(ctx context.Context, status TableStatus) (int64, error)
+	CountUsers(ctx context.Context) (int64, error)
+	CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) (ApiKey, error)
+	CreateCashMovement(ctx context.Context, arg CreateCashMovementParams) (CashMovement, error)
+	CreateCombo(ctx context.Context, arg CreateComboParams) (Combo, error)
+	CreateComboSlot(ctx context.Context, arg CreateComboSlotParams) (ComboSlot, error)
+	CreateCustomer(ctx context.Context, arg CreateCustomerParams) (Customer, error)
+	CreateDeliveryDetails(ctx context.Context, arg CreateDeliveryDetailsParams) error
+	CreateDevice(ctx context.Context, arg CreateDeviceParams) (Device, error)
+	CreateFirstAdmin(ctx context.Context, arg CreateFirstAdminParams) (User, error)
+	CreateGiftCard(ctx context.Context, arg CreateGiftCardParams) (GiftCard, error)
+	CreateGiftCardTransaction(ctx context.Context, arg CreateGiftCardTransactionParams) (GiftCardTransaction, error)
+	CreateIngredient(ctx context.Context, arg CreateIngredientParams) (Ingredient, error)
+	CreateLoyaltyTier(ctx context.Context, arg CreateLoyaltyTierParams) (LoyaltyTier, error)
+	CreateLoyaltyTransaction(ctx context.Context, arg CreateLoyaltyTransactionParams) (LoyaltyTransaction, error)
+	CreateMenu(ctx context.Context, arg CreateMenuParams) (Menu, error)
 	CreateMenuItem(ctx context.Context, arg CreateMenuItemParams) (MenuItem, error)
+	CreateMenuSchedule(ctx context.Context, arg CreateMenuScheduleParams) (MenuSchedule, error)
 	CreateOrder(ctx context.Context, arg CreateOrderParams) (pgtype.UUID, error)
+	CreateOrderCombo(ctx context.Context, arg CreateOrderComboParams) (OrderCombo, error)
+	CreateOrderDiscount(ctx context.Context, arg CreateOrderDiscountParams) (OrderDiscount, error)
+	CreatePromotion(ctx context.Context, arg CreatePromotionParams) (Promotion, error)
+	CreatePurchaseOrder(ctx context.Context, arg CreatePurchaseOrderParams) (PurchaseOrder, error)
+	CreateRole(ctx context.Context, arg CreateRoleParams) (Role, error)
+	CreateScheduledShift(ctx context.Context, arg CreateScheduledShiftParams) (ScheduledShift, error)
+	CreateStockMovement(ctx context.Context, arg CreateStockMovementParams) (StockMovement, error)
+	CreateSupplier(ctx context.Context, arg CreateSupplierParams) (Supplier, error)
+	CreateTakeawayDetails(ctx context.Context, arg CreateTakeawayDetailsParams) error
+	CreateTender(ctx context.Context, arg CreateTenderParams) (Tender, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	CreateZReport(ctx context.Context, arg CreateZReportParams) (ZReport, error)
+	CreditGiftCard(ctx context.Context, arg CreditGiftCardParams) (GiftCard, error)
+	DebitGiftCard(ctx context.Context, arg DebitGiftCardParams) (GiftCard, error)
+	DeleteComboSlots(ctx context.Context, comboID int32) error
+	DeleteCustomerAddresses(ctx context.Context, customerID int32) error
+	DeleteCustomerPhones(ctx context.Context, customerID int32) error
+	DeleteLoyaltyTier(ctx context.Context, id int32) (int64, error)
+	DeleteMenu(ctx context.Context, id int32) (int64, error)
+	DeleteMenuEntries(ctx context.Context, menuID int32) error
+	DeleteMenuItemTranslation(ctx context.Context, arg DeleteMenuItemTranslationParams) (int64, error)
+	DeleteMenuSchedule(ctx context.Context, arg DeleteMenuScheduleParams) (int64, error)
+	DeleteRecipe(ctx context.Context, itemID int32) error
+	DeleteRole(ctx context.Context, id int32) (int64, error)
+	DeleteRolePermissions(ctx context.Context, roleID int32) error
+	DeleteScheduledShift(ctx context.Context, id int64) (int64, error)
+	EarnLoyaltyPoints(ctx context.Context, arg EarnLoyaltyPointsParams) (int64, error)
+	EndBreak(ctx context.Context, timeEntryID int64) (Break, error)
+	GetAPIKeyByHash(ctx context.Context, keyHash string) (ApiKey, error)
+	GetAPIKeys(ctx context.Context) ([]ApiKey, error)
+	GetAutomaticPromotions(ctx context.Context) ([]Promotion, error)
+	GetCashMovements(ctx context.Context, sessionID int64) ([]CashMovement, error)
+	GetComboByID(ctx context.Context, id int32) (Combo, error)
+	GetComboSlotOptions(ctx context.Context, comboIds []int32) ([]GetComboSlotOptionsRow, error)
+	GetComboSlots(ctx context.Context, comboIds []int32) ([]ComboSlot, error)
+	GetCombos(ctx context.Context, activeOnly bool) ([]Combo, error)
+	GetCombosByIDs(ctx context.Context, ids []int32) ([]Combo, error)
+	GetCustomerAddresses(ctx context.Context, customerID int32) ([]CustomerAddress, error)
+	GetCustomerByID(ctx context.Context, id int32) (Customer, error)
+	GetCustomerByPhone(ctx context.Context, phone string) (Customer, error)
+	GetCustomerPhones(ctx context.Context, customerID int32) ([]CustomerPhone, error)
+	GetCustomerStats(ctx context.Context, customerID pgtype.Int4) (GetCustomerStatsRow, error)
+	GetCustomers(ctx context.Context, arg GetCustomersParams) ([]Customer, error)
+	GetDeviceByTokenHash(ctx context.Context, tokenHash string) (Device, error)
+	GetDevices(ctx context.Context) ([]Device, error)
+	GetDrawerExpectedCash(ctx context.Context, id int64) (float64, error)
+	GetDrawerSessionByID(ctx context.Context, id int64) (DrawerSession, error)
+	GetDrawerSessionForShare(ctx context.Context, id int64) (DrawerSession, error)
+	GetDrawerSessionForUpdate(ctx context.Context, id int64) (DrawerSession, error)
+	GetDrawerSessions(ctx context.Context, arg GetDrawerSessionsParams) ([]DrawerSession, error)
+	GetGiftCardByCode(ctx context.Context, code string) (GiftCard, error)
+	GetGiftCardTransactions(ctx context.Context, arg GetGiftCardTransactionsParams) ([]GiftCardTransaction, error)
+	GetGiftCards(ctx context.Context, arg GetGiftCardsParams) ([]GiftCard, error)
+	GetIngredientByID(ctx context.Context, id int32) (Ingredient, error)
+	GetIngredients(ctx context.Context, arg GetIngredientsParams) ([]Ingredient, error)
 	GetItemByID(ctx context.Context, id int32) (MenuItem, error)
-	GetMenuItems(ctx context.Context, arg GetMenuItemsParams) ([]MenuItem, error)
+	GetLowStockIngredients(ctx context.Context) ([]Ingredient, error)
+	GetLoyaltyBalance(ctx context.Context, customerID int32) (GetLoyaltyBalanceRow, error)
+	GetLoyaltyTiers(ctx context.Context) ([]LoyaltyTier, error)
+	GetLoyaltyTransactions(ctx context.Context, arg GetLoyaltyTransactionsParams) ([]LoyaltyTransaction, error)
+	GetMenuByID(ctx context.Context, id int32) (Menu, error)
+	GetMenuEngineeringStats(ctx context.Context, arg GetMenuEngineeringStatsParams) ([]GetMenuEngineeringStatsRow, error)
+	GetMenuEntries(ctx context.Context, menuID int32) ([]int32, error)
+	GetMenuItemCosts(ctx context.Context, itemID int32) ([]MenuItemCost, error)
+	GetMenuItemTranslation(ctx context.Context, arg GetMenuItemTranslationParams) (MenuItemTranslation, error)
+	GetMenuItemTranslations(ctx context.Context, itemID int32) ([]MenuItemTranslation, error)
+	GetMenuItems(ctx context.Context, arg GetMenuItemsParams) ([]GetMenuItemsRow, error)
+	GetMenuSchedules(ctx context.Context, menuID int32) ([]MenuSchedule, error)
+	GetMenus(ctx context.Context) ([]Menu, error)
+	GetOpenBreak(ctx context.Context, timeEntryID int64) (Break, error)
+	GetOpenTimeEntry(ctx context.Context, userID pgtype.UUID) (TimeEntry, error)
+	GetOrderBill(ctx context.Context, orderID pgtype.UUID) (GetOrderBillRow, error)
 	GetOrderByID(ctx context.Context, id pgtype.UUID) (Order, error)
+	GetOrderDiscounts(ctx context.Context, orderID pgtype.UUID) ([]OrderDiscount, error)
+	GetOrderForUpdate(ctx context.Context, id pgtype.UUID) (Order, error)
 	GetOrderItemByID(ctx context.Context, arg GetOrderItemByIDParams) (OrderItem, error)
+	GetOrderItems(ctx context.Context, orderID pgtype.UUID) ([]OrderItem, error)
+	GetOrderTendered(ctx context.Context, orderID pgtype.UUID) (float64, error)
+	GetOrderableItems(ctx context.Context, ids []int32) ([]GetOrderableItemsRow, error)
 	GetOrders(ctx context.Context, arg GetOrdersParams) ([]Order, error)
+	GetOrdersBefore(ctx context.Context, arg GetOrdersBeforeParams) ([]Order, error)
+	GetPINUsers(ctx context.Context) ([]GetPINUsersRow, error)
+	GetPromotionByCode(ctx context.Context, code pgtype.Text) (Promotion, error)
+	GetPromotionByID(ctx context.Context, id int32) (Promotion, error)
+	GetPromotionForUpdate(ctx context.Context, id int32) (Promotion, error)
+	GetPromotionUsage(ctx context.Context, arg GetPromotionUsageParams) (GetPromotionUsageRow, error)
+	GetPromotions(ctx context.Context, arg GetPromotionsParams) ([]Promotion, error)
+	GetPurchaseOrderByID(ctx context.Context, id int64) (PurchaseOrder, error)
+	GetPurchaseOrderItems(ctx context.Context, purchaseOrderID int64) ([]GetPurchaseOrderItemsRow, error)
+	GetPurchaseOrders(ctx context.Context, arg GetPurchaseOrdersParams) ([]PurchaseOrder, error)
+	GetRecipe(ctx context.Context, itemID int32) ([]GetRecipeRow, error)
+	GetReorderSuggestions(ctx context.Context, usageSince pgtype.Timestamp) ([]GetReorderSuggestionsRow, error)
+	GetRoleByID(ctx context.Context, id int32) (Role, error)
+	GetRoleByName(ctx context.Context, name string) (Role, error)
+	GetRolePermissions(ctx context.Context, roleID int32) ([]string, error)
+	GetRoles(ctx context.Context) ([]GetRolesRow, error)
+	GetSalesReport(ctx context.Context, arg GetSalesReportParams) ([]GetSalesReportRow, error)
+	GetSalesTotals(ctx context.Context, arg GetSalesTotalsParams) (GetSalesTotalsRow, error)
+	GetSchedulePrices(ctx context.Context, menuID int32) ([]SchedulePrice, error)
+	GetScheduledShifts(ctx context.Context, arg GetScheduledShiftsParams) ([]ScheduledShift, error)
+	GetStockMovements(ctx context.Context, arg GetStockMovementsParams) ([]StockMovement, error)
+	GetSupplierByID(ctx context.Context, id int32) (Supplier, error)
+	GetSuppliers(ctx context.Context) ([]Supplier, error)
 	GetTableByID(ctx context.Context, id string) (Table, error)
-	GetTables(ctx context.Context, status TableStatus) ([]Table, error)
+	GetTables(ctx context.Context, arg GetTablesParams) ([]Table, error)
+	GetTenders(ctx context.Context, sessionID int64) ([]Tender, error)
+	GetTimesheet(ctx context.Context, arg GetTimesheetParams) ([]GetTimesheetRow, error)
+	GetUserAuthorization(ctx context.Context, id pgtype.UUID) (GetUserAuthorizationRow, error)
 	GetUserByEmail(ctx context.Context, email string) (User, error)
+	GetUserByID(ctx context.Context, id pgtype.UUID) (User, error)
+	GetUserPIN(ctx context.Context, userID pgtype.UUID) (GetUserPINRow, error)
 	GetUsers(ctx context.Context, arg GetUsersParams) ([]User, error)
+	GetZReportByID(ctx context.Context, id int64) (ZReport, error)
+	GetZReportPeriod(ctx context.Context) (GetZReportPeriodRow, error)
+	GetZReportTotals(ctx context.Context, arg GetZReportTotalsParams) (GetZReportTotalsRow, error)
+	GetZReports(ctx context.Context, arg GetZReportsParams) ([]ZReport, error)
+	IsPromotionOn(ctx context.Context, id int32) (bool, error)
+	LockBootstrap(ctx context.Context) error
+	LockCustomer(ctx context.Context, id int32) error
+	LockDrawerSessions(ctx context.Context) error
+	MarkOrderItemDepleted(ctx context.Context, id int64) (MarkOrderItemDepletedRow, error)
+	OpenDrawerSession(ctx context.Context, arg OpenDrawerSessionParams) (DrawerSession, error)
+	ReceivePurchaseOrderItem(ctx context.Context, arg ReceivePurchaseOrderItemParams) (PurchaseOrderItem, error)
+	RefreshEightySixed(ctx context.Context, arg RefreshEightySixedParams) error
+	ResetPINFailures(ctx context.Context, userID pgtype.UUID) error
+	RevokeAPIKey(ctx context.Context, id pgtype.UUID) (int64, error)
+	RevokeDevice(ctx context.Context, id pgtype.UUID) (int64, error)
+	SearchOrders(ctx context.Context, arg SearchOrdersParams) ([]SearchOrdersRow, error)
+	SetOrderAllergies(ctx context.Context, arg SetOrderAllergiesParams) (int64, error)
+	SetOrderCustomer(ctx context.Context, arg SetOrderCustomerParams) (int64, error)
+	SetUserActive(ctx context.Context, arg SetUserActiveParams) (User, error)
+	StartBreak(ctx context.Context, arg StartBreakParams) (Break, error)
+	TouchAPIKey(ctx context.Context, id pgtype.UUID) error
+	TouchDevice(ctx context.Context, id pgtype.UUID) error
+	UpdateCombo(ctx context.Context, arg UpdateComboParams) (Combo, error)
+	UpdateCustomer(ctx context.Context, arg UpdateCustomerParams) (Customer, error)
+	UpdateIngredient(ctx context.Context, arg UpdateIngredientParams) (Ingredient, error)
+	UpdateLoyaltyTier(ctx context.Context, arg UpdateLoyaltyTierParams) (LoyaltyTier, error)
+	UpdateMenu(ctx context.Context, arg UpdateMenuParams) (Menu, error)
+	UpdateMenuItemImage(ctx context.Context, arg UpdateMenuItemImageParams) (int64, error)
+	UpdateMenuItemTags(ctx context.Context, arg UpdateMenuItemTagsParams) (MenuItem, error)
 	UpdateOrderItemStatus(ctx context.Context, arg UpdateOrderItemStatusParams) error
+	UpdatePromotion(ctx context.Context, arg UpdatePromotionParams) (Promotion, error)
+	UpdatePurchaseOrderStatus(ctx context.Context, arg UpdatePurchaseOrderStatusParams) (PurchaseOrder, error)
+	UpdateRole(ctx context.Context, arg UpdateRoleParams) (Role, error)
+	UpdateSupplier(ctx context.Context, arg UpdateSupplierParams) (Supplier, error)
 	UpdateTableStatus(ctx context.Context, arg UpdateTableStatusParams) error
+	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
+	UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) (int64, error)
+	UpsertMenuItemTranslation(ctx context.Context, arg UpsertMenuItemTranslationParams) (MenuItemTranslation, error)
+	UpsertUserPIN(ctx context.Context, arg UpsertUserPINParams) error
 }
 
 var _ Querier = (*Queries)(nil)
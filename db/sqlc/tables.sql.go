@@ -9,6 +9,18 @@ import (
 	"context"
 )
 
+const countTables = `-- name: CountTables :one
+SELECT count(*) FROM tables
+WHERE status = $1
+`
+
+func (q *Queries) CountTables(ctx context.Context, status TableStatus) (int64, error) {
+	row := q.db.QueryRow(ctx, countTables, status)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const getTableByID = `-- name: GetTableByID :one
 SELECT id, capacity, status, notes FROM tables
 WHERE id = $1
@@ -29,10 +41,19 @@ func (q *Queries) GetTableByID(ctx context.Context, id string) (Table, error) {
 const getTables = `-- name: GetTables :many
 SELECT id, capacity, status, notes FROM tables
 WHERE status = $1
+ORDER BY id
+LIMIT $2
+OFFSET $3
 `
 
-func (q *Queries) GetTables(ctx context.Context, status TableStatus) ([]Table, error) {
-	rows, err := q.db.Query(ctx, getTables, status)
+type GetTablesParams struct {
+	Status TableStatus `db:"status"`
+	Limit  int32       `db:"limit"`
+	Offset int32       `db:"offset"`
+}
+
+func (q *Queries) GetTables(ctx context.Context, arg GetTablesParams) ([]Table, error) {
+	rows, err := q.db.Query(ctx, getTables, arg.Status, arg.Limit, arg.Offset)
 	if err != nil {
 		return nil, err
 	}
@@ -7,17 +7,71 @@ package sqlc
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const countUsers = `-- name: CountUsers :one
+SELECT count(*) FROM users
+`
+
+func (q *Queries) CountUsers(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, countUsers)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createFirstAdmin = `-- name: CreateFirstAdmin :one
+-- Inserts nothing while any user has a superuser role
+INSERT INTO users (
+  email,
+  name,
+  type,
+  password,
+  role_id
+)
+SELECT $1::text, $2::text, 'admin', $3::text, (SELECT id FROM roles WHERE roles.name = 'admin')
+WHERE NOT EXISTS (
+  SELECT 1 FROM users u
+  JOIN roles r ON r.id = u.role_id
+  WHERE r.superuser
+)
+RETURNING id, email, name, type, password, created_at, active, role_id
+`
+
+type CreateFirstAdminParams struct {
+	Email    string `db:"email"`
+	Name     string `db:"name"`
+	Password string `db:"password"`
+}
+
+func (q *Queries) CreateFirstAdmin(ctx context.Context, arg CreateFirstAdminParams) (User, error) {
+	row := q.db.QueryRow(ctx, createFirstAdmin, arg.Email, arg.Name, arg.Password)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Name,
+		&i.Type,
+		&i.Password,
+		&i.CreatedAt,
+		&i.Active,
+		&i.RoleID,
+	)
+	return i, err
+}
+
 const createUser = `-- name: CreateUser :one
 INSERT INTO users (
   email,
   name,
   type,
-  password
+  password,
+  role_id
 ) VALUES (
-  $1, $2, $3, $4
-) RETURNING id, email, name, type, password, created_at
+  $1, $2, $3, $4, $5
+) RETURNING id, email, name, type, password, created_at, active, role_id
 `
 
 type CreateUserParams struct {
@@ -25,6 +79,7 @@ type CreateUserParams struct {
 	Name     string   `db:"name"`
 	Type     UserType `db:"type"`
 	Password string   `db:"password"`
+	RoleID   int32    `db:"role_id"`
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
@@ -33,6 +88,7 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		arg.Name,
 		arg.Type,
 		arg.Password,
+		arg.RoleID,
 	)
 	var i User
 	err := row.Scan(
@@ -42,12 +98,52 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.Type,
 		&i.Password,
 		&i.CreatedAt,
+		&i.Active,
+		&i.RoleID,
+	)
+	return i, err
+}
+
+const getUserAuthorization = `-- name: GetUserAuthorization :one
+SELECT
+  u.id,
+  u.type,
+  u.active,
+  u.role_id,
+  r.superuser,
+  COALESCE(array_agg(rp.permission) FILTER (WHERE rp.permission IS NOT NULL), '{}')::text[] AS permissions
+FROM users u
+JOIN roles r ON r.id = u.role_id
+LEFT JOIN role_permissions rp ON rp.role_id = r.id
+WHERE u.id = $1
+GROUP BY u.id, r.id
+`
+
+type GetUserAuthorizationRow struct {
+	ID          pgtype.UUID `db:"id"`
+	Type        UserType    `db:"type"`
+	Active      bool        `db:"active"`
+	RoleID      int32       `db:"role_id"`
+	Superuser   bool        `db:"superuser"`
+	Permissions []string    `db:"permissions"`
+}
+
+func (q *Queries) GetUserAuthorization(ctx context.Context, id pgtype.UUID) (GetUserAuthorizationRow, error) {
+	row := q.db.QueryRow(ctx, getUserAuthorization, id)
+	var i GetUserAuthorizationRow
+	err := row.Scan(
+		&i.ID,
+		&i.Type,
+		&i.Active,
+		&i.RoleID,
+		&i.Superuser,
+		&i.Permissions,
 	)
 	return i, err
 }
 
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, email, name, type, password, created_at FROM users 
+SELECT id, email, name, type, password, created_at, active, role_id FROM users 
 WHERE email = $1 LIMIT 1
 `
 
@@ -61,12 +157,35 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.Type,
 		&i.Password,
 		&i.CreatedAt,
+		&i.Active,
+		&i.RoleID,
+	)
+	return i, err
+}
+
+const getUserByID = `-- name: GetUserByID :one
+SELECT id, email, name, type, password, created_at, active, role_id FROM users
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetUserByID(ctx context.Context, id pgtype.UUID) (User, error) {
+	row := q.db.QueryRow(ctx, getUserByID, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Name,
+		&i.Type,
+		&i.Password,
+		&i.CreatedAt,
+		&i.Active,
+		&i.RoleID,
 	)
 	return i, err
 }
 
 const getUsers = `-- name: GetUsers :many
-SELECT id, email, name, type, password, created_at FROM users
+SELECT id, email, name, type, password, created_at, active, role_id FROM users
 ORDER BY name
 LIMIT $1
 OFFSET $2
@@ -93,6 +212,8 @@ func (q *Queries) GetUsers(ctx context.Context, arg GetUsersParams) ([]User, err
 			&i.Type,
 			&i.Password,
 			&i.CreatedAt,
+			&i.Active,
+			&i.RoleID,
 		); err != nil {
 			return nil, err
 		}
@@ -103,3 +224,95 @@ func (q *Queries) GetUsers(ctx context.Context, arg GetUsersParams) ([]User, err
 	}
 	return items, nil
 }
+
+const lockBootstrap = `-- name: LockBootstrap :exec
+-- Held until the transaction ends so concurrent bootstraps run one after the other
+SELECT pg_advisory_xact_lock(hashtext('bootstrap_admin'))
+`
+
+func (q *Queries) LockBootstrap(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, lockBootstrap)
+	return err
+}
+
+const setUserActive = `-- name: SetUserActive :one
+UPDATE users
+SET active = $1
+WHERE id = $2
+RETURNING id, email, name, type, password, created_at, active, role_id
+`
+
+type SetUserActiveParams struct {
+	Active bool        `db:"active"`
+	ID     pgtype.UUID `db:"id"`
+}
+
+func (q *Queries) SetUserActive(ctx context.Context, arg SetUserActiveParams) (User, error) {
+	row := q.db.QueryRow(ctx, setUserActive, arg.Active, arg.ID)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Name,
+		&i.Type,
+		&i.Password,
+		&i.CreatedAt,
+		&i.Active,
+		&i.RoleID,
+	)
+	return i, err
+}
+
+const updateUser = `-- name: UpdateUser :one
+UPDATE users
+SET name = $1, type = $2, role_id = $3
+WHERE id = $4
+RETURNING id, email, name, type, password, created_at, active, role_id
+`
+
+type UpdateUserParams struct {
+	Name   string      `db:"name"`
+	Type   UserType    `db:"type"`
+	RoleID int32       `db:"role_id"`
+	ID     pgtype.UUID `db:"id"`
+}
+
+func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUser,
+		arg.Name,
+		arg.Type,
+		arg.RoleID,
+		arg.ID,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Name,
+		&i.Type,
+		&i.Password,
+		&i.CreatedAt,
+		&i.Active,
+		&i.RoleID,
+	)
+	return i, err
+}
+
+const updateUserPassword = `-- name: UpdateUserPassword :execrows
+UPDATE users
+SET password = $1
+WHERE id = $2
+`
+
+type UpdateUserPasswordParams struct {
+	Password string      `db:"password"`
+	ID       pgtype.UUID `db:"id"`
+}
+
+func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateUserPassword, arg.Password, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
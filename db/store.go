@@ -2,15 +2,47 @@ package db
 
 import (
 	"context"
+	"math"
 
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/pdridh/k-line/db/sqlc"
+	"github.com/pkg/errors"
 )
 
 type Store interface {
 	sqlc.Querier
-	CreateDiningOrderTx(ctx context.Context, tableID pgtype.Text, employeeID pgtype.UUID) (*pgtype.UUID, error)
+	CreateDiningOrderTx(ctx context.Context, tableID pgtype.Text, employeeID pgtype.UUID, covers pgtype.Int2, customerID pgtype.Int4) (*pgtype.UUID, error)
+	CreateTakeawayOrderTx(ctx context.Context, employeeID pgtype.UUID, customerID pgtype.Int4, details sqlc.CreateTakeawayDetailsParams) (*pgtype.UUID, error)
+	CreateDeliveryOrderTx(ctx context.Context, employeeID pgtype.UUID, customerID pgtype.Int4, details sqlc.CreateDeliveryDetailsParams) (*pgtype.UUID, error)
+	CreateFirstAdminTx(ctx context.Context, arg sqlc.CreateFirstAdminParams) (sqlc.User, error)
+	CreateRoleTx(ctx context.Context, arg sqlc.CreateRoleParams, permissions []string) (sqlc.Role, error)
+	UpdateRoleTx(ctx context.Context, arg sqlc.UpdateRoleParams, permissions []string) (sqlc.Role, error)
+	ClockOutTx(ctx context.Context, userID pgtype.UUID) (sqlc.TimeEntry, error)
+	AddCashMovementTx(ctx context.Context, arg sqlc.CreateCashMovementParams) (sqlc.CashMovement, error)
+	AddTenderTx(ctx context.Context, arg sqlc.CreateTenderParams) (sqlc.Tender, error)
+	CloseDrawerSessionTx(ctx context.Context, id int64, build func(expected float64) sqlc.CloseDrawerSessionParams) (sqlc.DrawerSession, error)
+	CloseZReportTx(ctx context.Context, build func(sqlc.GetZReportPeriodRow, sqlc.GetZReportTotalsRow) sqlc.CreateZReportParams) (sqlc.ZReport, error)
+	UpdateOrderItemStatusTx(ctx context.Context, arg sqlc.UpdateOrderItemStatusParams, deplete bool) error
+	AdjustStockTx(ctx context.Context, arg sqlc.CreateStockMovementParams) (sqlc.Ingredient, error)
+	SetRecipeTx(ctx context.Context, itemID int32, ingredientIDs []int32, quantities []float64) error
+	CreatePurchaseOrderTx(ctx context.Context, arg sqlc.CreatePurchaseOrderParams, ingredientIDs []int32, quantities []float64, unitCosts []float64) (sqlc.PurchaseOrder, error)
+	ReceivePurchaseOrderTx(ctx context.Context, purchaseOrderID int64, itemIDs []int64, quantities []float64, receivedBy pgtype.UUID) (sqlc.PurchaseOrder, error)
+	CreateMenuTx(ctx context.Context, arg sqlc.CreateMenuParams, itemIDs []int32) (sqlc.Menu, error)
+	UpdateMenuTx(ctx context.Context, arg sqlc.UpdateMenuParams, itemIDs []int32) (sqlc.Menu, error)
+	CreateMenuScheduleTx(ctx context.Context, arg sqlc.CreateMenuScheduleParams, itemIDs []int32, prices []float64) (sqlc.MenuSchedule, error)
+	CreateComboTx(ctx context.Context, arg sqlc.CreateComboParams, slots []sqlc.CreateComboSlotParams, options []sqlc.AddComboSlotOptionsParams) (sqlc.Combo, error)
+	UpdateComboTx(ctx context.Context, arg sqlc.UpdateComboParams, slots []sqlc.CreateComboSlotParams, options []sqlc.AddComboSlotOptionsParams) (sqlc.Combo, error)
+	AddOrderItemsTx(ctx context.Context, items sqlc.AddOrderItemsBulkParams, combos []sqlc.CreateOrderComboParams, comboItems []sqlc.AddOrderComboItemsParams) error
+	CreateCustomerTx(ctx context.Context, arg sqlc.CreateCustomerParams, phones sqlc.AddCustomerPhonesParams, addresses sqlc.AddCustomerAddressesParams) (sqlc.Customer, error)
+	UpdateCustomerTx(ctx context.Context, arg sqlc.UpdateCustomerParams, phones sqlc.AddCustomerPhonesParams, addresses sqlc.AddCustomerAddressesParams) (sqlc.Customer, error)
+	CompleteOrderTx(ctx context.Context, orderID pgtype.UUID, earnRate float64) (sqlc.Order, error)
+	RedeemLoyaltyPointsTx(ctx context.Context, arg sqlc.CreateLoyaltyTransactionParams, discount sqlc.CreateOrderDiscountParams) (sqlc.OrderDiscount, error)
+	AdjustLoyaltyPointsTx(ctx context.Context, arg sqlc.CreateLoyaltyTransactionParams) (sqlc.LoyaltyTransaction, error)
+	IssueGiftCardTx(ctx context.Context, arg sqlc.CreateGiftCardParams, payIn sqlc.CreateCashMovementParams) (sqlc.GiftCard, error)
+	ReloadGiftCardTx(ctx context.Context, arg sqlc.CreditGiftCardParams, payIn sqlc.CreateCashMovementParams) (sqlc.GiftCard, error)
+	RedeemGiftCardTx(ctx context.Context, giftCardID int32, tender sqlc.CreateTenderParams, taxRate float64) (sqlc.GiftCardTransaction, error)
+	ApplyPromotionTx(ctx context.Context, promotionID int32, orderID pgtype.UUID, build func(p sqlc.Promotion, on bool, o sqlc.Order, items []sqlc.OrderItem, discounts []sqlc.OrderDiscount) (sqlc.CreateOrderDiscountParams, error)) (sqlc.OrderDiscount, error)
 }
 
 type psqlStore struct {
@@ -25,7 +57,7 @@ func NewPSQLStore(pool *pgxpool.Pool) Store {
 	}
 }
 
-func (s *psqlStore) CreateDiningOrderTx(ctx context.Context, tableID pgtype.Text, employeeID pgtype.UUID) (*pgtype.UUID, error) {
+func (s *psqlStore) CreateDiningOrderTx(ctx context.Context, tableID pgtype.Text, employeeID pgtype.UUID, covers pgtype.Int2, customerID pgtype.Int4) (*pgtype.UUID, error) {
 
 	var orderID pgtype.UUID
 	err := s.execTx(ctx, func(q *sqlc.Queries) error {
@@ -35,6 +67,8 @@ func (s *psqlStore) CreateDiningOrderTx(ctx context.Context, tableID pgtype.Text
 			Type:       sqlc.OrderTypeDining,
 			TableID:    tableID,
 			EmployeeID: employeeID,
+			Covers:     covers,
+			CustomerID: customerID,
 		})
 
 		if err != nil {
@@ -55,3 +89,928 @@ func (s *psqlStore) CreateDiningOrderTx(ctx context.Context, tableID pgtype.Text
 
 	return &orderID, err
 }
+
+// Creates a takeaway order along with who it is picked up by
+func (s *psqlStore) CreateTakeawayOrderTx(ctx context.Context, employeeID pgtype.UUID, customerID pgtype.Int4, details sqlc.CreateTakeawayDetailsParams) (*pgtype.UUID, error) {
+	var orderID pgtype.UUID
+	err := s.execTx(ctx, func(q *sqlc.Queries) error {
+		var err error
+
+		orderID, err = q.CreateOrder(ctx, sqlc.CreateOrderParams{
+			Type:       sqlc.OrderTypeTakeaway,
+			EmployeeID: employeeID,
+			CustomerID: customerID,
+		})
+		if err != nil {
+			return err
+		}
+
+		details.OrderID = orderID
+		return q.CreateTakeawayDetails(ctx, details)
+	})
+
+	return &orderID, err
+}
+
+// Creates a delivery order along with where it goes and who drives it there
+func (s *psqlStore) CreateDeliveryOrderTx(ctx context.Context, employeeID pgtype.UUID, customerID pgtype.Int4, details sqlc.CreateDeliveryDetailsParams) (*pgtype.UUID, error) {
+	var orderID pgtype.UUID
+	err := s.execTx(ctx, func(q *sqlc.Queries) error {
+		var err error
+
+		orderID, err = q.CreateOrder(ctx, sqlc.CreateOrderParams{
+			Type:       sqlc.OrderTypeDelivery,
+			EmployeeID: employeeID,
+			CustomerID: customerID,
+		})
+		if err != nil {
+			return err
+		}
+
+		details.OrderID = orderID
+		return q.CreateDeliveryDetails(ctx, details)
+	})
+
+	return &orderID, err
+}
+
+// Creates the first admin with the bootstrap lock held, two bootstraps at once cant both see that
+// there is no superuser yet
+func (s *psqlStore) CreateFirstAdminTx(ctx context.Context, arg sqlc.CreateFirstAdminParams) (sqlc.User, error) {
+	var u sqlc.User
+	err := s.execTx(ctx, func(q *sqlc.Queries) error {
+		if err := q.LockBootstrap(ctx); err != nil {
+			return err
+		}
+
+		var err error
+		u, err = q.CreateFirstAdmin(ctx, arg)
+		return err
+	})
+
+	return u, err
+}
+
+func (s *psqlStore) CreateRoleTx(ctx context.Context, arg sqlc.CreateRoleParams, permissions []string) (sqlc.Role, error) {
+	var role sqlc.Role
+	err := s.execTx(ctx, func(q *sqlc.Queries) error {
+		var err error
+
+		role, err = q.CreateRole(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		return q.AddRolePermissions(ctx, sqlc.AddRolePermissionsParams{RoleID: role.ID, Permissions: permissions})
+	})
+
+	return role, err
+}
+
+// Updates the role and replaces all of its permissions with the given ones
+func (s *psqlStore) UpdateRoleTx(ctx context.Context, arg sqlc.UpdateRoleParams, permissions []string) (sqlc.Role, error) {
+	var role sqlc.Role
+	err := s.execTx(ctx, func(q *sqlc.Queries) error {
+		var err error
+
+		role, err = q.UpdateRole(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		if err := q.DeleteRolePermissions(ctx, role.ID); err != nil {
+			return err
+		}
+
+		return q.AddRolePermissions(ctx, sqlc.AddRolePermissionsParams{RoleID: role.ID, Permissions: permissions})
+	})
+
+	return role, err
+}
+
+// Clocks the user out, ending the break they are on (if any) at the same time
+func (s *psqlStore) ClockOutTx(ctx context.Context, userID pgtype.UUID) (sqlc.TimeEntry, error) {
+	var entry sqlc.TimeEntry
+	err := s.execTx(ctx, func(q *sqlc.Queries) error {
+		var err error
+
+		entry, err = q.ClockOut(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		if _, err := q.EndBreak(ctx, entry.ID); err != nil && !errors.Is(err, ErrRecordNotFound) {
+			return err
+		}
+
+		return nil
+	})
+
+	return entry, err
+}
+
+// Adds the pay in or out with its drawer session locked, failing with ErrDrawerClosed if the session was closed
+func (s *psqlStore) AddCashMovementTx(ctx context.Context, arg sqlc.CreateCashMovementParams) (sqlc.CashMovement, error) {
+	var m sqlc.CashMovement
+	err := s.execTx(ctx, func(q *sqlc.Queries) error {
+		if err := lockOpenSession(ctx, q, arg.SessionID); err != nil {
+			return err
+		}
+
+		var err error
+		m, err = q.CreateCashMovement(ctx, arg)
+		return err
+	})
+
+	return m, err
+}
+
+// Adds the tender with its drawer session locked, failing with ErrDrawerClosed if the session was closed
+func (s *psqlStore) AddTenderTx(ctx context.Context, arg sqlc.CreateTenderParams) (sqlc.Tender, error) {
+	var t sqlc.Tender
+	err := s.execTx(ctx, func(q *sqlc.Queries) error {
+		if err := lockOpenSession(ctx, q, arg.SessionID); err != nil {
+			return err
+		}
+
+		var err error
+		t, err = q.CreateTender(ctx, arg)
+		return err
+	})
+
+	return t, err
+}
+
+// Closes the drawer session with what build makes of the cash expected in the drawer. The session is
+// locked first so nothing can be added to it between working out the expected cash and closing it.
+// Fails with ErrDrawerClosed if the session was already closed.
+func (s *psqlStore) CloseDrawerSessionTx(ctx context.Context, id int64, build func(expected float64) sqlc.CloseDrawerSessionParams) (sqlc.DrawerSession, error) {
+	var d sqlc.DrawerSession
+	err := s.execTx(ctx, func(q *sqlc.Queries) error {
+		current, err := q.GetDrawerSessionForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if current.ClosedAt.Valid {
+			return ErrDrawerClosed
+		}
+
+		expected, err := q.GetDrawerExpectedCash(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		arg := build(expected)
+		arg.ID = id
+
+		d, err = q.CloseDrawerSession(ctx, arg)
+		return err
+	})
+
+	return d, err
+}
+
+// Closes the z report for everything since the previous one. The drawer sessions stay locked while
+// the totals are read so no drawer can open or close before the report is saved, build turns the
+// period and totals into the report. Returns ErrDrawersOpen when a drawer has not been closed.
+func (s *psqlStore) CloseZReportTx(ctx context.Context, build func(sqlc.GetZReportPeriodRow, sqlc.GetZReportTotalsRow) sqlc.CreateZReportParams) (sqlc.ZReport, error) {
+	var report sqlc.ZReport
+	err := s.execTx(ctx, func(q *sqlc.Queries) error {
+		if err := q.LockDrawerSessions(ctx); err != nil {
+			return err
+		}
+
+		open, err := q.CountOpenDrawerSessions(ctx)
+		if err != nil {
+			return err
+		}
+
+		if open > 0 {
+			return ErrDrawersOpen
+		}
+
+		p, err := q.GetZReportPeriod(ctx)
+		if err != nil {
+			return err
+		}
+
+		t, err := q.GetZReportTotals(ctx, sqlc.GetZReportTotalsParams{PeriodStart: p.PeriodStart, PeriodEnd: p.PeriodEnd})
+		if err != nil {
+			return err
+		}
+
+		arg := build(p, t)
+
+		report, err = q.CreateZReport(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		// Ties the counted drawer sessions to the report so they cant be counted again
+		_, err = q.AttachDrawerSessionsToZReport(ctx, sqlc.AttachDrawerSessionsToZReportParams{
+			ZReportID: pgtype.Int8{Int64: report.ID, Valid: true},
+			ClosedAt:  arg.PeriodEnd,
+		})
+
+		return err
+	})
+
+	return report, err
+}
+
+// Updates the status of the order item, when deplete is set the ingredients in its recipe are also
+// taken out of stock (only the first time) and items that ran out are 86'd.
+func (s *psqlStore) UpdateOrderItemStatusTx(ctx context.Context, arg sqlc.UpdateOrderItemStatusParams, deplete bool) error {
+	return s.execTx(ctx, func(q *sqlc.Queries) error {
+		if err := q.UpdateOrderItemStatus(ctx, arg); err != nil {
+			return err
+		}
+
+		if !deplete {
+			return nil
+		}
+
+		item, err := q.MarkOrderItemDepleted(ctx, arg.ID)
+		if err != nil {
+			// Already depleted when it went through an earlier status
+			if errors.Is(err, ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		recipe, err := q.GetRecipe(ctx, item.ItemID)
+		if err != nil {
+			return err
+		}
+
+		ingredientIDs := []int32{}
+		for _, r := range recipe {
+			used := r.Quantity * float64(item.Quantity)
+
+			if _, err := q.AdjustIngredientStock(ctx, sqlc.AdjustIngredientStockParams{Delta: -used, ID: r.IngredientID}); err != nil {
+				return err
+			}
+
+			_, err := q.CreateStockMovement(ctx, sqlc.CreateStockMovementParams{
+				IngredientID: r.IngredientID,
+				Type:         sqlc.StockMovementTypeSale,
+				Quantity:     -used,
+				OrderItemID:  pgtype.Int8{Int64: arg.ID, Valid: true},
+			})
+			if err != nil {
+				return err
+			}
+
+			ingredientIDs = append(ingredientIDs, r.IngredientID)
+		}
+
+		if len(ingredientIDs) == 0 {
+			return nil
+		}
+
+		return q.RefreshEightySixed(ctx, sqlc.RefreshEightySixedParams{ItemIds: []int32{}, IngredientIds: ingredientIDs})
+	})
+}
+
+// Changes the stock of the ingredient by arg.Quantity, records the movement and 86s (or brings back)
+// the items that use it.
+func (s *psqlStore) AdjustStockTx(ctx context.Context, arg sqlc.CreateStockMovementParams) (sqlc.Ingredient, error) {
+	var ingredient sqlc.Ingredient
+	err := s.execTx(ctx, func(q *sqlc.Queries) error {
+		var err error
+
+		ingredient, err = q.AdjustIngredientStock(ctx, sqlc.AdjustIngredientStockParams{Delta: arg.Quantity, ID: arg.IngredientID})
+		if err != nil {
+			return err
+		}
+
+		if _, err := q.CreateStockMovement(ctx, arg); err != nil {
+			return err
+		}
+
+		return q.RefreshEightySixed(ctx, sqlc.RefreshEightySixedParams{ItemIds: []int32{}, IngredientIds: []int32{arg.IngredientID}})
+	})
+
+	return ingredient, err
+}
+
+// Replaces the recipe of the menu item and updates whether it is 86'd with the new ingredients
+func (s *psqlStore) SetRecipeTx(ctx context.Context, itemID int32, ingredientIDs []int32, quantities []float64) error {
+	return s.execTx(ctx, func(q *sqlc.Queries) error {
+		if err := q.DeleteRecipe(ctx, itemID); err != nil {
+			return err
+		}
+
+		if len(ingredientIDs) > 0 {
+			arg := sqlc.AddRecipeIngredientsParams{ItemID: itemID, IngredientIds: ingredientIDs, Quantities: quantities}
+			if err := q.AddRecipeIngredients(ctx, arg); err != nil {
+				return err
+			}
+		}
+
+		return q.RefreshEightySixed(ctx, sqlc.RefreshEightySixedParams{ItemIds: []int32{itemID}, IngredientIds: []int32{}})
+	})
+}
+
+func (s *psqlStore) CreatePurchaseOrderTx(ctx context.Context, arg sqlc.CreatePurchaseOrderParams, ingredientIDs []int32, quantities []float64, unitCosts []float64) (sqlc.PurchaseOrder, error) {
+	var po sqlc.PurchaseOrder
+	err := s.execTx(ctx, func(q *sqlc.Queries) error {
+		var err error
+
+		po, err = q.CreatePurchaseOrder(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		return q.AddPurchaseOrderItems(ctx, sqlc.AddPurchaseOrderItemsParams{
+			PurchaseOrderID: po.ID,
+			IngredientIds:   ingredientIDs,
+			Quantities:      quantities,
+			UnitCosts:       unitCosts,
+		})
+	})
+
+	return po, err
+}
+
+// Receives the given quantities of the purchase order items into stock, recording a movement for each
+// and bringing back items that were 86'd. The order becomes received once every line is fully in.
+func (s *psqlStore) ReceivePurchaseOrderTx(ctx context.Context, purchaseOrderID int64, itemIDs []int64, quantities []float64, receivedBy pgtype.UUID) (sqlc.PurchaseOrder, error) {
+	var po sqlc.PurchaseOrder
+	err := s.execTx(ctx, func(q *sqlc.Queries) error {
+		ingredientIDs := []int32{}
+		for i, id := range itemIDs {
+			item, err := q.ReceivePurchaseOrderItem(ctx, sqlc.ReceivePurchaseOrderItemParams{
+				Quantity:        quantities[i],
+				ID:              id,
+				PurchaseOrderID: purchaseOrderID,
+			})
+			if err != nil {
+				return err
+			}
+
+			if _, err := q.AdjustIngredientStock(ctx, sqlc.AdjustIngredientStockParams{Delta: quantities[i], ID: item.IngredientID}); err != nil {
+				return err
+			}
+
+			_, err = q.CreateStockMovement(ctx, sqlc.CreateStockMovementParams{
+				IngredientID:    item.IngredientID,
+				Type:            sqlc.StockMovementTypeReceived,
+				Quantity:        quantities[i],
+				PurchaseOrderID: pgtype.Int8{Int64: purchaseOrderID, Valid: true},
+				CreatedBy:       receivedBy,
+			})
+			if err != nil {
+				return err
+			}
+
+			ingredientIDs = append(ingredientIDs, item.IngredientID)
+		}
+
+		if err := q.RefreshEightySixed(ctx, sqlc.RefreshEightySixedParams{ItemIds: []int32{}, IngredientIds: ingredientIDs}); err != nil {
+			return err
+		}
+
+		items, err := q.GetPurchaseOrderItems(ctx, purchaseOrderID)
+		if err != nil {
+			return err
+		}
+
+		status := sqlc.PurchaseOrderStatusReceived
+		for _, i := range items {
+			if i.ReceivedQuantity < i.Quantity {
+				status = sqlc.PurchaseOrderStatusPartiallyReceived
+				break
+			}
+		}
+
+		// Fails with no rows if the order was cancelled or received in the meantime
+		po, err = q.UpdatePurchaseOrderStatus(ctx, sqlc.UpdatePurchaseOrderStatusParams{
+			Status:       status,
+			ID:           purchaseOrderID,
+			FromStatuses: []string{string(sqlc.PurchaseOrderStatusOrdered), string(sqlc.PurchaseOrderStatusPartiallyReceived)},
+		})
+
+		return err
+	})
+
+	return po, err
+}
+
+func (s *psqlStore) CreateMenuTx(ctx context.Context, arg sqlc.CreateMenuParams, itemIDs []int32) (sqlc.Menu, error) {
+	var menu sqlc.Menu
+	err := s.execTx(ctx, func(q *sqlc.Queries) error {
+		var err error
+
+		menu, err = q.CreateMenu(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		if len(itemIDs) == 0 {
+			return nil
+		}
+
+		return q.AddMenuEntries(ctx, sqlc.AddMenuEntriesParams{MenuID: menu.ID, ItemIds: itemIDs})
+	})
+
+	return menu, err
+}
+
+// Updates the menu and replaces the items on it
+func (s *psqlStore) UpdateMenuTx(ctx context.Context, arg sqlc.UpdateMenuParams, itemIDs []int32) (sqlc.Menu, error) {
+	var menu sqlc.Menu
+	err := s.execTx(ctx, func(q *sqlc.Queries) error {
+		var err error
+
+		menu, err = q.UpdateMenu(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		if err := q.DeleteMenuEntries(ctx, menu.ID); err != nil {
+			return err
+		}
+
+		if len(itemIDs) == 0 {
+			return nil
+		}
+
+		return q.AddMenuEntries(ctx, sqlc.AddMenuEntriesParams{MenuID: menu.ID, ItemIds: itemIDs})
+	})
+
+	return menu, err
+}
+
+func (s *psqlStore) CreateMenuScheduleTx(ctx context.Context, arg sqlc.CreateMenuScheduleParams, itemIDs []int32, prices []float64) (sqlc.MenuSchedule, error) {
+	var schedule sqlc.MenuSchedule
+	err := s.execTx(ctx, func(q *sqlc.Queries) error {
+		var err error
+
+		schedule, err = q.CreateMenuSchedule(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		if len(itemIDs) == 0 {
+			return nil
+		}
+
+		return q.AddSchedulePrices(ctx, sqlc.AddSchedulePricesParams{ScheduleID: schedule.ID, ItemIds: itemIDs, Prices: prices})
+	})
+
+	return schedule, err
+}
+
+// Creates the combo with its slots, options[i] are the items that can be picked for slots[i]
+func (s *psqlStore) CreateComboTx(ctx context.Context, arg sqlc.CreateComboParams, slots []sqlc.CreateComboSlotParams, options []sqlc.AddComboSlotOptionsParams) (sqlc.Combo, error) {
+	var combo sqlc.Combo
+	err := s.execTx(ctx, func(q *sqlc.Queries) error {
+		var err error
+
+		combo, err = q.CreateCombo(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		return addComboSlots(ctx, q, combo.ID, slots, options)
+	})
+
+	return combo, err
+}
+
+// Updates the combo and replaces all of its slots
+func (s *psqlStore) UpdateComboTx(ctx context.Context, arg sqlc.UpdateComboParams, slots []sqlc.CreateComboSlotParams, options []sqlc.AddComboSlotOptionsParams) (sqlc.Combo, error) {
+	var combo sqlc.Combo
+	err := s.execTx(ctx, func(q *sqlc.Queries) error {
+		var err error
+
+		combo, err = q.UpdateCombo(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		if err := q.DeleteComboSlots(ctx, combo.ID); err != nil {
+			return err
+		}
+
+		return addComboSlots(ctx, q, combo.ID, slots, options)
+	})
+
+	return combo, err
+}
+
+func addComboSlots(ctx context.Context, q *sqlc.Queries, comboID int32, slots []sqlc.CreateComboSlotParams, options []sqlc.AddComboSlotOptionsParams) error {
+	for i, arg := range slots {
+		arg.ComboID = comboID
+
+		slot, err := q.CreateComboSlot(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		opts := options[i]
+		opts.SlotID = slot.ID
+		if err := q.AddComboSlotOptions(ctx, opts); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Adds the items and combos to the order, comboItems[i] are the items that make up combos[i]
+func (s *psqlStore) AddOrderItemsTx(ctx context.Context, items sqlc.AddOrderItemsBulkParams, combos []sqlc.CreateOrderComboParams, comboItems []sqlc.AddOrderComboItemsParams) error {
+	return s.execTx(ctx, func(q *sqlc.Queries) error {
+		if len(items.ItemIds) > 0 {
+			if err := q.AddOrderItemsBulk(ctx, items); err != nil {
+				return err
+			}
+		}
+
+		for i, arg := range combos {
+			combo, err := q.CreateOrderCombo(ctx, arg)
+			if err != nil {
+				return err
+			}
+
+			components := comboItems[i]
+			components.OrderComboID = combo.ID
+			if err := q.AddOrderComboItems(ctx, components); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// Creates the customer with their phones and addresses
+func (s *psqlStore) CreateCustomerTx(ctx context.Context, arg sqlc.CreateCustomerParams, phones sqlc.AddCustomerPhonesParams, addresses sqlc.AddCustomerAddressesParams) (sqlc.Customer, error) {
+	var customer sqlc.Customer
+	err := s.execTx(ctx, func(q *sqlc.Queries) error {
+		var err error
+
+		customer, err = q.CreateCustomer(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		return addCustomerContacts(ctx, q, customer.ID, phones, addresses)
+	})
+
+	return customer, err
+}
+
+// Updates the customer and replaces all of their phones and addresses
+func (s *psqlStore) UpdateCustomerTx(ctx context.Context, arg sqlc.UpdateCustomerParams, phones sqlc.AddCustomerPhonesParams, addresses sqlc.AddCustomerAddressesParams) (sqlc.Customer, error) {
+	var customer sqlc.Customer
+	err := s.execTx(ctx, func(q *sqlc.Queries) error {
+		var err error
+
+		customer, err = q.UpdateCustomer(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		if err := q.DeleteCustomerPhones(ctx, customer.ID); err != nil {
+			return err
+		}
+
+		if err := q.DeleteCustomerAddresses(ctx, customer.ID); err != nil {
+			return err
+		}
+
+		return addCustomerContacts(ctx, q, customer.ID, phones, addresses)
+	})
+
+	return customer, err
+}
+
+func addCustomerContacts(ctx context.Context, q *sqlc.Queries, customerID int32, phones sqlc.AddCustomerPhonesParams, addresses sqlc.AddCustomerAddressesParams) error {
+	phones.CustomerID = customerID
+	if err := q.AddCustomerPhones(ctx, phones); err != nil {
+		return err
+	}
+
+	addresses.CustomerID = customerID
+	return q.AddCustomerAddresses(ctx, addresses)
+}
+
+// Completes the order, freeing its table and awarding loyalty points to its customer (if any)
+// at earnRate points per unit paid
+func (s *psqlStore) CompleteOrderTx(ctx context.Context, orderID pgtype.UUID, earnRate float64) (sqlc.Order, error) {
+	var order sqlc.Order
+	err := s.execTx(ctx, func(q *sqlc.Queries) error {
+		var err error
+
+		// Fails with no rows if the order is not ongoing anymore
+		order, err = q.CompleteOrder(ctx, orderID)
+		if err != nil {
+			return err
+		}
+
+		if order.TableID.Valid {
+			if err := q.UpdateTableStatus(ctx, sqlc.UpdateTableStatusParams{Status: sqlc.TableStatusAvailable, ID: order.TableID.String}); err != nil {
+				return err
+			}
+		}
+
+		if order.CustomerID.Valid {
+			if _, err := q.EarnLoyaltyPoints(ctx, sqlc.EarnLoyaltyPointsParams{Rate: earnRate, OrderID: order.ID}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return order, err
+}
+
+// Spends the points of the customer (arg.Points is negative) on a discount off the order. Both the order
+// and the customer are locked so the same points or the same part of the bill cant be spent twice.
+// Fails with ErrOrderNotOngoing or ErrOrderCustomer when the order is no longer ongoing or no longer
+// linked to arg.CustomerID.
+func (s *psqlStore) RedeemLoyaltyPointsTx(ctx context.Context, arg sqlc.CreateLoyaltyTransactionParams, discount sqlc.CreateOrderDiscountParams) (sqlc.OrderDiscount, error) {
+	var d sqlc.OrderDiscount
+	err := s.execTx(ctx, func(q *sqlc.Queries) error {
+		o, err := q.GetOrderForUpdate(ctx, discount.OrderID)
+		if err != nil {
+			return err
+		}
+
+		// The order could have been completed or linked to someone else since the caller read it
+		if o.Status != sqlc.OrderStatusOngoing {
+			return ErrOrderNotOngoing
+		}
+
+		if !o.CustomerID.Valid || o.CustomerID.Int32 != arg.CustomerID {
+			return ErrOrderCustomer
+		}
+
+		bill, err := q.GetOrderBill(ctx, discount.OrderID)
+		if err != nil {
+			return err
+		}
+
+		if discount.Amount > bill.Subtotal-bill.Discounts {
+			return ErrDiscountExceedsBill
+		}
+
+		if _, err := addLoyaltyTransaction(ctx, q, arg); err != nil {
+			return err
+		}
+
+		d, err = q.CreateOrderDiscount(ctx, discount)
+		return err
+	})
+
+	return d, err
+}
+
+// Adds or takes away points by hand, the balance cant go below zero
+func (s *psqlStore) AdjustLoyaltyPointsTx(ctx context.Context, arg sqlc.CreateLoyaltyTransactionParams) (sqlc.LoyaltyTransaction, error) {
+	var t sqlc.LoyaltyTransaction
+	err := s.execTx(ctx, func(q *sqlc.Queries) error {
+		var err error
+		t, err = addLoyaltyTransaction(ctx, q, arg)
+		return err
+	})
+
+	return t, err
+}
+
+// Records the transaction with the customer locked, failing with ErrInsufficientBalance when it would
+// leave them with a negative balance
+func addLoyaltyTransaction(ctx context.Context, q *sqlc.Queries, arg sqlc.CreateLoyaltyTransactionParams) (sqlc.LoyaltyTransaction, error) {
+	if err := q.LockCustomer(ctx, arg.CustomerID); err != nil {
+		return sqlc.LoyaltyTransaction{}, err
+	}
+
+	if arg.Points < 0 {
+		b, err := q.GetLoyaltyBalance(ctx, arg.CustomerID)
+		if err != nil {
+			return sqlc.LoyaltyTransaction{}, err
+		}
+
+		if b.Balance+arg.Points < 0 {
+			return sqlc.LoyaltyTransaction{}, ErrInsufficientBalance
+		}
+	}
+
+	return q.CreateLoyaltyTransaction(ctx, arg)
+}
+
+// Creates the card with its starting balance and pays what was taken for it into the drawer session,
+// failing with ErrDrawerClosed if the session was closed
+func (s *psqlStore) IssueGiftCardTx(ctx context.Context, arg sqlc.CreateGiftCardParams, payIn sqlc.CreateCashMovementParams) (sqlc.GiftCard, error) {
+	var card sqlc.GiftCard
+	err := s.execTx(ctx, func(q *sqlc.Queries) error {
+		if err := addPayIn(ctx, q, payIn); err != nil {
+			return err
+		}
+
+		var err error
+
+		card, err = q.CreateGiftCard(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		_, err = q.CreateGiftCardTransaction(ctx, sqlc.CreateGiftCardTransactionParams{
+			GiftCardID:   card.ID,
+			Type:         sqlc.GiftCardTransactionTypeIssue,
+			Amount:       arg.Balance,
+			BalanceAfter: card.Balance,
+			CreatedBy:    arg.IssuedBy,
+		})
+		return err
+	})
+
+	return card, err
+}
+
+// Adds to the balance of the card and pays what was taken for it into the drawer session, fails with
+// no rows if the card expired or ErrDrawerClosed if the session was closed
+func (s *psqlStore) ReloadGiftCardTx(ctx context.Context, arg sqlc.CreditGiftCardParams, payIn sqlc.CreateCashMovementParams) (sqlc.GiftCard, error) {
+	var card sqlc.GiftCard
+	err := s.execTx(ctx, func(q *sqlc.Queries) error {
+		if err := addPayIn(ctx, q, payIn); err != nil {
+			return err
+		}
+
+		var err error
+
+		card, err = q.CreditGiftCard(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		_, err = q.CreateGiftCardTransaction(ctx, sqlc.CreateGiftCardTransactionParams{
+			GiftCardID:   card.ID,
+			Type:         sqlc.GiftCardTransactionTypeReload,
+			Amount:       arg.Amount,
+			BalanceAfter: card.Balance,
+			CreatedBy:    payIn.CreatedBy,
+		})
+		return err
+	})
+
+	return card, err
+}
+
+// Locks the drawer session so it cant be closed until the transaction ends, failing with ErrDrawerClosed
+// if it already was
+func lockOpenSession(ctx context.Context, q *sqlc.Queries, id int64) error {
+	d, err := q.GetDrawerSessionForShare(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if d.ClosedAt.Valid {
+		return ErrDrawerClosed
+	}
+
+	return nil
+}
+
+// Records the pay in with its drawer session locked, failing with ErrDrawerClosed if the session was closed
+func addPayIn(ctx context.Context, q *sqlc.Queries, payIn sqlc.CreateCashMovementParams) error {
+	if err := lockOpenSession(ctx, q, payIn.SessionID); err != nil {
+		return err
+	}
+
+	payIn.Type = sqlc.CashMovementTypePayIn
+	_, err := q.CreateCashMovement(ctx, payIn)
+	return err
+}
+
+// Pays tender.Amount of the order from the card. The drawer session and the order are locked first so
+// the drawer cant close and the bill cant be paid twice over while the tender is added. Fails with
+// ErrDrawerClosed, ErrOrderNotOngoing, ErrTenderExceedsBill when the amount is more than is left to pay
+// at taxRate, or ErrInsufficientBalance when the card doesnt have that much left (or expired).
+func (s *psqlStore) RedeemGiftCardTx(ctx context.Context, giftCardID int32, tender sqlc.CreateTenderParams, taxRate float64) (sqlc.GiftCardTransaction, error) {
+	var t sqlc.GiftCardTransaction
+	err := s.execTx(ctx, func(q *sqlc.Queries) error {
+		if err := lockOpenSession(ctx, q, tender.SessionID); err != nil {
+			return err
+		}
+
+		o, err := q.GetOrderForUpdate(ctx, tender.OrderID)
+		if err != nil {
+			return err
+		}
+
+		if o.Status != sqlc.OrderStatusOngoing {
+			return ErrOrderNotOngoing
+		}
+
+		bill, err := q.GetOrderBill(ctx, tender.OrderID)
+		if err != nil {
+			return err
+		}
+
+		tendered, err := q.GetOrderTendered(ctx, tender.OrderID)
+		if err != nil {
+			return err
+		}
+
+		// Compared in cents so float noise doesnt refuse paying off exactly what is left
+		net := math.Max(bill.Subtotal-bill.Discounts, 0)
+		if math.Round(tender.Amount*100) > math.Round((net+net*taxRate-tendered)*100) {
+			return ErrTenderExceedsBill
+		}
+
+		card, err := q.DebitGiftCard(ctx, sqlc.DebitGiftCardParams{Amount: tender.Amount, ID: giftCardID})
+		if err != nil {
+			if errors.Is(err, ErrRecordNotFound) {
+				return ErrInsufficientBalance
+			}
+			return err
+		}
+
+		tender.Method = sqlc.TenderMethodGiftCard
+		tn, err := q.CreateTender(ctx, tender)
+		if err != nil {
+			return err
+		}
+
+		t, err = q.CreateGiftCardTransaction(ctx, sqlc.CreateGiftCardTransactionParams{
+			GiftCardID:   card.ID,
+			Type:         sqlc.GiftCardTransactionTypeRedeem,
+			Amount:       -tender.Amount,
+			BalanceAfter: card.Balance,
+			OrderID:      tender.OrderID,
+			TenderID:     pgtype.Int8{Int64: tn.ID, Valid: true},
+			CreatedBy:    tender.CreatedBy,
+		})
+		return err
+	})
+
+	return t, err
+}
+
+// Applies the promotion to the order. The promotion and the order are locked before the order is
+// read so build works the discount out from what the order has when it is applied, and two orders
+// cant both take the last use of the promotion. Whatever error build returns is passed on, otherwise
+// it fails with ErrUsageLimitReached or ErrCustomerLimitReached when the promotion has been used up.
+func (s *psqlStore) ApplyPromotionTx(ctx context.Context, promotionID int32, orderID pgtype.UUID, build func(p sqlc.Promotion, on bool, o sqlc.Order, items []sqlc.OrderItem, discounts []sqlc.OrderDiscount) (sqlc.CreateOrderDiscountParams, error)) (sqlc.OrderDiscount, error) {
+	var d sqlc.OrderDiscount
+	err := s.execTx(ctx, func(q *sqlc.Queries) error {
+		p, err := q.GetPromotionForUpdate(ctx, promotionID)
+		if err != nil {
+			return err
+		}
+
+		o, err := q.GetOrderForUpdate(ctx, orderID)
+		if err != nil {
+			return err
+		}
+
+		items, err := q.GetOrderItems(ctx, orderID)
+		if err != nil {
+			return err
+		}
+
+		discounts, err := q.GetOrderDiscounts(ctx, orderID)
+		if err != nil {
+			return err
+		}
+
+		on, err := q.IsPromotionOn(ctx, promotionID)
+		if err != nil {
+			return err
+		}
+
+		discount, err := build(p, on, o, items, discounts)
+		if err != nil {
+			return err
+		}
+
+		if p.UsageLimit.Valid || p.CustomerLimit.Valid {
+			u, err := q.GetPromotionUsage(ctx, sqlc.GetPromotionUsageParams{
+				CustomerID:  discount.CustomerID.Int32,
+				PromotionID: p.ID,
+			})
+			if err != nil {
+				return err
+			}
+
+			if p.UsageLimit.Valid && u.Uses >= int64(p.UsageLimit.Int32) {
+				return ErrUsageLimitReached
+			}
+
+			if p.CustomerLimit.Valid && u.CustomerUses >= int64(p.CustomerLimit.Int32) {
+				return ErrCustomerLimitReached
+			}
+		}
+
+		d, err = q.CreateOrderDiscount(ctx, discount)
+		return err
+	})
+
+	return d, err
+}
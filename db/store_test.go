@@ -0,0 +1,347 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pdridh/k-line/db/sqlc"
+	"github.com/pkg/errors"
+)
+
+// Store tests run against a migrated database at TEST_DATABASE_URI and are skipped without one.
+// They leave their rows behind, every name and code they create is unique so they can be run again.
+func testStore(t *testing.T) Store {
+	t.Helper()
+
+	uri := os.Getenv("TEST_DATABASE_URI")
+	if uri == "" {
+		t.Skip("TEST_DATABASE_URI is not set")
+	}
+
+	pool, err := pgxpool.New(context.Background(), uri)
+	if err != nil {
+		t.Fatalf("cannot create connection pool: %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	return NewPSQLStore(pool)
+}
+
+// Something unique to tell apart the rows of every test run
+func testSuffix() string {
+	return fmt.Sprintf("%d", time.Now().UnixNano())
+}
+
+func createTestUser(t *testing.T, s Store) sqlc.User {
+	t.Helper()
+
+	ctx := context.Background()
+	role, err := s.GetRoleByName(ctx, "register")
+	if err != nil {
+		t.Fatalf("cannot get role: %v", err)
+	}
+
+	u, err := s.CreateUser(ctx, sqlc.CreateUserParams{
+		Email:    "store-test-" + testSuffix() + "@example.com",
+		Name:     "Store test",
+		Type:     sqlc.UserTypeRegister,
+		Password: "not a hash",
+		RoleID:   role.ID,
+	})
+	if err != nil {
+		t.Fatalf("cannot create user: %v", err)
+	}
+
+	return u
+}
+
+// Creates an ongoing takeaway order with one item that costs total
+func createTestOrder(t *testing.T, s Store, employeeID pgtype.UUID, total float64) pgtype.UUID {
+	t.Helper()
+
+	ctx := context.Background()
+	item, err := s.CreateMenuItem(ctx, sqlc.CreateMenuItemParams{
+		Name:        "Store test " + testSuffix(),
+		Price:       total,
+		Allergens:   []string{},
+		DietaryTags: []string{},
+	})
+	if err != nil {
+		t.Fatalf("cannot create menu item: %v", err)
+	}
+
+	orderID, err := s.CreateOrder(ctx, sqlc.CreateOrderParams{Type: sqlc.OrderTypeTakeaway, EmployeeID: employeeID})
+	if err != nil {
+		t.Fatalf("cannot create order: %v", err)
+	}
+
+	err = s.AddOrderItemsBulk(ctx, sqlc.AddOrderItemsBulkParams{
+		OrderID:    orderID,
+		ItemIds:    []int32{item.ID},
+		Quantity:   []int32{1},
+		Notes:      []string{""},
+		UnitPrices: []float64{total},
+	})
+	if err != nil {
+		t.Fatalf("cannot add order item: %v", err)
+	}
+
+	return orderID
+}
+
+// Runs every f at the same time and returns their errors in order
+func runConcurrently(fs ...func() error) []error {
+	errs := make([]error, len(fs))
+
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for n, f := range fs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			errs[n] = f()
+		}()
+	}
+
+	close(start)
+	wg.Wait()
+
+	return errs
+}
+
+// Counts the errors that are target, failing on any other error
+func countErrors(t *testing.T, errs []error, target error) int {
+	t.Helper()
+
+	n := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+		case errors.Is(err, target):
+			n++
+		default:
+			t.Fatalf("unexpected error %v", err)
+		}
+	}
+
+	return n
+}
+
+func TestRedeemGiftCardTxConcurrent(t *testing.T) {
+	s := testStore(t)
+	ctx := context.Background()
+
+	u := createTestUser(t, s)
+
+	session, err := s.OpenDrawerSession(ctx, sqlc.OpenDrawerSessionParams{Drawer: "store-test-" + testSuffix(), OpenedBy: u.ID})
+	if err != nil {
+		t.Fatalf("cannot open drawer session: %v", err)
+	}
+
+	newCard := func(balance float64) sqlc.GiftCard {
+		card, err := s.CreateGiftCard(ctx, sqlc.CreateGiftCardParams{Code: "TEST" + testSuffix(), Balance: balance, IssuedBy: u.ID})
+		if err != nil {
+			t.Fatalf("cannot create gift card: %v", err)
+		}
+		return card
+	}
+
+	redeem := func(card sqlc.GiftCard, orderID pgtype.UUID, amount float64) func() error {
+		return func() error {
+			_, err := s.RedeemGiftCardTx(ctx, card.ID, sqlc.CreateTenderParams{
+				SessionID: session.ID,
+				OrderID:   orderID,
+				Amount:    amount,
+				CreatedBy: u.ID,
+			}, 0)
+			return err
+		}
+	}
+
+	t.Run("one card cant be spent twice", func(t *testing.T) {
+		card := newCard(50)
+		first := createTestOrder(t, s, u.ID, 40)
+		second := createTestOrder(t, s, u.ID, 40)
+
+		errs := runConcurrently(redeem(card, first, 40), redeem(card, second, 40))
+		if n := countErrors(t, errs, ErrInsufficientBalance); n != 1 {
+			t.Fatalf("got %d insufficient balance errors, want 1", n)
+		}
+
+		card, err := s.GetGiftCardByCode(ctx, card.Code)
+		if err != nil {
+			t.Fatalf("cannot get gift card: %v", err)
+		}
+		if card.Balance != 10 {
+			t.Errorf("got balance %v, want 10", card.Balance)
+		}
+	})
+
+	t.Run("one order cant be paid twice", func(t *testing.T) {
+		card := newCard(100)
+		order := createTestOrder(t, s, u.ID, 50)
+
+		errs := runConcurrently(redeem(card, order, 40), redeem(card, order, 40))
+		if n := countErrors(t, errs, ErrTenderExceedsBill); n != 1 {
+			t.Fatalf("got %d tender exceeds bill errors, want 1", n)
+		}
+
+		tendered, err := s.GetOrderTendered(ctx, order)
+		if err != nil {
+			t.Fatalf("cannot get tendered: %v", err)
+		}
+		if tendered != 40 {
+			t.Errorf("got tendered %v, want 40", tendered)
+		}
+	})
+}
+
+// Creates a customer with points to spend
+func createTestCustomer(t *testing.T, s Store, createdBy pgtype.UUID, points int32) sqlc.Customer {
+	t.Helper()
+
+	ctx := context.Background()
+	c, err := s.CreateCustomer(ctx, sqlc.CreateCustomerParams{Name: "Store test " + testSuffix()})
+	if err != nil {
+		t.Fatalf("cannot create customer: %v", err)
+	}
+
+	_, err = s.AdjustLoyaltyPointsTx(ctx, sqlc.CreateLoyaltyTransactionParams{
+		CustomerID: c.ID,
+		Type:       sqlc.LoyaltyTransactionTypeAdjust,
+		Points:     points,
+		CreatedBy:  createdBy,
+	})
+	if err != nil {
+		t.Fatalf("cannot add points: %v", err)
+	}
+
+	return c
+}
+
+// Spends points of the customer on amount off the order
+func redeemTestPoints(s Store, c sqlc.Customer, orderID pgtype.UUID, points int32, amount float64, createdBy pgtype.UUID) func() error {
+	return func() error {
+		_, err := s.RedeemLoyaltyPointsTx(context.Background(), sqlc.CreateLoyaltyTransactionParams{
+			CustomerID: c.ID,
+			Type:       sqlc.LoyaltyTransactionTypeRedeem,
+			Points:     -points,
+			OrderID:    orderID,
+			CreatedBy:  createdBy,
+		}, sqlc.CreateOrderDiscountParams{
+			OrderID:     orderID,
+			Type:        sqlc.DiscountTypeLoyalty,
+			Description: "Store test",
+			Amount:      amount,
+			CreatedBy:   createdBy,
+			CustomerID:  pgtype.Int4{Int32: c.ID, Valid: true},
+		})
+		return err
+	}
+}
+
+func TestRedeemLoyaltyPointsTxConcurrent(t *testing.T) {
+	s := testStore(t)
+	ctx := context.Background()
+
+	u := createTestUser(t, s)
+
+	newCustomer := func(points int32) sqlc.Customer {
+		return createTestCustomer(t, s, u.ID, points)
+	}
+
+	redeem := func(c sqlc.Customer, orderID pgtype.UUID, points int32, amount float64) func() error {
+		return redeemTestPoints(s, c, orderID, points, amount, u.ID)
+	}
+
+	t.Run("points cant be spent twice", func(t *testing.T) {
+		c := newCustomer(100)
+		first := createTestOrder(t, s, u.ID, 50)
+		second := createTestOrder(t, s, u.ID, 50)
+
+		errs := runConcurrently(redeem(c, first, 80, 0.8), redeem(c, second, 80, 0.8))
+		if n := countErrors(t, errs, ErrInsufficientBalance); n != 1 {
+			t.Fatalf("got %d insufficient balance errors, want 1", n)
+		}
+
+		b, err := s.GetLoyaltyBalance(ctx, c.ID)
+		if err != nil {
+			t.Fatalf("cannot get balance: %v", err)
+		}
+		if b.Balance != 20 {
+			t.Errorf("got balance %v, want 20", b.Balance)
+		}
+	})
+
+	t.Run("discounts cant take more than the bill", func(t *testing.T) {
+		c := newCustomer(2000)
+		order := createTestOrder(t, s, u.ID, 10)
+
+		errs := runConcurrently(redeem(c, order, 800, 8), redeem(c, order, 800, 8))
+		if n := countErrors(t, errs, ErrDiscountExceedsBill); n != 1 {
+			t.Fatalf("got %d discount exceeds bill errors, want 1", n)
+		}
+
+		bill, err := s.GetOrderBill(ctx, order)
+		if err != nil {
+			t.Fatalf("cannot get bill: %v", err)
+		}
+		if bill.Discounts != 8 {
+			t.Errorf("got discounts %v, want 8", bill.Discounts)
+		}
+	})
+}
+
+func TestRedeemLoyaltyPointsTxOrderChanged(t *testing.T) {
+	s := testStore(t)
+	ctx := context.Background()
+
+	u := createTestUser(t, s)
+
+	linkedOrder := func(c sqlc.Customer) pgtype.UUID {
+		order := createTestOrder(t, s, u.ID, 50)
+		_, err := s.SetOrderCustomer(ctx, sqlc.SetOrderCustomerParams{ID: order, CustomerID: pgtype.Int4{Int32: c.ID, Valid: true}})
+		if err != nil {
+			t.Fatalf("cannot link customer: %v", err)
+		}
+		return order
+	}
+
+	t.Run("completed order", func(t *testing.T) {
+		c := createTestCustomer(t, s, u.ID, 100)
+		order := linkedOrder(c)
+
+		if _, err := s.CompleteOrder(ctx, order); err != nil {
+			t.Fatalf("cannot complete order: %v", err)
+		}
+
+		if err := redeemTestPoints(s, c, order, 50, 0.5, u.ID)(); !errors.Is(err, ErrOrderNotOngoing) {
+			t.Fatalf("got error %v, want %v", err, ErrOrderNotOngoing)
+		}
+
+		b, err := s.GetLoyaltyBalance(ctx, c.ID)
+		if err != nil {
+			t.Fatalf("cannot get balance: %v", err)
+		}
+		if b.Balance != 100 {
+			t.Errorf("got balance %v, want 100", b.Balance)
+		}
+	})
+
+	t.Run("order linked to someone else", func(t *testing.T) {
+		c := createTestCustomer(t, s, u.ID, 100)
+		other := createTestCustomer(t, s, u.ID, 0)
+		order := linkedOrder(other)
+
+		if err := redeemTestPoints(s, c, order, 50, 0.5, u.ID)(); !errors.Is(err, ErrOrderCustomer) {
+			t.Fatalf("got error %v, want %v", err, ErrOrderCustomer)
+		}
+	})
+}
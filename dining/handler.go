@@ -7,6 +7,7 @@ import (
 
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/pdridh/k-line/api"
+	"github.com/pdridh/k-line/auth"
 	"github.com/pdridh/k-line/db/sqlc"
 )
 
@@ -23,12 +24,12 @@ func NewHandler(s *service) *handler {
 func (h *handler) CreateOrder() http.HandlerFunc {
 
 	type RequestPayload struct {
-		TableID pgtype.Text `json:"table_id" validate:"required"`
+		TableID    pgtype.Text `json:"table_id" validate:"required"`
+		Covers     pgtype.Int2 `json:"covers"`      // Number of guests seated, optional
+		CustomerID pgtype.Int4 `json:"customer_id"` // Optional, usually found by phone through /customers/lookup
 	}
 
 	return func(w http.ResponseWriter, r *http.Request) {
-		userIDstr := api.CurrentUserID(r)
-
 		var p RequestPayload
 
 		if err := api.ParseJSON(r, &p); err != nil {
@@ -41,20 +42,31 @@ func (h *handler) CreateOrder() http.HandlerFunc {
 			return
 		}
 
-		var userID pgtype.UUID
-		if err := userID.Scan(userIDstr); err != nil {
-			api.WriteInternalError(w, r)
+		if p.Covers.Valid && p.Covers.Int16 < 1 {
+			api.WriteBadRequestError(w, r)
+			return
 		}
 
-		o, err := h.Service.CreateOrder(r.Context(), p.TableID, userID)
+		userID, ok := currentEmployee(w, r)
+		if !ok {
+			return
+		}
+
+		o, err := h.Service.CreateOrder(r.Context(), p.TableID, p.Covers, p.CustomerID, userID, api.CurrentUserType(r))
 		if err != nil {
 			switch {
+			case errors.Is(err, api.ErrNotClockedIn.Error):
+				api.WriteError(w, r, http.StatusForbidden, api.ErrNotClockedIn, nil)
+				return
 			case errors.Is(err, api.ErrUnknownTable.Error):
 				api.WriteNotFoundError(w, r)
 				return
 			case errors.Is(err, api.ErrTableNotAvaliable.Error):
 				api.WriteError(w, r, http.StatusConflict, api.ErrTableNotAvaliable, nil)
 				return
+			case errors.Is(err, api.ErrUnknownCustomer.Error):
+				api.WriteError(w, r, http.StatusBadRequest, api.ErrUnknownCustomer, nil)
+				return
 			default:
 				api.WriteInternalError(w, r)
 				return
@@ -66,10 +78,108 @@ func (h *handler) CreateOrder() http.HandlerFunc {
 
 }
 
+func (h *handler) CreateTakeawayOrder() http.HandlerFunc {
+
+	type RequestPayload struct {
+		Contact    string      `json:"contact" validate:"required"` // Phone number, links the customer with it when customer_id is left out
+		Notes      pgtype.Text `json:"notes"`
+		CustomerID pgtype.Int4 `json:"customer_id"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var p RequestPayload
+
+		if err := api.ParseJSON(r, &p); err != nil {
+			api.WriteBadRequestError(w, r)
+			return
+		}
+
+		if err := h.Service.Validate.Struct(p); err != nil {
+			api.WriteValidationError(w, r, err)
+			return
+		}
+
+		userID, ok := currentEmployee(w, r)
+		if !ok {
+			return
+		}
+
+		o, err := h.Service.CreateTakeawayOrder(r.Context(), p.Contact, p.Notes, p.CustomerID, userID, api.CurrentUserType(r))
+		if err != nil {
+			writeCreateOrderError(w, r, err)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusCreated, "Order created succefully", o)
+	}
+
+}
+
+func (h *handler) CreateDeliveryOrder() http.HandlerFunc {
+
+	type RequestPayload struct {
+		Contact    string      `json:"contact" validate:"required"` // Phone number, links the customer with it when customer_id is left out
+		Address    string      `json:"address" validate:"required"`
+		DriverID   string      `json:"driver_id" validate:"required,uuid"`
+		CustomerID pgtype.Int4 `json:"customer_id"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var p RequestPayload
+
+		if err := api.ParseJSON(r, &p); err != nil {
+			api.WriteBadRequestError(w, r)
+			return
+		}
+
+		if err := h.Service.Validate.Struct(p); err != nil {
+			api.WriteValidationError(w, r, err)
+			return
+		}
+
+		var driverID pgtype.UUID
+		if err := driverID.Scan(p.DriverID); err != nil {
+			api.WriteBadRequestError(w, r)
+			return
+		}
+
+		userID, ok := currentEmployee(w, r)
+		if !ok {
+			return
+		}
+
+		o, err := h.Service.CreateDeliveryOrder(r.Context(), p.Contact, p.Address, driverID, p.CustomerID, userID, api.CurrentUserType(r))
+		if err != nil {
+			writeCreateOrderError(w, r, err)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusCreated, "Order created succefully", o)
+	}
+
+}
+
+// Writes the errors shared by takeaway and delivery order creation
+func writeCreateOrderError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, api.ErrNotClockedIn.Error):
+		api.WriteError(w, r, http.StatusForbidden, api.ErrNotClockedIn, nil)
+	case errors.Is(err, api.ErrInvalidPhone.Error):
+		api.WriteError(w, r, http.StatusBadRequest, api.ErrInvalidPhone, nil)
+	case errors.Is(err, api.ErrInvalidDriver.Error):
+		api.WriteError(w, r, http.StatusBadRequest, api.ErrInvalidDriver, nil)
+	case errors.Is(err, api.ErrUnknownCustomer.Error):
+		api.WriteError(w, r, http.StatusBadRequest, api.ErrUnknownCustomer, nil)
+	default:
+		api.WriteInternalError(w, r)
+	}
+}
+
 func (h *handler) AddOrderItem() http.HandlerFunc {
 
 	type RequestPayload struct {
-		Items []RequestItem `json:"items" validate:"required"`
+		Items  []RequestItem  `json:"items" validate:"required_without=Combos"`
+		Combos []RequestCombo `json:"combos" validate:"required_without=Items,dive"`
 	}
 
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -94,24 +204,208 @@ func (h *handler) AddOrderItem() http.HandlerFunc {
 			return
 		}
 
-		if err := h.Service.AddItemsToOrder(r.Context(), id, p.Items); err != nil {
+		userID, ok := currentEmployee(w, r)
+		if !ok {
+			return
+		}
+
+		conflicts, err := h.Service.AddItemsToOrder(r.Context(), id, p.Items, p.Combos, userID, api.CurrentUserType(r))
+		if err != nil {
 			switch {
+			case errors.Is(err, api.ErrNotClockedIn.Error):
+				api.WriteError(w, r, http.StatusForbidden, api.ErrNotClockedIn, nil)
+				return
 			case errors.Is(err, api.ErrUnknownOrder.Error):
 				api.WriteNotFoundError(w, r)
 				return
 			case errors.Is(err, api.ErrOrderNotOngoing.Error):
 				api.WriteError(w, r, http.StatusConflict, api.ErrOrderNotOngoing, nil)
 				return
+			case errors.Is(err, api.ErrItemEightySixed.Error):
+				api.WriteError(w, r, http.StatusConflict, api.ErrItemEightySixed, nil)
+				return
+			case errors.Is(err, api.ErrItemNotAvailable.Error):
+				api.WriteError(w, r, http.StatusConflict, api.ErrItemNotAvailable, nil)
+				return
+			case errors.Is(err, api.ErrUnkownMenuItem.Error):
+				api.WriteError(w, r, http.StatusBadRequest, api.ErrUnkownMenuItem, nil)
+				return
+			case errors.Is(err, api.ErrUnknownCombo.Error):
+				api.WriteError(w, r, http.StatusBadRequest, api.ErrUnknownCombo, nil)
+				return
+			case errors.Is(err, api.ErrComboNotActive.Error):
+				api.WriteError(w, r, http.StatusConflict, api.ErrComboNotActive, nil)
+				return
+			case errors.Is(err, api.ErrInvalidComboChoice.Error):
+				api.WriteError(w, r, http.StatusBadRequest, api.ErrInvalidComboChoice, nil)
+				return
+			case errors.Is(err, api.ErrAllergenConflict.Error):
+				api.WriteError(w, r, http.StatusConflict, api.ErrAllergenConflict, conflicts)
+				return
 			default:
 				api.WriteInternalError(w, r)
 				return
 			}
 		}
 
+		// The items were added but the server should double check with the guests
+		if len(conflicts) > 0 {
+			api.WriteSuccess(w, r, http.StatusCreated, "Added items containing declared allergens", map[string]any{"allergen_warnings": conflicts})
+			return
+		}
+
 		api.WriteSuccess(w, r, http.StatusCreated, "Succesfully added item to order", nil)
 	}
 }
 
+func (h *handler) SetOrderAllergies() http.HandlerFunc {
+	type RequestPayload struct {
+		Allergies []string `json:"allergies" validate:"unique,dive,allergen"`
+		Block     bool     `json:"block"` // Refuse conflicting items instead of only warning
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := pgtype.UUID{}
+		if err := id.Scan(r.PathValue("id")); err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		var p RequestPayload
+
+		if err := api.ParseJSON(r, &p); err != nil {
+			api.WriteBadRequestError(w, r)
+			return
+		}
+
+		if err := h.Service.Validate.Struct(p); err != nil {
+			api.WriteValidationError(w, r, err)
+			return
+		}
+
+		userID, ok := currentEmployee(w, r)
+		if !ok {
+			return
+		}
+
+		if err := h.Service.SetOrderAllergies(r.Context(), id, p.Allergies, p.Block, userID, api.CurrentUserType(r)); err != nil {
+			switch {
+			case errors.Is(err, api.ErrNotClockedIn.Error):
+				api.WriteError(w, r, http.StatusForbidden, api.ErrNotClockedIn, nil)
+			case errors.Is(err, api.ErrUnknownOrder.Error):
+				api.WriteNotFoundError(w, r)
+			case errors.Is(err, api.ErrOrderNotOngoing.Error):
+				api.WriteError(w, r, http.StatusConflict, api.ErrOrderNotOngoing, nil)
+			default:
+				api.WriteInternalError(w, r)
+			}
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Successfully updated order allergies", nil)
+	}
+}
+
+// Links the order to a customer, a null customer_id unlinks it
+func (h *handler) SetOrderCustomer() http.HandlerFunc {
+	type RequestPayload struct {
+		CustomerID pgtype.Int4 `json:"customer_id"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := pgtype.UUID{}
+		if err := id.Scan(r.PathValue("id")); err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		var p RequestPayload
+
+		if err := api.ParseJSON(r, &p); err != nil {
+			api.WriteBadRequestError(w, r)
+			return
+		}
+
+		userID, ok := currentEmployee(w, r)
+		if !ok {
+			return
+		}
+
+		if err := h.Service.SetOrderCustomer(r.Context(), id, p.CustomerID, userID, api.CurrentUserType(r)); err != nil {
+			switch {
+			case errors.Is(err, api.ErrNotClockedIn.Error):
+				api.WriteError(w, r, http.StatusForbidden, api.ErrNotClockedIn, nil)
+			case errors.Is(err, api.ErrUnknownOrder.Error):
+				api.WriteNotFoundError(w, r)
+			case errors.Is(err, api.ErrOrderNotOngoing.Error):
+				api.WriteError(w, r, http.StatusConflict, api.ErrOrderNotOngoing, nil)
+			case errors.Is(err, api.ErrUnknownCustomer.Error):
+				api.WriteError(w, r, http.StatusBadRequest, api.ErrUnknownCustomer, nil)
+			default:
+				api.WriteInternalError(w, r)
+			}
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Successfully updated order customer", nil)
+	}
+}
+
+func (h *handler) CompleteOrder() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := pgtype.UUID{}
+		if err := id.Scan(r.PathValue("id")); err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		userID, ok := currentEmployee(w, r)
+		if !ok {
+			return
+		}
+
+		o, err := h.Service.CompleteOrder(r.Context(), id, userID, api.CurrentUserType(r))
+		if err != nil {
+			switch {
+			case errors.Is(err, api.ErrNotClockedIn.Error):
+				api.WriteError(w, r, http.StatusForbidden, api.ErrNotClockedIn, nil)
+			case errors.Is(err, api.ErrUnknownOrder.Error):
+				api.WriteNotFoundError(w, r)
+			case errors.Is(err, api.ErrOrderNotOngoing.Error):
+				api.WriteError(w, r, http.StatusConflict, api.ErrOrderNotOngoing, nil)
+			default:
+				api.WriteInternalError(w, r)
+			}
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Successfully completed order", o)
+	}
+}
+
+func (h *handler) GetBill() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := pgtype.UUID{}
+		if err := id.Scan(r.PathValue("id")); err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		b, err := h.Service.GetBill(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, api.ErrUnknownOrder.Error) {
+				api.WriteNotFoundError(w, r)
+				return
+			}
+
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Retrieval successful", b)
+	}
+}
+
 func (h *handler) UpdateOrderItem() http.HandlerFunc {
 	type RequestPayload struct {
 		Status sqlc.OrderItemStatus `json:"status" validate:"required,oneof=pending preparing ready served delivered cancelled"`
@@ -145,8 +439,22 @@ func (h *handler) UpdateOrderItem() http.HandlerFunc {
 			return
 		}
 
-		if err := h.Service.UpdateOrderItem(r.Context(), orderID, itemID, p.Status); err != nil {
+		// Cancelling an item voids it from the bill so it needs its own permission
+		if p.Status == sqlc.OrderItemStatusCancelled && !api.CurrentUserCan(r, string(auth.PermOrderVoid)) {
+			api.WriteForbiddenError(w, r)
+			return
+		}
+
+		userID, ok := currentEmployee(w, r)
+		if !ok {
+			return
+		}
+
+		if err := h.Service.UpdateOrderItem(r.Context(), orderID, itemID, p.Status, userID, api.CurrentUserType(r)); err != nil {
 			switch {
+			case errors.Is(err, api.ErrNotClockedIn.Error):
+				api.WriteError(w, r, http.StatusForbidden, api.ErrNotClockedIn, nil)
+				return
 			case errors.Is(err, api.ErrUnknownOrder.Error), errors.Is(err, api.ErrUnknownOrderItem.Error):
 				api.WriteNotFoundError(w, r)
 				return
@@ -165,38 +473,133 @@ func (h *handler) UpdateOrderItem() http.HandlerFunc {
 }
 
 func (h *handler) GetTables() http.HandlerFunc {
-	type QueryParams struct {
-		Status sqlc.TableStatus `json:"status" validate:"required,oneof=available occupied closed"`
-	}
-
 	return func(w http.ResponseWriter, r *http.Request) {
-		var p QueryParams
+		var filters TableFilters
 
-		api.ParseQueryParams(r.URL.Query(), &p)
+		api.ParseQueryParams(r.URL.Query(), &filters)
+		filters.Validate(100, 50)
 
-		if err := h.Service.Validate.Struct(p); err != nil {
+		if err := h.Service.Validate.Struct(filters); err != nil {
 			api.WriteValidationError(w, r, err)
 			return
 		}
 
-		t, err := h.Service.GetTables(r.Context(), p.Status)
+		offset := (filters.Page - 1) * filters.Limit
+
+		t, total, err := h.Service.GetTables(r.Context(), filters.Status, filters.Limit, offset)
 		if err != nil {
 			api.WriteInternalError(w, r)
 			return
 		}
 
-		api.WriteSuccess(w, r, http.StatusOK, "Retrieval successful", t)
+		meta := api.CalculatePaginationMeta(total, int(filters.Page), int(filters.Limit))
+		api.WriteSuccess(w, r, http.StatusOK, "Retrieval successful", api.NewPaginatedResponse(t, meta))
 	}
 }
 
+// Lists the ongoing dining orders, pages can be fetched by page or with the cursor returned in the meta
+// which keeps working while new orders come in
 func (h *handler) GetActiveOrders() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		o, err := h.Service.GetOrders(r.Context(), sqlc.OrderStatusOngoing, sqlc.OrderTypeDining)
+		var filters OrderFilters
+
+		api.ParseQueryParams(r.URL.Query(), &filters)
+		filters.Validate(100, 50)
+
+		var cursor *api.Cursor
+		if filters.Cursor != "" {
+			c, err := api.ParseCursor(filters.Cursor)
+			if err != nil {
+				api.WriteError(w, r, http.StatusBadRequest, api.ErrInvalidCursor, nil)
+				return
+			}
+			cursor = &c
+		}
+
+		offset := (filters.Page - 1) * filters.Limit
+
+		o, total, next, err := h.Service.GetOrders(r.Context(), sqlc.OrderStatusOngoing, sqlc.OrderTypeDining, filters.Limit, offset, cursor)
 		if err != nil {
+			if errors.Is(err, api.ErrInvalidCursor.Error) {
+				api.WriteError(w, r, http.StatusBadRequest, api.ErrInvalidCursor, nil)
+				return
+			}
+
 			api.WriteInternalError(w, r)
 			return
 		}
 
-		api.WriteSuccess(w, r, http.StatusOK, "Retrieval successful", o)
+		meta := api.CalculatePaginationMeta(total, int(filters.Page), int(filters.Limit))
+		meta.NextCursor = next
+		if cursor != nil {
+			// Pages dont apply when going by cursor
+			meta.Page = 0
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Retrieval successful", api.NewPaginatedResponse(o, meta))
 	}
 }
+
+// Finds orders of any status and type, like the one at table 12 last friday
+func (h *handler) SearchOrders() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var filters OrderSearchFilters
+
+		api.ParseQueryParams(r.URL.Query(), &filters)
+		filters.Validate(100, 50)
+
+		if err := h.Service.Validate.Struct(filters); err != nil {
+			api.WriteValidationError(w, r, err)
+			return
+		}
+
+		start, end, err := filters.Period()
+		if err != nil {
+			api.WriteError(w, r, http.StatusBadRequest, api.ErrInvalidPeriod, nil)
+			return
+		}
+
+		arg := sqlc.SearchOrdersParams{
+			Limit:       filters.Limit,
+			Offset:      (filters.Page - 1) * filters.Limit,
+			PeriodStart: start,
+			PeriodEnd:   end,
+			Status:      filters.Status,
+			Type:        filters.Type,
+			TableID:     filters.TableID,
+			ItemID:      filters.ItemID,
+			CustomerID:  filters.CustomerID,
+			MinTotal:    pgtype.Float8{Float64: filters.MinTotal, Valid: filters.MinTotal > 0},
+			MaxTotal:    pgtype.Float8{Float64: filters.MaxTotal, Valid: filters.MaxTotal > 0},
+			Sort:        filters.Sort,
+			SortDesc:    filters.Order == "desc",
+		}
+
+		if filters.EmployeeID != "" {
+			if err := arg.EmployeeID.Scan(filters.EmployeeID); err != nil {
+				api.WriteBadRequestError(w, r)
+				return
+			}
+		}
+
+		o, total, err := h.Service.SearchOrders(r.Context(), arg)
+		if err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		meta := api.CalculatePaginationMeta(total, int(filters.Page), int(filters.Limit))
+		api.WriteSuccess(w, r, http.StatusOK, "Retrieval successful", api.NewPaginatedResponse(o, meta))
+	}
+}
+
+// Reads the id of the logged in employee, writing an internal error if it cant be parsed
+func currentEmployee(w http.ResponseWriter, r *http.Request) (pgtype.UUID, bool) {
+	var userID pgtype.UUID
+	if err := userID.Scan(api.CurrentUserID(r)); err != nil {
+		api.WriteInternalError(w, r)
+		return userID, false
+	}
+
+	return userID, true
+}
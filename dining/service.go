@@ -2,28 +2,42 @@ package dining
 
 import (
 	"context"
+	"math"
+	"slices"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/pdridh/k-line/api"
+	"github.com/pdridh/k-line/config"
+	"github.com/pdridh/k-line/customer"
 	"github.com/pdridh/k-line/db"
 	"github.com/pdridh/k-line/db/sqlc"
 	"github.com/pkg/errors"
 )
 
+// Decides whether an employee is allowed to work on orders, implemented by the shift service
+type clockInChecker interface {
+	CheckClockedIn(ctx context.Context, userID pgtype.UUID, userType sqlc.UserType) error
+}
+
 type service struct {
 	Validate *validator.Validate
 	store    db.Store
+	shifts   clockInChecker
 }
 
-func NewService(v *validator.Validate, s db.Store) *service {
+func NewService(v *validator.Validate, s db.Store, shifts clockInChecker) *service {
 	return &service{
 		Validate: v,
 		store:    s,
+		shifts:   shifts,
 	}
 }
 
-func (s *service) CreateOrder(ctx context.Context, tableID pgtype.Text, employeeID pgtype.UUID) (*pgtype.UUID, error) {
+func (s *service) CreateOrder(ctx context.Context, tableID pgtype.Text, covers pgtype.Int2, customerID pgtype.Int4, employeeID pgtype.UUID, employeeType sqlc.UserType) (*pgtype.UUID, error) {
+	if err := s.checkClockedIn(ctx, employeeID, employeeType); err != nil {
+		return nil, err
+	}
 
 	t, err := s.store.GetTableByID(ctx, tableID.String)
 	if err != nil {
@@ -38,7 +52,104 @@ func (s *service) CreateOrder(ctx context.Context, tableID pgtype.Text, employee
 		return nil, errors.Wrap(api.ErrTableNotAvaliable.Error, "store")
 	}
 
-	return s.store.CreateDiningOrderTx(ctx, tableID, employeeID)
+	id, err := s.store.CreateDiningOrderTx(ctx, tableID, employeeID, covers, customerID)
+	if err != nil {
+		if db.GetSQLErrorCode(err) == db.ForeignKeyViolation {
+			return nil, errors.Wrap(api.ErrUnknownCustomer.Error, "store")
+		}
+		return nil, errors.Wrap(err, "store")
+	}
+
+	return id, nil
+}
+
+// Opens a takeaway order picked up under the contact phone number. Without a customerID the order is
+// linked to the customer with that number, if there is one.
+func (s *service) CreateTakeawayOrder(ctx context.Context, contact string, notes pgtype.Text, customerID pgtype.Int4, employeeID pgtype.UUID, employeeType sqlc.UserType) (*pgtype.UUID, error) {
+	if err := s.checkClockedIn(ctx, employeeID, employeeType); err != nil {
+		return nil, err
+	}
+
+	contact, customerID, err := s.findCustomer(ctx, contact, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := s.store.CreateTakeawayOrderTx(ctx, employeeID, customerID, sqlc.CreateTakeawayDetailsParams{Contact: contact, Notes: notes})
+	if err != nil {
+		if db.GetSQLErrorCode(err) == db.ForeignKeyViolation {
+			return nil, errors.Wrap(api.ErrUnknownCustomer.Error, "store")
+		}
+		return nil, errors.Wrap(err, "store")
+	}
+
+	return id, nil
+}
+
+// Opens a delivery order to the address for the contact phone number, driven by driverID. Without a
+// customerID the order is linked to the customer with that number, if there is one.
+func (s *service) CreateDeliveryOrder(ctx context.Context, contact string, address string, driverID pgtype.UUID, customerID pgtype.Int4, employeeID pgtype.UUID, employeeType sqlc.UserType) (*pgtype.UUID, error) {
+	if err := s.checkClockedIn(ctx, employeeID, employeeType); err != nil {
+		return nil, err
+	}
+
+	d, err := s.store.GetUserByID(ctx, driverID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, errors.Wrap(api.ErrInvalidDriver.Error, "store")
+		}
+		return nil, errors.Wrap(err, "store")
+	}
+
+	if d.Type != sqlc.UserTypeDriver || !d.Active {
+		return nil, errors.Wrap(api.ErrInvalidDriver.Error, "driver")
+	}
+
+	contact, customerID, err = s.findCustomer(ctx, contact, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := s.store.CreateDeliveryOrderTx(ctx, employeeID, customerID, sqlc.CreateDeliveryDetailsParams{Address: address, Contact: contact, DriverID: driverID})
+	if err != nil {
+		if db.GetSQLErrorCode(err) == db.ForeignKeyViolation {
+			return nil, errors.Wrap(api.ErrUnknownCustomer.Error, "store")
+		}
+		return nil, errors.Wrap(err, "store")
+	}
+
+	return id, nil
+}
+
+// Normalizes the contact phone number and, when no customer was picked, looks up the one it belongs to
+func (s *service) findCustomer(ctx context.Context, contact string, customerID pgtype.Int4) (string, pgtype.Int4, error) {
+	phone, ok := customer.NormalizePhone(contact)
+	if !ok {
+		return "", customerID, errors.Wrap(api.ErrInvalidPhone.Error, "contact")
+	}
+
+	if customerID.Valid {
+		return phone, customerID, nil
+	}
+
+	c, err := s.store.GetCustomerByPhone(ctx, phone)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return phone, customerID, nil
+		}
+		return "", customerID, errors.Wrap(err, "store")
+	}
+
+	return phone, pgtype.Int4{Int32: c.ID, Valid: true}, nil
+}
+
+// When the server requires it, waiters have to be clocked in to work on orders
+func (s *service) checkClockedIn(ctx context.Context, employeeID pgtype.UUID, employeeType sqlc.UserType) error {
+	if err := s.shifts.CheckClockedIn(ctx, employeeID, employeeType); err != nil {
+		return errors.Wrap(err, "shift")
+	}
+
+	return nil
 }
 
 func (s *service) IsOngoingOrder(ctx context.Context, orderID pgtype.UUID) (bool, error) {
@@ -51,37 +162,390 @@ func (s *service) IsOngoingOrder(ctx context.Context, orderID pgtype.UUID) (bool
 	return o.Status == sqlc.OrderStatusOngoing, nil
 }
 
-func (s *service) AddItemsToOrder(ctx context.Context, orderID pgtype.UUID, items []RequestItem) error {
-	// Check if the orderID is open and shit
-	ongoing, err := s.IsOngoingOrder(ctx, orderID)
+// Adds the items and combos to the order. A combo becomes one order item per slot so the kitchen
+// sees what was picked, with the combo price split between them.
+// Items containing allergens the guests declared are returned as conflicts, when the order blocks
+// them nothing is added and ErrAllergenConflict is returned with the conflicts.
+func (s *service) AddItemsToOrder(ctx context.Context, orderID pgtype.UUID, items []RequestItem, combos []RequestCombo, employeeID pgtype.UUID, employeeType sqlc.UserType) ([]AllergenConflict, error) {
+	if err := s.checkClockedIn(ctx, employeeID, employeeType); err != nil {
+		return nil, err
+	}
+
+	o, err := s.store.GetOrderByID(ctx, orderID)
 	if err != nil {
 		if errors.Is(err, db.ErrRecordNotFound) {
-			return errors.Wrap(api.ErrUnknownOrder.Error, "store")
+			return nil, errors.Wrap(api.ErrUnknownOrder.Error, "store")
 		}
-		return errors.Wrap(err, "store")
+		return nil, errors.Wrap(err, "store")
 	}
 
-	if !ongoing {
-		return errors.Wrap(api.ErrOrderNotOngoing.Error, "store")
+	if o.Status != sqlc.OrderStatusOngoing {
+		return nil, errors.Wrap(api.ErrOrderNotOngoing.Error, "store")
+	}
+
+	comboLines, comboItems, err := s.buildComboLines(ctx, orderID, combos)
+	if err != nil {
+		return nil, err
+	}
+
+	itemIDs := make([]int32, 0, len(items))
+	for _, i := range items {
+		itemIDs = append(itemIDs, int32(i.ItemID))
+	}
+	for _, c := range comboItems {
+		itemIDs = append(itemIDs, c.ItemIds...)
+	}
+
+	orderable, err := s.store.GetOrderableItems(ctx, itemIDs)
+	if err != nil {
+		return nil, errors.Wrap(err, "store")
+	}
+
+	// Items that ran out cant be ordered until they are restocked and items whose menus arent
+	// being served right now cant be ordered at all
+	prices := map[int32]float64{}
+	conflicts := []AllergenConflict{}
+	for _, i := range orderable {
+		if i.EightySixed {
+			return nil, errors.Wrap(api.ErrItemEightySixed.Error, "store")
+		}
+		if !i.Available {
+			return nil, errors.Wrap(api.ErrItemNotAvailable.Error, "store")
+		}
+		prices[i.ID] = i.CurrentPrice
+
+		if c := allergenConflict(i, o.Allergies); c != nil {
+			conflicts = append(conflicts, *c)
+		}
+	}
+
+	if len(conflicts) > 0 && o.BlockAllergens {
+		return conflicts, errors.Wrap(api.ErrAllergenConflict.Error, "store")
 	}
 
 	var arg sqlc.AddOrderItemsBulkParams
 	arg.OrderID = orderID
 
 	for _, i := range items {
+		price, ok := prices[int32(i.ItemID)]
+		if !ok {
+			return nil, errors.Wrap(api.ErrUnkownMenuItem.Error, "store")
+		}
+
 		arg.ItemIds = append(arg.ItemIds, int32(i.ItemID))
 		arg.Quantity = append(arg.Quantity, int32(i.Quantity))
 		arg.Notes = append(arg.Notes, i.Note)
+		// Priced when added so a happy hour ending later doesnt change what was ordered
+		arg.UnitPrices = append(arg.UnitPrices, price)
+	}
+
+	if err := s.store.AddOrderItemsTx(ctx, arg, comboLines, comboItems); err != nil {
+		return nil, errors.Wrap(err, "store")
+	}
+
+	return conflicts, nil
+}
+
+// Returns which of the declared allergies the item contains, nil when it is safe
+func allergenConflict(i sqlc.GetOrderableItemsRow, allergies []string) *AllergenConflict {
+	var found []string
+	for _, a := range i.Allergens {
+		if slices.Contains(allergies, a) {
+			found = append(found, a)
+		}
+	}
+
+	if len(found) == 0 {
+		return nil
+	}
+
+	return &AllergenConflict{ItemID: i.ID, Name: i.Name, Allergens: found}
+}
+
+// Records the allergies the guests at the ongoing order declared, block decides whether items
+// containing them are refused or only warned about when added
+func (s *service) SetOrderAllergies(ctx context.Context, orderID pgtype.UUID, allergies []string, block bool, employeeID pgtype.UUID, employeeType sqlc.UserType) error {
+	if err := s.checkClockedIn(ctx, employeeID, employeeType); err != nil {
+		return err
+	}
+
+	if allergies == nil {
+		allergies = []string{}
+	}
+
+	n, err := s.store.SetOrderAllergies(ctx, sqlc.SetOrderAllergiesParams{ID: orderID, Allergies: allergies, BlockAllergens: block})
+	if err != nil {
+		return errors.Wrap(err, "store")
+	}
+
+	if n == 0 {
+		return s.notOngoingError(ctx, orderID)
+	}
+
+	return nil
+}
+
+// Tells apart why an update of an ongoing order touched nothing, either there is no such order
+// or it is not ongoing anymore
+func (s *service) notOngoingError(ctx context.Context, orderID pgtype.UUID) error {
+	if _, err := s.store.GetOrderByID(ctx, orderID); err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return errors.Wrap(api.ErrUnknownOrder.Error, "store")
+		}
+		return errors.Wrap(err, "store")
 	}
+	return errors.Wrap(api.ErrOrderNotOngoing.Error, "store")
+}
 
-	if err := s.store.AddOrderItemsBulk(ctx, arg); err != nil {
+// Links the ongoing order to the customer it is for, an invalid customerID unlinks it
+func (s *service) SetOrderCustomer(ctx context.Context, orderID pgtype.UUID, customerID pgtype.Int4, employeeID pgtype.UUID, employeeType sqlc.UserType) error {
+	if err := s.checkClockedIn(ctx, employeeID, employeeType); err != nil {
 		return err
 	}
 
+	n, err := s.store.SetOrderCustomer(ctx, sqlc.SetOrderCustomerParams{ID: orderID, CustomerID: customerID})
+	if err != nil {
+		if db.GetSQLErrorCode(err) == db.ForeignKeyViolation {
+			return errors.Wrap(api.ErrUnknownCustomer.Error, "store")
+		}
+		return errors.Wrap(err, "store")
+	}
+
+	if n == 0 {
+		return s.notOngoingError(ctx, orderID)
+	}
+
 	return nil
 }
 
-func (s *service) UpdateOrderItem(ctx context.Context, orderID pgtype.UUID, orderItemID int, status sqlc.OrderItemStatus) error {
+// Completes the order and frees its table, the customer of the order earns loyalty points for what they paid
+func (s *service) CompleteOrder(ctx context.Context, orderID pgtype.UUID, employeeID pgtype.UUID, employeeType sqlc.UserType) (*Order, error) {
+	if err := s.checkClockedIn(ctx, employeeID, employeeType); err != nil {
+		return nil, err
+	}
+
+	o, err := s.store.CompleteOrderTx(ctx, orderID, config.Server().LoyaltyEarnRate)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			// Either there is no such order or it was already completed or cancelled
+			if _, err := s.store.GetOrderByID(ctx, orderID); err != nil {
+				if errors.Is(err, db.ErrRecordNotFound) {
+					return nil, errors.Wrap(api.ErrUnknownOrder.Error, "store")
+				}
+				return nil, errors.Wrap(err, "store")
+			}
+			return nil, errors.Wrap(api.ErrOrderNotOngoing.Error, "store")
+		}
+		return nil, errors.Wrap(err, "store")
+	}
+
+	return &Order{
+		ID:          o.ID,
+		Type:        o.Type,
+		EmployeeID:  o.EmployeeID,
+		Status:      o.Status,
+		TableID:     o.TableID,
+		CustomerID:  o.CustomerID,
+		Allergies:   o.Allergies,
+		CreatedAt:   o.CreatedAt,
+		CompletedAt: o.CompletedAt,
+	}, nil
+}
+
+func (s *service) GetBill(ctx context.Context, orderID pgtype.UUID) (*Bill, error) {
+	if _, err := s.store.GetOrderByID(ctx, orderID); err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, errors.Wrap(api.ErrUnknownOrder.Error, "store")
+		}
+		return nil, errors.Wrap(err, "store")
+	}
+
+	b, err := s.store.GetOrderBill(ctx, orderID)
+	if err != nil {
+		return nil, errors.Wrap(err, "store")
+	}
+
+	d, err := s.store.GetOrderDiscounts(ctx, orderID)
+	if err != nil {
+		return nil, errors.Wrap(err, "store")
+	}
+
+	net := math.Max(b.Subtotal-b.Discounts, 0)
+	rate := config.Server().TaxRate
+
+	bill := &Bill{
+		OrderID:       orderID,
+		Subtotal:      api.Round(b.Subtotal),
+		Discounts:     []Discount{},
+		DiscountTotal: api.Round(b.Discounts),
+		Net:           api.Round(net),
+		TaxRate:       rate,
+		Tax:           api.Round(net * rate),
+		Total:         api.Round(net + net*rate),
+	}
+
+	for _, discount := range d {
+		bill.Discounts = append(bill.Discounts, Discount{
+			ID:          discount.ID,
+			Type:        discount.Type,
+			Description: discount.Description,
+			Amount:      discount.Amount,
+			PromotionID: discount.PromotionID,
+			CreatedBy:   discount.CreatedBy,
+			CreatedAt:   discount.CreatedAt,
+		})
+	}
+
+	return bill, nil
+}
+
+// Checks that every slot of each combo has one of its options picked and splits the combo price
+// between the picked items. comboItems[i] are the order items that make up comboLines[i].
+func (s *service) buildComboLines(ctx context.Context, orderID pgtype.UUID, combos []RequestCombo) ([]sqlc.CreateOrderComboParams, []sqlc.AddOrderComboItemsParams, error) {
+	if len(combos) == 0 {
+		return nil, nil, nil
+	}
+
+	comboIDs := make([]int32, 0, len(combos))
+	for _, c := range combos {
+		comboIDs = append(comboIDs, c.ComboID)
+	}
+
+	rows, err := s.store.GetCombosByIDs(ctx, comboIDs)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "store")
+	}
+
+	slots, err := s.store.GetComboSlots(ctx, comboIDs)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "store")
+	}
+
+	options, err := s.store.GetComboSlotOptions(ctx, comboIDs)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "store")
+	}
+
+	byID := map[int32]sqlc.Combo{}
+	for _, c := range rows {
+		byID[c.ID] = c
+	}
+
+	slotsByCombo := map[int32][]sqlc.ComboSlot{}
+	for _, slot := range slots {
+		slotsByCombo[slot.ComboID] = append(slotsByCombo[slot.ComboID], slot)
+	}
+
+	type slotItem struct {
+		slotID int32
+		itemID int32
+	}
+
+	optionsBySlot := map[slotItem]sqlc.GetComboSlotOptionsRow{}
+	for _, o := range options {
+		optionsBySlot[slotItem{o.SlotID, o.ItemID}] = o
+	}
+
+	lines := []sqlc.CreateOrderComboParams{}
+	lineItems := []sqlc.AddOrderComboItemsParams{}
+	for _, c := range combos {
+		combo, ok := byID[c.ComboID]
+		if !ok {
+			return nil, nil, errors.Wrap(api.ErrUnknownCombo.Error, "store")
+		}
+
+		if !combo.Active {
+			return nil, nil, errors.Wrap(api.ErrComboNotActive.Error, "store")
+		}
+
+		picks := map[int32]int32{}
+		for _, choice := range c.Choices {
+			picks[choice.SlotID] = choice.ItemID
+		}
+
+		comboSlots := slotsByCombo[combo.ID]
+		if len(picks) != len(comboSlots) {
+			return nil, nil, errors.Wrap(api.ErrInvalidComboChoice.Error, "store")
+		}
+
+		itemIDs := []int32{}
+		listPrices := []float64{}
+		upcharges := []float64{}
+		notes := []string{}
+		for _, slot := range comboSlots {
+			option, ok := optionsBySlot[slotItem{slot.ID, picks[slot.ID]}]
+			if !ok {
+				return nil, nil, errors.Wrap(api.ErrInvalidComboChoice.Error, "store")
+			}
+
+			itemIDs = append(itemIDs, option.ItemID)
+			listPrices = append(listPrices, option.Price)
+			upcharges = append(upcharges, option.Upcharge)
+			notes = append(notes, c.Note)
+		}
+
+		unitPrices := allocateComboPrice(combo.Price, listPrices, upcharges)
+
+		var unitPrice float64
+		for _, p := range unitPrices {
+			unitPrice += p
+		}
+
+		lines = append(lines, sqlc.CreateOrderComboParams{
+			OrderID:   orderID,
+			ComboID:   combo.ID,
+			Quantity:  int32(c.Quantity),
+			UnitPrice: api.Round(unitPrice),
+		})
+
+		lineItems = append(lineItems, sqlc.AddOrderComboItemsParams{
+			OrderID:    orderID,
+			Quantity:   int32(c.Quantity),
+			ItemIds:    itemIDs,
+			Notes:      notes,
+			UnitPrices: unitPrices,
+		})
+	}
+
+	return lines, lineItems, nil
+}
+
+// Splits the combo price between its items in proportion to what they cost on their own, each item
+// then gets its own upcharge on top. Rounding is settled on the last item so the parts add up.
+func allocateComboPrice(price float64, listPrices []float64, upcharges []float64) []float64 {
+	var total float64
+	for _, p := range listPrices {
+		total += p
+	}
+
+	shares := make([]float64, len(listPrices))
+	var allocated float64
+	for i, p := range listPrices {
+		if i == len(listPrices)-1 {
+			shares[i] = api.Round(price - allocated)
+			break
+		}
+
+		if total > 0 {
+			shares[i] = api.Round(price * p / total)
+		} else {
+			shares[i] = api.Round(price / float64(len(listPrices)))
+		}
+		allocated += shares[i]
+	}
+
+	for i := range shares {
+		shares[i] = api.Round(shares[i] + upcharges[i])
+	}
+
+	return shares
+}
+
+func (s *service) UpdateOrderItem(ctx context.Context, orderID pgtype.UUID, orderItemID int, status sqlc.OrderItemStatus, employeeID pgtype.UUID, employeeType sqlc.UserType) error {
+	if err := s.checkClockedIn(ctx, employeeID, employeeType); err != nil {
+		return err
+	}
+
 	// Check if the order is valid
 	ongoing, err := s.IsOngoingOrder(ctx, orderID)
 	if err != nil {
@@ -111,20 +575,25 @@ func (s *service) UpdateOrderItem(ctx context.Context, orderID pgtype.UUID, orde
 		Status:  status,
 	}
 
-	return s.store.UpdateOrderItemStatus(ctx, arg)
+	// The ingredients are used up once the kitchen starts on the item (or it gets served straight away)
+	deplete := status == sqlc.OrderItemStatusPreparing || status == sqlc.OrderItemStatusServed
+
+	return s.store.UpdateOrderItemStatusTx(ctx, arg, deplete)
 }
 
-func (s *service) GetTables(ctx context.Context, status sqlc.TableStatus) ([]Table, error) {
-	t, err := s.store.GetTables(ctx, status)
+// Returns a page of the tables with the status and how many there are in total
+func (s *service) GetTables(ctx context.Context, status sqlc.TableStatus, limit int32, offset int32) ([]Table, int, error) {
+	t, err := s.store.GetTables(ctx, sqlc.GetTablesParams{Status: status, Limit: limit, Offset: offset})
 	if err != nil {
-		return []Table{}, errors.Wrap(err, "store")
+		return []Table{}, 0, errors.Wrap(err, "store")
 	}
 
-	if len(t) == 0 {
-		return []Table{}, nil
+	total, err := s.store.CountTables(ctx, status)
+	if err != nil {
+		return []Table{}, 0, errors.Wrap(err, "store")
 	}
 
-	var tables []Table
+	tables := []Table{}
 	for _, table := range t {
 		tables = append(tables, Table{
 			ID:       table.ID,
@@ -134,20 +603,38 @@ func (s *service) GetTables(ctx context.Context, status sqlc.TableStatus) ([]Tab
 		})
 	}
 
-	return tables, nil
+	return tables, int(total), nil
 }
 
-func (s *service) GetOrders(ctx context.Context, status sqlc.OrderStatus, orderType sqlc.OrderType) ([]Order, error) {
-	o, err := s.store.GetOrders(ctx, sqlc.GetOrdersParams{Status: status, Type: orderType})
+// Returns a page of the orders newest first and how many there are in total. When a cursor is given
+// the page starts right after it and offset is ignored, nextCursor is where the page after this one starts.
+func (s *service) GetOrders(ctx context.Context, status sqlc.OrderStatus, orderType sqlc.OrderType, limit int32, offset int32, cursor *api.Cursor) (orders []Order, total int, nextCursor string, err error) {
+	var o []sqlc.Order
+	if cursor != nil {
+		arg := sqlc.GetOrdersBeforeParams{
+			Status:          status,
+			Type:            orderType,
+			Limit:           limit,
+			CursorCreatedAt: pgtype.Timestamp{Time: cursor.Time, Valid: true},
+		}
+		if err := arg.CursorID.Scan(cursor.ID); err != nil {
+			return []Order{}, 0, "", errors.Wrap(api.ErrInvalidCursor.Error, "cursor")
+		}
+
+		o, err = s.store.GetOrdersBefore(ctx, arg)
+	} else {
+		o, err = s.store.GetOrders(ctx, sqlc.GetOrdersParams{Status: status, Type: orderType, Limit: limit, Offset: offset})
+	}
 	if err != nil {
-		return []Order{}, errors.Wrap(err, "store")
+		return []Order{}, 0, "", errors.Wrap(err, "store")
 	}
 
-	if len(o) == 0 {
-		return []Order{}, nil
+	count, err := s.store.CountOrders(ctx, sqlc.CountOrdersParams{Status: status, Type: orderType})
+	if err != nil {
+		return []Order{}, 0, "", errors.Wrap(err, "store")
 	}
 
-	var orders []Order
+	orders = []Order{}
 	for _, order := range o {
 		orders = append(orders, Order{
 			ID:          order.ID,
@@ -155,10 +642,61 @@ func (s *service) GetOrders(ctx context.Context, status sqlc.OrderStatus, orderT
 			EmployeeID:  order.EmployeeID,
 			Status:      order.Status,
 			TableID:     order.TableID,
+			CustomerID:  order.CustomerID,
+			Allergies:   order.Allergies,
 			CreatedAt:   order.CreatedAt,
 			CompletedAt: order.CompletedAt,
 		})
 	}
 
-	return orders, nil
+	// A full page means there could be more after it
+	if len(o) > 0 && len(o) == int(limit) {
+		last := o[len(o)-1]
+		nextCursor = api.Cursor{Time: last.CreatedAt.Time, ID: last.ID.String()}.Encode()
+	}
+
+	return orders, int(count), nextCursor, nil
+}
+
+// Returns a page of the orders matching the search and how many match in total
+func (s *service) SearchOrders(ctx context.Context, arg sqlc.SearchOrdersParams) ([]OrderSummary, int, error) {
+	rows, err := s.store.SearchOrders(ctx, arg)
+	if err != nil {
+		return []OrderSummary{}, 0, errors.Wrap(err, "store")
+	}
+
+	total, err := s.store.CountSearchOrders(ctx, sqlc.CountSearchOrdersParams{
+		PeriodStart: arg.PeriodStart,
+		PeriodEnd:   arg.PeriodEnd,
+		Status:      arg.Status,
+		Type:        arg.Type,
+		TableID:     arg.TableID,
+		EmployeeID:  arg.EmployeeID,
+		ItemID:      arg.ItemID,
+		MinTotal:    arg.MinTotal,
+		MaxTotal:    arg.MaxTotal,
+		CustomerID:  arg.CustomerID,
+	})
+	if err != nil {
+		return []OrderSummary{}, 0, errors.Wrap(err, "store")
+	}
+
+	orders := []OrderSummary{}
+	for _, o := range rows {
+		orders = append(orders, OrderSummary{
+			ID:           o.ID,
+			Type:         o.Type,
+			EmployeeID:   o.EmployeeID,
+			EmployeeName: o.EmployeeName,
+			Status:       o.Status,
+			TableID:      o.TableID,
+			Covers:       o.Covers,
+			CustomerID:   o.CustomerID,
+			Total:        api.Round(o.Total),
+			CreatedAt:    o.CreatedAt,
+			CompletedAt:  o.CompletedAt,
+		})
+	}
+
+	return orders, int(total), nil
 }
@@ -0,0 +1,71 @@
+package dining
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/pdridh/k-line/api"
+	"github.com/pdridh/k-line/db/sqlc"
+)
+
+// Nobody is clocked in
+type clockedOut struct{}
+
+func (clockedOut) CheckClockedIn(ctx context.Context, userID pgtype.UUID, userType sqlc.UserType) error {
+	return api.ErrNotClockedIn.Error
+}
+
+// Every write a waiter can do is refused before the store is touched, the nil store would panic otherwise
+func TestWritesNeedClockIn(t *testing.T) {
+	s := NewService(validator.New(), nil, clockedOut{})
+	ctx := context.Background()
+
+	var id pgtype.UUID
+	waiter := sqlc.UserTypeWaiter
+
+	writes := []struct {
+		name string
+		f    func() error
+	}{
+		{"create order", func() error {
+			_, err := s.CreateOrder(ctx, pgtype.Text{}, pgtype.Int2{}, pgtype.Int4{}, id, waiter)
+			return err
+		}},
+		{"create takeaway order", func() error {
+			_, err := s.CreateTakeawayOrder(ctx, "", pgtype.Text{}, pgtype.Int4{}, id, waiter)
+			return err
+		}},
+		{"create delivery order", func() error {
+			_, err := s.CreateDeliveryOrder(ctx, "", "", id, pgtype.Int4{}, id, waiter)
+			return err
+		}},
+		{"add items", func() error {
+			_, err := s.AddItemsToOrder(ctx, id, nil, nil, id, waiter)
+			return err
+		}},
+		{"set allergies", func() error {
+			return s.SetOrderAllergies(ctx, id, nil, false, id, waiter)
+		}},
+		{"set customer", func() error {
+			return s.SetOrderCustomer(ctx, id, pgtype.Int4{}, id, waiter)
+		}},
+		{"complete order", func() error {
+			_, err := s.CompleteOrder(ctx, id, id, waiter)
+			return err
+		}},
+		{"update item", func() error {
+			return s.UpdateOrderItem(ctx, id, 1, sqlc.OrderItemStatusServed, id, waiter)
+		}},
+	}
+
+	for _, tt := range writes {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.f(); !errors.Is(err, api.ErrNotClockedIn.Error) {
+				t.Errorf("got error %v, want %v", err, api.ErrNotClockedIn.Error)
+			}
+		})
+	}
+}
@@ -1,7 +1,10 @@
 package dining
 
 import (
+	"time"
+
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/pdridh/k-line/api"
 	"github.com/pdridh/k-line/db/sqlc"
 )
 
@@ -11,6 +14,95 @@ type RequestItem struct {
 	Note     string `json:"notes"`
 }
 
+// A combo ordered as one line, Choices picks an item for every slot of the combo
+type RequestCombo struct {
+	ComboID  int32         `json:"combo_id" validate:"required"`
+	Quantity int           `json:"quantity" validate:"required,min=1"`
+	Choices  []ComboChoice `json:"choices" validate:"required,unique=SlotID,dive"`
+	Note     string        `json:"notes"` // Passed on to every item of the combo
+}
+
+type ComboChoice struct {
+	SlotID int32 `json:"slot_id" validate:"required"`
+	ItemID int32 `json:"item_id" validate:"required"`
+}
+
+type TableFilters struct {
+	Status sqlc.TableStatus `json:"status" validate:"required,oneof=available occupied closed"`
+	Page   int32            `json:"page"` // The request is sent as page but converted to offset for db
+	Limit  int32            `json:"limit"`
+}
+
+func (f *TableFilters) Validate(maxLimit int32, defaultLimit int32) {
+	api.ClampPage(&f.Limit, &f.Page, maxLimit, defaultLimit)
+}
+
+type OrderFilters struct {
+	Cursor string `json:"cursor"` // next_cursor of the previous page, page is ignored when set
+	Page   int32  `json:"page"`   // The request is sent as page but converted to offset for db
+	Limit  int32  `json:"limit"`
+}
+
+func (f *OrderFilters) Validate(maxLimit int32, defaultLimit int32) {
+	api.ClampPage(&f.Limit, &f.Page, maxLimit, defaultLimit)
+}
+
+// Every filter is optional, orders are newest first unless sorted otherwise
+type OrderSearchFilters struct {
+	From       string  `json:"from"` // Inclusive day in api.DateLayout
+	To         string  `json:"to"`   // Inclusive day in api.DateLayout
+	Status     string  `json:"status" validate:"omitempty,oneof=ongoing completed cancelled"`
+	Type       string  `json:"type" validate:"omitempty,oneof=dining delivery takeaway"`
+	TableID    string  `json:"table_id"`
+	EmployeeID string  `json:"employee_id" validate:"omitempty,uuid"`
+	ItemID     int32   `json:"item_id"` // Only orders that have this menu item on them
+	CustomerID int32   `json:"customer_id"`
+	MinTotal   float64 `json:"min_total" validate:"gte=0"`
+	MaxTotal   float64 `json:"max_total" validate:"gte=0"` // 0 means no upper bound
+	Sort       string  `json:"sort" validate:"oneof=created_at completed_at total"`
+	Order      string  `json:"order" validate:"oneof=asc desc"`
+	Page       int32   `json:"page"` // The request is sent as page but converted to offset for db
+	Limit      int32   `json:"limit"`
+}
+
+func (f *OrderSearchFilters) Validate(maxLimit int32, defaultLimit int32) {
+	api.ClampPage(&f.Limit, &f.Page, maxLimit, defaultLimit)
+
+	if f.Sort == "" {
+		f.Sort = "created_at"
+	}
+
+	if f.Order == "" {
+		f.Order = "desc"
+	}
+}
+
+// Parses From and To into [start, end) so the whole "to" day is included, a missing bound is left open
+func (f *OrderSearchFilters) Period() (pgtype.Timestamp, pgtype.Timestamp, error) {
+	var start, end pgtype.Timestamp
+	if f.From != "" {
+		t, err := time.Parse(api.DateLayout, f.From)
+		if err != nil {
+			return start, end, api.ErrInvalidPeriod.Error
+		}
+		start = pgtype.Timestamp{Time: t, Valid: true}
+	}
+
+	if f.To != "" {
+		t, err := time.Parse(api.DateLayout, f.To)
+		if err != nil {
+			return start, end, api.ErrInvalidPeriod.Error
+		}
+		end = pgtype.Timestamp{Time: t.AddDate(0, 0, 1), Valid: true}
+	}
+
+	if start.Valid && end.Valid && !start.Time.Before(end.Time) {
+		return start, end, api.ErrInvalidPeriod.Error
+	}
+
+	return start, end, nil
+}
+
 type Table struct {
 	ID       string           `json:"id"`
 	Capacity int16            `json:"capacity"`
@@ -24,6 +116,52 @@ type Order struct {
 	EmployeeID  pgtype.UUID      `json:"employee_id"`
 	Status      sqlc.OrderStatus `json:"status"`
 	TableID     pgtype.Text      `json:"table_id"`
+	CustomerID  pgtype.Int4      `json:"customer_id"`
+	Allergies   []string         `json:"allergies"` // Declared by the guests
 	CreatedAt   pgtype.Timestamp `json:"created_at"`
 	CompletedAt pgtype.Timestamp `json:"completed_at"`
 }
+
+// An order as it is listed in a search, Total leaves out cancelled items
+type OrderSummary struct {
+	ID           pgtype.UUID      `json:"id"`
+	Type         sqlc.OrderType   `json:"type"`
+	EmployeeID   pgtype.UUID      `json:"employee_id"`
+	EmployeeName string           `json:"employee_name"`
+	Status       sqlc.OrderStatus `json:"status"`
+	TableID      pgtype.Text      `json:"table_id"`
+	Covers       pgtype.Int2      `json:"covers"`
+	CustomerID   pgtype.Int4      `json:"customer_id"`
+	Total        float64          `json:"total"`
+	CreatedAt    pgtype.Timestamp `json:"created_at"`
+	CompletedAt  pgtype.Timestamp `json:"completed_at"`
+}
+
+// What the order comes to, tax is added after the discounts are taken off
+type Bill struct {
+	OrderID       pgtype.UUID `json:"order_id"`
+	Subtotal      float64     `json:"subtotal"` // Leaves out cancelled items
+	Discounts     []Discount  `json:"discounts"`
+	DiscountTotal float64     `json:"discount_total"`
+	Net           float64     `json:"net"`
+	TaxRate       float64     `json:"tax_rate"`
+	Tax           float64     `json:"tax"`
+	Total         float64     `json:"total"`
+}
+
+type Discount struct {
+	ID          int64             `json:"id"`
+	Type        sqlc.DiscountType `json:"type"`
+	Description string            `json:"description"`
+	Amount      float64           `json:"amount"`
+	PromotionID pgtype.Int4       `json:"promotion_id"`
+	CreatedBy   pgtype.UUID       `json:"created_by"`
+	CreatedAt   pgtype.Timestamp  `json:"created_at"`
+}
+
+// An item added to an order that contains allergens the guests declared
+type AllergenConflict struct {
+	ItemID    int32    `json:"item_id"`
+	Name      string   `json:"name"`
+	Allergens []string `json:"allergens"`
+}
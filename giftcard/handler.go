@@ -0,0 +1,209 @@
+package giftcard
+
+import (
+	"net/http"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/pdridh/k-line/api"
+	"github.com/pkg/errors"
+)
+
+type handler struct {
+	Service *service
+}
+
+func NewHandler(s *service) *handler {
+	return &handler{
+		Service: s,
+	}
+}
+
+func writeGiftCardError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, api.ErrUnknownGiftCard.Error):
+		api.WriteNotFoundError(w, r)
+	case errors.Is(err, api.ErrGiftCardExpired.Error):
+		api.WriteError(w, r, http.StatusConflict, api.ErrGiftCardExpired, nil)
+	case errors.Is(err, api.ErrGiftCardBalance.Error):
+		api.WriteError(w, r, http.StatusConflict, api.ErrGiftCardBalance, nil)
+	case errors.Is(err, api.ErrGiftCardCodeConflict.Error):
+		api.WriteError(w, r, http.StatusConflict, api.ErrGiftCardCodeConflict, nil)
+	case errors.Is(err, api.ErrInvalidGiftCardCode.Error):
+		api.WriteError(w, r, http.StatusBadRequest, api.ErrInvalidGiftCardCode, nil)
+	case errors.Is(err, api.ErrUnknownCustomer.Error):
+		api.WriteError(w, r, http.StatusBadRequest, api.ErrUnknownCustomer, nil)
+	case errors.Is(err, api.ErrUnknownOrder.Error):
+		api.WriteError(w, r, http.StatusBadRequest, api.ErrUnknownOrder, nil)
+	case errors.Is(err, api.ErrUnknownDrawerSession.Error):
+		api.WriteError(w, r, http.StatusBadRequest, api.ErrUnknownDrawerSession, nil)
+	case errors.Is(err, api.ErrDrawerSessionClosed.Error):
+		api.WriteError(w, r, http.StatusConflict, api.ErrDrawerSessionClosed, nil)
+	case errors.Is(err, api.ErrOrderNotOngoing.Error):
+		api.WriteError(w, r, http.StatusConflict, api.ErrOrderNotOngoing, nil)
+	case errors.Is(err, api.ErrTenderExceedsBill.Error):
+		api.WriteError(w, r, http.StatusConflict, api.ErrTenderExceedsBill, nil)
+	default:
+		api.WriteInternalError(w, r)
+	}
+}
+
+func (h *handler) IssueCard() http.HandlerFunc {
+	type RequestPayload struct {
+		Code       string      `json:"code"` // Optional, printed on the card
+		Amount     float64     `json:"amount" validate:"gt=0"`
+		CustomerID pgtype.Int4 `json:"customer_id"`
+		SessionID  int64       `json:"session_id" validate:"required"` // Drawer the cash paid for the card goes into
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var p RequestPayload
+		if err := api.ParseJSON(r, &p); err != nil {
+			api.WriteBadRequestError(w, r)
+			return
+		}
+
+		if err := h.Service.Validate.Struct(p); err != nil {
+			api.WriteValidationError(w, r, err)
+			return
+		}
+
+		var userID pgtype.UUID
+		if err := userID.Scan(api.CurrentUserID(r)); err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		c, err := h.Service.IssueCard(r.Context(), p.Code, p.Amount, p.CustomerID, p.SessionID, userID)
+		if err != nil {
+			writeGiftCardError(w, r, err)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusCreated, "Successfully issued gift card", c)
+	}
+}
+
+func (h *handler) GetCards() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var filters GiftCardFilters
+
+		api.ParseQueryParams(r.URL.Query(), &filters)
+		filters.Validate(100, 50)
+
+		c, total, err := h.Service.GetCards(r.Context(), filters.CustomerID, filters.Limit, (filters.Page-1)*filters.Limit)
+		if err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		meta := api.CalculatePaginationMeta(total, int(filters.Page), int(filters.Limit))
+		api.WriteSuccess(w, r, http.StatusOK, "Retrieval successful", api.NewPaginatedResponse(c, meta))
+	}
+}
+
+// Checks the balance and expiry of a card
+func (h *handler) GetCard() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		c, err := h.Service.GetCard(r.Context(), r.PathValue("code"))
+		if err != nil {
+			writeGiftCardError(w, r, err)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Retrieval successful", c)
+	}
+}
+
+func (h *handler) GetTransactions() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var filters TransactionFilters
+
+		api.ParseQueryParams(r.URL.Query(), &filters)
+		filters.Validate(100, 50)
+
+		t, total, err := h.Service.GetTransactions(r.Context(), r.PathValue("code"), filters.Limit, (filters.Page-1)*filters.Limit)
+		if err != nil {
+			writeGiftCardError(w, r, err)
+			return
+		}
+
+		meta := api.CalculatePaginationMeta(total, int(filters.Page), int(filters.Limit))
+		api.WriteSuccess(w, r, http.StatusOK, "Retrieval successful", api.NewPaginatedResponse(t, meta))
+	}
+}
+
+func (h *handler) ReloadCard() http.HandlerFunc {
+	type RequestPayload struct {
+		Amount    float64 `json:"amount" validate:"gt=0"`
+		SessionID int64   `json:"session_id" validate:"required"` // Drawer the cash paid for the reload goes into
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var p RequestPayload
+		if err := api.ParseJSON(r, &p); err != nil {
+			api.WriteBadRequestError(w, r)
+			return
+		}
+
+		if err := h.Service.Validate.Struct(p); err != nil {
+			api.WriteValidationError(w, r, err)
+			return
+		}
+
+		var userID pgtype.UUID
+		if err := userID.Scan(api.CurrentUserID(r)); err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		c, err := h.Service.ReloadCard(r.Context(), r.PathValue("code"), p.Amount, p.SessionID, userID)
+		if err != nil {
+			writeGiftCardError(w, r, err)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Successfully reloaded gift card", c)
+	}
+}
+
+// Pays part or all of an order with the card, recorded as a gift card tender in the drawer session
+func (h *handler) RedeemCard() http.HandlerFunc {
+	type RequestPayload struct {
+		SessionID int64   `json:"session_id" validate:"required"`
+		OrderID   string  `json:"order_id" validate:"required,uuid"`
+		Amount    float64 `json:"amount" validate:"gt=0"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var p RequestPayload
+		if err := api.ParseJSON(r, &p); err != nil {
+			api.WriteBadRequestError(w, r)
+			return
+		}
+
+		if err := h.Service.Validate.Struct(p); err != nil {
+			api.WriteValidationError(w, r, err)
+			return
+		}
+
+		var orderID pgtype.UUID
+		if err := orderID.Scan(p.OrderID); err != nil {
+			api.WriteBadRequestError(w, r)
+			return
+		}
+
+		var userID pgtype.UUID
+		if err := userID.Scan(api.CurrentUserID(r)); err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		red, err := h.Service.RedeemCard(r.Context(), r.PathValue("code"), p.SessionID, orderID, p.Amount, userID)
+		if err != nil {
+			writeGiftCardError(w, r, err)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusCreated, "Successfully redeemed gift card", red)
+	}
+}
@@ -0,0 +1,296 @@
+package giftcard
+
+import (
+	"context"
+	"crypto/rand"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/pdridh/k-line/api"
+	"github.com/pdridh/k-line/config"
+	"github.com/pdridh/k-line/db"
+	"github.com/pdridh/k-line/db/sqlc"
+	"github.com/pkg/errors"
+)
+
+// Letters and digits that cant be mistaken for each other when read out (no I, O, 0 or 1)
+const codeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+const codeLength = 16
+
+type service struct {
+	Validate *validator.Validate
+	store    db.Store
+}
+
+func NewService(v *validator.Validate, s db.Store) *service {
+	return &service{
+		Validate: v,
+		store:    s,
+	}
+}
+
+// Issues a card loaded with amount, paid for in cash into the drawer session. Without a code (like one printed
+// on a physical card) a random one is made.
+func (s *service) IssueCard(ctx context.Context, code string, amount float64, customerID pgtype.Int4, sessionID int64, issuedBy pgtype.UUID) (*GiftCard, error) {
+	if code == "" {
+		generated, err := generateCode()
+		if err != nil {
+			return nil, errors.Wrap(err, "code")
+		}
+		code = generated
+	}
+
+	code, ok := normalizeCode(code)
+	if !ok {
+		return nil, errors.Wrap(api.ErrInvalidGiftCardCode.Error, "code")
+	}
+
+	if err := s.checkSessionOpen(ctx, sessionID); err != nil {
+		return nil, err
+	}
+
+	amount = api.Round(amount)
+	c, err := s.store.IssueGiftCardTx(ctx, sqlc.CreateGiftCardParams{
+		Code:       code,
+		Balance:    amount,
+		ExpiresAt:  expiry(),
+		CustomerID: customerID,
+		IssuedBy:   issuedBy,
+	}, sqlc.CreateCashMovementParams{SessionID: sessionID, Amount: amount, Reason: "Gift card sold", CreatedBy: issuedBy})
+	if err != nil {
+		if errors.Is(err, db.ErrDrawerClosed) {
+			return nil, errors.Wrap(api.ErrDrawerSessionClosed.Error, "store")
+		}
+		switch db.GetSQLErrorCode(err) {
+		case db.UniqueViolation:
+			return nil, errors.Wrap(api.ErrGiftCardCodeConflict.Error, "store")
+		case db.ForeignKeyViolation:
+			return nil, errors.Wrap(api.ErrUnknownCustomer.Error, "store")
+		}
+		return nil, errors.Wrap(err, "store")
+	}
+
+	return newGiftCard(c), nil
+}
+
+// Returns a page of the cards newest first and how many there are in total
+func (s *service) GetCards(ctx context.Context, customerID int32, limit int32, offset int32) ([]GiftCard, int, error) {
+	rows, err := s.store.GetGiftCards(ctx, sqlc.GetGiftCardsParams{Limit: limit, Offset: offset, CustomerID: customerID})
+	if err != nil {
+		return []GiftCard{}, 0, errors.Wrap(err, "store")
+	}
+
+	total, err := s.store.CountGiftCards(ctx, customerID)
+	if err != nil {
+		return []GiftCard{}, 0, errors.Wrap(err, "store")
+	}
+
+	cards := []GiftCard{}
+	for _, c := range rows {
+		cards = append(cards, *newGiftCard(c))
+	}
+
+	return cards, int(total), nil
+}
+
+func (s *service) GetCard(ctx context.Context, code string) (*GiftCard, error) {
+	c, err := s.getCard(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	return newGiftCard(c), nil
+}
+
+// Returns a page of the transactions of the card newest first and how many it has in total
+func (s *service) GetTransactions(ctx context.Context, code string, limit int32, offset int32) ([]Transaction, int, error) {
+	c, err := s.getCard(ctx, code)
+	if err != nil {
+		return []Transaction{}, 0, err
+	}
+
+	rows, err := s.store.GetGiftCardTransactions(ctx, sqlc.GetGiftCardTransactionsParams{GiftCardID: c.ID, Limit: limit, Offset: offset})
+	if err != nil {
+		return []Transaction{}, 0, errors.Wrap(err, "store")
+	}
+
+	total, err := s.store.CountGiftCardTransactions(ctx, c.ID)
+	if err != nil {
+		return []Transaction{}, 0, errors.Wrap(err, "store")
+	}
+
+	transactions := []Transaction{}
+	for _, t := range rows {
+		transactions = append(transactions, *newTransaction(t))
+	}
+
+	return transactions, int(total), nil
+}
+
+// Adds amount to the card, paid for in cash into the drawer session, and starts its validity over.
+// Expired cards cant be reloaded.
+func (s *service) ReloadCard(ctx context.Context, code string, amount float64, sessionID int64, createdBy pgtype.UUID) (*GiftCard, error) {
+	c, err := s.getCard(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	if expired(c) {
+		return nil, errors.Wrap(api.ErrGiftCardExpired.Error, "expired")
+	}
+
+	if err := s.checkSessionOpen(ctx, sessionID); err != nil {
+		return nil, err
+	}
+
+	amount = api.Round(amount)
+	c, err = s.store.ReloadGiftCardTx(ctx, sqlc.CreditGiftCardParams{Amount: amount, ExpiresAt: expiry(), ID: c.ID},
+		sqlc.CreateCashMovementParams{SessionID: sessionID, Amount: amount, Reason: "Gift card reloaded", CreatedBy: createdBy})
+	if err != nil {
+		if errors.Is(err, db.ErrDrawerClosed) {
+			return nil, errors.Wrap(api.ErrDrawerSessionClosed.Error, "store")
+		}
+		// It expired between the check and the update
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, errors.Wrap(api.ErrGiftCardExpired.Error, "store")
+		}
+		return nil, errors.Wrap(err, "store")
+	}
+
+	return newGiftCard(c), nil
+}
+
+// Pays amount of the order with the card as a tender in the drawer session, whatever is left stays on the card.
+// The order has to be ongoing and amount cant be more than is left to pay on it.
+func (s *service) RedeemCard(ctx context.Context, code string, sessionID int64, orderID pgtype.UUID, amount float64, createdBy pgtype.UUID) (*Redemption, error) {
+	c, err := s.getCard(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	if expired(c) {
+		return nil, errors.Wrap(api.ErrGiftCardExpired.Error, "expired")
+	}
+
+	amount = api.Round(amount)
+	if c.Balance < amount {
+		return nil, errors.Wrap(api.ErrGiftCardBalance.Error, "balance")
+	}
+
+	if err := s.checkSessionOpen(ctx, sessionID); err != nil {
+		return nil, err
+	}
+
+	t, err := s.store.RedeemGiftCardTx(ctx, c.ID, sqlc.CreateTenderParams{
+		SessionID: sessionID,
+		OrderID:   orderID,
+		Amount:    amount,
+		CreatedBy: createdBy,
+	}, config.Server().TaxRate)
+	if err != nil {
+		switch {
+		// Another redemption spent the balance first
+		case errors.Is(err, db.ErrInsufficientBalance):
+			return nil, errors.Wrap(api.ErrGiftCardBalance.Error, "store")
+		// The drawer was closed after it was checked above
+		case errors.Is(err, db.ErrDrawerClosed):
+			return nil, errors.Wrap(api.ErrDrawerSessionClosed.Error, "store")
+		case errors.Is(err, db.ErrOrderNotOngoing):
+			return nil, errors.Wrap(api.ErrOrderNotOngoing.Error, "store")
+		case errors.Is(err, db.ErrTenderExceedsBill):
+			return nil, errors.Wrap(api.ErrTenderExceedsBill.Error, "store")
+		case errors.Is(err, db.ErrRecordNotFound):
+			return nil, errors.Wrap(api.ErrUnknownOrder.Error, "store")
+		}
+		return nil, errors.Wrap(err, "store")
+	}
+
+	return &Redemption{
+		Code:     formatCode(c.Code),
+		OrderID:  orderID,
+		TenderID: t.TenderID.Int64,
+		Amount:   -t.Amount,
+		Balance:  t.BalanceAfter,
+	}, nil
+}
+
+// Fails unless the drawer session exists and is still open, the store checks again once it is locked
+func (s *service) checkSessionOpen(ctx context.Context, sessionID int64) error {
+	d, err := s.store.GetDrawerSessionByID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return errors.Wrap(api.ErrUnknownDrawerSession.Error, "store")
+		}
+		return errors.Wrap(err, "store")
+	}
+
+	if d.ClosedAt.Valid {
+		return errors.Wrap(api.ErrDrawerSessionClosed.Error, "closed")
+	}
+
+	return nil
+}
+
+func (s *service) getCard(ctx context.Context, code string) (sqlc.GiftCard, error) {
+	normalized, ok := normalizeCode(code)
+	if !ok {
+		return sqlc.GiftCard{}, errors.Wrap(api.ErrUnknownGiftCard.Error, "code")
+	}
+
+	c, err := s.store.GetGiftCardByCode(ctx, normalized)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return c, errors.Wrap(api.ErrUnknownGiftCard.Error, "store")
+		}
+		return c, errors.Wrap(err, "store")
+	}
+
+	return c, nil
+}
+
+// When a card issued or reloaded now expires, never when the validity is 0
+func expiry() pgtype.Timestamp {
+	days := config.Server().GiftCardValidity
+	if days == 0 {
+		return pgtype.Timestamp{}
+	}
+
+	return pgtype.Timestamp{Time: time.Now().UTC().AddDate(0, 0, days), Valid: true}
+}
+
+func expired(c sqlc.GiftCard) bool {
+	return c.ExpiresAt.Valid && !c.ExpiresAt.Time.After(time.Now().UTC())
+}
+
+func generateCode() (string, error) {
+	b := make([]byte, codeLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	// 256 is a multiple of the 32 letters so every one is as likely
+	for i := range b {
+		b[i] = codeAlphabet[int(b[i])%len(codeAlphabet)]
+	}
+
+	return string(b), nil
+}
+
+// Codes are matched however they are typed, in any case and with or without the dashes and spaces
+func normalizeCode(code string) (string, bool) {
+	code = strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+	if len(code) < 6 || len(code) > 32 {
+		return "", false
+	}
+
+	for _, r := range code {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return "", false
+		}
+	}
+
+	return code, true
+}
@@ -0,0 +1,29 @@
+package giftcard
+
+import "testing"
+
+func TestNormalizeCode(t *testing.T) {
+	tests := []struct {
+		code string
+		want string
+		ok   bool
+	}{
+		{"ABCD1234", "ABCD1234", true},
+		{"abcd1234", "ABCD1234", true},
+		{"ABCD-1234-EF56", "ABCD1234EF56", true},
+		{"abcd 1234 ef56", "ABCD1234EF56", true},
+		{"ABC12", "", false},
+		{"ABC-12", "", false},
+		{"ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456", "", false},
+		{"ABCD_1234", "", false},
+		{"ÄBCD1234", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := normalizeCode(tt.code)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("normalizeCode(%q) = %q, %v, want %q, %v", tt.code, got, ok, tt.want, tt.ok)
+		}
+	}
+}
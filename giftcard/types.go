@@ -0,0 +1,96 @@
+package giftcard
+
+import (
+	"github.com/pdridh/k-line/api"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/pdridh/k-line/db/sqlc"
+)
+
+type GiftCardFilters struct {
+	CustomerID int32 `json:"customer_id"`
+	Page       int32 `json:"page"` // The request is sent as page but converted to offset for db
+	Limit      int32 `json:"limit"`
+}
+
+func (f *GiftCardFilters) Validate(maxLimit int32, defaultLimit int32) {
+	api.ClampPage(&f.Limit, &f.Page, maxLimit, defaultLimit)
+}
+
+type TransactionFilters struct {
+	Page  int32 `json:"page"` // The request is sent as page but converted to offset for db
+	Limit int32 `json:"limit"`
+}
+
+func (f *TransactionFilters) Validate(maxLimit int32, defaultLimit int32) {
+	api.ClampPage(&f.Limit, &f.Page, maxLimit, defaultLimit)
+}
+
+type GiftCard struct {
+	ID         int32            `json:"id"`
+	Code       string           `json:"code"` // Grouped in fours for reading out, like ABCD-EFGH-JKLM-NPQR
+	Balance    float64          `json:"balance"`
+	ExpiresAt  pgtype.Timestamp `json:"expires_at"` // Null when the card never expires
+	Expired    bool             `json:"expired"`
+	CustomerID pgtype.Int4      `json:"customer_id"`
+	IssuedBy   pgtype.UUID      `json:"issued_by"`
+	CreatedAt  pgtype.Timestamp `json:"created_at"`
+}
+
+func newGiftCard(c sqlc.GiftCard) *GiftCard {
+	return &GiftCard{
+		ID:         c.ID,
+		Code:       formatCode(c.Code),
+		Balance:    c.Balance,
+		ExpiresAt:  c.ExpiresAt,
+		Expired:    expired(c),
+		CustomerID: c.CustomerID,
+		IssuedBy:   c.IssuedBy,
+		CreatedAt:  c.CreatedAt,
+	}
+}
+
+type Transaction struct {
+	ID           int64                        `json:"id"`
+	Type         sqlc.GiftCardTransactionType `json:"type"`
+	Amount       float64                      `json:"amount"` // Negative when the card was spent
+	BalanceAfter float64                      `json:"balance_after"`
+	OrderID      pgtype.UUID                  `json:"order_id"`
+	TenderID     pgtype.Int8                  `json:"tender_id"`
+	CreatedBy    pgtype.UUID                  `json:"created_by"`
+	CreatedAt    pgtype.Timestamp             `json:"created_at"`
+}
+
+func newTransaction(t sqlc.GiftCardTransaction) *Transaction {
+	return &Transaction{
+		ID:           t.ID,
+		Type:         t.Type,
+		Amount:       t.Amount,
+		BalanceAfter: t.BalanceAfter,
+		OrderID:      t.OrderID,
+		TenderID:     t.TenderID,
+		CreatedBy:    t.CreatedBy,
+		CreatedAt:    t.CreatedAt,
+	}
+}
+
+// Part of an order paid with a gift card
+type Redemption struct {
+	Code     string      `json:"code"`
+	OrderID  pgtype.UUID `json:"order_id"`
+	TenderID int64       `json:"tender_id"`
+	Amount   float64     `json:"amount"`
+	Balance  float64     `json:"balance"` // What is left on the card
+}
+
+func formatCode(code string) string {
+	var b strings.Builder
+	for i, r := range code {
+		if i > 0 && i%4 == 0 {
+			b.WriteByte('-')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,334 @@
+package inventory
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/pdridh/k-line/api"
+	"github.com/pdridh/k-line/db/sqlc"
+	"github.com/pkg/errors"
+)
+
+type handler struct {
+	Service *service
+}
+
+func NewHandler(s *service) *handler {
+	return &handler{
+		Service: s,
+	}
+}
+
+func (h *handler) CreateIngredient() http.HandlerFunc {
+	type RequestPayload struct {
+		Name          string      `json:"name" validate:"required"`
+		Unit          string      `json:"unit" validate:"required"`
+		LowStockLevel float64     `json:"low_stock_level" validate:"gte=0"`
+		ParLevel      float64     `json:"par_level" validate:"gte=0"`
+		SupplierID    pgtype.Int4 `json:"supplier_id"` // Who it is usually bought from, optional
+		Allergens     []string    `json:"allergens" validate:"unique,dive,allergen"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var p RequestPayload
+		if err := api.ParseJSON(r, &p); err != nil {
+			api.WriteBadRequestError(w, r)
+			return
+		}
+
+		if err := h.Service.Validate.Struct(p); err != nil {
+			api.WriteValidationError(w, r, err)
+			return
+		}
+
+		arg := sqlc.CreateIngredientParams{
+			Name:          p.Name,
+			Unit:          p.Unit,
+			LowStockLevel: p.LowStockLevel,
+			ParLevel:      p.ParLevel,
+			SupplierID:    p.SupplierID,
+			Allergens:     p.Allergens,
+		}
+
+		i, err := h.Service.CreateIngredient(r.Context(), arg)
+		if err != nil {
+			switch {
+			case errors.Is(err, api.ErrIngredientNameConflict.Error):
+				api.WriteError(w, r, http.StatusConflict, api.ErrIngredientNameConflict, nil)
+				return
+			case errors.Is(err, api.ErrUnknownSupplier.Error):
+				api.WriteError(w, r, http.StatusBadRequest, api.ErrUnknownSupplier, nil)
+				return
+			default:
+				api.WriteInternalError(w, r)
+				return
+			}
+		}
+
+		api.WriteSuccess(w, r, http.StatusCreated, "Successfully created ingredient", i)
+	}
+}
+
+func (h *handler) UpdateIngredient() http.HandlerFunc {
+	type RequestPayload struct {
+		Name          string      `json:"name" validate:"required"`
+		Unit          string      `json:"unit" validate:"required"`
+		LowStockLevel float64     `json:"low_stock_level" validate:"gte=0"`
+		ParLevel      float64     `json:"par_level" validate:"gte=0"`
+		SupplierID    pgtype.Int4 `json:"supplier_id"` // Who it is usually bought from, optional
+		Allergens     []string    `json:"allergens" validate:"unique,dive,allergen"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		var p RequestPayload
+		if err := api.ParseJSON(r, &p); err != nil {
+			api.WriteBadRequestError(w, r)
+			return
+		}
+
+		if err := h.Service.Validate.Struct(p); err != nil {
+			api.WriteValidationError(w, r, err)
+			return
+		}
+
+		arg := sqlc.UpdateIngredientParams{
+			ID:            int32(id),
+			Name:          p.Name,
+			Unit:          p.Unit,
+			LowStockLevel: p.LowStockLevel,
+			ParLevel:      p.ParLevel,
+			SupplierID:    p.SupplierID,
+			Allergens:     p.Allergens,
+		}
+
+		i, err := h.Service.UpdateIngredient(r.Context(), arg)
+		if err != nil {
+			switch {
+			case errors.Is(err, api.ErrUnknownIngredient.Error):
+				api.WriteNotFoundError(w, r)
+				return
+			case errors.Is(err, api.ErrIngredientNameConflict.Error):
+				api.WriteError(w, r, http.StatusConflict, api.ErrIngredientNameConflict, nil)
+				return
+			case errors.Is(err, api.ErrUnknownSupplier.Error):
+				api.WriteError(w, r, http.StatusBadRequest, api.ErrUnknownSupplier, nil)
+				return
+			default:
+				api.WriteInternalError(w, r)
+				return
+			}
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Successfully updated ingredient", i)
+	}
+}
+
+func (h *handler) GetIngredients() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var filters IngredientFilters
+
+		api.ParseQueryParams(r.URL.Query(), &filters)
+		filters.Validate(100, 50)
+
+		offset := (filters.Page - 1) * filters.Limit
+
+		i, err := h.Service.GetIngredients(r.Context(), filters.LowStock, filters.Limit, offset)
+		if err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Retrieval successful", i)
+	}
+}
+
+func (h *handler) GetIngredientByID() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		i, err := h.Service.GetIngredientByID(r.Context(), int32(id))
+		if err != nil {
+			if errors.Is(err, api.ErrUnknownIngredient.Error) {
+				api.WriteNotFoundError(w, r)
+				return
+			}
+
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Retrieval successful", i)
+	}
+}
+
+func (h *handler) GetLowStockAlerts() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		i, err := h.Service.GetLowStockAlerts(r.Context())
+		if err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Retrieval successful", i)
+	}
+}
+
+// Records a stock adjustment (signed quantity, like after a stock count) or waste (quantity thrown away)
+func (h *handler) AdjustStock() http.HandlerFunc {
+	type RequestPayload struct {
+		Type     sqlc.StockMovementType `json:"type" validate:"required,oneof=adjustment waste"`
+		Quantity float64                `json:"quantity" validate:"required"`
+		Reason   string                 `json:"reason" validate:"required"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		var userID pgtype.UUID
+		if err := userID.Scan(api.CurrentUserID(r)); err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		var p RequestPayload
+		if err := api.ParseJSON(r, &p); err != nil {
+			api.WriteBadRequestError(w, r)
+			return
+		}
+
+		if err := h.Service.Validate.Struct(p); err != nil {
+			api.WriteValidationError(w, r, err)
+			return
+		}
+
+		// Waste is always stock going out
+		quantity := p.Quantity
+		if p.Type == sqlc.StockMovementTypeWaste {
+			quantity = -math.Abs(quantity)
+		}
+
+		i, err := h.Service.AdjustStock(r.Context(), int32(id), p.Type, quantity, pgtype.Text{String: p.Reason, Valid: true}, userID)
+		if err != nil {
+			if errors.Is(err, api.ErrUnknownIngredient.Error) {
+				api.WriteNotFoundError(w, r)
+				return
+			}
+
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Successfully updated stock", i)
+	}
+}
+
+func (h *handler) GetStockMovements() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		var filters IngredientFilters
+
+		api.ParseQueryParams(r.URL.Query(), &filters)
+		filters.Validate(100, 50)
+
+		offset := (filters.Page - 1) * filters.Limit
+
+		m, err := h.Service.GetStockMovements(r.Context(), int32(id), filters.Limit, offset)
+		if err != nil {
+			if errors.Is(err, api.ErrUnknownIngredient.Error) {
+				api.WriteNotFoundError(w, r)
+				return
+			}
+
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Retrieval successful", m)
+	}
+}
+
+func (h *handler) GetRecipe() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		recipe, err := h.Service.GetRecipe(r.Context(), int32(id))
+		if err != nil {
+			if errors.Is(err, api.ErrUnkownMenuItem.Error) {
+				api.WriteNotFoundError(w, r)
+				return
+			}
+
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Retrieval successful", recipe)
+	}
+}
+
+func (h *handler) SetRecipe() http.HandlerFunc {
+	type RequestPayload struct {
+		Ingredients []RecipeIngredient `json:"ingredients" validate:"unique=IngredientID,dive"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		var p RequestPayload
+		if err := api.ParseJSON(r, &p); err != nil {
+			api.WriteBadRequestError(w, r)
+			return
+		}
+
+		if err := h.Service.Validate.Struct(p); err != nil {
+			api.WriteValidationError(w, r, err)
+			return
+		}
+
+		recipe, err := h.Service.SetRecipe(r.Context(), int32(id), p.Ingredients)
+		if err != nil {
+			switch {
+			case errors.Is(err, api.ErrUnkownMenuItem.Error):
+				api.WriteNotFoundError(w, r)
+				return
+			case errors.Is(err, api.ErrUnknownIngredient.Error):
+				api.WriteError(w, r, http.StatusBadRequest, api.ErrUnknownIngredient, nil)
+				return
+			default:
+				api.WriteInternalError(w, r)
+				return
+			}
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Successfully updated recipe", recipe)
+	}
+}
@@ -0,0 +1,226 @@
+package inventory
+
+import (
+	"context"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/pdridh/k-line/api"
+	"github.com/pdridh/k-line/db"
+	"github.com/pdridh/k-line/db/sqlc"
+	"github.com/pkg/errors"
+)
+
+type service struct {
+	Validate *validator.Validate
+	store    db.Store
+}
+
+func NewService(v *validator.Validate, s db.Store) *service {
+	return &service{
+		Validate: v,
+		store:    s,
+	}
+}
+
+func (s *service) CreateIngredient(ctx context.Context, arg sqlc.CreateIngredientParams) (*Ingredient, error) {
+	// The column is NOT NULL, no allergens is an empty list
+	if arg.Allergens == nil {
+		arg.Allergens = []string{}
+	}
+
+	i, err := s.store.CreateIngredient(ctx, arg)
+	if err != nil {
+		switch db.GetSQLErrorCode(err) {
+		case db.UniqueViolation:
+			return nil, errors.Wrap(api.ErrIngredientNameConflict.Error, "store")
+		case db.ForeignKeyViolation:
+			return nil, errors.Wrap(api.ErrUnknownSupplier.Error, "store")
+		}
+		return nil, errors.Wrap(err, "store")
+	}
+
+	return newIngredient(i), nil
+}
+
+func (s *service) UpdateIngredient(ctx context.Context, arg sqlc.UpdateIngredientParams) (*Ingredient, error) {
+	// The column is NOT NULL, no allergens is an empty list
+	if arg.Allergens == nil {
+		arg.Allergens = []string{}
+	}
+
+	i, err := s.store.UpdateIngredient(ctx, arg)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, errors.Wrap(api.ErrUnknownIngredient.Error, "store")
+		}
+		switch db.GetSQLErrorCode(err) {
+		case db.UniqueViolation:
+			return nil, errors.Wrap(api.ErrIngredientNameConflict.Error, "store")
+		case db.ForeignKeyViolation:
+			return nil, errors.Wrap(api.ErrUnknownSupplier.Error, "store")
+		}
+		return nil, errors.Wrap(err, "store")
+	}
+
+	return newIngredient(i), nil
+}
+
+func (s *service) GetIngredients(ctx context.Context, lowStockOnly bool, limit int32, offset int32) ([]Ingredient, error) {
+	rows, err := s.store.GetIngredients(ctx, sqlc.GetIngredientsParams{Limit: limit, Offset: offset, LowStockOnly: lowStockOnly})
+	if err != nil {
+		return []Ingredient{}, errors.Wrap(err, "store")
+	}
+
+	ingredients := []Ingredient{}
+	for _, i := range rows {
+		ingredients = append(ingredients, *newIngredient(i))
+	}
+
+	return ingredients, nil
+}
+
+func (s *service) GetIngredientByID(ctx context.Context, id int32) (*Ingredient, error) {
+	i, err := s.store.GetIngredientByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, errors.Wrap(api.ErrUnknownIngredient.Error, "store")
+		}
+		return nil, errors.Wrap(err, "store")
+	}
+
+	return newIngredient(i), nil
+}
+
+// Every ingredient at or below its low stock level, the ones furthest below come first
+func (s *service) GetLowStockAlerts(ctx context.Context) ([]Ingredient, error) {
+	rows, err := s.store.GetLowStockIngredients(ctx)
+	if err != nil {
+		return []Ingredient{}, errors.Wrap(err, "store")
+	}
+
+	ingredients := []Ingredient{}
+	for _, i := range rows {
+		ingredients = append(ingredients, *newIngredient(i))
+	}
+
+	return ingredients, nil
+}
+
+// Changes the stock by quantity (negative to take stock out) and records why.
+// Menu items using the ingredient are 86'd or brought back depending on the new stock.
+func (s *service) AdjustStock(ctx context.Context, id int32, movementType sqlc.StockMovementType, quantity float64, reason pgtype.Text, createdBy pgtype.UUID) (*Ingredient, error) {
+	arg := sqlc.CreateStockMovementParams{
+		IngredientID: id,
+		Type:         movementType,
+		Quantity:     quantity,
+		Reason:       reason,
+		CreatedBy:    createdBy,
+	}
+
+	i, err := s.store.AdjustStockTx(ctx, arg)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, errors.Wrap(api.ErrUnknownIngredient.Error, "store")
+		}
+		return nil, errors.Wrap(err, "store")
+	}
+
+	return newIngredient(i), nil
+}
+
+func (s *service) GetStockMovements(ctx context.Context, id int32, limit int32, offset int32) ([]StockMovement, error) {
+	if _, err := s.GetIngredientByID(ctx, id); err != nil {
+		return []StockMovement{}, err
+	}
+
+	rows, err := s.store.GetStockMovements(ctx, sqlc.GetStockMovementsParams{IngredientID: id, Limit: limit, Offset: offset})
+	if err != nil {
+		return []StockMovement{}, errors.Wrap(err, "store")
+	}
+
+	movements := []StockMovement{}
+	for _, m := range rows {
+		movements = append(movements, StockMovement{
+			ID:              m.ID,
+			IngredientID:    m.IngredientID,
+			Type:            m.Type,
+			Quantity:        m.Quantity,
+			Reason:          m.Reason,
+			OrderItemID:     m.OrderItemID,
+			PurchaseOrderID: m.PurchaseOrderID,
+			CreatedBy:       m.CreatedBy,
+			CreatedAt:       m.CreatedAt,
+		})
+	}
+
+	return movements, nil
+}
+
+func (s *service) GetRecipe(ctx context.Context, itemID int32) (*Recipe, error) {
+	if _, err := s.store.GetItemByID(ctx, itemID); err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, errors.Wrap(api.ErrUnkownMenuItem.Error, "store")
+		}
+		return nil, errors.Wrap(err, "store")
+	}
+
+	rows, err := s.store.GetRecipe(ctx, itemID)
+	if err != nil {
+		return nil, errors.Wrap(err, "store")
+	}
+
+	recipe := &Recipe{ItemID: itemID, Ingredients: []RecipeIngredient{}}
+	for _, r := range rows {
+		recipe.Ingredients = append(recipe.Ingredients, RecipeIngredient{
+			IngredientID: r.IngredientID,
+			Name:         r.Name,
+			Unit:         r.Unit,
+			Quantity:     r.Quantity,
+		})
+	}
+
+	return recipe, nil
+}
+
+// Replaces the whole recipe of the item, an empty list removes it
+func (s *service) SetRecipe(ctx context.Context, itemID int32, ingredients []RecipeIngredient) (*Recipe, error) {
+	if _, err := s.store.GetItemByID(ctx, itemID); err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, errors.Wrap(api.ErrUnkownMenuItem.Error, "store")
+		}
+		return nil, errors.Wrap(err, "store")
+	}
+
+	ids := make([]int32, 0, len(ingredients))
+	quantities := make([]float64, 0, len(ingredients))
+	for _, i := range ingredients {
+		ids = append(ids, i.IngredientID)
+		quantities = append(quantities, i.Quantity)
+	}
+
+	if err := s.store.SetRecipeTx(ctx, itemID, ids, quantities); err != nil {
+		// The item was checked above so it has to be one of the ingredients
+		if db.GetSQLErrorCode(err) == db.ForeignKeyViolation {
+			return nil, errors.Wrap(api.ErrUnknownIngredient.Error, "store")
+		}
+		return nil, errors.Wrap(err, "store")
+	}
+
+	return s.GetRecipe(ctx, itemID)
+}
+
+func newIngredient(i sqlc.Ingredient) *Ingredient {
+	return &Ingredient{
+		ID:            i.ID,
+		Name:          i.Name,
+		Unit:          i.Unit,
+		Stock:         i.Stock,
+		LowStockLevel: i.LowStockLevel,
+		LowStock:      i.Stock <= i.LowStockLevel,
+		ParLevel:      i.ParLevel,
+		SupplierID:    i.SupplierID,
+		Allergens:     i.Allergens,
+		CreatedAt:     i.CreatedAt,
+	}
+}
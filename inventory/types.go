@@ -0,0 +1,56 @@
+package inventory
+
+import (
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/pdridh/k-line/api"
+	"github.com/pdridh/k-line/db/sqlc"
+)
+
+type IngredientFilters struct {
+	LowStock bool  `json:"low_stock"` // Only list ingredients at or below their low stock level
+	Page     int32 `json:"page"`      // The request is sent as page but converted to offset for db
+	Limit    int32 `json:"limit"`
+}
+
+func (f *IngredientFilters) Validate(maxLimit int32, defaultLimit int32) {
+	api.ClampPage(&f.Limit, &f.Page, maxLimit, defaultLimit)
+}
+
+type Ingredient struct {
+	ID            int32            `json:"id"`
+	Name          string           `json:"name"`
+	Unit          string           `json:"unit"`
+	Stock         float64          `json:"stock"`
+	LowStockLevel float64          `json:"low_stock_level"`
+	LowStock      bool             `json:"low_stock"` // Stock is at or below LowStockLevel
+	ParLevel      float64          `json:"par_level"` // Stock to reorder up to
+	SupplierID    pgtype.Int4      `json:"supplier_id"`
+	Allergens     []string         `json:"allergens"` // Passed on to the menu items that use it
+	CreatedAt     pgtype.Timestamp `json:"created_at"`
+}
+
+// A change in stock, Quantity is negative when stock went down
+type StockMovement struct {
+	ID              int64                  `json:"id"`
+	IngredientID    int32                  `json:"ingredient_id"`
+	Type            sqlc.StockMovementType `json:"type"`
+	Quantity        float64                `json:"quantity"`
+	Reason          pgtype.Text            `json:"reason"`
+	OrderItemID     pgtype.Int8            `json:"order_item_id"`
+	PurchaseOrderID pgtype.Int8            `json:"purchase_order_id"`
+	CreatedBy       pgtype.UUID            `json:"created_by"`
+	CreatedAt       pgtype.Timestamp       `json:"created_at"`
+}
+
+// How much of an ingredient goes into one portion of a menu item
+type RecipeIngredient struct {
+	IngredientID int32   `json:"ingredient_id" validate:"required"`
+	Name         string  `json:"name"`
+	Unit         string  `json:"unit"`
+	Quantity     float64 `json:"quantity" validate:"gt=0"`
+}
+
+type Recipe struct {
+	ItemID      int32              `json:"item_id"`
+	Ingredients []RecipeIngredient `json:"ingredients"`
+}
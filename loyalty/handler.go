@@ -0,0 +1,264 @@
+package loyalty
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/pdridh/k-line/api"
+	"github.com/pdridh/k-line/db/sqlc"
+	"github.com/pkg/errors"
+)
+
+type handler struct {
+	Service *service
+}
+
+func NewHandler(s *service) *handler {
+	return &handler{
+		Service: s,
+	}
+}
+
+func writeLoyaltyError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, api.ErrUnknownCustomer.Error),
+		errors.Is(err, api.ErrUnknownOrder.Error),
+		errors.Is(err, api.ErrUnknownLoyaltyTier.Error):
+		api.WriteNotFoundError(w, r)
+	case errors.Is(err, api.ErrLoyaltyTierConflict.Error):
+		api.WriteError(w, r, http.StatusConflict, api.ErrLoyaltyTierConflict, nil)
+	case errors.Is(err, api.ErrOrderNotOngoing.Error):
+		api.WriteError(w, r, http.StatusConflict, api.ErrOrderNotOngoing, nil)
+	case errors.Is(err, api.ErrOrderNoCustomer.Error):
+		api.WriteError(w, r, http.StatusBadRequest, api.ErrOrderNoCustomer, nil)
+	case errors.Is(err, api.ErrInsufficientPoints.Error):
+		api.WriteError(w, r, http.StatusBadRequest, api.ErrInsufficientPoints, nil)
+	case errors.Is(err, api.ErrDiscountExceedsBill.Error):
+		api.WriteError(w, r, http.StatusBadRequest, api.ErrDiscountExceedsBill, nil)
+	default:
+		api.WriteInternalError(w, r)
+	}
+}
+
+func (h *handler) GetTiers() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		t, err := h.Service.GetTiers(r.Context())
+		if err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Retrieval successful", t)
+	}
+}
+
+func (h *handler) CreateTier() http.HandlerFunc {
+	type RequestPayload struct {
+		Name       string  `json:"name" validate:"required"`
+		MinPoints  int32   `json:"min_points" validate:"gte=0"`
+		Multiplier float64 `json:"multiplier" validate:"gt=0"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var p RequestPayload
+		if err := api.ParseJSON(r, &p); err != nil {
+			api.WriteBadRequestError(w, r)
+			return
+		}
+
+		if err := h.Service.Validate.Struct(p); err != nil {
+			api.WriteValidationError(w, r, err)
+			return
+		}
+
+		t, err := h.Service.CreateTier(r.Context(), sqlc.CreateLoyaltyTierParams{Name: p.Name, MinPoints: p.MinPoints, Multiplier: p.Multiplier})
+		if err != nil {
+			writeLoyaltyError(w, r, err)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusCreated, "Successfully created loyalty tier", t)
+	}
+}
+
+func (h *handler) UpdateTier() http.HandlerFunc {
+	type RequestPayload struct {
+		Name       string  `json:"name" validate:"required"`
+		MinPoints  int32   `json:"min_points" validate:"gte=0"`
+		Multiplier float64 `json:"multiplier" validate:"gt=0"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		var p RequestPayload
+		if err := api.ParseJSON(r, &p); err != nil {
+			api.WriteBadRequestError(w, r)
+			return
+		}
+
+		if err := h.Service.Validate.Struct(p); err != nil {
+			api.WriteValidationError(w, r, err)
+			return
+		}
+
+		arg := sqlc.UpdateLoyaltyTierParams{
+			ID:         int32(id),
+			Name:       p.Name,
+			MinPoints:  p.MinPoints,
+			Multiplier: p.Multiplier,
+		}
+
+		t, err := h.Service.UpdateTier(r.Context(), arg)
+		if err != nil {
+			writeLoyaltyError(w, r, err)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Successfully updated loyalty tier", t)
+	}
+}
+
+func (h *handler) DeleteTier() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		if err := h.Service.DeleteTier(r.Context(), int32(id)); err != nil {
+			writeLoyaltyError(w, r, err)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Successfully deleted loyalty tier", nil)
+	}
+}
+
+func (h *handler) GetAccount() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		a, err := h.Service.GetAccount(r.Context(), int32(id))
+		if err != nil {
+			writeLoyaltyError(w, r, err)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Retrieval successful", a)
+	}
+}
+
+func (h *handler) GetTransactions() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		var filters TransactionFilters
+
+		api.ParseQueryParams(r.URL.Query(), &filters)
+		filters.Validate(100, 50)
+
+		t, total, err := h.Service.GetTransactions(r.Context(), int32(id), filters.Limit, (filters.Page-1)*filters.Limit)
+		if err != nil {
+			writeLoyaltyError(w, r, err)
+			return
+		}
+
+		meta := api.CalculatePaginationMeta(total, int(filters.Page), int(filters.Limit))
+		api.WriteSuccess(w, r, http.StatusOK, "Retrieval successful", api.NewPaginatedResponse(t, meta))
+	}
+}
+
+func (h *handler) AdjustPoints() http.HandlerFunc {
+	type RequestPayload struct {
+		Points int32  `json:"points" validate:"required"` // Negative to take points away
+		Note   string `json:"note" validate:"required"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		var p RequestPayload
+		if err := api.ParseJSON(r, &p); err != nil {
+			api.WriteBadRequestError(w, r)
+			return
+		}
+
+		if err := h.Service.Validate.Struct(p); err != nil {
+			api.WriteValidationError(w, r, err)
+			return
+		}
+
+		var userID pgtype.UUID
+		if err := userID.Scan(api.CurrentUserID(r)); err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		t, err := h.Service.AdjustPoints(r.Context(), int32(id), p.Points, p.Note, userID)
+		if err != nil {
+			writeLoyaltyError(w, r, err)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusCreated, "Successfully adjusted loyalty points", t)
+	}
+}
+
+// Takes points of the customer linked to the order off its bill
+func (h *handler) RedeemPoints() http.HandlerFunc {
+	type RequestPayload struct {
+		Points int32 `json:"points" validate:"required,gt=0"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := pgtype.UUID{}
+		if err := id.Scan(r.PathValue("id")); err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		var p RequestPayload
+		if err := api.ParseJSON(r, &p); err != nil {
+			api.WriteBadRequestError(w, r)
+			return
+		}
+
+		if err := h.Service.Validate.Struct(p); err != nil {
+			api.WriteValidationError(w, r, err)
+			return
+		}
+
+		var userID pgtype.UUID
+		if err := userID.Scan(api.CurrentUserID(r)); err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		red, err := h.Service.RedeemPoints(r.Context(), id, p.Points, userID)
+		if err != nil {
+			writeLoyaltyError(w, r, err)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusCreated, "Successfully redeemed loyalty points", red)
+	}
+}
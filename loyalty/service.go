@@ -0,0 +1,245 @@
+package loyalty
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/pdridh/k-line/api"
+	"github.com/pdridh/k-line/config"
+	"github.com/pdridh/k-line/db"
+	"github.com/pdridh/k-line/db/sqlc"
+	"github.com/pkg/errors"
+)
+
+type service struct {
+	Validate *validator.Validate
+	store    db.Store
+}
+
+func NewService(v *validator.Validate, s db.Store) *service {
+	return &service{
+		Validate: v,
+		store:    s,
+	}
+}
+
+func (s *service) GetTiers(ctx context.Context) ([]Tier, error) {
+	rows, err := s.store.GetLoyaltyTiers(ctx)
+	if err != nil {
+		return []Tier{}, errors.Wrap(err, "store")
+	}
+
+	tiers := []Tier{}
+	for _, t := range rows {
+		tiers = append(tiers, *newTier(t))
+	}
+
+	return tiers, nil
+}
+
+func (s *service) CreateTier(ctx context.Context, arg sqlc.CreateLoyaltyTierParams) (*Tier, error) {
+	t, err := s.store.CreateLoyaltyTier(ctx, arg)
+	if err != nil {
+		if db.GetSQLErrorCode(err) == db.UniqueViolation {
+			return nil, errors.Wrap(api.ErrLoyaltyTierConflict.Error, "store")
+		}
+		return nil, errors.Wrap(err, "store")
+	}
+
+	return newTier(t), nil
+}
+
+func (s *service) UpdateTier(ctx context.Context, arg sqlc.UpdateLoyaltyTierParams) (*Tier, error) {
+	t, err := s.store.UpdateLoyaltyTier(ctx, arg)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, errors.Wrap(api.ErrUnknownLoyaltyTier.Error, "store")
+		}
+		if db.GetSQLErrorCode(err) == db.UniqueViolation {
+			return nil, errors.Wrap(api.ErrLoyaltyTierConflict.Error, "store")
+		}
+		return nil, errors.Wrap(err, "store")
+	}
+
+	return newTier(t), nil
+}
+
+func (s *service) DeleteTier(ctx context.Context, id int32) error {
+	n, err := s.store.DeleteLoyaltyTier(ctx, id)
+	if err != nil {
+		return errors.Wrap(err, "store")
+	}
+
+	if n == 0 {
+		return errors.Wrap(api.ErrUnknownLoyaltyTier.Error, "store")
+	}
+
+	return nil
+}
+
+// Returns the balance and tier of the customer, both are worked out from their transactions
+func (s *service) GetAccount(ctx context.Context, customerID int32) (*Account, error) {
+	if err := s.checkCustomer(ctx, customerID); err != nil {
+		return nil, err
+	}
+
+	b, err := s.store.GetLoyaltyBalance(ctx, customerID)
+	if err != nil {
+		return nil, errors.Wrap(err, "store")
+	}
+
+	tiers, err := s.GetTiers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	account := &Account{
+		CustomerID:   customerID,
+		Balance:      b.Balance,
+		BalanceValue: api.Round(float64(b.Balance) * config.Server().LoyaltyPointValue),
+		Earned:       b.Earned,
+	}
+
+	// Tiers are sorted by min points so the last one reached is the tier of the customer
+	for _, t := range tiers {
+		if t.MinPoints <= b.Earned {
+			account.Tier = &t
+			continue
+		}
+
+		account.NextTier = &t
+		account.PointsToNextTier = t.MinPoints - b.Earned
+		break
+	}
+
+	return account, nil
+}
+
+// Returns a page of the transactions of the customer newest first and how many they have in total
+func (s *service) GetTransactions(ctx context.Context, customerID int32, limit int32, offset int32) ([]Transaction, int, error) {
+	if err := s.checkCustomer(ctx, customerID); err != nil {
+		return []Transaction{}, 0, err
+	}
+
+	rows, err := s.store.GetLoyaltyTransactions(ctx, sqlc.GetLoyaltyTransactionsParams{CustomerID: customerID, Limit: limit, Offset: offset})
+	if err != nil {
+		return []Transaction{}, 0, errors.Wrap(err, "store")
+	}
+
+	total, err := s.store.CountLoyaltyTransactions(ctx, customerID)
+	if err != nil {
+		return []Transaction{}, 0, errors.Wrap(err, "store")
+	}
+
+	transactions := []Transaction{}
+	for _, t := range rows {
+		transactions = append(transactions, *newTransaction(t))
+	}
+
+	return transactions, int(total), nil
+}
+
+// Gives or takes away points by hand (like a goodwill gesture or fixing a mistake), the note says why
+func (s *service) AdjustPoints(ctx context.Context, customerID int32, points int32, note string, createdBy pgtype.UUID) (*Transaction, error) {
+	t, err := s.store.AdjustLoyaltyPointsTx(ctx, sqlc.CreateLoyaltyTransactionParams{
+		CustomerID: customerID,
+		Type:       sqlc.LoyaltyTransactionTypeAdjust,
+		Points:     points,
+		Note:       pgtype.Text{String: note, Valid: true},
+		CreatedBy:  createdBy,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrInsufficientBalance) {
+			return nil, errors.Wrap(api.ErrInsufficientPoints.Error, "store")
+		}
+		if db.GetSQLErrorCode(err) == db.ForeignKeyViolation {
+			return nil, errors.Wrap(api.ErrUnknownCustomer.Error, "store")
+		}
+		return nil, errors.Wrap(err, "store")
+	}
+
+	return newTransaction(t), nil
+}
+
+// Spends points of the customer of the order on a discount off its bill
+func (s *service) RedeemPoints(ctx context.Context, orderID pgtype.UUID, points int32, createdBy pgtype.UUID) (*Redemption, error) {
+	o, err := s.store.GetOrderByID(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, errors.Wrap(api.ErrUnknownOrder.Error, "store")
+		}
+		return nil, errors.Wrap(err, "store")
+	}
+
+	if o.Status != sqlc.OrderStatusOngoing {
+		return nil, errors.Wrap(api.ErrOrderNotOngoing.Error, "status")
+	}
+
+	if !o.CustomerID.Valid {
+		return nil, errors.Wrap(api.ErrOrderNoCustomer.Error, "customer")
+	}
+
+	amount := api.Round(float64(points) * config.Server().LoyaltyPointValue)
+	if amount <= 0 {
+		return nil, errors.Wrap(api.ErrInsufficientPoints.Error, "amount")
+	}
+
+	arg := sqlc.CreateLoyaltyTransactionParams{
+		CustomerID: o.CustomerID.Int32,
+		Type:       sqlc.LoyaltyTransactionTypeRedeem,
+		Points:     -points,
+		OrderID:    orderID,
+		CreatedBy:  createdBy,
+	}
+
+	discount := sqlc.CreateOrderDiscountParams{
+		OrderID:     orderID,
+		Type:        sqlc.DiscountTypeLoyalty,
+		Description: fmt.Sprintf("%d loyalty points", points),
+		Amount:      amount,
+		CreatedBy:   createdBy,
+		CustomerID:  o.CustomerID,
+	}
+
+	d, err := s.store.RedeemLoyaltyPointsTx(ctx, arg, discount)
+	if err != nil {
+		switch {
+		case errors.Is(err, db.ErrInsufficientBalance):
+			return nil, errors.Wrap(api.ErrInsufficientPoints.Error, "store")
+		case errors.Is(err, db.ErrDiscountExceedsBill):
+			return nil, errors.Wrap(api.ErrDiscountExceedsBill.Error, "store")
+		case errors.Is(err, db.ErrOrderNotOngoing):
+			return nil, errors.Wrap(api.ErrOrderNotOngoing.Error, "store")
+		case errors.Is(err, db.ErrOrderCustomer):
+			return nil, errors.Wrap(api.ErrOrderNoCustomer.Error, "store")
+		}
+		return nil, errors.Wrap(err, "store")
+	}
+
+	b, err := s.store.GetLoyaltyBalance(ctx, o.CustomerID.Int32)
+	if err != nil {
+		return nil, errors.Wrap(err, "store")
+	}
+
+	return &Redemption{
+		OrderID:    orderID,
+		CustomerID: o.CustomerID.Int32,
+		Points:     points,
+		DiscountID: d.ID,
+		Amount:     d.Amount,
+		Balance:    b.Balance,
+	}, nil
+}
+
+func (s *service) checkCustomer(ctx context.Context, customerID int32) error {
+	if _, err := s.store.GetCustomerByID(ctx, customerID); err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return errors.Wrap(api.ErrUnknownCustomer.Error, "store")
+		}
+		return errors.Wrap(err, "store")
+	}
+
+	return nil
+}
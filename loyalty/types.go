@@ -0,0 +1,80 @@
+package loyalty
+
+import (
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/pdridh/k-line/api"
+	"github.com/pdridh/k-line/db/sqlc"
+)
+
+type TransactionFilters struct {
+	Page  int32 `json:"page"` // The request is sent as page but converted to offset for db
+	Limit int32 `json:"limit"`
+}
+
+func (f *TransactionFilters) Validate(maxLimit int32, defaultLimit int32) {
+	api.ClampPage(&f.Limit, &f.Page, maxLimit, defaultLimit)
+}
+
+// Customers that earned MinPoints in total are in the tier and earn Multiplier times the points
+type Tier struct {
+	ID         int32            `json:"id"`
+	Name       string           `json:"name"`
+	MinPoints  int32            `json:"min_points"`
+	Multiplier float64          `json:"multiplier"`
+	CreatedAt  pgtype.Timestamp `json:"created_at"`
+}
+
+func newTier(t sqlc.LoyaltyTier) *Tier {
+	return &Tier{
+		ID:         t.ID,
+		Name:       t.Name,
+		MinPoints:  t.MinPoints,
+		Multiplier: t.Multiplier,
+		CreatedAt:  t.CreatedAt,
+	}
+}
+
+// Where a customer stands, worked out from their transactions every time
+type Account struct {
+	CustomerID       int32   `json:"customer_id"`
+	Balance          int32   `json:"balance"`
+	BalanceValue     float64 `json:"balance_value"` // What the balance takes off a bill
+	Earned           int32   `json:"earned"`        // Every point ever earned, decides the tier
+	Tier             *Tier   `json:"tier"`
+	NextTier         *Tier   `json:"next_tier"`
+	PointsToNextTier int32   `json:"points_to_next_tier"`
+}
+
+type Transaction struct {
+	ID         int64                       `json:"id"`
+	CustomerID int32                       `json:"customer_id"`
+	Type       sqlc.LoyaltyTransactionType `json:"type"`
+	Points     int32                       `json:"points"` // Negative when points are spent or taken away
+	OrderID    pgtype.UUID                 `json:"order_id"`
+	Note       pgtype.Text                 `json:"note"`
+	CreatedBy  pgtype.UUID                 `json:"created_by"`
+	CreatedAt  pgtype.Timestamp            `json:"created_at"`
+}
+
+func newTransaction(t sqlc.LoyaltyTransaction) *Transaction {
+	return &Transaction{
+		ID:         t.ID,
+		CustomerID: t.CustomerID,
+		Type:       t.Type,
+		Points:     t.Points,
+		OrderID:    t.OrderID,
+		Note:       t.Note,
+		CreatedBy:  t.CreatedBy,
+		CreatedAt:  t.CreatedAt,
+	}
+}
+
+// Points spent on a discount off an order
+type Redemption struct {
+	OrderID    pgtype.UUID `json:"order_id"`
+	CustomerID int32       `json:"customer_id"`
+	Points     int32       `json:"points"`
+	DiscountID int64       `json:"discount_id"`
+	Amount     float64     `json:"amount"`
+	Balance    int32       `json:"balance"` // What the customer has left
+}
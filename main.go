@@ -9,6 +9,8 @@ import (
 
 	"github.com/go-playground/validator/v10"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pdridh/k-line/auth"
+	"github.com/pdridh/k-line/blob"
 	"github.com/pdridh/k-line/config"
 	"github.com/pdridh/k-line/db"
 	"github.com/pdridh/k-line/server"
@@ -23,6 +25,10 @@ var interruptSignals = []os.Signal{
 func main() {
 	config.Load()
 
+	if err := auth.LoadKeys(); err != nil {
+		log.Fatalln("cannot load jwt keys:", err)
+	}
+
 	uri := config.Server().DatabaseURI
 
 	ctx, stop := signal.NotifyContext(context.Background(), interruptSignals...)
@@ -40,7 +46,10 @@ func main() {
 	store := db.NewPSQLStore(pool)
 
 	v := validator.New()
-	s := server.New(v, store)
+
+	blobs := blob.NewLocalStore(config.Server().UploadDir, config.Server().UploadURL)
+
+	s := server.New(v, store, blobs)
 
 	if err := s.Start(); err != nil {
 		log.Fatalln("failed to start the server: ", err)
@@ -2,11 +2,13 @@ package menu
 
 import (
 	"errors"
+	"io"
 	"net/http"
 	"strconv"
 
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/pdridh/k-line/api"
+	"github.com/pdridh/k-line/config"
 	"github.com/pdridh/k-line/db"
 )
 
@@ -26,6 +28,8 @@ func (h *handler) CreateItem() http.HandlerFunc {
 		Description    pgtype.Text `json:"description" validate:"required"`
 		Price          float64     `json:"price" validate:"required"`
 		RequiresTicket bool        `json:"requires_ticket" validate:"required"`
+		Allergens      []string    `json:"allergens" validate:"unique,dive,allergen"`
+		DietaryTags    []string    `json:"dietary_tags" validate:"unique,dive,dietary_tag"`
 	}
 
 	type ResponsePayload struct {
@@ -33,6 +37,8 @@ func (h *handler) CreateItem() http.HandlerFunc {
 		Name        string           `json:"name"`
 		Description pgtype.Text      `json:"description"`
 		Price       float64          `json:"price"`
+		Allergens   []string         `json:"allergens"`
+		DietaryTags []string         `json:"dietary_tags"`
 		CreatedAt   pgtype.Timestamp `json:"created_at"`
 	}
 
@@ -49,7 +55,7 @@ func (h *handler) CreateItem() http.HandlerFunc {
 			return
 		}
 
-		i, err := h.Service.CreateItem(r.Context(), payload.Name, payload.Description, payload.Price, payload.RequiresTicket)
+		i, err := h.Service.CreateItem(r.Context(), payload.Name, payload.Description, payload.Price, payload.RequiresTicket, payload.Allergens, payload.DietaryTags)
 		if err != nil {
 			errCode := db.GetSQLErrorCode(err)
 			if errCode == db.UniqueViolation {
@@ -65,6 +71,8 @@ func (h *handler) CreateItem() http.HandlerFunc {
 			Name:        i.Name,
 			Description: i.Description,
 			Price:       i.Price,
+			Allergens:   i.Allergens,
+			DietaryTags: i.DietaryTags,
 			CreatedAt:   i.CreatedAt,
 		}
 
@@ -81,15 +89,25 @@ func (h *handler) GetAllItems() http.HandlerFunc {
 		api.ParseQueryParams(r.URL.Query(), &filters)
 		filters.Validate(50, 20)
 
+		if err := h.Service.Validate.Struct(filters); err != nil {
+			api.WriteValidationError(w, r, err)
+			return
+		}
+
 		offset := (filters.Page - 1) * filters.Limit
 
-		i, err := h.Service.GetItems(r.Context(), filters.Search, filters.Limit, offset)
+		lang := api.PreferredLanguage(r, config.Server().Languages)
+		w.Header().Set("Content-Language", lang)
+		w.Header().Add("Vary", "Accept-Language")
+
+		i, total, err := h.Service.GetItems(r.Context(), filters.Search, filters.All, filters.Exclude, filters.Diet, lang, filters.Limit, offset)
 		if err != nil {
 			api.WriteInternalError(w, r)
 			return
 		}
 
-		api.WriteSuccess(w, r, http.StatusOK, "Retrieval successful", i)
+		meta := api.CalculatePaginationMeta(total, int(filters.Page), int(filters.Limit))
+		api.WriteSuccess(w, r, http.StatusOK, "Retrieval successful", api.NewPaginatedResponse(i, meta))
 	}
 }
 
@@ -103,7 +121,11 @@ func (h *handler) GetItemById() http.HandlerFunc {
 			return
 		}
 
-		i, err := h.Service.GetItemByID(r.Context(), int32(id))
+		lang := api.PreferredLanguage(r, config.Server().Languages)
+		w.Header().Set("Content-Language", lang)
+		w.Header().Add("Vary", "Accept-Language")
+
+		i, err := h.Service.GetItemInLanguage(r.Context(), int32(id), lang)
 		if err != nil {
 			if errors.Is(err, api.ErrUnkownMenuItem.Error) {
 				api.WriteNotFoundError(w, r)
@@ -117,3 +139,630 @@ func (h *handler) GetItemById() http.HandlerFunc {
 		api.WriteSuccess(w, r, http.StatusOK, "Retrieval successful", i)
 	}
 }
+
+func (h *handler) GetItemTranslations() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		t, err := h.Service.GetItemTranslations(r.Context(), int32(id))
+		if err != nil {
+			if errors.Is(err, api.ErrUnkownMenuItem.Error) {
+				api.WriteNotFoundError(w, r)
+				return
+			}
+
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Retrieval successful", t)
+	}
+}
+
+func (h *handler) SetItemTranslation() http.HandlerFunc {
+	type RequestPayload struct {
+		Name        string      `json:"name" validate:"required"`
+		Description pgtype.Text `json:"description"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		var payload RequestPayload
+		if err := api.ParseJSON(r, &payload); err != nil {
+			api.WriteBadRequestError(w, r)
+			return
+		}
+
+		if err := h.Service.Validate.Struct(payload); err != nil {
+			api.WriteValidationError(w, r, err)
+			return
+		}
+
+		t, err := h.Service.SetItemTranslation(r.Context(), int32(id), r.PathValue("lang"), payload.Name, payload.Description)
+		if err != nil {
+			switch {
+			case errors.Is(err, api.ErrUnkownMenuItem.Error):
+				api.WriteNotFoundError(w, r)
+			case errors.Is(err, api.ErrUnsupportedLanguage.Error):
+				api.WriteError(w, r, http.StatusBadRequest, api.ErrUnsupportedLanguage, nil)
+			default:
+				api.WriteInternalError(w, r)
+			}
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Successfully updated item translation", t)
+	}
+}
+
+func (h *handler) DeleteItemTranslation() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		if err := h.Service.DeleteItemTranslation(r.Context(), int32(id), r.PathValue("lang")); err != nil {
+			if errors.Is(err, api.ErrUnknownTranslation.Error) {
+				api.WriteNotFoundError(w, r)
+				return
+			}
+
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Successfully deleted item translation", nil)
+	}
+}
+
+func (h *handler) UpdateItemTags() http.HandlerFunc {
+	type RequestPayload struct {
+		Allergens   []string `json:"allergens" validate:"unique,dive,allergen"`
+		DietaryTags []string `json:"dietary_tags" validate:"unique,dive,dietary_tag"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		var payload RequestPayload
+		if err := api.ParseJSON(r, &payload); err != nil {
+			api.WriteBadRequestError(w, r)
+			return
+		}
+
+		if err := h.Service.Validate.Struct(payload); err != nil {
+			api.WriteValidationError(w, r, err)
+			return
+		}
+
+		i, err := h.Service.UpdateItemTags(r.Context(), int32(id), payload.Allergens, payload.DietaryTags)
+		if err != nil {
+			if errors.Is(err, api.ErrUnkownMenuItem.Error) {
+				api.WriteNotFoundError(w, r)
+				return
+			}
+
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Successfully updated item allergens", i)
+	}
+}
+
+// Takes a multipart form with the image in the image field
+func (h *handler) UploadItemImage() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		// Room for the rest of the form on top of the image
+		r.Body = http.MaxBytesReader(w, r.Body, maxImageUpload+1<<20)
+		if err := r.ParseMultipartForm(maxImageUpload); err != nil {
+			var maxErr *http.MaxBytesError
+			if errors.As(err, &maxErr) {
+				api.WriteError(w, r, http.StatusRequestEntityTooLarge, api.ErrImageTooLarge, nil)
+				return
+			}
+
+			api.WriteBadRequestError(w, r)
+			return
+		}
+
+		f, header, err := r.FormFile("image")
+		if err != nil {
+			api.WriteBadRequestError(w, r)
+			return
+		}
+		defer f.Close()
+
+		if header.Size > maxImageUpload {
+			api.WriteError(w, r, http.StatusRequestEntityTooLarge, api.ErrImageTooLarge, nil)
+			return
+		}
+
+		data, err := io.ReadAll(f)
+		if err != nil {
+			api.WriteBadRequestError(w, r)
+			return
+		}
+
+		i, err := h.Service.UploadItemImage(r.Context(), int32(id), data)
+		if err != nil {
+			switch {
+			case errors.Is(err, api.ErrUnkownMenuItem.Error):
+				api.WriteNotFoundError(w, r)
+			case errors.Is(err, api.ErrUnsupportedImage.Error):
+				api.WriteError(w, r, http.StatusUnsupportedMediaType, api.ErrUnsupportedImage, nil)
+			case errors.Is(err, api.ErrImageTooLarge.Error):
+				api.WriteError(w, r, http.StatusRequestEntityTooLarge, api.ErrImageTooLarge, nil)
+			default:
+				api.WriteInternalError(w, r)
+			}
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Successfully uploaded item image", i)
+	}
+}
+
+func (h *handler) SetItemCost() http.HandlerFunc {
+	type RequestPayload struct {
+		Cost float64 `json:"cost" validate:"gte=0"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		var userID pgtype.UUID
+		if err := userID.Scan(api.CurrentUserID(r)); err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		var payload RequestPayload
+		if err := api.ParseJSON(r, &payload); err != nil {
+			api.WriteBadRequestError(w, r)
+			return
+		}
+
+		if err := h.Service.Validate.Struct(payload); err != nil {
+			api.WriteValidationError(w, r, err)
+			return
+		}
+
+		c, err := h.Service.SetItemCost(r.Context(), int32(id), payload.Cost, userID)
+		if err != nil {
+			if errors.Is(err, api.ErrUnkownMenuItem.Error) {
+				api.WriteNotFoundError(w, r)
+				return
+			}
+
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusCreated, "Successfully updated item cost", c)
+	}
+}
+
+func (h *handler) GetItemCosts() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		c, err := h.Service.GetItemCosts(r.Context(), int32(id))
+		if err != nil {
+			if errors.Is(err, api.ErrUnkownMenuItem.Error) {
+				api.WriteNotFoundError(w, r)
+				return
+			}
+
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Retrieval successful", c)
+	}
+}
+
+func (h *handler) CreateMenu() http.HandlerFunc {
+	type RequestPayload struct {
+		Name    string  `json:"name" validate:"required"`
+		Active  bool    `json:"active"`
+		ItemIDs []int32 `json:"item_ids" validate:"unique"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var payload RequestPayload
+		if err := api.ParseJSON(r, &payload); err != nil {
+			api.WriteBadRequestError(w, r)
+			return
+		}
+
+		if err := h.Service.Validate.Struct(payload); err != nil {
+			api.WriteValidationError(w, r, err)
+			return
+		}
+
+		m, err := h.Service.CreateMenu(r.Context(), payload.Name, payload.Active, payload.ItemIDs)
+		if err != nil {
+			switch {
+			case errors.Is(err, api.ErrMenuNameConflict.Error):
+				api.WriteError(w, r, http.StatusConflict, api.ErrMenuNameConflict, nil)
+				return
+			case errors.Is(err, api.ErrUnkownMenuItem.Error):
+				api.WriteError(w, r, http.StatusBadRequest, api.ErrUnkownMenuItem, nil)
+				return
+			default:
+				api.WriteInternalError(w, r)
+				return
+			}
+		}
+
+		api.WriteSuccess(w, r, http.StatusCreated, "Created new menu", m)
+	}
+}
+
+func (h *handler) UpdateMenu() http.HandlerFunc {
+	type RequestPayload struct {
+		Name    string  `json:"name" validate:"required"`
+		Active  bool    `json:"active"`
+		ItemIDs []int32 `json:"item_ids" validate:"unique"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		var payload RequestPayload
+		if err := api.ParseJSON(r, &payload); err != nil {
+			api.WriteBadRequestError(w, r)
+			return
+		}
+
+		if err := h.Service.Validate.Struct(payload); err != nil {
+			api.WriteValidationError(w, r, err)
+			return
+		}
+
+		m, err := h.Service.UpdateMenu(r.Context(), int32(id), payload.Name, payload.Active, payload.ItemIDs)
+		if err != nil {
+			switch {
+			case errors.Is(err, api.ErrUnknownMenu.Error):
+				api.WriteNotFoundError(w, r)
+				return
+			case errors.Is(err, api.ErrMenuNameConflict.Error):
+				api.WriteError(w, r, http.StatusConflict, api.ErrMenuNameConflict, nil)
+				return
+			case errors.Is(err, api.ErrUnkownMenuItem.Error):
+				api.WriteError(w, r, http.StatusBadRequest, api.ErrUnkownMenuItem, nil)
+				return
+			default:
+				api.WriteInternalError(w, r)
+				return
+			}
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Successfully updated menu", m)
+	}
+}
+
+func (h *handler) GetMenus() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		m, err := h.Service.GetMenus(r.Context())
+		if err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Retrieval successful", m)
+	}
+}
+
+func (h *handler) GetMenuByID() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		m, err := h.Service.GetMenuByID(r.Context(), int32(id))
+		if err != nil {
+			if errors.Is(err, api.ErrUnknownMenu.Error) {
+				api.WriteNotFoundError(w, r)
+				return
+			}
+
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Retrieval successful", m)
+	}
+}
+
+func (h *handler) DeleteMenu() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		if err := h.Service.DeleteMenu(r.Context(), int32(id)); err != nil {
+			if errors.Is(err, api.ErrUnknownMenu.Error) {
+				api.WriteNotFoundError(w, r)
+				return
+			}
+
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Successfully deleted menu", nil)
+	}
+}
+
+func (h *handler) CreateSchedule() http.HandlerFunc {
+	type RequestPayload struct {
+		Name      string          `json:"name" validate:"required"`
+		Days      []int16         `json:"days" validate:"unique,dive,min=0,max=6"`
+		StartTime string          `json:"start_time"` // HH:MM, defaults to 00:00
+		EndTime   string          `json:"end_time"`   // HH:MM, defaults to 24:00
+		StartDate pgtype.Date     `json:"start_date"`
+		EndDate   pgtype.Date     `json:"end_date"`
+		Prices    []SchedulePrice `json:"prices" validate:"unique=ItemID,dive"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		var payload RequestPayload
+		if err := api.ParseJSON(r, &payload); err != nil {
+			api.WriteBadRequestError(w, r)
+			return
+		}
+
+		if err := h.Service.Validate.Struct(payload); err != nil {
+			api.WriteValidationError(w, r, err)
+			return
+		}
+
+		sch := Schedule{
+			MenuID:    int32(id),
+			Name:      payload.Name,
+			Days:      payload.Days,
+			StartTime: payload.StartTime,
+			EndTime:   payload.EndTime,
+			StartDate: payload.StartDate,
+			EndDate:   payload.EndDate,
+			Prices:    payload.Prices,
+		}
+
+		s, err := h.Service.CreateSchedule(r.Context(), sch)
+		if err != nil {
+			switch {
+			case errors.Is(err, api.ErrUnknownMenu.Error):
+				api.WriteNotFoundError(w, r)
+				return
+			case errors.Is(err, api.ErrInvalidMenuSchedule.Error):
+				api.WriteError(w, r, http.StatusBadRequest, api.ErrInvalidMenuSchedule, nil)
+				return
+			case errors.Is(err, api.ErrUnkownMenuItem.Error):
+				api.WriteError(w, r, http.StatusBadRequest, api.ErrUnkownMenuItem, nil)
+				return
+			default:
+				api.WriteInternalError(w, r)
+				return
+			}
+		}
+
+		api.WriteSuccess(w, r, http.StatusCreated, "Created new menu schedule", s)
+	}
+}
+
+func (h *handler) DeleteSchedule() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		scheduleID, err := strconv.Atoi(r.PathValue("schedule_id"))
+		if err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		if err := h.Service.DeleteSchedule(r.Context(), int32(id), int32(scheduleID)); err != nil {
+			if errors.Is(err, api.ErrUnknownMenuSchedule.Error) {
+				api.WriteNotFoundError(w, r)
+				return
+			}
+
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Successfully deleted menu schedule", nil)
+	}
+}
+
+func (h *handler) CreateCombo() http.HandlerFunc {
+	type RequestPayload struct {
+		Name        string      `json:"name" validate:"required"`
+		Description pgtype.Text `json:"description"`
+		Price       float64     `json:"price" validate:"gte=0"`
+		Active      bool        `json:"active"`
+		Slots       []ComboSlot `json:"slots" validate:"required,min=1,unique=Name,dive"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var payload RequestPayload
+		if err := api.ParseJSON(r, &payload); err != nil {
+			api.WriteBadRequestError(w, r)
+			return
+		}
+
+		if err := h.Service.Validate.Struct(payload); err != nil {
+			api.WriteValidationError(w, r, err)
+			return
+		}
+
+		combo := Combo{
+			Name:        payload.Name,
+			Description: payload.Description,
+			Price:       payload.Price,
+			Active:      payload.Active,
+			Slots:       payload.Slots,
+		}
+
+		c, err := h.Service.CreateCombo(r.Context(), combo)
+		if err != nil {
+			switch {
+			case errors.Is(err, api.ErrComboNameConflict.Error):
+				api.WriteError(w, r, http.StatusConflict, api.ErrComboNameConflict, nil)
+				return
+			case errors.Is(err, api.ErrUnkownMenuItem.Error):
+				api.WriteError(w, r, http.StatusBadRequest, api.ErrUnkownMenuItem, nil)
+				return
+			default:
+				api.WriteInternalError(w, r)
+				return
+			}
+		}
+
+		api.WriteSuccess(w, r, http.StatusCreated, "Created new combo", c)
+	}
+}
+
+func (h *handler) UpdateCombo() http.HandlerFunc {
+	type RequestPayload struct {
+		Name        string      `json:"name" validate:"required"`
+		Description pgtype.Text `json:"description"`
+		Price       float64     `json:"price" validate:"gte=0"`
+		Active      bool        `json:"active"`
+		Slots       []ComboSlot `json:"slots" validate:"required,min=1,unique=Name,dive"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		var payload RequestPayload
+		if err := api.ParseJSON(r, &payload); err != nil {
+			api.WriteBadRequestError(w, r)
+			return
+		}
+
+		if err := h.Service.Validate.Struct(payload); err != nil {
+			api.WriteValidationError(w, r, err)
+			return
+		}
+
+		combo := Combo{
+			ID:          int32(id),
+			Name:        payload.Name,
+			Description: payload.Description,
+			Price:       payload.Price,
+			Active:      payload.Active,
+			Slots:       payload.Slots,
+		}
+
+		c, err := h.Service.UpdateCombo(r.Context(), combo)
+		if err != nil {
+			switch {
+			case errors.Is(err, api.ErrUnknownCombo.Error):
+				api.WriteNotFoundError(w, r)
+				return
+			case errors.Is(err, api.ErrComboNameConflict.Error):
+				api.WriteError(w, r, http.StatusConflict, api.ErrComboNameConflict, nil)
+				return
+			case errors.Is(err, api.ErrUnkownMenuItem.Error):
+				api.WriteError(w, r, http.StatusBadRequest, api.ErrUnkownMenuItem, nil)
+				return
+			default:
+				api.WriteInternalError(w, r)
+				return
+			}
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Successfully updated combo", c)
+	}
+}
+
+// Lists the combos being sold, ?all=true also lists the inactive ones
+func (h *handler) GetCombos() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		all, _ := strconv.ParseBool(r.URL.Query().Get("all"))
+
+		c, err := h.Service.GetCombos(r.Context(), !all)
+		if err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Retrieval successful", c)
+	}
+}
+
+func (h *handler) GetComboByID() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		c, err := h.Service.GetComboByID(r.Context(), int32(id))
+		if err != nil {
+			if errors.Is(err, api.ErrUnknownCombo.Error) {
+				api.WriteNotFoundError(w, r)
+				return
+			}
+
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Retrieval successful", c)
+	}
+}
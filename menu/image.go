@@ -0,0 +1,121 @@
+package menu
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"net/http"
+
+	_ "image/gif"
+	_ "image/png"
+
+	"github.com/pdridh/k-line/api"
+)
+
+const (
+	maxImageUpload     = 10 << 20
+	fullImageSize      = 1200 // Longest side of the image shown on the item page
+	thumbnailImageSize = 320  // Longest side of the image shown in lists
+	maxImagePixels     = 40_000_000
+	imageQuality       = 85
+)
+
+var imageTypes = []string{"image/jpeg", "image/png", "image/gif"}
+
+// Checks what the upload really is from its content, the content type the client sent cant be trusted
+func sniffImage(data []byte) bool {
+	ct := http.DetectContentType(data)
+	for _, t := range imageTypes {
+		if ct == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Decodes the upload and encodes the full and thumbnail variants as jpeg
+func resizeImage(data []byte) (full []byte, thumbnail []byte, err error) {
+	// Checked before decoding so a tiny file claiming to be huge doesnt eat all the memory
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, err
+	}
+	if cfg.Width*cfg.Height > maxImagePixels {
+		return nil, nil, api.ErrImageTooLarge.Error
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Jpeg has no transparency so transparent parts end up white
+	b := src.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), &image.Uniform{color.White}, image.Point{}, draw.Src)
+	draw.Draw(rgba, rgba.Bounds(), src, b.Min, draw.Over)
+
+	if full, err = encodeJPEG(fit(rgba, fullImageSize)); err != nil {
+		return nil, nil, err
+	}
+
+	if thumbnail, err = encodeJPEG(fit(rgba, thumbnailImageSize)); err != nil {
+		return nil, nil, err
+	}
+
+	return full, thumbnail, nil
+}
+
+func encodeJPEG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: imageQuality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Scales the image down so its longest side is at most size, keeping the aspect ratio.
+// Every pixel is the average of the source pixels it covers, smaller images are left as they are.
+func fit(src *image.RGBA, size int) *image.RGBA {
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	if w <= size && h <= size {
+		return src
+	}
+
+	dw, dh := size, h*size/w
+	if h > w {
+		dw, dh = w*size/h, size
+	}
+	dw, dh = max(dw, 1), max(dh, 1)
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := range dh {
+		y0, y1 := y*h/dh, max((y+1)*h/dh, y*h/dh+1)
+		for x := range dw {
+			x0, x1 := x*w/dw, max((x+1)*w/dw, x*w/dw+1)
+
+			var r, g, b, a, n int
+			for sy := y0; sy < y1; sy++ {
+				i := src.PixOffset(x0, sy)
+				for sx := x0; sx < x1; sx++ {
+					r += int(src.Pix[i])
+					g += int(src.Pix[i+1])
+					b += int(src.Pix[i+2])
+					a += int(src.Pix[i+3])
+					i += 4
+					n++
+				}
+			}
+
+			j := dst.PixOffset(x, y)
+			dst.Pix[j] = uint8(r / n)
+			dst.Pix[j+1] = uint8(g / n)
+			dst.Pix[j+2] = uint8(b / n)
+			dst.Pix[j+3] = uint8(a / n)
+		}
+	}
+
+	return dst
+}
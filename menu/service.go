@@ -2,11 +2,19 @@ package menu
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/pdridh/k-line/api"
+	"github.com/pdridh/k-line/blob"
+	"github.com/pdridh/k-line/config"
 	"github.com/pdridh/k-line/db"
 	"github.com/pdridh/k-line/db/sqlc"
 )
@@ -14,22 +22,26 @@ import (
 type service struct {
 	Validate *validator.Validate
 	store    db.Store
+	blobs    blob.BlobStore
 }
 
-func NewService(v *validator.Validate, s db.Store) *service {
+func NewService(v *validator.Validate, s db.Store, b blob.BlobStore) *service {
 	return &service{
 		Validate: v,
 		store:    s,
+		blobs:    b,
 	}
 }
 
-func (s *service) CreateItem(ctx context.Context, name string, description pgtype.Text, price float64, requiresTicket bool) (*Item, error) {
+func (s *service) CreateItem(ctx context.Context, name string, description pgtype.Text, price float64, requiresTicket bool, allergens []string, dietaryTags []string) (*Item, error) {
 
 	arg := sqlc.CreateMenuItemParams{
 		Name:           name,
 		Description:    description,
 		Price:          price,
 		RequiresTicket: requiresTicket,
+		Allergens:      orEmpty(allergens),
+		DietaryTags:    orEmpty(dietaryTags),
 	}
 
 	i, err := s.store.CreateMenuItem(ctx, arg)
@@ -47,37 +59,89 @@ func (s *service) CreateItem(ctx context.Context, name string, description pgtyp
 		Name:           i.Name,
 		Description:    i.Description,
 		RequiresTicket: i.RequiresTicket,
+		EightySixed:    i.EightySixed,
+		Allergens:      i.Allergens,
+		DietaryTags:    i.DietaryTags,
 		CreatedAt:      i.CreatedAt,
+		// A new item isnt on any menu yet so it is always available at its own price
+		CurrentPrice: i.Price,
+		Available:    true,
 	}
 
 	return item, nil
 }
 
-func (s *service) GetItems(ctx context.Context, search string, limit int32, offset int32) ([]Item, error) {
+// Replaces the allergens and dietary tags declared on the item itself,
+// allergens of the ingredients in its recipe are added on top when it is retrieved
+func (s *service) UpdateItemTags(ctx context.Context, id int32, allergens []string, dietaryTags []string) (*Item, error) {
+	arg := sqlc.UpdateMenuItemTagsParams{
+		ID:          id,
+		Allergens:   orEmpty(allergens),
+		DietaryTags: orEmpty(dietaryTags),
+	}
+
+	if _, err := s.store.UpdateMenuItemTags(ctx, arg); err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, api.ErrUnkownMenuItem.Error
+		}
+		return nil, err
+	}
+
+	return s.GetItemByID(ctx, id)
+}
+
+// Lists the items with their names and descriptions in lang, items not translated to it are in the default language.
+// A search matches whole words in names and descriptions and names that are close to it (typos and partial words),
+// the best matches come first.
+// Total is the number of items matching the filters over all pages.
+func (s *service) GetItems(ctx context.Context, search string, includeUnavailable bool, exclude []string, diet []string, lang string, limit int32, offset int32) ([]Item, int, error) {
 	arg := sqlc.GetMenuItemsParams{
-		Search: search,
-		Limit:  limit,
-		Offset: offset,
+		Lang:               lang,
+		Search:             search,
+		IncludeUnavailable: includeUnavailable,
+		ExcludeAllergens:   exclude,
+		DietaryTags:        diet,
+		Limit:              limit,
+		Offset:             offset,
 	}
 
 	i, err := s.store.GetMenuItems(ctx, arg)
 	if err != nil {
-		return []Item{}, err
+		return []Item{}, 0, err
 	}
 
-	var items []Item
+	total, err := s.store.CountMenuItems(ctx, sqlc.CountMenuItemsParams{
+		Lang:               lang,
+		Search:             search,
+		IncludeUnavailable: includeUnavailable,
+		ExcludeAllergens:   exclude,
+		DietaryTags:        diet,
+	})
+	if err != nil {
+		return []Item{}, 0, err
+	}
+
+	items := []Item{}
 	for _, item := range i {
 		items = append(items, Item{
 			ID:             item.ID,
 			Name:           item.Name,
 			Description:    item.Description,
 			Price:          item.Price,
+			CurrentPrice:   item.CurrentPrice,
+			Available:      item.Available,
 			CreatedAt:      item.CreatedAt,
 			RequiresTicket: item.RequiresTicket,
+			EightySixed:    item.EightySixed,
+			Allergens:      item.Allergens,
+			DietaryTags:    item.DietaryTags,
+			ImageURL:       s.blobURL(item.ImageKey),
+			ThumbnailURL:   s.blobURL(item.ThumbnailKey),
+			Highlight:      newItemHighlight(item.NameHighlight, item.DescriptionHighlight),
 		})
 	}
 
-	return items, nil
+	return items, int(total), nil
 }
 
 func (s *service) GetItemByID(ctx context.Context, id int32) (*Item, error) {
@@ -91,6 +155,11 @@ func (s *service) GetItemByID(ctx context.Context, id int32) (*Item, error) {
 		return nil, err
 	}
 
+	orderable, err := s.store.GetOrderableItems(ctx, []int32{id})
+	if err != nil {
+		return nil, err
+	}
+
 	item := &Item{
 		ID:             i.ID,
 		Name:           i.Name,
@@ -98,7 +167,616 @@ func (s *service) GetItemByID(ctx context.Context, id int32) (*Item, error) {
 		Price:          i.Price,
 		CreatedAt:      i.CreatedAt,
 		RequiresTicket: i.RequiresTicket,
+		EightySixed:    i.EightySixed,
+		DietaryTags:    i.DietaryTags,
+		ImageURL:       s.blobURL(i.ImageKey),
+		ThumbnailURL:   s.blobURL(i.ThumbnailKey),
+	}
+
+	for _, o := range orderable {
+		item.CurrentPrice = o.CurrentPrice
+		item.Available = o.Available
+		item.Allergens = o.Allergens
 	}
 
 	return item, nil
 }
+
+// Same as GetItemByID with the name and description in lang when the item has been translated to it
+func (s *service) GetItemInLanguage(ctx context.Context, id int32, lang string) (*Item, error) {
+	item, err := s.GetItemByID(ctx, id)
+	if err != nil || lang == config.Server().DefaultLanguage() {
+		return item, err
+	}
+
+	t, err := s.store.GetMenuItemTranslation(ctx, sqlc.GetMenuItemTranslationParams{ItemID: id, Lang: lang})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return item, nil
+		}
+		return nil, err
+	}
+
+	item.Name = t.Name
+	if t.Description.Valid {
+		item.Description = t.Description
+	}
+
+	return item, nil
+}
+
+func (s *service) GetItemTranslations(ctx context.Context, id int32) ([]Translation, error) {
+	if _, err := s.store.GetItemByID(ctx, id); err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return []Translation{}, api.ErrUnkownMenuItem.Error
+		}
+		return []Translation{}, err
+	}
+
+	rows, err := s.store.GetMenuItemTranslations(ctx, id)
+	if err != nil {
+		return []Translation{}, err
+	}
+
+	translations := []Translation{}
+	for _, t := range rows {
+		translations = append(translations, *newTranslation(t))
+	}
+
+	return translations, nil
+}
+
+// Adds or replaces the translation of the item, the default language is edited on the item itself
+func (s *service) SetItemTranslation(ctx context.Context, id int32, lang string, name string, description pgtype.Text) (*Translation, error) {
+	if !isTranslatable(lang) {
+		return nil, api.ErrUnsupportedLanguage.Error
+	}
+
+	arg := sqlc.UpsertMenuItemTranslationParams{
+		ItemID:      id,
+		Lang:        lang,
+		Name:        name,
+		Description: description,
+	}
+
+	t, err := s.store.UpsertMenuItemTranslation(ctx, arg)
+	if err != nil {
+		if db.GetSQLErrorCode(err) == db.ForeignKeyViolation {
+			return nil, api.ErrUnkownMenuItem.Error
+		}
+		return nil, err
+	}
+
+	return newTranslation(t), nil
+}
+
+func (s *service) DeleteItemTranslation(ctx context.Context, id int32, lang string) error {
+	n, err := s.store.DeleteMenuItemTranslation(ctx, sqlc.DeleteMenuItemTranslationParams{ItemID: id, Lang: lang})
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return api.ErrUnknownTranslation.Error
+	}
+
+	return nil
+}
+
+func isTranslatable(lang string) bool {
+	langs := config.Server().Languages
+	return slices.Contains(langs[1:], lang)
+}
+
+// Replaces the image of the item with the upload, which is stored as a full size and a thumbnail jpeg.
+// Every upload gets new keys so clients caching the old urls see the change.
+func (s *service) UploadItemImage(ctx context.Context, id int32, data []byte) (*Item, error) {
+	i, err := s.store.GetItemByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, api.ErrUnkownMenuItem.Error
+		}
+		return nil, err
+	}
+
+	if !sniffImage(data) {
+		return nil, api.ErrUnsupportedImage.Error
+	}
+
+	full, thumbnail, err := resizeImage(data)
+	if err != nil {
+		if errors.Is(err, api.ErrImageTooLarge.Error) {
+			return nil, err
+		}
+		return nil, api.ErrUnsupportedImage.Error
+	}
+
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("menu/%d/%s", id, hex.EncodeToString(suffix))
+	imageKey := pgtype.Text{String: key + "-full.jpg", Valid: true}
+	thumbnailKey := pgtype.Text{String: key + "-thumb.jpg", Valid: true}
+
+	if err := s.blobs.Put(ctx, imageKey.String, full, "image/jpeg"); err != nil {
+		return nil, err
+	}
+
+	if err := s.blobs.Put(ctx, thumbnailKey.String, thumbnail, "image/jpeg"); err != nil {
+		s.deleteBlobs(ctx, imageKey)
+		return nil, err
+	}
+
+	n, err := s.store.UpdateMenuItemImage(ctx, sqlc.UpdateMenuItemImageParams{ID: id, ImageKey: imageKey, ThumbnailKey: thumbnailKey})
+	if err != nil || n == 0 {
+		s.deleteBlobs(ctx, imageKey, thumbnailKey)
+		if err != nil {
+			return nil, err
+		}
+		return nil, api.ErrUnkownMenuItem.Error
+	}
+
+	// The old image isnt referenced anymore
+	s.deleteBlobs(ctx, i.ImageKey, i.ThumbnailKey)
+
+	return s.GetItemByID(ctx, id)
+}
+
+// Removes the blobs, failing only leaves an unused file behind so it is logged and ignored
+func (s *service) deleteBlobs(ctx context.Context, keys ...pgtype.Text) {
+	for _, k := range keys {
+		if !k.Valid {
+			continue
+		}
+		if err := s.blobs.Delete(ctx, k.String); err != nil {
+			log.Println("failed to delete blob", k.String, err)
+		}
+	}
+}
+
+func (s *service) blobURL(key pgtype.Text) string {
+	if !key.Valid {
+		return ""
+	}
+	return s.blobs.URL(key.String)
+}
+
+// Records the new cost of the item, effective from now
+func (s *service) SetItemCost(ctx context.Context, id int32, cost float64, createdBy pgtype.UUID) (*ItemCost, error) {
+	c, err := s.store.AddMenuItemCost(ctx, sqlc.AddMenuItemCostParams{ItemID: id, Cost: cost, CreatedBy: createdBy})
+	if err != nil {
+		if db.GetSQLErrorCode(err) == db.ForeignKeyViolation {
+			return nil, api.ErrUnkownMenuItem.Error
+		}
+		return nil, err
+	}
+
+	return newItemCost(c), nil
+}
+
+func (s *service) GetItemCosts(ctx context.Context, id int32) ([]ItemCost, error) {
+	if _, err := s.GetItemByID(ctx, id); err != nil {
+		return []ItemCost{}, err
+	}
+
+	c, err := s.store.GetMenuItemCosts(ctx, id)
+	if err != nil {
+		return []ItemCost{}, err
+	}
+
+	costs := []ItemCost{}
+	for _, cost := range c {
+		costs = append(costs, *newItemCost(cost))
+	}
+
+	return costs, nil
+}
+
+func (s *service) CreateMenu(ctx context.Context, name string, active bool, itemIDs []int32) (*Menu, error) {
+	m, err := s.store.CreateMenuTx(ctx, sqlc.CreateMenuParams{Name: name, Active: active}, itemIDs)
+	if err != nil {
+		switch db.GetSQLErrorCode(err) {
+		case db.UniqueViolation:
+			return nil, api.ErrMenuNameConflict.Error
+		case db.ForeignKeyViolation:
+			return nil, api.ErrUnkownMenuItem.Error
+		}
+		return nil, err
+	}
+
+	return s.GetMenuByID(ctx, m.ID)
+}
+
+// Updates the menu, itemIDs replaces every item on it
+func (s *service) UpdateMenu(ctx context.Context, id int32, name string, active bool, itemIDs []int32) (*Menu, error) {
+	_, err := s.store.UpdateMenuTx(ctx, sqlc.UpdateMenuParams{ID: id, Name: name, Active: active}, itemIDs)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, api.ErrUnknownMenu.Error
+		}
+		switch db.GetSQLErrorCode(err) {
+		case db.UniqueViolation:
+			return nil, api.ErrMenuNameConflict.Error
+		case db.ForeignKeyViolation:
+			return nil, api.ErrUnkownMenuItem.Error
+		}
+		return nil, err
+	}
+
+	return s.GetMenuByID(ctx, id)
+}
+
+func (s *service) GetMenus(ctx context.Context) ([]Menu, error) {
+	m, err := s.store.GetMenus(ctx)
+	if err != nil {
+		return []Menu{}, err
+	}
+
+	menus := []Menu{}
+	for _, menu := range m {
+		menus = append(menus, *newMenu(menu))
+	}
+
+	return menus, nil
+}
+
+func (s *service) GetMenuByID(ctx context.Context, id int32) (*Menu, error) {
+	m, err := s.store.GetMenuByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, api.ErrUnknownMenu.Error
+		}
+		return nil, err
+	}
+
+	itemIDs, err := s.store.GetMenuEntries(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	schedules, err := s.store.GetMenuSchedules(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	prices, err := s.store.GetSchedulePrices(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	menu := newMenu(m)
+	menu.ItemIDs = append([]int32{}, itemIDs...)
+	menu.Schedules = []Schedule{}
+	for _, sch := range schedules {
+		schedule := newSchedule(sch)
+		for _, p := range prices {
+			if p.ScheduleID == sch.ID {
+				schedule.Prices = append(schedule.Prices, SchedulePrice{ItemID: p.ItemID, Price: p.Price})
+			}
+		}
+		menu.Schedules = append(menu.Schedules, *schedule)
+	}
+
+	return menu, nil
+}
+
+func (s *service) DeleteMenu(ctx context.Context, id int32) error {
+	n, err := s.store.DeleteMenu(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return api.ErrUnknownMenu.Error
+	}
+
+	return nil
+}
+
+// Adds a schedule to the menu along with the prices its items have while it is on
+func (s *service) CreateSchedule(ctx context.Context, sch Schedule) (*Schedule, error) {
+	startTime, err := parseClock(sch.StartTime, 0)
+	if err != nil {
+		return nil, api.ErrInvalidMenuSchedule.Error
+	}
+
+	endTime, err := parseClock(sch.EndTime, 24*time.Hour)
+	if err != nil {
+		return nil, api.ErrInvalidMenuSchedule.Error
+	}
+
+	if sch.StartDate.Valid && sch.EndDate.Valid && sch.StartDate.Time.After(sch.EndDate.Time) {
+		return nil, api.ErrInvalidMenuSchedule.Error
+	}
+
+	if _, err := s.store.GetMenuByID(ctx, sch.MenuID); err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, api.ErrUnknownMenu.Error
+		}
+		return nil, err
+	}
+
+	itemIDs := make([]int32, 0, len(sch.Prices))
+	prices := make([]float64, 0, len(sch.Prices))
+	for _, p := range sch.Prices {
+		itemIDs = append(itemIDs, p.ItemID)
+		prices = append(prices, p.Price)
+	}
+
+	arg := sqlc.CreateMenuScheduleParams{
+		MenuID:    sch.MenuID,
+		Name:      sch.Name,
+		Days:      append([]int16{}, sch.Days...),
+		StartTime: startTime,
+		EndTime:   endTime,
+		StartDate: sch.StartDate,
+		EndDate:   sch.EndDate,
+	}
+
+	created, err := s.store.CreateMenuScheduleTx(ctx, arg, itemIDs, prices)
+	if err != nil {
+		switch db.GetSQLErrorCode(err) {
+		// The menu was checked above so it has to be one of the items
+		case db.ForeignKeyViolation:
+			return nil, api.ErrUnkownMenuItem.Error
+		case db.CheckViolation:
+			return nil, api.ErrInvalidMenuSchedule.Error
+		}
+		return nil, err
+	}
+
+	schedule := newSchedule(created)
+	schedule.Prices = append(schedule.Prices, sch.Prices...)
+
+	return schedule, nil
+}
+
+func (s *service) DeleteSchedule(ctx context.Context, menuID int32, scheduleID int32) error {
+	n, err := s.store.DeleteMenuSchedule(ctx, sqlc.DeleteMenuScheduleParams{ID: scheduleID, MenuID: menuID})
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return api.ErrUnknownMenuSchedule.Error
+	}
+
+	return nil
+}
+
+func (s *service) CreateCombo(ctx context.Context, c Combo) (*Combo, error) {
+	slots, options := comboSlotParams(c.Slots)
+
+	arg := sqlc.CreateComboParams{
+		Name:        c.Name,
+		Description: c.Description,
+		Price:       c.Price,
+		Active:      c.Active,
+	}
+
+	combo, err := s.store.CreateComboTx(ctx, arg, slots, options)
+	if err != nil {
+		switch db.GetSQLErrorCode(err) {
+		case db.UniqueViolation:
+			return nil, api.ErrComboNameConflict.Error
+		case db.ForeignKeyViolation:
+			return nil, api.ErrUnkownMenuItem.Error
+		}
+		return nil, err
+	}
+
+	return s.GetComboByID(ctx, combo.ID)
+}
+
+// Updates the combo, c.Slots replaces all of its slots
+func (s *service) UpdateCombo(ctx context.Context, c Combo) (*Combo, error) {
+	slots, options := comboSlotParams(c.Slots)
+
+	arg := sqlc.UpdateComboParams{
+		ID:          c.ID,
+		Name:        c.Name,
+		Description: c.Description,
+		Price:       c.Price,
+		Active:      c.Active,
+	}
+
+	_, err := s.store.UpdateComboTx(ctx, arg, slots, options)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, api.ErrUnknownCombo.Error
+		}
+		switch db.GetSQLErrorCode(err) {
+		case db.UniqueViolation:
+			return nil, api.ErrComboNameConflict.Error
+		case db.ForeignKeyViolation:
+			return nil, api.ErrUnkownMenuItem.Error
+		}
+		return nil, err
+	}
+
+	return s.GetComboByID(ctx, c.ID)
+}
+
+func (s *service) GetCombos(ctx context.Context, activeOnly bool) ([]Combo, error) {
+	c, err := s.store.GetCombos(ctx, activeOnly)
+	if err != nil {
+		return []Combo{}, err
+	}
+
+	return s.withSlots(ctx, c)
+}
+
+func (s *service) GetComboByID(ctx context.Context, id int32) (*Combo, error) {
+	c, err := s.store.GetComboByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, api.ErrUnknownCombo.Error
+		}
+		return nil, err
+	}
+
+	combos, err := s.withSlots(ctx, []sqlc.Combo{c})
+	if err != nil {
+		return nil, err
+	}
+
+	return &combos[0], nil
+}
+
+// Loads the slots and options of every combo
+func (s *service) withSlots(ctx context.Context, c []sqlc.Combo) ([]Combo, error) {
+	ids := make([]int32, 0, len(c))
+	for _, combo := range c {
+		ids = append(ids, combo.ID)
+	}
+
+	slots, err := s.store.GetComboSlots(ctx, ids)
+	if err != nil {
+		return []Combo{}, err
+	}
+
+	options, err := s.store.GetComboSlotOptions(ctx, ids)
+	if err != nil {
+		return []Combo{}, err
+	}
+
+	combos := []Combo{}
+	for _, combo := range c {
+		item := Combo{
+			ID:          combo.ID,
+			Name:        combo.Name,
+			Description: combo.Description,
+			Price:       combo.Price,
+			Active:      combo.Active,
+			Slots:       []ComboSlot{},
+			CreatedAt:   combo.CreatedAt,
+		}
+
+		for _, slot := range slots {
+			if slot.ComboID != combo.ID {
+				continue
+			}
+
+			comboSlot := ComboSlot{ID: slot.ID, Name: slot.Name, Options: []ComboOption{}}
+			for _, o := range options {
+				if o.SlotID == slot.ID {
+					comboSlot.Options = append(comboSlot.Options, ComboOption{
+						ItemID:   o.ItemID,
+						Name:     o.Name,
+						Price:    o.Price,
+						Upcharge: o.Upcharge,
+					})
+				}
+			}
+
+			item.Slots = append(item.Slots, comboSlot)
+		}
+
+		combos = append(combos, item)
+	}
+
+	return combos, nil
+}
+
+// Turns the slots into store params, options[i] are the items that can be picked for slots[i]
+func comboSlotParams(comboSlots []ComboSlot) ([]sqlc.CreateComboSlotParams, []sqlc.AddComboSlotOptionsParams) {
+	slots := make([]sqlc.CreateComboSlotParams, 0, len(comboSlots))
+	options := make([]sqlc.AddComboSlotOptionsParams, 0, len(comboSlots))
+	for i, slot := range comboSlots {
+		slots = append(slots, sqlc.CreateComboSlotParams{Name: slot.Name, Position: int16(i)})
+
+		var opts sqlc.AddComboSlotOptionsParams
+		for _, o := range slot.Options {
+			opts.ItemIds = append(opts.ItemIds, o.ItemID)
+			opts.Upcharges = append(opts.Upcharges, o.Upcharge)
+		}
+		options = append(options, opts)
+	}
+
+	return slots, options
+}
+
+// Parses a HH:MM time of day, 24:00 is allowed to end a window at midnight
+func parseClock(s string, fallback time.Duration) (pgtype.Time, error) {
+	if s == "" {
+		return pgtype.Time{Microseconds: fallback.Microseconds(), Valid: true}, nil
+	}
+
+	if s == "24:00" {
+		return pgtype.Time{Microseconds: (24 * time.Hour).Microseconds(), Valid: true}, nil
+	}
+
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return pgtype.Time{}, err
+	}
+
+	d := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	return pgtype.Time{Microseconds: d.Microseconds(), Valid: true}, nil
+}
+
+func formatClock(t pgtype.Time) string {
+	d := time.Duration(t.Microseconds) * time.Microsecond
+	return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// The columns are NOT NULL so a missing list is stored as an empty one
+func orEmpty(l []string) []string {
+	if l == nil {
+		return []string{}
+	}
+	return l
+}
+
+func newMenu(m sqlc.Menu) *Menu {
+	return &Menu{
+		ID:        m.ID,
+		Name:      m.Name,
+		Active:    m.Active,
+		CreatedAt: m.CreatedAt,
+	}
+}
+
+func newSchedule(s sqlc.MenuSchedule) *Schedule {
+	return &Schedule{
+		ID:        s.ID,
+		MenuID:    s.MenuID,
+		Name:      s.Name,
+		Days:      append([]int16{}, s.Days...),
+		StartTime: formatClock(s.StartTime),
+		EndTime:   formatClock(s.EndTime),
+		StartDate: s.StartDate,
+		EndDate:   s.EndDate,
+		Prices:    []SchedulePrice{},
+		CreatedAt: s.CreatedAt,
+	}
+}
+
+func newItemHighlight(name pgtype.Text, description pgtype.Text) *ItemHighlight {
+	if !name.Valid {
+		return nil
+	}
+	return &ItemHighlight{Name: name.String, Description: description.String}
+}
+
+func newTranslation(t sqlc.MenuItemTranslation) *Translation {
+	return &Translation{
+		ItemID:      t.ItemID,
+		Lang:        t.Lang,
+		Name:        t.Name,
+		Description: t.Description,
+		UpdatedAt:   t.UpdatedAt,
+	}
+}
+
+func newItemCost(c sqlc.MenuItemCost) *ItemCost {
+	return &ItemCost{
+		ID:            c.ID,
+		ItemID:        c.ItemID,
+		Cost:          c.Cost,
+		EffectiveFrom: c.EffectiveFrom,
+		CreatedBy:     c.CreatedBy,
+	}
+}
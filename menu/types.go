@@ -1,26 +1,33 @@
 package menu
 
-import "github.com/jackc/pgx/v5/pgtype"
+import (
+	"github.com/pdridh/k-line/api"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// The 14 major allergens that have to be declared
+var Allergens = []string{
+	"celery", "gluten", "crustaceans", "eggs", "fish", "lupin", "milk",
+	"molluscs", "mustard", "tree_nuts", "peanuts", "sesame", "soya", "sulphites",
+}
+
+var DietaryTags = []string{"vegan", "vegetarian", "gluten_free", "halal"}
 
 type MenuFilters struct {
-	Search string `json:"search"`
-	Page   int32  `json:"page"` // The request is sent as page but converted to offset for db
-	Limit  int32  `json:"limit"`
+	Search  string   `json:"search"`                           // Matched against names and descriptions, results are ranked by relevance
+	All     bool     `json:"all"`                              // Also list items whose menus arent being served right now
+	Exclude []string `json:"exclude" validate:"dive,allergen"` // Leave out items containing any of these allergens
+	Diet    []string `json:"diet" validate:"dive,dietary_tag"` // Only items that have all of these tags
+	Page    int32    `json:"page"`                             // The request is sent as page but converted to offset for db
+	Limit   int32    `json:"limit"`
 }
 
 func (f *MenuFilters) Validate(maxLimit int32, defaultLimit int32) {
-	// Normalize pagination
-	if f.Limit <= 0 || f.Limit > maxLimit {
-		f.Limit = defaultLimit
-	}
-
-	if f.Page < 1 {
-		f.Page = 1
-	}
+	api.ClampPage(&f.Limit, &f.Page, maxLimit, defaultLimit)
 
-	if f.Search != "" {
-		f.Search = "%" + f.Search + "%"
-	}
+	f.Search = strings.TrimSpace(f.Search)
 }
 
 type Item struct {
@@ -28,6 +35,95 @@ type Item struct {
 	Name           string           `json:"name"`
 	Description    pgtype.Text      `json:"description"`
 	Price          float64          `json:"price"`
+	CurrentPrice   float64          `json:"current_price"` // Price right now, lower than Price during a scheduled offer
+	Available      bool             `json:"available"`     // On a menu that is being served right now
 	RequiresTicket bool             `json:"requires_ticket"`
+	EightySixed    bool             `json:"eighty_sixed"` // Out of stock, cant be ordered
+	Allergens      []string         `json:"allergens"`    // Its own and the ones from the ingredients in its recipe
+	DietaryTags    []string         `json:"dietary_tags"`
+	ImageURL       string           `json:"image_url,omitempty"`
+	ThumbnailURL   string           `json:"thumbnail_url,omitempty"`
+	Highlight      *ItemHighlight   `json:"highlight,omitempty"` // Only set when searching
 	CreatedAt      pgtype.Timestamp `json:"created_at"`
 }
+
+// Name and description html escaped, with the words matching the search wrapped in <mark>
+type ItemHighlight struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// Name and description of an item in a language other than the default
+type Translation struct {
+	ItemID      int32            `json:"item_id"`
+	Lang        string           `json:"lang"`
+	Name        string           `json:"name"`
+	Description pgtype.Text      `json:"description"` // The default description is shown when not set
+	UpdatedAt   pgtype.Timestamp `json:"updated_at"`
+}
+
+// What an item costs to make, a new entry is added every time the cost changes
+type ItemCost struct {
+	ID            int64            `json:"id"`
+	ItemID        int32            `json:"item_id"`
+	Cost          float64          `json:"cost"`
+	EffectiveFrom pgtype.Timestamp `json:"effective_from"`
+	CreatedBy     pgtype.UUID      `json:"created_by"`
+}
+
+// A set of items served together like brunch or the bar menu.
+// ItemIDs and Schedules are only set when a single menu is retrieved.
+type Menu struct {
+	ID        int32            `json:"id"`
+	Name      string           `json:"name"`
+	Active    bool             `json:"active"`
+	ItemIDs   []int32          `json:"item_ids,omitempty"`
+	Schedules []Schedule       `json:"schedules,omitempty"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
+// When a menu is served. Times are HH:MM and a window ending before it starts runs past midnight,
+// Days are 0 (sunday) to 6 and empty means every day.
+type Schedule struct {
+	ID        int32            `json:"id"`
+	MenuID    int32            `json:"menu_id"`
+	Name      string           `json:"name"`
+	Days      []int16          `json:"days"`
+	StartTime string           `json:"start_time"`
+	EndTime   string           `json:"end_time"`
+	StartDate pgtype.Date      `json:"start_date"`
+	EndDate   pgtype.Date      `json:"end_date"`
+	Prices    []SchedulePrice  `json:"prices"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
+// Price of an item while the schedule is on
+type SchedulePrice struct {
+	ItemID int32   `json:"item_id" validate:"required"`
+	Price  float64 `json:"price" validate:"gte=0"`
+}
+
+// Items sold together at a fixed price, like a set lunch
+type Combo struct {
+	ID          int32            `json:"id"`
+	Name        string           `json:"name"`
+	Description pgtype.Text      `json:"description"`
+	Price       float64          `json:"price"`
+	Active      bool             `json:"active"`
+	Slots       []ComboSlot      `json:"slots"`
+	CreatedAt   pgtype.Timestamp `json:"created_at"`
+}
+
+// One pick the guest makes when ordering the combo, like the starter
+type ComboSlot struct {
+	ID      int32         `json:"id"`
+	Name    string        `json:"name" validate:"required"`
+	Options []ComboOption `json:"options" validate:"required,min=1,unique=ItemID,dive"`
+}
+
+type ComboOption struct {
+	ItemID   int32   `json:"item_id" validate:"required"`
+	Name     string  `json:"name"`
+	Price    float64 `json:"price"`                     // Menu price of the item on its own
+	Upcharge float64 `json:"upcharge" validate:"gte=0"` // Added to the combo price when picked
+}
@@ -0,0 +1,204 @@
+package promotion
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/pdridh/k-line/api"
+	"github.com/pkg/errors"
+)
+
+type handler struct {
+	Service *service
+}
+
+func NewHandler(s *service) *handler {
+	return &handler{
+		Service: s,
+	}
+}
+
+func writePromotionError(w http.ResponseWriter, r *http.Request, err error) {
+	for _, e := range rejections {
+		if errors.Is(err, e.Error) {
+			status := http.StatusBadRequest
+			if e == api.ErrPromotionApplied || e == api.ErrPromotionStacked {
+				status = http.StatusConflict
+			}
+			api.WriteError(w, r, status, e, nil)
+			return
+		}
+	}
+
+	switch {
+	case errors.Is(err, api.ErrUnknownPromotion.Error),
+		errors.Is(err, api.ErrUnknownOrder.Error):
+		api.WriteNotFoundError(w, r)
+	case errors.Is(err, api.ErrPromotionCodeConflict.Error):
+		api.WriteError(w, r, http.StatusConflict, api.ErrPromotionCodeConflict, nil)
+	case errors.Is(err, api.ErrInvalidPromotion.Error):
+		api.WriteError(w, r, http.StatusBadRequest, api.ErrInvalidPromotion, nil)
+	case errors.Is(err, api.ErrUnkownMenuItem.Error):
+		api.WriteError(w, r, http.StatusBadRequest, api.ErrUnkownMenuItem, nil)
+	case errors.Is(err, api.ErrOrderNotOngoing.Error):
+		api.WriteError(w, r, http.StatusConflict, api.ErrOrderNotOngoing, nil)
+	default:
+		api.WriteInternalError(w, r)
+	}
+}
+
+func (h *handler) GetPromotions() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var filters PromotionFilters
+
+		api.ParseQueryParams(r.URL.Query(), &filters)
+		filters.Validate(100, 50)
+
+		p, total, err := h.Service.GetPromotions(r.Context(), filters.ActiveOnly, filters.Limit, (filters.Page-1)*filters.Limit)
+		if err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		meta := api.CalculatePaginationMeta(total, int(filters.Page), int(filters.Limit))
+		api.WriteSuccess(w, r, http.StatusOK, "Retrieval successful", api.NewPaginatedResponse(p, meta))
+	}
+}
+
+func (h *handler) GetPromotion() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		p, err := h.Service.GetPromotion(r.Context(), int32(id))
+		if err != nil {
+			writePromotionError(w, r, err)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Retrieval successful", p)
+	}
+}
+
+func (h *handler) CreatePromotion() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var p RequestPromotion
+		if err := api.ParseJSON(r, &p); err != nil {
+			api.WriteBadRequestError(w, r)
+			return
+		}
+
+		if err := h.Service.Validate.Struct(p); err != nil {
+			api.WriteValidationError(w, r, err)
+			return
+		}
+
+		var userID pgtype.UUID
+		if err := userID.Scan(api.CurrentUserID(r)); err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		created, err := h.Service.CreatePromotion(r.Context(), p, userID)
+		if err != nil {
+			writePromotionError(w, r, err)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusCreated, "Created new promotion", created)
+	}
+}
+
+// Replaces every rule of the promotion, an active of false turns it off
+func (h *handler) UpdatePromotion() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		var p RequestPromotion
+		if err := api.ParseJSON(r, &p); err != nil {
+			api.WriteBadRequestError(w, r)
+			return
+		}
+
+		if err := h.Service.Validate.Struct(p); err != nil {
+			api.WriteValidationError(w, r, err)
+			return
+		}
+
+		updated, err := h.Service.UpdatePromotion(r.Context(), int32(id), p)
+		if err != nil {
+			writePromotionError(w, r, err)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Successfully updated promotion", updated)
+	}
+}
+
+// Shows what the promotions offered without a code (and the one of the code query param) would take
+// off the order
+func (h *handler) EvaluateOrder() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := pgtype.UUID{}
+		if err := id.Scan(r.PathValue("id")); err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		e, err := h.Service.EvaluateOrder(r.Context(), id, r.URL.Query().Get("code"))
+		if err != nil {
+			writePromotionError(w, r, err)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Retrieval successful", e)
+	}
+}
+
+func (h *handler) ApplyPromotion() http.HandlerFunc {
+	type RequestPayload struct {
+		PromotionID int32  `json:"promotion_id" validate:"required_without=Code"`
+		Code        string `json:"code" validate:"required_without=PromotionID"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := pgtype.UUID{}
+		if err := id.Scan(r.PathValue("id")); err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		var p RequestPayload
+		if err := api.ParseJSON(r, &p); err != nil {
+			api.WriteBadRequestError(w, r)
+			return
+		}
+
+		if err := h.Service.Validate.Struct(p); err != nil {
+			api.WriteValidationError(w, r, err)
+			return
+		}
+
+		var userID pgtype.UUID
+		if err := userID.Scan(api.CurrentUserID(r)); err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		applied, err := h.Service.ApplyPromotion(r.Context(), id, p.PromotionID, p.Code, userID)
+		if err != nil {
+			writePromotionError(w, r, err)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusCreated, "Successfully applied promotion", applied)
+	}
+}
@@ -0,0 +1,561 @@
+package promotion
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/pdridh/k-line/api"
+	"github.com/pdridh/k-line/db"
+	"github.com/pdridh/k-line/db/sqlc"
+	"github.com/pkg/errors"
+)
+
+type service struct {
+	Validate *validator.Validate
+	store    db.Store
+}
+
+func NewService(v *validator.Validate, s db.Store) *service {
+	return &service{
+		Validate: v,
+		store:    s,
+	}
+}
+
+// The reasons a promotion can be turned down for an order
+var rejections = []api.APIError{
+	api.ErrPromotionInactive,
+	api.ErrPromotionCodeRequired,
+	api.ErrPromotionNotOn,
+	api.ErrPromotionOrderType,
+	api.ErrPromotionMinSpend,
+	api.ErrPromotionItems,
+	api.ErrPromotionUsedUp,
+	api.ErrPromotionCustomerLimit,
+	api.ErrPromotionApplied,
+	api.ErrPromotionStacked,
+	api.ErrOrderNoCustomer,
+}
+
+func (s *service) CreatePromotion(ctx context.Context, req RequestPromotion, createdBy pgtype.UUID) (*Promotion, error) {
+	arg, err := promotionParams(req)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := s.store.CreatePromotion(ctx, sqlc.CreatePromotionParams{
+		Name:          arg.Name,
+		Code:          arg.Code,
+		Type:          arg.Type,
+		Value:         arg.Value,
+		BuyItemID:     arg.BuyItemID,
+		BuyQuantity:   arg.BuyQuantity,
+		GetItemID:     arg.GetItemID,
+		GetQuantity:   arg.GetQuantity,
+		MinSpend:      arg.MinSpend,
+		OrderTypes:    arg.OrderTypes,
+		Days:          arg.Days,
+		StartTime:     arg.StartTime,
+		EndTime:       arg.EndTime,
+		StartDate:     arg.StartDate,
+		EndDate:       arg.EndDate,
+		UsageLimit:    arg.UsageLimit,
+		CustomerLimit: arg.CustomerLimit,
+		Active:        arg.Active,
+		CreatedBy:     createdBy,
+	})
+	if err != nil {
+		return nil, promotionStoreError(err)
+	}
+
+	return newPromotion(p), nil
+}
+
+// Replaces the rules of the promotion, discounts it already gave stay as they were
+func (s *service) UpdatePromotion(ctx context.Context, id int32, req RequestPromotion) (*Promotion, error) {
+	arg, err := promotionParams(req)
+	if err != nil {
+		return nil, err
+	}
+
+	arg.ID = id
+	p, err := s.store.UpdatePromotion(ctx, arg)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, errors.Wrap(api.ErrUnknownPromotion.Error, "store")
+		}
+		return nil, promotionStoreError(err)
+	}
+
+	return newPromotion(p), nil
+}
+
+func (s *service) GetPromotions(ctx context.Context, activeOnly bool, limit int32, offset int32) ([]Promotion, int, error) {
+	rows, err := s.store.GetPromotions(ctx, sqlc.GetPromotionsParams{ActiveOnly: activeOnly, Limit: limit, Offset: offset})
+	if err != nil {
+		return []Promotion{}, 0, errors.Wrap(err, "store")
+	}
+
+	total, err := s.store.CountPromotions(ctx, activeOnly)
+	if err != nil {
+		return []Promotion{}, 0, errors.Wrap(err, "store")
+	}
+
+	promotions := []Promotion{}
+	for _, p := range rows {
+		promotions = append(promotions, *newPromotion(p))
+	}
+
+	return promotions, int(total), nil
+}
+
+func (s *service) GetPromotion(ctx context.Context, id int32) (*Promotion, error) {
+	p, err := s.store.GetPromotionByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, errors.Wrap(api.ErrUnknownPromotion.Error, "store")
+		}
+		return nil, errors.Wrap(err, "store")
+	}
+
+	return newPromotion(p), nil
+}
+
+// Works out every promotion that is offered without a code against the order, along with the one
+// the code is for if there is one, without applying any of them
+func (s *service) EvaluateOrder(ctx context.Context, orderID pgtype.UUID, code string) ([]Evaluation, error) {
+	o, items, err := s.loadOrder(ctx, orderID)
+	if err != nil {
+		return []Evaluation{}, err
+	}
+
+	promotions, err := s.store.GetAutomaticPromotions(ctx)
+	if err != nil {
+		return []Evaluation{}, errors.Wrap(err, "store")
+	}
+
+	if code != "" {
+		p, err := s.getByCode(ctx, code)
+		if err != nil {
+			return []Evaluation{}, err
+		}
+		promotions = append(promotions, p)
+	}
+
+	discounts, err := s.store.GetOrderDiscounts(ctx, orderID)
+	if err != nil {
+		return []Evaluation{}, errors.Wrap(err, "store")
+	}
+
+	evaluations := []Evaluation{}
+	for _, p := range promotions {
+		e := Evaluation{
+			PromotionID: p.ID,
+			Name:        p.Name,
+			Code:        p.Code,
+		}
+
+		on, err := s.store.IsPromotionOn(ctx, p.ID)
+		if err != nil {
+			return []Evaluation{}, errors.Wrap(err, "store")
+		}
+
+		amount, description, err := evaluate(p, on, o.Type, items, discountTotal(discounts))
+		if err == nil {
+			err = s.checkUsage(ctx, p, o, discounts)
+		}
+
+		if err != nil {
+			if e.Reason = reason(err); e.Reason == "" {
+				return []Evaluation{}, err
+			}
+		} else {
+			e.Eligible = true
+			e.Description = description
+			e.Amount = amount
+		}
+
+		evaluations = append(evaluations, e)
+	}
+
+	return evaluations, nil
+}
+
+// Applies the promotion with the code, or the one with promotionID when there is no code, to the
+// order. The discount is worked out with the order locked and stored with it so later changes to the
+// promotion dont change it.
+func (s *service) ApplyPromotion(ctx context.Context, orderID pgtype.UUID, promotionID int32, code string, createdBy pgtype.UUID) (*Applied, error) {
+	var p sqlc.Promotion
+	var err error
+	if code != "" {
+		if p, err = s.getByCode(ctx, code); err != nil {
+			return nil, err
+		}
+	} else {
+		if p, err = s.store.GetPromotionByID(ctx, promotionID); err != nil {
+			if errors.Is(err, db.ErrRecordNotFound) {
+				return nil, errors.Wrap(api.ErrUnknownPromotion.Error, "store")
+			}
+			return nil, errors.Wrap(err, "store")
+		}
+
+		if p.Code.Valid {
+			return nil, errors.Wrap(api.ErrPromotionCodeRequired.Error, "code")
+		}
+	}
+
+	d, err := s.store.ApplyPromotionTx(ctx, p.ID, orderID, func(p sqlc.Promotion, on bool, o sqlc.Order, items []sqlc.OrderItem, discounts []sqlc.OrderDiscount) (sqlc.CreateOrderDiscountParams, error) {
+		if o.Status != sqlc.OrderStatusOngoing {
+			return sqlc.CreateOrderDiscountParams{}, errors.Wrap(api.ErrOrderNotOngoing.Error, "status")
+		}
+
+		if err := checkOrder(p, o, discounts); err != nil {
+			return sqlc.CreateOrderDiscountParams{}, err
+		}
+
+		amount, description, err := evaluate(p, on, o.Type, items, discountTotal(discounts))
+		if err != nil {
+			return sqlc.CreateOrderDiscountParams{}, err
+		}
+
+		return sqlc.CreateOrderDiscountParams{
+			OrderID:     orderID,
+			Type:        sqlc.DiscountTypePromotion,
+			Description: description,
+			Amount:      amount,
+			CreatedBy:   createdBy,
+			PromotionID: pgtype.Int4{Int32: p.ID, Valid: true},
+			CustomerID:  o.CustomerID,
+		}, nil
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, db.ErrRecordNotFound):
+			return nil, errors.Wrap(api.ErrUnknownOrder.Error, "store")
+		case errors.Is(err, db.ErrUsageLimitReached):
+			return nil, errors.Wrap(api.ErrPromotionUsedUp.Error, "store")
+		case errors.Is(err, db.ErrCustomerLimitReached):
+			return nil, errors.Wrap(api.ErrPromotionCustomerLimit.Error, "store")
+		case db.GetSQLErrorCode(err) == db.UniqueViolation:
+			return nil, errors.Wrap(api.ErrPromotionApplied.Error, "store")
+		}
+		// Otherwise it is the reason the promotion was turned down, or a store error
+		return nil, errors.Wrap(err, "store")
+	}
+
+	return &Applied{
+		DiscountID:  d.ID,
+		OrderID:     orderID,
+		PromotionID: p.ID,
+		Description: d.Description,
+		Amount:      d.Amount,
+	}, nil
+}
+
+func (s *service) loadOrder(ctx context.Context, orderID pgtype.UUID) (sqlc.Order, []sqlc.OrderItem, error) {
+	o, err := s.store.GetOrderByID(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return sqlc.Order{}, nil, errors.Wrap(api.ErrUnknownOrder.Error, "store")
+		}
+		return sqlc.Order{}, nil, errors.Wrap(err, "store")
+	}
+
+	items, err := s.store.GetOrderItems(ctx, orderID)
+	if err != nil {
+		return sqlc.Order{}, nil, errors.Wrap(err, "store")
+	}
+
+	return o, items, nil
+}
+
+func (s *service) getByCode(ctx context.Context, code string) (sqlc.Promotion, error) {
+	p, err := s.store.GetPromotionByCode(ctx, pgtype.Text{String: normalizeCode(code), Valid: true})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return sqlc.Promotion{}, errors.Wrap(api.ErrUnknownPromotion.Error, "store")
+		}
+		return sqlc.Promotion{}, errors.Wrap(err, "store")
+	}
+
+	return p, nil
+}
+
+// Checks the order can take p and that p still has uses left for it. ApplyPromotionTx counts the uses
+// again with the promotion locked.
+func (s *service) checkUsage(ctx context.Context, p sqlc.Promotion, o sqlc.Order, discounts []sqlc.OrderDiscount) error {
+	if err := checkOrder(p, o, discounts); err != nil {
+		return err
+	}
+
+	if !p.UsageLimit.Valid && !p.CustomerLimit.Valid {
+		return nil
+	}
+
+	u, err := s.store.GetPromotionUsage(ctx, sqlc.GetPromotionUsageParams{CustomerID: o.CustomerID.Int32, PromotionID: p.ID})
+	if err != nil {
+		return errors.Wrap(err, "store")
+	}
+
+	if p.UsageLimit.Valid && u.Uses >= int64(p.UsageLimit.Int32) {
+		return errors.Wrap(api.ErrPromotionUsedUp.Error, "usage")
+	}
+
+	if p.CustomerLimit.Valid && u.CustomerUses >= int64(p.CustomerLimit.Int32) {
+		return errors.Wrap(api.ErrPromotionCustomerLimit.Error, "usage")
+	}
+
+	return nil
+}
+
+// Checks the order doesnt have a promotion yet, only one can be applied to an order, and that it is
+// linked to a customer when p has a per customer limit
+func checkOrder(p sqlc.Promotion, o sqlc.Order, discounts []sqlc.OrderDiscount) error {
+	for _, d := range discounts {
+		if d.PromotionID.Valid && d.PromotionID.Int32 == p.ID {
+			return errors.Wrap(api.ErrPromotionApplied.Error, "usage")
+		}
+		if d.PromotionID.Valid {
+			return errors.Wrap(api.ErrPromotionStacked.Error, "usage")
+		}
+	}
+
+	if p.CustomerLimit.Valid && !o.CustomerID.Valid {
+		return errors.Wrap(api.ErrOrderNoCustomer.Error, "usage")
+	}
+
+	return nil
+}
+
+// Works out what p takes off an order of orderType with the items, on is whether the time window of p
+// is open (IsPromotionOn) and discounted what other discounts already took off the order. It only
+// depends on its arguments so the same order always gets the same discount, cancelled items never
+// count and the cheapest get items are the ones made free. The min spend is checked against the
+// subtotal but the discount is worked out on what is left after the other discounts, and is never
+// more than that.
+func evaluate(p sqlc.Promotion, on bool, orderType sqlc.OrderType, items []sqlc.OrderItem, discounted float64) (float64, string, error) {
+	if !p.Active {
+		return 0, "", errors.Wrap(api.ErrPromotionInactive.Error, "evaluate")
+	}
+
+	if !on {
+		return 0, "", errors.Wrap(api.ErrPromotionNotOn.Error, "evaluate")
+	}
+
+	if len(p.OrderTypes) > 0 && !slices.Contains(p.OrderTypes, string(orderType)) {
+		return 0, "", errors.Wrap(api.ErrPromotionOrderType.Error, "evaluate")
+	}
+
+	subtotal := 0.0
+	for _, i := range items {
+		if i.Status != sqlc.OrderItemStatusCancelled {
+			subtotal += float64(i.Quantity) * i.UnitPrice
+		}
+	}
+
+	if subtotal < p.MinSpend {
+		return 0, "", errors.Wrap(api.ErrPromotionMinSpend.Error, "evaluate")
+	}
+
+	remaining := math.Max(subtotal-discounted, 0)
+
+	var amount float64
+	var description string
+	switch p.Type {
+	case sqlc.PromotionTypePercentage:
+		amount = remaining * p.Value / 100
+		description = fmt.Sprintf("%s (%g%% off)", p.Name, p.Value)
+	case sqlc.PromotionTypeFixed:
+		amount = p.Value
+		description = p.Name
+	case sqlc.PromotionTypeBuyXGetY:
+		amount = buyXGetY(p, items)
+		description = fmt.Sprintf("%s (buy %d get %d)", p.Name, p.BuyQuantity, p.GetQuantity)
+	}
+
+	amount = api.Round(math.Min(amount, remaining))
+	if amount <= 0 {
+		return 0, "", errors.Wrap(api.ErrPromotionItems.Error, "evaluate")
+	}
+
+	if p.Code.Valid {
+		description = fmt.Sprintf("%s, code %s", description, p.Code.String)
+	}
+
+	return amount, description, nil
+}
+
+// Every BuyQuantity of the buy item gets GetQuantity of the get item Value percent off. Items that
+// are part of a combo are already discounted so they dont count.
+func buyXGetY(p sqlc.Promotion, items []sqlc.OrderItem) float64 {
+	var bought int32
+	var prices []float64
+	for _, i := range items {
+		if i.Status == sqlc.OrderItemStatusCancelled || i.OrderComboID.Valid {
+			continue
+		}
+
+		if i.ItemID == p.BuyItemID.Int32 {
+			bought += i.Quantity
+		}
+
+		if i.ItemID == p.GetItemID.Int32 {
+			for range i.Quantity {
+				prices = append(prices, i.UnitPrice)
+			}
+		}
+	}
+
+	var sets int32
+	if p.BuyItemID.Int32 == p.GetItemID.Int32 {
+		// The get items come out of the same ones that were bought
+		sets = bought / (p.BuyQuantity + p.GetQuantity)
+	} else {
+		sets = min(bought/p.BuyQuantity, int32(len(prices))/p.GetQuantity)
+	}
+
+	slices.Sort(prices)
+
+	amount := 0.0
+	for _, price := range prices[:sets*p.GetQuantity] {
+		amount += price * p.Value / 100
+	}
+
+	return amount
+}
+
+// What the discounts take off the order altogether
+func discountTotal(discounts []sqlc.OrderDiscount) float64 {
+	total := 0.0
+	for _, d := range discounts {
+		total += d.Amount
+	}
+	return total
+}
+
+// Checks the rules of the promotion and fills in the defaults, the buy and get fields are cleared
+// for types that dont use them
+func promotionParams(req RequestPromotion) (sqlc.UpdatePromotionParams, error) {
+	arg := sqlc.UpdatePromotionParams{
+		Name:          req.Name,
+		Type:          req.Type,
+		Value:         req.Value,
+		BuyQuantity:   1,
+		GetQuantity:   1,
+		MinSpend:      req.MinSpend,
+		OrderTypes:    orEmpty(req.OrderTypes),
+		Days:          append([]int16{}, req.Days...),
+		StartDate:     req.StartDate,
+		EndDate:       req.EndDate,
+		UsageLimit:    req.UsageLimit,
+		CustomerLimit: req.CustomerLimit,
+		Active:        req.Active == nil || *req.Active,
+	}
+
+	if code := normalizeCode(req.Code); code != "" {
+		arg.Code = pgtype.Text{String: code, Valid: true}
+	}
+
+	if req.Type != sqlc.PromotionTypeFixed && req.Value > 100 {
+		return arg, errors.Wrap(api.ErrInvalidPromotion.Error, "value")
+	}
+
+	if req.Type == sqlc.PromotionTypeBuyXGetY {
+		if !req.BuyItemID.Valid || !req.GetItemID.Valid {
+			return arg, errors.Wrap(api.ErrInvalidPromotion.Error, "items")
+		}
+
+		arg.BuyItemID = req.BuyItemID
+		arg.GetItemID = req.GetItemID
+		if req.BuyQuantity > 0 {
+			arg.BuyQuantity = req.BuyQuantity
+		}
+		if req.GetQuantity > 0 {
+			arg.GetQuantity = req.GetQuantity
+		}
+	}
+
+	if (req.UsageLimit.Valid && req.UsageLimit.Int32 <= 0) || (req.CustomerLimit.Valid && req.CustomerLimit.Int32 <= 0) {
+		return arg, errors.Wrap(api.ErrInvalidPromotion.Error, "limits")
+	}
+
+	var err error
+	if arg.StartTime, err = parseClock(req.StartTime, 0); err != nil {
+		return arg, errors.Wrap(api.ErrInvalidPromotion.Error, "start time")
+	}
+
+	if arg.EndTime, err = parseClock(req.EndTime, 24*time.Hour); err != nil {
+		return arg, errors.Wrap(api.ErrInvalidPromotion.Error, "end time")
+	}
+
+	if req.StartDate.Valid && req.EndDate.Valid && req.StartDate.Time.After(req.EndDate.Time) {
+		return arg, errors.Wrap(api.ErrInvalidPromotion.Error, "dates")
+	}
+
+	return arg, nil
+}
+
+func promotionStoreError(err error) error {
+	switch db.GetSQLErrorCode(err) {
+	case db.UniqueViolation:
+		return errors.Wrap(api.ErrPromotionCodeConflict.Error, "store")
+	// Only the buy and get items can be missing
+	case db.ForeignKeyViolation:
+		return errors.Wrap(api.ErrUnkownMenuItem.Error, "store")
+	case db.CheckViolation:
+		return errors.Wrap(api.ErrInvalidPromotion.Error, "store")
+	}
+	return errors.Wrap(err, "store")
+}
+
+// The error code of why a promotion was turned down, empty when err is not one of the rejections
+func reason(err error) string {
+	for _, e := range rejections {
+		if errors.Is(err, e.Error) {
+			return e.Code
+		}
+	}
+	return ""
+}
+
+// Codes are matched uppercase and without spaces so customers can type them however they like
+func normalizeCode(code string) string {
+	return strings.ToUpper(strings.ReplaceAll(code, " ", ""))
+}
+
+func parseClock(s string, fallback time.Duration) (pgtype.Time, error) {
+	if s == "" {
+		return pgtype.Time{Microseconds: fallback.Microseconds(), Valid: true}, nil
+	}
+
+	if s == "24:00" {
+		return pgtype.Time{Microseconds: (24 * time.Hour).Microseconds(), Valid: true}, nil
+	}
+
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return pgtype.Time{}, err
+	}
+
+	d := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	return pgtype.Time{Microseconds: d.Microseconds(), Valid: true}, nil
+}
+
+func formatClock(t pgtype.Time) string {
+	d := time.Duration(t.Microseconds) * time.Microsecond
+	return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// The columns are NOT NULL so a missing list is stored as an empty one
+func orEmpty(l []string) []string {
+	if l == nil {
+		return []string{}
+	}
+	return l
+}
@@ -0,0 +1,252 @@
+package promotion
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/pdridh/k-line/api"
+	"github.com/pdridh/k-line/db/sqlc"
+)
+
+func item(itemID int32, quantity int32, price float64) sqlc.OrderItem {
+	return sqlc.OrderItem{ItemID: itemID, Quantity: quantity, UnitPrice: price, Status: sqlc.OrderItemStatusPending}
+}
+
+func TestEvaluate(t *testing.T) {
+	percent := sqlc.Promotion{Name: "Happy hour", Type: sqlc.PromotionTypePercentage, Value: 10, Active: true}
+	fixed := sqlc.Promotion{Name: "Fiver", Type: sqlc.PromotionTypeFixed, Value: 5, Active: true}
+
+	cancelled := item(1, 1, 100)
+	cancelled.Status = sqlc.OrderItemStatusCancelled
+
+	tests := []struct {
+		name        string
+		p           sqlc.Promotion
+		on          bool
+		orderType   sqlc.OrderType
+		items       []sqlc.OrderItem
+		discounted  float64
+		amount      float64
+		description string
+		err         error
+	}{
+		{
+			name:        "percentage of the subtotal",
+			p:           percent,
+			on:          true,
+			items:       []sqlc.OrderItem{item(1, 2, 12.5), item(2, 1, 5)},
+			amount:      3,
+			description: "Happy hour (10% off)",
+		},
+		{
+			name:        "percentage of what is left after other discounts",
+			p:           percent,
+			on:          true,
+			items:       []sqlc.OrderItem{item(1, 1, 30)},
+			discounted:  10,
+			amount:      2,
+			description: "Happy hour (10% off)",
+		},
+		{
+			name:        "cancelled items dont count",
+			p:           percent,
+			on:          true,
+			items:       []sqlc.OrderItem{item(1, 1, 20), cancelled},
+			amount:      2,
+			description: "Happy hour (10% off)",
+		},
+		{
+			name:        "fixed amount",
+			p:           fixed,
+			on:          true,
+			items:       []sqlc.OrderItem{item(1, 1, 20)},
+			amount:      5,
+			description: "Fiver",
+		},
+		{
+			name:        "fixed amount capped at what is left",
+			p:           fixed,
+			on:          true,
+			items:       []sqlc.OrderItem{item(1, 1, 20)},
+			discounted:  17,
+			amount:      3,
+			description: "Fiver",
+		},
+		{
+			name:        "code in the description",
+			p:           sqlc.Promotion{Name: "Fiver", Code: pgtype.Text{String: "SAVE5", Valid: true}, Type: sqlc.PromotionTypeFixed, Value: 5, Active: true},
+			on:          true,
+			items:       []sqlc.OrderItem{item(1, 1, 20)},
+			amount:      5,
+			description: "Fiver, code SAVE5",
+		},
+		{
+			name:  "inactive",
+			p:     sqlc.Promotion{Type: sqlc.PromotionTypeFixed, Value: 5},
+			on:    true,
+			items: []sqlc.OrderItem{item(1, 1, 20)},
+			err:   api.ErrPromotionInactive.Error,
+		},
+		{
+			name:  "outside its hours",
+			p:     fixed,
+			items: []sqlc.OrderItem{item(1, 1, 20)},
+			err:   api.ErrPromotionNotOn.Error,
+		},
+		{
+			name:      "wrong order type",
+			p:         sqlc.Promotion{Type: sqlc.PromotionTypeFixed, Value: 5, OrderTypes: []string{"delivery"}, Active: true},
+			on:        true,
+			orderType: sqlc.OrderTypeDining,
+			items:     []sqlc.OrderItem{item(1, 1, 20)},
+			err:       api.ErrPromotionOrderType.Error,
+		},
+		{
+			name:  "below the min spend",
+			p:     sqlc.Promotion{Type: sqlc.PromotionTypeFixed, Value: 5, MinSpend: 25, Active: true},
+			on:    true,
+			items: []sqlc.OrderItem{item(1, 1, 20)},
+			err:   api.ErrPromotionMinSpend.Error,
+		},
+		{
+			name:       "nothing left to take off",
+			p:          fixed,
+			on:         true,
+			items:      []sqlc.OrderItem{item(1, 1, 20)},
+			discounted: 20,
+			err:        api.ErrPromotionItems.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orderType := tt.orderType
+			if orderType == "" {
+				orderType = sqlc.OrderTypeDining
+			}
+
+			amount, description, err := evaluate(tt.p, tt.on, orderType, tt.items, tt.discounted)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("got error %v, want %v", err, tt.err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if amount != tt.amount {
+				t.Errorf("got amount %v, want %v", amount, tt.amount)
+			}
+
+			if description != tt.description {
+				t.Errorf("got description %q, want %q", description, tt.description)
+			}
+		})
+	}
+}
+
+func TestBuyXGetY(t *testing.T) {
+	buyGet := func(buyItem int32, buyQuantity int32, getItem int32, getQuantity int32, value float64) sqlc.Promotion {
+		return sqlc.Promotion{
+			Type:        sqlc.PromotionTypeBuyXGetY,
+			BuyItemID:   pgtype.Int4{Int32: buyItem, Valid: true},
+			BuyQuantity: buyQuantity,
+			GetItemID:   pgtype.Int4{Int32: getItem, Valid: true},
+			GetQuantity: getQuantity,
+			Value:       value,
+		}
+	}
+
+	inCombo := item(1, 3, 10)
+	inCombo.OrderComboID = pgtype.Int8{Int64: 1, Valid: true}
+
+	cancelled := item(1, 3, 10)
+	cancelled.Status = sqlc.OrderItemStatusCancelled
+
+	tests := []struct {
+		name   string
+		p      sqlc.Promotion
+		items  []sqlc.OrderItem
+		amount float64
+	}{
+		{
+			name:   "buy 2 get 1 free of the same item",
+			p:      buyGet(1, 2, 1, 1, 100),
+			items:  []sqlc.OrderItem{item(1, 3, 10)},
+			amount: 10,
+		},
+		{
+			name:   "same item needs the whole set",
+			p:      buyGet(1, 2, 1, 1, 100),
+			items:  []sqlc.OrderItem{item(1, 2, 10)},
+			amount: 0,
+		},
+		{
+			name:   "same item counts every full set",
+			p:      buyGet(1, 2, 1, 1, 100),
+			items:  []sqlc.OrderItem{item(1, 7, 10)},
+			amount: 20,
+		},
+		{
+			name:   "cheapest get items are discounted",
+			p:      buyGet(1, 1, 2, 1, 50),
+			items:  []sqlc.OrderItem{item(1, 1, 10), item(2, 1, 8), item(2, 1, 4)},
+			amount: 2,
+		},
+		{
+			name:   "limited by the get items on the order",
+			p:      buyGet(1, 1, 2, 1, 100),
+			items:  []sqlc.OrderItem{item(1, 3, 10), item(2, 1, 6)},
+			amount: 6,
+		},
+		{
+			name:   "limited by the buy items on the order",
+			p:      buyGet(1, 2, 2, 1, 100),
+			items:  []sqlc.OrderItem{item(1, 3, 10), item(2, 3, 6)},
+			amount: 6,
+		},
+		{
+			name:   "combo and cancelled items dont count",
+			p:      buyGet(1, 2, 1, 1, 100),
+			items:  []sqlc.OrderItem{inCombo, cancelled},
+			amount: 0,
+		},
+		{
+			name:   "no get item",
+			p:      buyGet(1, 1, 2, 1, 100),
+			items:  []sqlc.OrderItem{item(1, 4, 10)},
+			amount: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if amount := buyXGetY(tt.p, tt.items); amount != tt.amount {
+				t.Errorf("got %v, want %v", amount, tt.amount)
+			}
+		})
+	}
+}
+
+func TestNormalizeCode(t *testing.T) {
+	tests := []struct {
+		code string
+		want string
+	}{
+		{"save5", "SAVE5"},
+		{"Save 5", "SAVE5"},
+		{" SUMMER 2025 ", "SUMMER2025"},
+		{"HALF-OFF", "HALF-OFF"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := normalizeCode(tt.code); got != tt.want {
+			t.Errorf("normalizeCode(%q) = %q, want %q", tt.code, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package promotion
+
+import (
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/pdridh/k-line/api"
+	"github.com/pdridh/k-line/db/sqlc"
+)
+
+type PromotionFilters struct {
+	ActiveOnly bool  `json:"active_only"`
+	Page       int32 `json:"page"` // The request is sent as page but converted to offset for db
+	Limit      int32 `json:"limit"`
+}
+
+func (f *PromotionFilters) Validate(maxLimit int32, defaultLimit int32) {
+	api.ClampPage(&f.Limit, &f.Page, maxLimit, defaultLimit)
+}
+
+// The rules of a promotion as they are sent to create or update one.
+// Value is the percent off for percentage, the amount off for fixed and the percent off the get items
+// for buy_x_get_y. The buy and get fields are only used by buy_x_get_y.
+type RequestPromotion struct {
+	Name          string             `json:"name" validate:"required"`
+	Code          string             `json:"code"` // Empty for a promotion that is offered without a code
+	Type          sqlc.PromotionType `json:"type" validate:"required,oneof=percentage fixed buy_x_get_y"`
+	Value         float64            `json:"value" validate:"gt=0"`
+	BuyItemID     pgtype.Int4        `json:"buy_item_id"`
+	BuyQuantity   int32              `json:"buy_quantity" validate:"gte=0"` // Defaults to 1
+	GetItemID     pgtype.Int4        `json:"get_item_id"`
+	GetQuantity   int32              `json:"get_quantity" validate:"gte=0"` // Defaults to 1
+	MinSpend      float64            `json:"min_spend" validate:"gte=0"`
+	OrderTypes    []string           `json:"order_types" validate:"unique,dive,oneof=dining delivery takeaway"`
+	Days          []int16            `json:"days" validate:"unique,dive,min=0,max=6"`
+	StartTime     string             `json:"start_time"` // HH:MM, defaults to 00:00
+	EndTime       string             `json:"end_time"`   // HH:MM, defaults to 24:00
+	StartDate     pgtype.Date        `json:"start_date"`
+	EndDate       pgtype.Date        `json:"end_date"`
+	UsageLimit    pgtype.Int4        `json:"usage_limit"`    // Orders it can be applied to in total
+	CustomerLimit pgtype.Int4        `json:"customer_limit"` // Orders of the same customer it can be applied to
+	Active        *bool              `json:"active"`         // Defaults to true
+}
+
+// Empty OrderTypes and Days mean every type of order and every day. Times are HH:MM and a window
+// ending before it starts runs past midnight, like menu schedules.
+type Promotion struct {
+	ID            int32              `json:"id"`
+	Name          string             `json:"name"`
+	Code          pgtype.Text        `json:"code"`
+	Type          sqlc.PromotionType `json:"type"`
+	Value         float64            `json:"value"`
+	BuyItemID     pgtype.Int4        `json:"buy_item_id"`
+	BuyQuantity   int32              `json:"buy_quantity"`
+	GetItemID     pgtype.Int4        `json:"get_item_id"`
+	GetQuantity   int32              `json:"get_quantity"`
+	MinSpend      float64            `json:"min_spend"`
+	OrderTypes    []string           `json:"order_types"`
+	Days          []int16            `json:"days"`
+	StartTime     string             `json:"start_time"`
+	EndTime       string             `json:"end_time"`
+	StartDate     pgtype.Date        `json:"start_date"`
+	EndDate       pgtype.Date        `json:"end_date"`
+	UsageLimit    pgtype.Int4        `json:"usage_limit"`
+	CustomerLimit pgtype.Int4        `json:"customer_limit"`
+	Active        bool               `json:"active"`
+	CreatedBy     pgtype.UUID        `json:"created_by"`
+	CreatedAt     pgtype.Timestamp   `json:"created_at"`
+}
+
+func newPromotion(p sqlc.Promotion) *Promotion {
+	return &Promotion{
+		ID:            p.ID,
+		Name:          p.Name,
+		Code:          p.Code,
+		Type:          p.Type,
+		Value:         p.Value,
+		BuyItemID:     p.BuyItemID,
+		BuyQuantity:   p.BuyQuantity,
+		GetItemID:     p.GetItemID,
+		GetQuantity:   p.GetQuantity,
+		MinSpend:      p.MinSpend,
+		OrderTypes:    p.OrderTypes,
+		Days:          p.Days,
+		StartTime:     formatClock(p.StartTime),
+		EndTime:       formatClock(p.EndTime),
+		StartDate:     p.StartDate,
+		EndDate:       p.EndDate,
+		UsageLimit:    p.UsageLimit,
+		CustomerLimit: p.CustomerLimit,
+		Active:        p.Active,
+		CreatedBy:     p.CreatedBy,
+		CreatedAt:     p.CreatedAt,
+	}
+}
+
+// What a promotion would take off an order right now, Reason is the error code of why it doesnt apply
+type Evaluation struct {
+	PromotionID int32       `json:"promotion_id"`
+	Name        string      `json:"name"`
+	Code        pgtype.Text `json:"code"`
+	Eligible    bool        `json:"eligible"`
+	Description string      `json:"description,omitempty"`
+	Amount      float64     `json:"amount"`
+	Reason      string      `json:"reason,omitempty"`
+}
+
+// A promotion applied to an order, the discount keeps the description and amount it had at the time
+type Applied struct {
+	DiscountID  int64       `json:"discount_id"`
+	OrderID     pgtype.UUID `json:"order_id"`
+	PromotionID int32       `json:"promotion_id"`
+	Description string      `json:"description"`
+	Amount      float64     `json:"amount"`
+}
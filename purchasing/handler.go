@@ -0,0 +1,351 @@
+package purchasing
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/pdridh/k-line/api"
+	"github.com/pdridh/k-line/db/sqlc"
+	"github.com/pkg/errors"
+)
+
+type handler struct {
+	Service *service
+}
+
+func NewHandler(s *service) *handler {
+	return &handler{
+		Service: s,
+	}
+}
+
+func writePurchaseOrderError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, api.ErrUnknownPurchaseOrder.Error):
+		api.WriteNotFoundError(w, r)
+	case errors.Is(err, api.ErrPurchaseOrderStatus.Error):
+		api.WriteError(w, r, http.StatusConflict, api.ErrPurchaseOrderStatus, nil)
+	case errors.Is(err, api.ErrUnknownPurchaseOrderItem.Error):
+		api.WriteError(w, r, http.StatusBadRequest, api.ErrUnknownPurchaseOrderItem, nil)
+	case errors.Is(err, api.ErrReceiveExceedsOrdered.Error):
+		api.WriteError(w, r, http.StatusBadRequest, api.ErrReceiveExceedsOrdered, nil)
+	default:
+		api.WriteInternalError(w, r)
+	}
+}
+
+func (h *handler) CreateSupplier() http.HandlerFunc {
+	type RequestPayload struct {
+		Name        string      `json:"name" validate:"required"`
+		ContactName pgtype.Text `json:"contact_name"`
+		Phone       pgtype.Text `json:"phone"`
+		Email       pgtype.Text `json:"email"`
+		Notes       pgtype.Text `json:"notes"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var p RequestPayload
+		if err := api.ParseJSON(r, &p); err != nil {
+			api.WriteBadRequestError(w, r)
+			return
+		}
+
+		if err := h.Service.Validate.Struct(p); err != nil {
+			api.WriteValidationError(w, r, err)
+			return
+		}
+
+		arg := sqlc.CreateSupplierParams{
+			Name:        p.Name,
+			ContactName: p.ContactName,
+			Phone:       p.Phone,
+			Email:       p.Email,
+			Notes:       p.Notes,
+		}
+
+		s, err := h.Service.CreateSupplier(r.Context(), arg)
+		if err != nil {
+			if errors.Is(err, api.ErrSupplierNameConflict.Error) {
+				api.WriteError(w, r, http.StatusConflict, api.ErrSupplierNameConflict, nil)
+				return
+			}
+
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusCreated, "Successfully created supplier", s)
+	}
+}
+
+func (h *handler) UpdateSupplier() http.HandlerFunc {
+	type RequestPayload struct {
+		Name        string      `json:"name" validate:"required"`
+		ContactName pgtype.Text `json:"contact_name"`
+		Phone       pgtype.Text `json:"phone"`
+		Email       pgtype.Text `json:"email"`
+		Notes       pgtype.Text `json:"notes"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		var p RequestPayload
+		if err := api.ParseJSON(r, &p); err != nil {
+			api.WriteBadRequestError(w, r)
+			return
+		}
+
+		if err := h.Service.Validate.Struct(p); err != nil {
+			api.WriteValidationError(w, r, err)
+			return
+		}
+
+		arg := sqlc.UpdateSupplierParams{
+			ID:          int32(id),
+			Name:        p.Name,
+			ContactName: p.ContactName,
+			Phone:       p.Phone,
+			Email:       p.Email,
+			Notes:       p.Notes,
+		}
+
+		s, err := h.Service.UpdateSupplier(r.Context(), arg)
+		if err != nil {
+			switch {
+			case errors.Is(err, api.ErrUnknownSupplier.Error):
+				api.WriteNotFoundError(w, r)
+				return
+			case errors.Is(err, api.ErrSupplierNameConflict.Error):
+				api.WriteError(w, r, http.StatusConflict, api.ErrSupplierNameConflict, nil)
+				return
+			default:
+				api.WriteInternalError(w, r)
+				return
+			}
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Successfully updated supplier", s)
+	}
+}
+
+func (h *handler) GetSuppliers() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s, err := h.Service.GetSuppliers(r.Context())
+		if err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Retrieval successful", s)
+	}
+}
+
+func (h *handler) GetSupplierByID() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		s, err := h.Service.GetSupplierByID(r.Context(), int32(id))
+		if err != nil {
+			if errors.Is(err, api.ErrUnknownSupplier.Error) {
+				api.WriteNotFoundError(w, r)
+				return
+			}
+
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Retrieval successful", s)
+	}
+}
+
+func (h *handler) CreatePurchaseOrder() http.HandlerFunc {
+	type RequestPayload struct {
+		SupplierID int32       `json:"supplier_id" validate:"required"`
+		Notes      pgtype.Text `json:"notes"`
+		Items      []OrderLine `json:"items" validate:"required,min=1,unique=IngredientID,dive"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var userID pgtype.UUID
+		if err := userID.Scan(api.CurrentUserID(r)); err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		var p RequestPayload
+		if err := api.ParseJSON(r, &p); err != nil {
+			api.WriteBadRequestError(w, r)
+			return
+		}
+
+		if err := h.Service.Validate.Struct(p); err != nil {
+			api.WriteValidationError(w, r, err)
+			return
+		}
+
+		po, err := h.Service.CreatePurchaseOrder(r.Context(), p.SupplierID, p.Notes, p.Items, userID)
+		if err != nil {
+			switch {
+			case errors.Is(err, api.ErrUnknownSupplier.Error):
+				api.WriteError(w, r, http.StatusBadRequest, api.ErrUnknownSupplier, nil)
+				return
+			case errors.Is(err, api.ErrUnknownIngredient.Error):
+				api.WriteError(w, r, http.StatusBadRequest, api.ErrUnknownIngredient, nil)
+				return
+			default:
+				api.WriteInternalError(w, r)
+				return
+			}
+		}
+
+		api.WriteSuccess(w, r, http.StatusCreated, "Successfully created purchase order", po)
+	}
+}
+
+func (h *handler) GetPurchaseOrders() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var filters PurchaseOrderFilters
+
+		api.ParseQueryParams(r.URL.Query(), &filters)
+		filters.Validate(100, 50)
+
+		if err := h.Service.Validate.Struct(filters); err != nil {
+			api.WriteValidationError(w, r, err)
+			return
+		}
+
+		offset := (filters.Page - 1) * filters.Limit
+
+		po, err := h.Service.GetPurchaseOrders(r.Context(), filters.Status, filters.Limit, offset)
+		if err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Retrieval successful", po)
+	}
+}
+
+func (h *handler) GetPurchaseOrderByID() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		po, err := h.Service.GetPurchaseOrderByID(r.Context(), int64(id))
+		if err != nil {
+			writePurchaseOrderError(w, r, err)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Retrieval successful", po)
+	}
+}
+
+func (h *handler) MarkOrdered() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		po, err := h.Service.MarkOrdered(r.Context(), int64(id))
+		if err != nil {
+			writePurchaseOrderError(w, r, err)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Successfully marked purchase order as ordered", po)
+	}
+}
+
+func (h *handler) Cancel() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		po, err := h.Service.Cancel(r.Context(), int64(id))
+		if err != nil {
+			writePurchaseOrderError(w, r, err)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Successfully cancelled purchase order", po)
+	}
+}
+
+// Receives the items that came in, an empty body (or no items) receives everything still outstanding
+func (h *handler) Receive() http.HandlerFunc {
+	type RequestPayload struct {
+		Items []ReceiveLine `json:"items" validate:"unique=ItemID,dive"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		var userID pgtype.UUID
+		if err := userID.Scan(api.CurrentUserID(r)); err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		var p RequestPayload
+		if r.ContentLength != 0 {
+			if err := api.ParseJSON(r, &p); err != nil {
+				api.WriteBadRequestError(w, r)
+				return
+			}
+		}
+
+		if err := h.Service.Validate.Struct(p); err != nil {
+			api.WriteValidationError(w, r, err)
+			return
+		}
+
+		po, err := h.Service.Receive(r.Context(), int64(id), p.Items, userID)
+		if err != nil {
+			writePurchaseOrderError(w, r, err)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Successfully received purchase order", po)
+	}
+}
+
+func (h *handler) GetReorderSuggestions() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var filters ReorderFilters
+
+		api.ParseQueryParams(r.URL.Query(), &filters)
+		filters.Normalize()
+
+		s, err := h.Service.GetReorderSuggestions(r.Context(), filters.Days, filters.CoverDays)
+		if err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Retrieval successful", s)
+	}
+}
@@ -0,0 +1,318 @@
+package purchasing
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/pdridh/k-line/api"
+	"github.com/pdridh/k-line/db"
+	"github.com/pdridh/k-line/db/sqlc"
+	"github.com/pkg/errors"
+)
+
+type service struct {
+	Validate *validator.Validate
+	store    db.Store
+}
+
+func NewService(v *validator.Validate, s db.Store) *service {
+	return &service{
+		Validate: v,
+		store:    s,
+	}
+}
+
+func (s *service) CreateSupplier(ctx context.Context, arg sqlc.CreateSupplierParams) (*Supplier, error) {
+	sup, err := s.store.CreateSupplier(ctx, arg)
+	if err != nil {
+		if db.GetSQLErrorCode(err) == db.UniqueViolation {
+			return nil, errors.Wrap(api.ErrSupplierNameConflict.Error, "store")
+		}
+		return nil, errors.Wrap(err, "store")
+	}
+
+	return newSupplier(sup), nil
+}
+
+func (s *service) UpdateSupplier(ctx context.Context, arg sqlc.UpdateSupplierParams) (*Supplier, error) {
+	sup, err := s.store.UpdateSupplier(ctx, arg)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, errors.Wrap(api.ErrUnknownSupplier.Error, "store")
+		}
+		if db.GetSQLErrorCode(err) == db.UniqueViolation {
+			return nil, errors.Wrap(api.ErrSupplierNameConflict.Error, "store")
+		}
+		return nil, errors.Wrap(err, "store")
+	}
+
+	return newSupplier(sup), nil
+}
+
+func (s *service) GetSuppliers(ctx context.Context) ([]Supplier, error) {
+	rows, err := s.store.GetSuppliers(ctx)
+	if err != nil {
+		return []Supplier{}, errors.Wrap(err, "store")
+	}
+
+	suppliers := []Supplier{}
+	for _, sup := range rows {
+		suppliers = append(suppliers, *newSupplier(sup))
+	}
+
+	return suppliers, nil
+}
+
+func (s *service) GetSupplierByID(ctx context.Context, id int32) (*Supplier, error) {
+	sup, err := s.store.GetSupplierByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, errors.Wrap(api.ErrUnknownSupplier.Error, "store")
+		}
+		return nil, errors.Wrap(err, "store")
+	}
+
+	return newSupplier(sup), nil
+}
+
+// Creates a draft purchase order, nothing is expected in until it is marked as ordered
+func (s *service) CreatePurchaseOrder(ctx context.Context, supplierID int32, notes pgtype.Text, lines []OrderLine, createdBy pgtype.UUID) (*PurchaseOrder, error) {
+	if _, err := s.GetSupplierByID(ctx, supplierID); err != nil {
+		return nil, err
+	}
+
+	ingredientIDs := make([]int32, 0, len(lines))
+	quantities := make([]float64, 0, len(lines))
+	unitCosts := make([]float64, 0, len(lines))
+	for _, l := range lines {
+		ingredientIDs = append(ingredientIDs, l.IngredientID)
+		quantities = append(quantities, l.Quantity)
+		unitCosts = append(unitCosts, l.UnitCost)
+	}
+
+	arg := sqlc.CreatePurchaseOrderParams{
+		SupplierID: supplierID,
+		Notes:      notes,
+		CreatedBy:  createdBy,
+	}
+
+	po, err := s.store.CreatePurchaseOrderTx(ctx, arg, ingredientIDs, quantities, unitCosts)
+	if err != nil {
+		// The supplier was checked above so it has to be one of the ingredients
+		if db.GetSQLErrorCode(err) == db.ForeignKeyViolation {
+			return nil, errors.Wrap(api.ErrUnknownIngredient.Error, "store")
+		}
+		return nil, errors.Wrap(err, "store")
+	}
+
+	return s.GetPurchaseOrderByID(ctx, po.ID)
+}
+
+func (s *service) GetPurchaseOrders(ctx context.Context, status string, limit int32, offset int32) ([]PurchaseOrder, error) {
+	arg := sqlc.GetPurchaseOrdersParams{
+		Limit:  limit,
+		Offset: offset,
+		Status: sqlc.NullPurchaseOrderStatus{PurchaseOrderStatus: sqlc.PurchaseOrderStatus(status), Valid: status != ""},
+	}
+
+	rows, err := s.store.GetPurchaseOrders(ctx, arg)
+	if err != nil {
+		return []PurchaseOrder{}, errors.Wrap(err, "store")
+	}
+
+	orders := []PurchaseOrder{}
+	for _, po := range rows {
+		orders = append(orders, *newPurchaseOrder(po))
+	}
+
+	return orders, nil
+}
+
+func (s *service) GetPurchaseOrderByID(ctx context.Context, id int64) (*PurchaseOrder, error) {
+	po, err := s.store.GetPurchaseOrderByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, errors.Wrap(api.ErrUnknownPurchaseOrder.Error, "store")
+		}
+		return nil, errors.Wrap(err, "store")
+	}
+
+	rows, err := s.store.GetPurchaseOrderItems(ctx, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "store")
+	}
+
+	order := newPurchaseOrder(po)
+	order.Items = []PurchaseOrderItem{}
+	for _, i := range rows {
+		order.Items = append(order.Items, PurchaseOrderItem{
+			ID:               i.ID,
+			IngredientID:     i.IngredientID,
+			Name:             i.Name,
+			Unit:             i.Unit,
+			Quantity:         i.Quantity,
+			ReceivedQuantity: i.ReceivedQuantity,
+			UnitCost:         i.UnitCost,
+		})
+		order.Total += i.Quantity * i.UnitCost
+	}
+	order.Total = api.Round(order.Total)
+
+	return order, nil
+}
+
+// Sends a draft purchase order to the supplier, from then on it can be received
+func (s *service) MarkOrdered(ctx context.Context, id int64) (*PurchaseOrder, error) {
+	return s.updateStatus(ctx, id, sqlc.PurchaseOrderStatusOrdered, sqlc.PurchaseOrderStatusDraft)
+}
+
+// Cancels a purchase order that has not been fully received, whatever already came in stays in stock
+func (s *service) Cancel(ctx context.Context, id int64) (*PurchaseOrder, error) {
+	return s.updateStatus(ctx, id, sqlc.PurchaseOrderStatusCancelled,
+		sqlc.PurchaseOrderStatusDraft, sqlc.PurchaseOrderStatusOrdered, sqlc.PurchaseOrderStatusPartiallyReceived)
+}
+
+func (s *service) updateStatus(ctx context.Context, id int64, status sqlc.PurchaseOrderStatus, from ...sqlc.PurchaseOrderStatus) (*PurchaseOrder, error) {
+	fromStatuses := make([]string, 0, len(from))
+	for _, f := range from {
+		fromStatuses = append(fromStatuses, string(f))
+	}
+
+	_, err := s.store.UpdatePurchaseOrderStatus(ctx, sqlc.UpdatePurchaseOrderStatusParams{Status: status, ID: id, FromStatuses: fromStatuses})
+	if err != nil {
+		if !errors.Is(err, db.ErrRecordNotFound) {
+			return nil, errors.Wrap(err, "store")
+		}
+
+		// Either it doesnt exist or it is in a status that cant move to the new one
+		if _, err := s.store.GetPurchaseOrderByID(ctx, id); err != nil {
+			if errors.Is(err, db.ErrRecordNotFound) {
+				return nil, errors.Wrap(api.ErrUnknownPurchaseOrder.Error, "store")
+			}
+			return nil, errors.Wrap(err, "store")
+		}
+		return nil, errors.Wrap(api.ErrPurchaseOrderStatus.Error, "store")
+	}
+
+	return s.GetPurchaseOrderByID(ctx, id)
+}
+
+// Receives the lines into stock, when no lines are given everything still outstanding is received
+func (s *service) Receive(ctx context.Context, id int64, lines []ReceiveLine, receivedBy pgtype.UUID) (*PurchaseOrder, error) {
+	po, err := s.GetPurchaseOrderByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if po.Status != sqlc.PurchaseOrderStatusOrdered && po.Status != sqlc.PurchaseOrderStatusPartiallyReceived {
+		return nil, errors.Wrap(api.ErrPurchaseOrderStatus.Error, "store")
+	}
+
+	outstanding := map[int64]float64{}
+	for _, i := range po.Items {
+		outstanding[i.ID] = i.Quantity - i.ReceivedQuantity
+	}
+
+	itemIDs := []int64{}
+	quantities := []float64{}
+	if len(lines) == 0 {
+		for _, i := range po.Items {
+			if remaining := outstanding[i.ID]; remaining > 0 {
+				itemIDs = append(itemIDs, i.ID)
+				quantities = append(quantities, remaining)
+			}
+		}
+	}
+
+	for _, l := range lines {
+		remaining, ok := outstanding[l.ItemID]
+		if !ok {
+			return nil, errors.Wrap(api.ErrUnknownPurchaseOrderItem.Error, "store")
+		}
+		if l.Quantity > remaining {
+			return nil, errors.Wrap(api.ErrReceiveExceedsOrdered.Error, "store")
+		}
+
+		itemIDs = append(itemIDs, l.ItemID)
+		quantities = append(quantities, l.Quantity)
+	}
+
+	if _, err := s.store.ReceivePurchaseOrderTx(ctx, id, itemIDs, quantities, receivedBy); err != nil {
+		switch {
+		case errors.Is(err, db.ErrRecordNotFound):
+			// Cancelled or received by someone else in the meantime
+			return nil, errors.Wrap(api.ErrPurchaseOrderStatus.Error, "store")
+		case db.GetSQLErrorCode(err) == db.CheckViolation:
+			return nil, errors.Wrap(api.ErrReceiveExceedsOrdered.Error, "store")
+		default:
+			return nil, errors.Wrap(err, "store")
+		}
+	}
+
+	return s.GetPurchaseOrderByID(ctx, id)
+}
+
+// Suggests how much of each ingredient to order, based on its par level and the usage from
+// order items in the last days. Stock already on order counts towards it.
+func (s *service) GetReorderSuggestions(ctx context.Context, days int32, coverDays int32) ([]ReorderSuggestion, error) {
+	since := time.Now().UTC().AddDate(0, 0, -int(days))
+
+	rows, err := s.store.GetReorderSuggestions(ctx, pgtype.Timestamp{Time: since, Valid: true})
+	if err != nil {
+		return []ReorderSuggestion{}, errors.Wrap(err, "store")
+	}
+
+	suggestions := []ReorderSuggestion{}
+	for _, r := range rows {
+		dailyUsage := r.RecentUsage / float64(days)
+		target := math.Max(r.ParLevel, dailyUsage*float64(coverDays))
+
+		suggested := target - r.Stock - r.OnOrder
+		if suggested <= 0 {
+			continue
+		}
+
+		suggestions = append(suggestions, ReorderSuggestion{
+			IngredientID:      r.ID,
+			Name:              r.Name,
+			Unit:              r.Unit,
+			Stock:             r.Stock,
+			ParLevel:          r.ParLevel,
+			DailyUsage:        api.Round(dailyUsage),
+			OnOrder:           r.OnOrder,
+			SuggestedQuantity: api.Round(suggested),
+			SupplierID:        r.SupplierID,
+			SupplierName:      r.SupplierName,
+		})
+	}
+
+	return suggestions, nil
+}
+
+func newSupplier(s sqlc.Supplier) *Supplier {
+	return &Supplier{
+		ID:          s.ID,
+		Name:        s.Name,
+		ContactName: s.ContactName,
+		Phone:       s.Phone,
+		Email:       s.Email,
+		Notes:       s.Notes,
+		CreatedAt:   s.CreatedAt,
+	}
+}
+
+func newPurchaseOrder(po sqlc.PurchaseOrder) *PurchaseOrder {
+	return &PurchaseOrder{
+		ID:         po.ID,
+		SupplierID: po.SupplierID,
+		Status:     po.Status,
+		Notes:      po.Notes,
+		CreatedBy:  po.CreatedBy,
+		CreatedAt:  po.CreatedAt,
+		OrderedAt:  po.OrderedAt,
+		ReceivedAt: po.ReceivedAt,
+	}
+}
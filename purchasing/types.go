@@ -0,0 +1,95 @@
+package purchasing
+
+import (
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/pdridh/k-line/api"
+	"github.com/pdridh/k-line/db/sqlc"
+)
+
+type PurchaseOrderFilters struct {
+	Status string `json:"status" validate:"omitempty,oneof=draft ordered partially_received received cancelled"`
+	Page   int32  `json:"page"` // The request is sent as page but converted to offset for db
+	Limit  int32  `json:"limit"`
+}
+
+func (f *PurchaseOrderFilters) Validate(maxLimit int32, defaultLimit int32) {
+	api.ClampPage(&f.Limit, &f.Page, maxLimit, defaultLimit)
+}
+
+type ReorderFilters struct {
+	Days      int32 `json:"days"`       // How many days of sales the usage is based on
+	CoverDays int32 `json:"cover_days"` // How many days of usage the stock should last after restocking
+}
+
+// Defaults to 14 days of usage covering the next 7 days
+func (f *ReorderFilters) Normalize() {
+	if f.Days <= 0 || f.Days > 90 {
+		f.Days = 14
+	}
+
+	if f.CoverDays <= 0 || f.CoverDays > 90 {
+		f.CoverDays = 7
+	}
+}
+
+type Supplier struct {
+	ID          int32            `json:"id"`
+	Name        string           `json:"name"`
+	ContactName pgtype.Text      `json:"contact_name"`
+	Phone       pgtype.Text      `json:"phone"`
+	Email       pgtype.Text      `json:"email"`
+	Notes       pgtype.Text      `json:"notes"`
+	CreatedAt   pgtype.Timestamp `json:"created_at"`
+}
+
+// A line of a purchase order as it is sent when creating one
+type OrderLine struct {
+	IngredientID int32   `json:"ingredient_id" validate:"required"`
+	Quantity     float64 `json:"quantity" validate:"gt=0"`
+	UnitCost     float64 `json:"unit_cost" validate:"gte=0"`
+}
+
+// How much of a purchase order item came in
+type ReceiveLine struct {
+	ItemID   int64   `json:"item_id" validate:"required"`
+	Quantity float64 `json:"quantity" validate:"gt=0"`
+}
+
+type PurchaseOrderItem struct {
+	ID               int64   `json:"id"`
+	IngredientID     int32   `json:"ingredient_id"`
+	Name             string  `json:"name"`
+	Unit             string  `json:"unit"`
+	Quantity         float64 `json:"quantity"`
+	ReceivedQuantity float64 `json:"received_quantity"`
+	UnitCost         float64 `json:"unit_cost"`
+}
+
+// Items and Total are only set when a single purchase order is retrieved
+type PurchaseOrder struct {
+	ID         int64                    `json:"id"`
+	SupplierID int32                    `json:"supplier_id"`
+	Status     sqlc.PurchaseOrderStatus `json:"status"`
+	Notes      pgtype.Text              `json:"notes"`
+	Total      float64                  `json:"total,omitempty"`
+	Items      []PurchaseOrderItem      `json:"items,omitempty"`
+	CreatedBy  pgtype.UUID              `json:"created_by"`
+	CreatedAt  pgtype.Timestamp         `json:"created_at"`
+	OrderedAt  pgtype.Timestamp         `json:"ordered_at"`
+	ReceivedAt pgtype.Timestamp         `json:"received_at"`
+}
+
+// An ingredient that should be ordered to get back up to its par level, or to last the
+// cover days at the recent rate of usage if that is more.
+type ReorderSuggestion struct {
+	IngredientID      int32       `json:"ingredient_id"`
+	Name              string      `json:"name"`
+	Unit              string      `json:"unit"`
+	Stock             float64     `json:"stock"`
+	ParLevel          float64     `json:"par_level"`
+	DailyUsage        float64     `json:"daily_usage"`
+	OnOrder           float64     `json:"on_order"` // Ordered but not received yet
+	SuggestedQuantity float64     `json:"suggested_quantity"`
+	SupplierID        pgtype.Int4 `json:"supplier_id"`
+	SupplierName      pgtype.Text `json:"supplier_name"`
+}
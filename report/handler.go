@@ -0,0 +1,99 @@
+package report
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/pdridh/k-line/api"
+)
+
+type handler struct {
+	Service *service
+}
+
+func NewHandler(s *service) *handler {
+	return &handler{
+		Service: s,
+	}
+}
+
+// Sales between from and to grouped by group_by, as csv if format=csv
+func (h *handler) GetSalesReport() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var f SalesFilters
+		api.ParseQueryParams(r.URL.Query(), &f)
+		f.Normalize()
+
+		if err := h.Service.Validate.Struct(f); err != nil {
+			api.WriteValidationError(w, r, err)
+			return
+		}
+
+		start, end, err := f.Period()
+		if err != nil {
+			api.WriteError(w, r, http.StatusBadRequest, api.ErrInvalidPeriod, nil)
+			return
+		}
+
+		report, err := h.Service.GetSalesReport(r.Context(), f.GroupBy, start, end)
+		if err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		if f.Format == "csv" {
+			writeSalesCSV(w, r, report)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Retrieval successful", report)
+	}
+}
+
+func (h *handler) GetMenuEngineeringReport() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var f MenuEngineeringFilters
+		api.ParseQueryParams(r.URL.Query(), &f)
+
+		start, end, err := f.Period()
+		if err != nil {
+			api.WriteError(w, r, http.StatusBadRequest, api.ErrInvalidPeriod, nil)
+			return
+		}
+
+		report, err := h.Service.GetMenuEngineeringReport(r.Context(), start, end)
+		if err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Retrieval successful", report)
+	}
+}
+
+func writeSalesCSV(w http.ResponseWriter, r *http.Request, report *SalesReport) {
+	header := []string{report.GroupBy, "label", "order_count", "quantity", "revenue"}
+	rows := make([][]string, 0, len(report.Rows)+1)
+	for _, row := range report.Rows {
+		rows = append(rows, []string{
+			row.Key,
+			row.Label,
+			strconv.Itoa(int(row.OrderCount)),
+			strconv.Itoa(int(row.Quantity)),
+			strconv.FormatFloat(row.Revenue, 'f', 2, 64),
+		})
+	}
+
+	t := report.Totals
+	rows = append(rows, []string{
+		"total",
+		"",
+		strconv.Itoa(int(t.OrderCount)),
+		strconv.Itoa(int(t.Quantity)),
+		strconv.FormatFloat(t.Revenue, 'f', 2, 64),
+	})
+
+	name := fmt.Sprintf("sales_%s_%s_by_%s.csv", report.From, report.To, report.GroupBy)
+	api.WriteCSV(w, r, name, header, rows)
+}
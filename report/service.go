@@ -0,0 +1,212 @@
+package report
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/pdridh/k-line/api"
+	"github.com/pdridh/k-line/db"
+	"github.com/pdridh/k-line/db/sqlc"
+	"github.com/pkg/errors"
+)
+
+type service struct {
+	Validate *validator.Validate
+	store    db.Store
+}
+
+func NewService(v *validator.Validate, s db.Store) *service {
+	return &service{
+		Validate: v,
+		store:    s,
+	}
+}
+
+// Aggregates the sales of orders created in [start, end) grouped by groupBy.
+// Cancelled orders and items are left out, revenue is quantity times the price the item was ordered at.
+func (s *service) GetSalesReport(ctx context.Context, groupBy string, start time.Time, end time.Time) (*SalesReport, error) {
+	periodStart := pgtype.Timestamp{Time: start, Valid: true}
+	periodEnd := pgtype.Timestamp{Time: end, Valid: true}
+
+	rows, err := s.store.GetSalesReport(ctx, sqlc.GetSalesReportParams{GroupBy: groupBy, PeriodStart: periodStart, PeriodEnd: periodEnd})
+	if err != nil {
+		return nil, errors.Wrap(err, "store")
+	}
+
+	t, err := s.store.GetSalesTotals(ctx, sqlc.GetSalesTotalsParams{PeriodStart: periodStart, PeriodEnd: periodEnd})
+	if err != nil {
+		return nil, errors.Wrap(err, "store")
+	}
+
+	report := &SalesReport{
+		From:    start.Format(api.DateLayout),
+		To:      end.AddDate(0, 0, -1).Format(api.DateLayout),
+		GroupBy: groupBy,
+		Rows:    make([]SalesRow, 0, len(rows)),
+		Totals: SalesTotals{
+			OrderCount: t.OrderCount,
+			Quantity:   t.Quantity,
+			Revenue:    api.Round(t.Revenue),
+		},
+	}
+
+	for _, row := range rows {
+		report.Rows = append(report.Rows, SalesRow{
+			Key:        row.Bucket,
+			Label:      row.Label,
+			OrderCount: row.OrderCount,
+			Quantity:   row.Quantity,
+			Revenue:    api.Round(row.Revenue),
+		})
+	}
+
+	return report, nil
+}
+
+// Classifies every menu item into the menu engineering quadrants for [start, end)
+// and compares it against the period of the same length right before.
+func (s *service) GetMenuEngineeringReport(ctx context.Context, start time.Time, end time.Time) (*MenuEngineeringReport, error) {
+	prevStart := start.Add(-end.Sub(start))
+
+	current, err := s.store.GetMenuEngineeringStats(ctx, sqlc.GetMenuEngineeringStatsParams{
+		PeriodStart: pgtype.Timestamp{Time: start, Valid: true},
+		PeriodEnd:   pgtype.Timestamp{Time: end, Valid: true},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "store")
+	}
+
+	previous, err := s.store.GetMenuEngineeringStats(ctx, sqlc.GetMenuEngineeringStatsParams{
+		PeriodStart: pgtype.Timestamp{Time: prevStart, Valid: true},
+		PeriodEnd:   pgtype.Timestamp{Time: start, Valid: true},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "store")
+	}
+
+	items, threshold, avgMargin := classify(current)
+	prevItems, _, _ := classify(previous)
+
+	prevByID := make(map[int32]MenuEngineeringItem, len(prevItems))
+	for _, i := range prevItems {
+		prevByID[i.ItemID] = i
+	}
+
+	for n, i := range items {
+		prev, ok := prevByID[i.ItemID]
+		if !ok {
+			continue
+		}
+
+		t := &MenuEngineeringTrend{
+			QuantitySold:       prev.QuantitySold,
+			ContributionMargin: prev.ContributionMargin,
+			Class:              prev.Class,
+			MarginChange:       api.Round(i.ContributionMargin - prev.ContributionMargin),
+		}
+		if prev.QuantitySold > 0 {
+			change := float64(i.QuantitySold-prev.QuantitySold) / float64(prev.QuantitySold) * 100
+			t.QuantityChange = pgtype.Float8{Float64: api.Round(change), Valid: true}
+		}
+
+		items[n].Trend = t
+	}
+
+	return &MenuEngineeringReport{
+		From:                start.Format(api.DateLayout),
+		To:                  end.AddDate(0, 0, -1).Format(api.DateLayout),
+		PreviousFrom:        prevStart.Format(api.DateLayout),
+		PreviousTo:          start.AddDate(0, 0, -1).Format(api.DateLayout),
+		PopularityThreshold: api.Round(threshold),
+		AverageMargin:       api.Round(avgMargin),
+		Items:               items,
+	}, nil
+}
+
+// Sorts the items into quadrants. An item is popular when its menu mix reaches PopularityFactor of an
+// even share, and profitable when its contribution margin reaches the average margin of everything sold.
+// Items without any cost are ClassUncosted and left out of the average margin.
+// Returns the items along with the popularity threshold (percentage) and the average margin.
+func classify(rows []sqlc.GetMenuEngineeringStatsRow) ([]MenuEngineeringItem, float64, float64) {
+	items := make([]MenuEngineeringItem, 0, len(rows))
+	if len(rows) == 0 {
+		return items, 0, 0
+	}
+
+	var totalSold, costedSold, costedItems int32
+	var totalContribution, marginSum float64
+	for _, row := range rows {
+		i := MenuEngineeringItem{
+			ItemID:       row.ID,
+			Name:         row.Name,
+			Price:        row.Price,
+			Cost:         row.CurrentCost,
+			CostKnown:    row.CostKnown,
+			QuantitySold: row.QuantitySold,
+			UncostedSold: row.QuantitySold - row.CostedSold,
+		}
+
+		// Only the costed sales count towards the margin, items without those still get one from the
+		// current price and cost when there is a cost
+		switch {
+		case row.CostedSold > 0:
+			i.TotalContribution = row.CostedRevenue - row.FoodCost
+			i.ContributionMargin = i.TotalContribution / float64(row.CostedSold)
+		case row.CostKnown:
+			i.ContributionMargin = row.Price - row.CurrentCost
+		default:
+			i.Class = ClassUncosted
+		}
+
+		totalSold += row.QuantitySold
+		if i.Class != ClassUncosted {
+			costedSold += row.CostedSold
+			costedItems++
+			totalContribution += i.TotalContribution
+			marginSum += i.ContributionMargin
+		}
+		items = append(items, i)
+	}
+
+	threshold := 100 / float64(len(rows)) * PopularityFactor
+
+	// Weighted by costed quantity sold, when none sold every costed item counts the same
+	var avgMargin float64
+	switch {
+	case costedSold > 0:
+		avgMargin = totalContribution / float64(costedSold)
+	case costedItems > 0:
+		avgMargin = marginSum / float64(costedItems)
+	}
+
+	for n := range items {
+		i := &items[n]
+		if totalSold > 0 {
+			i.MenuMix = float64(i.QuantitySold) / float64(totalSold) * 100
+		}
+
+		popular := totalSold > 0 && i.MenuMix >= threshold
+		profitable := i.ContributionMargin >= avgMargin
+
+		switch {
+		case i.Class == ClassUncosted:
+			// Cant be placed without a cost
+		case popular && profitable:
+			i.Class = ClassStar
+		case popular:
+			i.Class = ClassPlowhorse
+		case profitable:
+			i.Class = ClassPuzzle
+		default:
+			i.Class = ClassDog
+		}
+
+		i.MenuMix = api.Round(i.MenuMix)
+		i.ContributionMargin = api.Round(i.ContributionMargin)
+		i.TotalContribution = api.Round(i.TotalContribution)
+	}
+
+	return items, threshold, avgMargin
+}
@@ -0,0 +1,119 @@
+package report
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/pdridh/k-line/api"
+)
+
+// What a sales report can be grouped by, time buckets or one of the order dimensions
+const (
+	GroupByHour      = "hour"
+	GroupByDay       = "day"
+	GroupByWeek      = "week"
+	GroupByOrderType = "order_type"
+	GroupByEmployee  = "employee"
+	GroupByTable     = "table"
+	GroupByItem      = "item"
+)
+
+type SalesFilters struct {
+	From    string `json:"from"` // Inclusive day in api.DateLayout
+	To      string `json:"to"`   // Inclusive day in api.DateLayout
+	GroupBy string `json:"group_by" validate:"oneof=hour day week order_type employee table item"`
+	Format  string `json:"format" validate:"omitempty,oneof=json csv"`
+}
+
+// Defaults to grouping by day when nothing is given
+func (f *SalesFilters) Normalize() {
+	if f.GroupBy == "" {
+		f.GroupBy = GroupByDay
+	}
+}
+
+// Parses From and To into [start, end), defaulting to the last 7 days
+func (f *SalesFilters) Period() (time.Time, time.Time, error) {
+	return api.ParseDateRange(f.From, f.To, 7)
+}
+
+// One group of the sales report.
+// Key is the bucket start for time groups, otherwise the order type, employee id, table id or item id.
+// Label is the employee or item name when grouping by those.
+type SalesRow struct {
+	Key        string  `json:"key"`
+	Label      string  `json:"label,omitempty"`
+	OrderCount int32   `json:"order_count"`
+	Quantity   int32   `json:"quantity"`
+	Revenue    float64 `json:"revenue"`
+}
+
+type SalesTotals struct {
+	OrderCount int32   `json:"order_count"`
+	Quantity   int32   `json:"quantity"`
+	Revenue    float64 `json:"revenue"`
+}
+
+type SalesReport struct {
+	From    string      `json:"from"`
+	To      string      `json:"to"`
+	GroupBy string      `json:"group_by"`
+	Rows    []SalesRow  `json:"rows"`
+	Totals  SalesTotals `json:"totals"`
+}
+
+// Menu engineering quadrants, popularity is quantity sold and profitability is contribution margin
+const (
+	ClassStar      = "star"      // Popular and profitable
+	ClassPlowhorse = "plowhorse" // Popular but low margin
+	ClassPuzzle    = "puzzle"    // Profitable but doesnt sell
+	ClassDog       = "dog"       // Neither
+	ClassUncosted  = "uncosted"  // No cost recorded, so it cant be told if it is profitable
+)
+
+// An item is popular when its share of items sold is at least 70% of an even share
+const PopularityFactor = 0.7
+
+type MenuEngineeringFilters struct {
+	From string `json:"from"` // Inclusive day in api.DateLayout
+	To   string `json:"to"`   // Inclusive day in api.DateLayout
+}
+
+// Parses From and To into [start, end), defaulting to the last 30 days
+func (f *MenuEngineeringFilters) Period() (time.Time, time.Time, error) {
+	return api.ParseDateRange(f.From, f.To, 30)
+}
+
+type MenuEngineeringItem struct {
+	ItemID             int32                 `json:"item_id"`
+	Name               string                `json:"name"`
+	Price              float64               `json:"price"`
+	Cost               float64               `json:"cost"`       // The latest cost recorded before the period ends
+	CostKnown          bool                  `json:"cost_known"` // False when no cost was recorded before the period ends
+	QuantitySold       int32                 `json:"quantity_sold"`
+	UncostedSold       int32                 `json:"uncosted_sold"`       // Sold before any cost was recorded, left out of the margin
+	MenuMix            float64               `json:"menu_mix"`            // Percentage of all items sold
+	ContributionMargin float64               `json:"contribution_margin"` // Average price minus cost per costed item sold
+	TotalContribution  float64               `json:"total_contribution"`
+	Class              string                `json:"class"`
+	Trend              *MenuEngineeringTrend `json:"trend"`
+}
+
+// How the item did in the period right before the report, of the same length
+type MenuEngineeringTrend struct {
+	QuantitySold       int32         `json:"quantity_sold"`
+	ContributionMargin float64       `json:"contribution_margin"`
+	Class              string        `json:"class"`
+	QuantityChange     pgtype.Float8 `json:"quantity_change"` // Percentage, null when nothing was sold before
+	MarginChange       float64       `json:"margin_change"`
+}
+
+type MenuEngineeringReport struct {
+	From                string                `json:"from"`
+	To                  string                `json:"to"`
+	PreviousFrom        string                `json:"previous_from"`
+	PreviousTo          string                `json:"previous_to"`
+	PopularityThreshold float64               `json:"popularity_threshold"` // Menu mix percentage an item needs to be popular
+	AverageMargin       float64               `json:"average_margin"`       // Contribution margin an item needs to be profitable, uncosted items left out
+	Items               []MenuEngineeringItem `json:"items"`
+}
@@ -4,15 +4,25 @@ import (
 	"log"
 	"net"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/pdridh/k-line/auth"
+	"github.com/pdridh/k-line/blob"
+	"github.com/pdridh/k-line/cash"
 	"github.com/pdridh/k-line/config"
+	"github.com/pdridh/k-line/customer"
 	"github.com/pdridh/k-line/db"
-	"github.com/pdridh/k-line/db/sqlc"
 	"github.com/pdridh/k-line/dining"
+	"github.com/pdridh/k-line/giftcard"
+	"github.com/pdridh/k-line/inventory"
+	"github.com/pdridh/k-line/loyalty"
 	"github.com/pdridh/k-line/menu"
+	"github.com/pdridh/k-line/promotion"
+	"github.com/pdridh/k-line/purchasing"
+	"github.com/pdridh/k-line/report"
+	"github.com/pdridh/k-line/shift"
 	"github.com/rs/cors"
 )
 
@@ -25,38 +35,210 @@ type server struct {
 	HttpServer *http.Server
 }
 
-func New(v *validator.Validate, store db.Store) *server {
+func New(v *validator.Validate, store db.Store, blobs blob.BlobStore) *server {
 	mux := http.NewServeMux()
 
+	// Tags the menu payloads are validated with
+	v.RegisterAlias("allergen", "oneof="+strings.Join(menu.Allergens, " "))
+	v.RegisterAlias("dietary_tag", "oneof="+strings.Join(menu.DietaryTags, " "))
+
 	authService := auth.NewService(v, store)
 	authHandler := auth.NewHandler(authService)
 
-	menuService := menu.NewService(v, store)
+	menuService := menu.NewService(v, store, blobs)
 	menuHandler := menu.NewHandler(menuService)
 
-	diningService := dining.NewService(v, store)
+	shiftService := shift.NewService(v, store)
+	shiftHandler := shift.NewHandler(shiftService)
+
+	// Dining asks the shift service whether waiters are clocked in
+	diningService := dining.NewService(v, store, shiftService)
 	diningHandler := dining.NewHandler(diningService)
 
-	mux.Handle("POST /auth/register", authHandler.Register())
+	cashService := cash.NewService(v, store)
+	cashHandler := cash.NewHandler(cashService)
+
+	reportService := report.NewService(v, store)
+	reportHandler := report.NewHandler(reportService)
+
+	inventoryService := inventory.NewService(v, store)
+	inventoryHandler := inventory.NewHandler(inventoryService)
+
+	purchasingService := purchasing.NewService(v, store)
+	purchasingHandler := purchasing.NewHandler(purchasingService)
+
+	customerService := customer.NewService(v, store)
+	customerHandler := customer.NewHandler(customerService)
+
+	loyaltyService := loyalty.NewService(v, store)
+	loyaltyHandler := loyalty.NewHandler(loyaltyService)
+
+	giftCardService := giftcard.NewService(v, store)
+	giftCardHandler := giftcard.NewHandler(giftCardService)
+
+	promotionService := promotion.NewService(v, store)
+	promotionHandler := promotion.NewHandler(promotionService)
+
+	authorize := auth.Middleware(store)
+
+	mux.Handle("GET /.well-known/jwks.json", authHandler.JWKS())
+
+	// Blobs kept on disk are served at the path of UPLOAD_URL, other stores serve them on their own
+	if h, ok := blobs.(http.Handler); ok {
+		uploads := config.Server().UploadPath
+		mux.Handle("GET "+uploads+"/", http.StripPrefix(uploads, h))
+	}
+
+	mux.Handle("POST /auth/bootstrap", authHandler.Bootstrap())
+	mux.Handle("POST /auth/register", authorize(authHandler.Register(), auth.PermUserManage))
 	mux.Handle("POST /auth/login", authHandler.Login())
 	mux.Handle("GET /auth/", authHandler.GetAuth())
+	mux.Handle("GET /auth/csrf", authHandler.CSRFToken())
+	mux.Handle("PUT /auth/pin", authorize(authHandler.SetOwnPIN()))
+	mux.Handle("GET /auth/pin/users", authHandler.GetPINUsers())
+	mux.Handle("POST /auth/pin/login", authHandler.PINLogin())
+
+	mux.Handle("GET /devices", authorize(authHandler.GetDevices(), auth.PermDeviceManage))
+	mux.Handle("POST /devices", authorize(authHandler.RegisterDevice(), auth.PermDeviceManage))
+	mux.Handle("DELETE /devices/{id}", authorize(authHandler.RevokeDevice(), auth.PermDeviceManage))
+
+	mux.Handle("GET /users", authorize(authHandler.GetUsers(), auth.PermUserManage))
+	mux.Handle("GET /users/{id}", authorize(authHandler.GetUserByID(), auth.PermUserManage))
+	mux.Handle("PUT /users/{id}", authorize(authHandler.UpdateUser(), auth.PermUserManage))
+	mux.Handle("POST /users/{id}/activate", authorize(authHandler.SetUserActive(true), auth.PermUserManage))
+	mux.Handle("POST /users/{id}/deactivate", authorize(authHandler.SetUserActive(false), auth.PermUserManage))
+	mux.Handle("PUT /users/{id}/password", authorize(authHandler.ResetPassword(), auth.PermUserManage))
+	mux.Handle("PUT /users/{id}/pin", authorize(authHandler.SetUserPIN(), auth.PermUserManage))
+	mux.Handle("POST /users/{id}/unlock", authorize(authHandler.UnlockUser(), auth.PermUserManage))
+
+	mux.Handle("GET /api-keys", authorize(authHandler.GetAPIKeys(), auth.PermAPIKeyManage))
+	mux.Handle("POST /api-keys", authorize(authHandler.CreateAPIKey(), auth.PermAPIKeyManage))
+	mux.Handle("DELETE /api-keys/{id}", authorize(authHandler.RevokeAPIKey(), auth.PermAPIKeyManage))
+
+	mux.Handle("GET /permissions", authorize(authHandler.GetPermissions(), auth.PermRoleManage))
+	mux.Handle("GET /roles", authorize(authHandler.GetRoles(), auth.PermRoleManage))
+	mux.Handle("POST /roles", authorize(authHandler.CreateRole(), auth.PermRoleManage))
+	mux.Handle("PUT /roles/{id}", authorize(authHandler.UpdateRole(), auth.PermRoleManage))
+	mux.Handle("DELETE /roles/{id}", authorize(authHandler.DeleteRole(), auth.PermRoleManage))
+
+	mux.Handle("GET /menu", authorize(menuHandler.GetAllItems(), auth.PermMenuRead))
+	mux.Handle("GET /menu/{id}", authorize(menuHandler.GetItemById(), auth.PermMenuRead))
+	mux.Handle("POST /menu", authorize(menuHandler.CreateItem(), auth.PermMenuWrite))
+	mux.Handle("POST /menu/{id}/image", authorize(menuHandler.UploadItemImage(), auth.PermMenuWrite))
+	mux.Handle("GET /menu/{id}/translations", authorize(menuHandler.GetItemTranslations(), auth.PermMenuRead))
+	mux.Handle("PUT /menu/{id}/translations/{lang}", authorize(menuHandler.SetItemTranslation(), auth.PermMenuWrite))
+	mux.Handle("DELETE /menu/{id}/translations/{lang}", authorize(menuHandler.DeleteItemTranslation(), auth.PermMenuWrite))
+	mux.Handle("PUT /menu/{id}/tags", authorize(menuHandler.UpdateItemTags(), auth.PermMenuWrite))
+	mux.Handle("GET /menu/{id}/costs", authorize(menuHandler.GetItemCosts(), auth.PermReportView))
+	mux.Handle("POST /menu/{id}/costs", authorize(menuHandler.SetItemCost(), auth.PermMenuWrite))
+	mux.Handle("GET /menus", authorize(menuHandler.GetMenus(), auth.PermMenuRead))
+	mux.Handle("POST /menus", authorize(menuHandler.CreateMenu(), auth.PermMenuWrite))
+	mux.Handle("GET /menus/{id}", authorize(menuHandler.GetMenuByID(), auth.PermMenuRead))
+	mux.Handle("PUT /menus/{id}", authorize(menuHandler.UpdateMenu(), auth.PermMenuWrite))
+	mux.Handle("DELETE /menus/{id}", authorize(menuHandler.DeleteMenu(), auth.PermMenuWrite))
+	mux.Handle("POST /menus/{id}/schedules", authorize(menuHandler.CreateSchedule(), auth.PermMenuWrite))
+	mux.Handle("DELETE /menus/{id}/schedules/{schedule_id}", authorize(menuHandler.DeleteSchedule(), auth.PermMenuWrite))
+	mux.Handle("GET /combos", authorize(menuHandler.GetCombos(), auth.PermMenuRead))
+	mux.Handle("POST /combos", authorize(menuHandler.CreateCombo(), auth.PermMenuWrite))
+	mux.Handle("GET /combos/{id}", authorize(menuHandler.GetComboByID(), auth.PermMenuRead))
+	mux.Handle("PUT /combos/{id}", authorize(menuHandler.UpdateCombo(), auth.PermMenuWrite))
+
+	mux.Handle("GET /inventory/ingredients", authorize(inventoryHandler.GetIngredients(), auth.PermInventoryManage))
+	mux.Handle("POST /inventory/ingredients", authorize(inventoryHandler.CreateIngredient(), auth.PermInventoryManage))
+	mux.Handle("GET /inventory/ingredients/{id}", authorize(inventoryHandler.GetIngredientByID(), auth.PermInventoryManage))
+	mux.Handle("PUT /inventory/ingredients/{id}", authorize(inventoryHandler.UpdateIngredient(), auth.PermInventoryManage))
+	mux.Handle("POST /inventory/ingredients/{id}/stock", authorize(inventoryHandler.AdjustStock(), auth.PermInventoryManage))
+	mux.Handle("GET /inventory/ingredients/{id}/movements", authorize(inventoryHandler.GetStockMovements(), auth.PermInventoryManage))
+	mux.Handle("GET /inventory/alerts", authorize(inventoryHandler.GetLowStockAlerts(), auth.PermInventoryManage))
+	mux.Handle("GET /inventory/recipes/{id}", authorize(inventoryHandler.GetRecipe(), auth.PermInventoryManage))
+	mux.Handle("PUT /inventory/recipes/{id}", authorize(inventoryHandler.SetRecipe(), auth.PermInventoryManage))
+
+	mux.Handle("GET /suppliers", authorize(purchasingHandler.GetSuppliers(), auth.PermPurchaseManage))
+	mux.Handle("POST /suppliers", authorize(purchasingHandler.CreateSupplier(), auth.PermPurchaseManage))
+	mux.Handle("GET /suppliers/{id}", authorize(purchasingHandler.GetSupplierByID(), auth.PermPurchaseManage))
+	mux.Handle("PUT /suppliers/{id}", authorize(purchasingHandler.UpdateSupplier(), auth.PermPurchaseManage))
+	mux.Handle("GET /purchase-orders", authorize(purchasingHandler.GetPurchaseOrders(), auth.PermPurchaseManage))
+	mux.Handle("POST /purchase-orders", authorize(purchasingHandler.CreatePurchaseOrder(), auth.PermPurchaseManage))
+	mux.Handle("GET /purchase-orders/suggestions", authorize(purchasingHandler.GetReorderSuggestions(), auth.PermPurchaseManage))
+	mux.Handle("GET /purchase-orders/{id}", authorize(purchasingHandler.GetPurchaseOrderByID(), auth.PermPurchaseManage))
+	mux.Handle("POST /purchase-orders/{id}/order", authorize(purchasingHandler.MarkOrdered(), auth.PermPurchaseManage))
+	mux.Handle("POST /purchase-orders/{id}/cancel", authorize(purchasingHandler.Cancel(), auth.PermPurchaseManage))
+	mux.Handle("POST /purchase-orders/{id}/receive", authorize(purchasingHandler.Receive(), auth.PermPurchaseManage))
+
+	mux.Handle("GET /customers", authorize(customerHandler.GetCustomers(), auth.PermCustomerManage))
+	mux.Handle("POST /customers", authorize(customerHandler.CreateCustomer(), auth.PermCustomerManage))
+	mux.Handle("GET /customers/lookup", authorize(customerHandler.LookupByPhone(), auth.PermOrderCreate))
+	mux.Handle("GET /customers/{id}", authorize(customerHandler.GetCustomerByID(), auth.PermCustomerManage))
+	mux.Handle("PUT /customers/{id}", authorize(customerHandler.UpdateCustomer(), auth.PermCustomerManage))
+	mux.Handle("GET /customers/{id}/orders", authorize(customerHandler.GetCustomerOrders(), auth.PermCustomerManage))
+	mux.Handle("GET /customers/{id}/loyalty", authorize(loyaltyHandler.GetAccount(), auth.PermLoyaltyRedeem))
+	mux.Handle("GET /customers/{id}/loyalty/transactions", authorize(loyaltyHandler.GetTransactions(), auth.PermLoyaltyRedeem))
+	mux.Handle("POST /customers/{id}/loyalty/adjust", authorize(loyaltyHandler.AdjustPoints(), auth.PermLoyaltyManage))
+
+	mux.Handle("GET /loyalty/tiers", authorize(loyaltyHandler.GetTiers(), auth.PermLoyaltyRedeem))
+	mux.Handle("POST /loyalty/tiers", authorize(loyaltyHandler.CreateTier(), auth.PermLoyaltyManage))
+	mux.Handle("PUT /loyalty/tiers/{id}", authorize(loyaltyHandler.UpdateTier(), auth.PermLoyaltyManage))
+	mux.Handle("DELETE /loyalty/tiers/{id}", authorize(loyaltyHandler.DeleteTier(), auth.PermLoyaltyManage))
+
+	mux.Handle("GET /gift-cards", authorize(giftCardHandler.GetCards(), auth.PermGiftCardManage))
+	mux.Handle("POST /gift-cards", authorize(giftCardHandler.IssueCard(), auth.PermGiftCardManage))
+	mux.Handle("GET /gift-cards/{code}", authorize(giftCardHandler.GetCard(), auth.PermGiftCardManage))
+	mux.Handle("GET /gift-cards/{code}/transactions", authorize(giftCardHandler.GetTransactions(), auth.PermGiftCardManage))
+	mux.Handle("POST /gift-cards/{code}/reload", authorize(giftCardHandler.ReloadCard(), auth.PermGiftCardManage))
+	mux.Handle("POST /gift-cards/{code}/redeem", authorize(giftCardHandler.RedeemCard(), auth.PermCashManage))
+
+	mux.Handle("GET /promotions", authorize(promotionHandler.GetPromotions(), auth.PermPromotionManage))
+	mux.Handle("POST /promotions", authorize(promotionHandler.CreatePromotion(), auth.PermPromotionManage))
+	mux.Handle("GET /promotions/{id}", authorize(promotionHandler.GetPromotion(), auth.PermPromotionManage))
+	mux.Handle("PUT /promotions/{id}", authorize(promotionHandler.UpdatePromotion(), auth.PermPromotionManage))
+
+	mux.Handle("GET /dining/table", authorize(diningHandler.GetTables(), auth.PermTableRead))
+	mux.Handle("POST /dining", authorize(diningHandler.CreateOrder(), auth.PermOrderCreate))
+	mux.Handle("POST /dining/takeaway", authorize(diningHandler.CreateTakeawayOrder(), auth.PermOrderCreate))
+	mux.Handle("POST /dining/delivery", authorize(diningHandler.CreateDeliveryOrder(), auth.PermOrderCreate))
+	mux.Handle("GET /dining", authorize(diningHandler.GetActiveOrders(), auth.PermOrderRead))
+	mux.Handle("POST /dining/{id}/item", authorize(diningHandler.AddOrderItem(), auth.PermOrderCreate))
+	mux.Handle("PUT /dining/{id}/allergies", authorize(diningHandler.SetOrderAllergies(), auth.PermOrderCreate))
+	mux.Handle("PUT /dining/{id}/customer", authorize(diningHandler.SetOrderCustomer(), auth.PermOrderCreate))
+	mux.Handle("GET /dining/{id}/bill", authorize(diningHandler.GetBill(), auth.PermOrderRead))
+	mux.Handle("POST /dining/{id}/loyalty", authorize(loyaltyHandler.RedeemPoints(), auth.PermLoyaltyRedeem))
+	mux.Handle("GET /dining/{id}/promotions", authorize(promotionHandler.EvaluateOrder(), auth.PermOrderRead))
+	mux.Handle("POST /dining/{id}/promotions", authorize(promotionHandler.ApplyPromotion(), auth.PermOrderCreate))
+	mux.Handle("POST /dining/{id}/complete", authorize(diningHandler.CompleteOrder(), auth.PermOrderCreate))
+	mux.Handle("PATCH /dining/{order_id}/{item_id}", authorize(diningHandler.UpdateOrderItem(), auth.PermOrderItemUpdate))
+
+	mux.Handle("GET /orders", authorize(diningHandler.SearchOrders(), auth.PermReportView))
+
+	mux.Handle("GET /shifts/me", authorize(shiftHandler.GetStatus()))
+	mux.Handle("POST /shifts/clock-in", authorize(shiftHandler.ClockIn()))
+	mux.Handle("POST /shifts/clock-out", authorize(shiftHandler.ClockOut()))
+	mux.Handle("POST /shifts/break/start", authorize(shiftHandler.StartBreak()))
+	mux.Handle("POST /shifts/break/end", authorize(shiftHandler.EndBreak()))
+	mux.Handle("GET /shifts/schedule", authorize(shiftHandler.GetScheduledShifts()))
+	mux.Handle("POST /shifts/schedule", authorize(shiftHandler.CreateScheduledShift(), auth.PermShiftManage))
+	mux.Handle("DELETE /shifts/schedule/{id}", authorize(shiftHandler.DeleteScheduledShift(), auth.PermShiftManage))
+	mux.Handle("GET /shifts/timesheet", authorize(shiftHandler.GetTimesheet()))
 
-	mux.Handle("GET /menu", auth.Middleware(menuHandler.GetAllItems(), sqlc.UserTypeWaiter, sqlc.UserTypeKitchen))
-	mux.Handle("GET /menu/{id}", auth.Middleware(menuHandler.GetItemById(), sqlc.UserTypeWaiter, sqlc.UserTypeKitchen))
-	mux.Handle("POST /menu", auth.Middleware(menuHandler.CreateItem()))
+	mux.Handle("GET /drawers", authorize(cashHandler.GetSessions(), auth.PermCashManage))
+	mux.Handle("POST /drawers", authorize(cashHandler.OpenSession(), auth.PermCashManage))
+	mux.Handle("GET /drawers/{id}", authorize(cashHandler.GetSession(), auth.PermCashManage))
+	mux.Handle("POST /drawers/{id}/movements", authorize(cashHandler.AddMovement(), auth.PermCashManage))
+	mux.Handle("POST /drawers/{id}/tenders", authorize(cashHandler.AddTender(), auth.PermCashManage))
+	mux.Handle("POST /drawers/{id}/close", authorize(cashHandler.CloseSession(), auth.PermCashManage))
 
-	mux.Handle("GET /dining/table", auth.Middleware(diningHandler.GetTables(), sqlc.UserTypeWaiter))
-	mux.Handle("POST /dining", auth.Middleware(diningHandler.CreateOrder(), sqlc.UserTypeWaiter))
-	mux.Handle("GET /dining", auth.Middleware(diningHandler.GetActiveOrders(), sqlc.UserTypeWaiter, sqlc.UserTypeKitchen))
-	mux.Handle("POST /dining/{id}/item", auth.Middleware(diningHandler.AddOrderItem(), sqlc.UserTypeWaiter))
-	mux.Handle("PATCH /dining/{order_id}/{item_id}", auth.Middleware(diningHandler.UpdateOrderItem(), sqlc.UserTypeWaiter, sqlc.UserTypeKitchen))
+	mux.Handle("GET /reports/sales", authorize(reportHandler.GetSalesReport(), auth.PermReportView))
+	mux.Handle("GET /reports/menu-engineering", authorize(reportHandler.GetMenuEngineeringReport(), auth.PermReportView))
+	mux.Handle("GET /reports/z", authorize(cashHandler.GetZReports(), auth.PermReportView))
+	mux.Handle("GET /reports/z/current", authorize(cashHandler.PreviewZReport(), auth.PermReportView))
+	mux.Handle("GET /reports/z/{id}", authorize(cashHandler.GetZReportByID(), auth.PermReportView))
+	mux.Handle("POST /reports/z", authorize(cashHandler.CloseZReport(), auth.PermCashManage))
 
 	mux.Handle("/", http.NotFoundHandler())
 
 	handler := cors.New(cors.Options{
 		AllowedOrigins:   []string{config.Server().FrontendOrigin},
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE"},
-		AllowedHeaders:   []string{"Content-Type"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE"},
+		AllowedHeaders:   []string{"Content-Type", "Authorization", auth.CSRFHeader, auth.DeviceTokenHeader},
 		AllowCredentials: true,
 	}).Handler(mux)
 
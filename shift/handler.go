@@ -0,0 +1,359 @@
+package shift
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/pdridh/k-line/api"
+	"github.com/pdridh/k-line/auth"
+	"github.com/pdridh/k-line/db/sqlc"
+	"github.com/pkg/errors"
+)
+
+type handler struct {
+	Service *service
+}
+
+func NewHandler(s *service) *handler {
+	return &handler{
+		Service: s,
+	}
+}
+
+// Resolves whose schedule or timesheet is being asked for.
+// Without shift.manage users can only see their own, so userID has to be empty or themselves.
+func targetUser(r *http.Request, userID string) (pgtype.UUID, bool) {
+	var id pgtype.UUID
+	self := api.CurrentUserID(r)
+
+	if userID == "" || userID == self {
+		return id, id.Scan(self) == nil
+	}
+
+	if !api.CurrentUserCan(r, string(auth.PermShiftManage)) {
+		return id, false
+	}
+
+	return id, id.Scan(userID) == nil
+}
+
+func (h *handler) GetStatus() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var userID pgtype.UUID
+		if err := userID.Scan(api.CurrentUserID(r)); err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		s, err := h.Service.GetStatus(r.Context(), userID)
+		if err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Retrieval successful", s)
+	}
+}
+
+func (h *handler) ClockIn() http.HandlerFunc {
+	type RequestPayload struct {
+		Notes pgtype.Text `json:"notes"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var userID pgtype.UUID
+		if err := userID.Scan(api.CurrentUserID(r)); err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		// The body is optional, its only used for notes
+		var p RequestPayload
+		if r.ContentLength > 0 {
+			if err := api.ParseJSON(r, &p); err != nil {
+				api.WriteBadRequestError(w, r)
+				return
+			}
+		}
+
+		e, err := h.Service.ClockIn(r.Context(), userID, p.Notes)
+		if err != nil {
+			if errors.Is(err, api.ErrAlreadyClockedIn.Error) {
+				api.WriteError(w, r, http.StatusConflict, api.ErrAlreadyClockedIn, nil)
+				return
+			}
+
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusCreated, "Successfully clocked in", e)
+	}
+}
+
+func (h *handler) ClockOut() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var userID pgtype.UUID
+		if err := userID.Scan(api.CurrentUserID(r)); err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		e, err := h.Service.ClockOut(r.Context(), userID)
+		if err != nil {
+			if errors.Is(err, api.ErrNotClockedIn.Error) {
+				api.WriteError(w, r, http.StatusConflict, api.ErrNotClockedIn, nil)
+				return
+			}
+
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Successfully clocked out", e)
+	}
+}
+
+func (h *handler) StartBreak() http.HandlerFunc {
+	type RequestPayload struct {
+		Paid bool `json:"paid"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var userID pgtype.UUID
+		if err := userID.Scan(api.CurrentUserID(r)); err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		var p RequestPayload
+		if r.ContentLength > 0 {
+			if err := api.ParseJSON(r, &p); err != nil {
+				api.WriteBadRequestError(w, r)
+				return
+			}
+		}
+
+		b, err := h.Service.StartBreak(r.Context(), userID, p.Paid)
+		if err != nil {
+			switch {
+			case errors.Is(err, api.ErrNotClockedIn.Error):
+				api.WriteError(w, r, http.StatusConflict, api.ErrNotClockedIn, nil)
+				return
+			case errors.Is(err, api.ErrAlreadyOnBreak.Error):
+				api.WriteError(w, r, http.StatusConflict, api.ErrAlreadyOnBreak, nil)
+				return
+			default:
+				api.WriteInternalError(w, r)
+				return
+			}
+		}
+
+		api.WriteSuccess(w, r, http.StatusCreated, "Break started", b)
+	}
+}
+
+func (h *handler) EndBreak() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var userID pgtype.UUID
+		if err := userID.Scan(api.CurrentUserID(r)); err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		b, err := h.Service.EndBreak(r.Context(), userID)
+		if err != nil {
+			switch {
+			case errors.Is(err, api.ErrNotClockedIn.Error):
+				api.WriteError(w, r, http.StatusConflict, api.ErrNotClockedIn, nil)
+				return
+			case errors.Is(err, api.ErrNotOnBreak.Error):
+				api.WriteError(w, r, http.StatusConflict, api.ErrNotOnBreak, nil)
+				return
+			default:
+				api.WriteInternalError(w, r)
+				return
+			}
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Break ended", b)
+	}
+}
+
+func (h *handler) CreateScheduledShift() http.HandlerFunc {
+	type RequestPayload struct {
+		UserID   string      `json:"user_id" validate:"required,uuid"`
+		StartsAt time.Time   `json:"starts_at" validate:"required"`
+		EndsAt   time.Time   `json:"ends_at" validate:"required"`
+		Notes    pgtype.Text `json:"notes"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var adminID pgtype.UUID
+		if err := adminID.Scan(api.CurrentUserID(r)); err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		var p RequestPayload
+		if err := api.ParseJSON(r, &p); err != nil {
+			api.WriteBadRequestError(w, r)
+			return
+		}
+
+		if err := h.Service.Validate.Struct(p); err != nil {
+			api.WriteValidationError(w, r, err)
+			return
+		}
+
+		var userID pgtype.UUID
+		if err := userID.Scan(p.UserID); err != nil {
+			api.WriteBadRequestError(w, r)
+			return
+		}
+
+		arg := sqlc.CreateScheduledShiftParams{
+			UserID:    userID,
+			StartsAt:  pgtype.Timestamp{Time: p.StartsAt.UTC(), Valid: true},
+			EndsAt:    pgtype.Timestamp{Time: p.EndsAt.UTC(), Valid: true},
+			Notes:     p.Notes,
+			CreatedBy: adminID,
+		}
+
+		sh, err := h.Service.CreateScheduledShift(r.Context(), arg)
+		if err != nil {
+			switch {
+			case errors.Is(err, api.ErrInvalidPeriod.Error):
+				api.WriteError(w, r, http.StatusBadRequest, api.ErrInvalidPeriod, nil)
+				return
+			case errors.Is(err, api.ErrUnknownUser.Error):
+				api.WriteError(w, r, http.StatusBadRequest, api.ErrUnknownUser, nil)
+				return
+			default:
+				api.WriteInternalError(w, r)
+				return
+			}
+		}
+
+		api.WriteSuccess(w, r, http.StatusCreated, "Successfully scheduled shift", sh)
+	}
+}
+
+// Lists the scheduled shifts in the period, everyone's when user_id is left out by someone with shift.manage
+func (h *handler) GetScheduledShifts() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var f PeriodFilters
+		api.ParseQueryParams(r.URL.Query(), &f)
+
+		start, end, err := f.Period(time.Now())
+		if err != nil {
+			api.WriteError(w, r, http.StatusBadRequest, api.ErrInvalidPeriod, nil)
+			return
+		}
+
+		var userID pgtype.UUID
+		if f.UserID != "" || !api.CurrentUserCan(r, string(auth.PermShiftManage)) {
+			var ok bool
+			if userID, ok = targetUser(r, f.UserID); !ok {
+				api.WriteForbiddenError(w, r)
+				return
+			}
+		}
+
+		shifts, err := h.Service.GetScheduledShifts(r.Context(), userID, start, end)
+		if err != nil {
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Retrieval successful", shifts)
+	}
+}
+
+func (h *handler) DeleteScheduledShift() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			api.WriteNotFoundError(w, r)
+			return
+		}
+
+		if err := h.Service.DeleteScheduledShift(r.Context(), int64(id)); err != nil {
+			if errors.Is(err, api.ErrUnknownShift.Error) {
+				api.WriteNotFoundError(w, r)
+				return
+			}
+
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Successfully deleted shift", nil)
+	}
+}
+
+// Returns the timesheet for a pay period (from and to query params), as csv if format=csv
+func (h *handler) GetTimesheet() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var f PeriodFilters
+		api.ParseQueryParams(r.URL.Query(), &f)
+
+		userID, ok := targetUser(r, f.UserID)
+		if !ok {
+			api.WriteForbiddenError(w, r)
+			return
+		}
+
+		start, end, err := f.Period(time.Now())
+		if err != nil {
+			api.WriteError(w, r, http.StatusBadRequest, api.ErrInvalidPeriod, nil)
+			return
+		}
+
+		t, err := h.Service.GetTimesheet(r.Context(), userID, start, end)
+		if err != nil {
+			if errors.Is(err, api.ErrUnknownUser.Error) {
+				api.WriteNotFoundError(w, r)
+				return
+			}
+
+			api.WriteInternalError(w, r)
+			return
+		}
+
+		if f.Format == "csv" {
+			writeTimesheetCSV(w, r, t)
+			return
+		}
+
+		api.WriteSuccess(w, r, http.StatusOK, "Retrieval successful", t)
+	}
+}
+
+func writeTimesheetCSV(w http.ResponseWriter, r *http.Request, t *Timesheet) {
+	const layout = "2006-01-02 15:04:05"
+
+	header := []string{"entry_id", "clock_in", "clock_out", "break_hours", "worked_hours"}
+	rows := make([][]string, 0, len(t.Entries)+1)
+	for _, e := range t.Entries {
+		clockOut := ""
+		if e.ClockOut.Valid {
+			clockOut = e.ClockOut.Time.Format(layout)
+		}
+
+		rows = append(rows, []string{
+			strconv.FormatInt(e.ID, 10),
+			e.ClockIn.Time.Format(layout),
+			clockOut,
+			strconv.FormatFloat(e.BreakHours, 'f', 2, 64),
+			strconv.FormatFloat(e.WorkedHours, 'f', 2, 64),
+		})
+	}
+	rows = append(rows, []string{"total", "", "", "", strconv.FormatFloat(t.TotalHours, 'f', 2, 64)})
+
+	name := fmt.Sprintf("timesheet_%s_%s_%s.csv", t.UserID.String(), t.PeriodStart, t.PeriodEnd)
+	api.WriteCSV(w, r, name, header, rows)
+}
@@ -0,0 +1,269 @@
+package shift
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/pdridh/k-line/api"
+	"github.com/pdridh/k-line/config"
+	"github.com/pdridh/k-line/db"
+	"github.com/pdridh/k-line/db/sqlc"
+	"github.com/pkg/errors"
+)
+
+type service struct {
+	Validate *validator.Validate
+	store    db.Store
+}
+
+func NewService(v *validator.Validate, s db.Store) *service {
+	return &service{
+		Validate: v,
+		store:    s,
+	}
+}
+
+func (s *service) ClockIn(ctx context.Context, userID pgtype.UUID, notes pgtype.Text) (*TimeEntry, error) {
+	e, err := s.store.ClockIn(ctx, sqlc.ClockInParams{UserID: userID, Notes: notes})
+	if err != nil {
+		// Only one open time entry is allowed per user
+		if db.GetSQLErrorCode(err) == db.UniqueViolation {
+			return nil, errors.Wrap(api.ErrAlreadyClockedIn.Error, "store")
+		}
+		return nil, errors.Wrap(err, "store")
+	}
+
+	return newTimeEntry(e), nil
+}
+
+// Clocks the user out, a break that is still going is ended along with the shift
+func (s *service) ClockOut(ctx context.Context, userID pgtype.UUID) (*TimeEntry, error) {
+	e, err := s.store.ClockOutTx(ctx, userID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, errors.Wrap(api.ErrNotClockedIn.Error, "store")
+		}
+		return nil, errors.Wrap(err, "store")
+	}
+
+	return newTimeEntry(e), nil
+}
+
+func (s *service) StartBreak(ctx context.Context, userID pgtype.UUID, paid bool) (*Break, error) {
+	e, err := s.openEntry(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := s.store.StartBreak(ctx, sqlc.StartBreakParams{TimeEntryID: e.ID, Paid: paid})
+	if err != nil {
+		if db.GetSQLErrorCode(err) == db.UniqueViolation {
+			return nil, errors.Wrap(api.ErrAlreadyOnBreak.Error, "store")
+		}
+		return nil, errors.Wrap(err, "store")
+	}
+
+	return newBreak(b), nil
+}
+
+func (s *service) EndBreak(ctx context.Context, userID pgtype.UUID) (*Break, error) {
+	e, err := s.openEntry(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := s.store.EndBreak(ctx, e.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, errors.Wrap(api.ErrNotOnBreak.Error, "store")
+		}
+		return nil, errors.Wrap(err, "store")
+	}
+
+	return newBreak(b), nil
+}
+
+func (s *service) GetStatus(ctx context.Context, userID pgtype.UUID) (*Status, error) {
+	e, err := s.openEntry(ctx, userID)
+	if err != nil {
+		if errors.Is(err, api.ErrNotClockedIn.Error) {
+			return &Status{}, nil
+		}
+		return nil, err
+	}
+
+	status := &Status{ClockedIn: true, Entry: newTimeEntry(e)}
+
+	b, err := s.store.GetOpenBreak(ctx, e.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return status, nil
+		}
+		return nil, errors.Wrap(err, "store")
+	}
+
+	status.OnBreak = true
+	status.Break = newBreak(b)
+
+	return status, nil
+}
+
+// Returns ErrNotClockedIn when the server requires waiters to be clocked in to take orders and the
+// user is a waiter without an open time entry
+func (s *service) CheckClockedIn(ctx context.Context, userID pgtype.UUID, userType sqlc.UserType) error {
+	if !config.Server().RequireClockIn || userType != sqlc.UserTypeWaiter {
+		return nil
+	}
+
+	_, err := s.openEntry(ctx, userID)
+	return err
+}
+
+func (s *service) openEntry(ctx context.Context, userID pgtype.UUID) (sqlc.TimeEntry, error) {
+	e, err := s.store.GetOpenTimeEntry(ctx, userID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return e, errors.Wrap(api.ErrNotClockedIn.Error, "store")
+		}
+		return e, errors.Wrap(err, "store")
+	}
+
+	return e, nil
+}
+
+func (s *service) CreateScheduledShift(ctx context.Context, arg sqlc.CreateScheduledShiftParams) (*ScheduledShift, error) {
+	if !arg.StartsAt.Time.Before(arg.EndsAt.Time) {
+		return nil, errors.Wrap(api.ErrInvalidPeriod.Error, "period")
+	}
+
+	sh, err := s.store.CreateScheduledShift(ctx, arg)
+	if err != nil {
+		if db.GetSQLErrorCode(err) == db.ForeignKeyViolation {
+			return nil, errors.Wrap(api.ErrUnknownUser.Error, "store")
+		}
+		return nil, errors.Wrap(err, "store")
+	}
+
+	return newScheduledShift(sh), nil
+}
+
+// Returns the shifts overlapping [start, end), only the given user's shifts if userID is valid
+func (s *service) GetScheduledShifts(ctx context.Context, userID pgtype.UUID, start time.Time, end time.Time) ([]ScheduledShift, error) {
+	arg := sqlc.GetScheduledShiftsParams{
+		RangeStart: pgtype.Timestamp{Time: start, Valid: true},
+		RangeEnd:   pgtype.Timestamp{Time: end, Valid: true},
+		UserID:     userID,
+	}
+
+	rows, err := s.store.GetScheduledShifts(ctx, arg)
+	if err != nil {
+		return []ScheduledShift{}, errors.Wrap(err, "store")
+	}
+
+	shifts := make([]ScheduledShift, 0, len(rows))
+	for _, sh := range rows {
+		shifts = append(shifts, *newScheduledShift(sh))
+	}
+
+	return shifts, nil
+}
+
+func (s *service) DeleteScheduledShift(ctx context.Context, id int64) error {
+	n, err := s.store.DeleteScheduledShift(ctx, id)
+	if err != nil {
+		return errors.Wrap(err, "store")
+	}
+
+	if n == 0 {
+		return errors.Wrap(api.ErrUnknownShift.Error, "store")
+	}
+
+	return nil
+}
+
+// Builds the timesheet of the user for the entries clocked in during [start, end).
+// Entries that are still open are counted up to now.
+func (s *service) GetTimesheet(ctx context.Context, userID pgtype.UUID, start time.Time, end time.Time) (*Timesheet, error) {
+	if _, err := s.store.GetUserByID(ctx, userID); err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, errors.Wrap(api.ErrUnknownUser.Error, "store")
+		}
+		return nil, errors.Wrap(err, "store")
+	}
+
+	arg := sqlc.GetTimesheetParams{
+		UserID:      userID,
+		PeriodStart: pgtype.Timestamp{Time: start, Valid: true},
+		PeriodEnd:   pgtype.Timestamp{Time: end, Valid: true},
+	}
+
+	rows, err := s.store.GetTimesheet(ctx, arg)
+	if err != nil {
+		return nil, errors.Wrap(err, "store")
+	}
+
+	t := &Timesheet{
+		UserID:      userID,
+		PeriodStart: start.Format(DateLayout),
+		PeriodEnd:   end.AddDate(0, 0, -1).Format(DateLayout),
+		Entries:     make([]TimesheetEntry, 0, len(rows)),
+	}
+
+	var totalSeconds float64
+	for _, row := range rows {
+		worked := max(row.TotalSeconds-row.UnpaidBreakSeconds, 0)
+		totalSeconds += worked
+
+		t.Entries = append(t.Entries, TimesheetEntry{
+			ID:          row.ID,
+			ClockIn:     row.ClockIn,
+			ClockOut:    row.ClockOut,
+			BreakHours:  hours(row.UnpaidBreakSeconds),
+			WorkedHours: hours(worked),
+		})
+	}
+
+	t.TotalHours = hours(totalSeconds)
+
+	return t, nil
+}
+
+// Converts seconds into hours rounded to 2 decimal places
+func hours(seconds float64) float64 {
+	return math.Round(seconds/36) / 100
+}
+
+func newTimeEntry(e sqlc.TimeEntry) *TimeEntry {
+	return &TimeEntry{
+		ID:       e.ID,
+		UserID:   e.UserID,
+		ClockIn:  e.ClockIn,
+		ClockOut: e.ClockOut,
+		Notes:    e.Notes,
+	}
+}
+
+func newBreak(b sqlc.Break) *Break {
+	return &Break{
+		ID:          b.ID,
+		TimeEntryID: b.TimeEntryID,
+		Paid:        b.Paid,
+		StartedAt:   b.StartedAt,
+		EndedAt:     b.EndedAt,
+	}
+}
+
+func newScheduledShift(sh sqlc.ScheduledShift) *ScheduledShift {
+	return &ScheduledShift{
+		ID:        sh.ID,
+		UserID:    sh.UserID,
+		StartsAt:  sh.StartsAt,
+		EndsAt:    sh.EndsAt,
+		Notes:     sh.Notes,
+		CreatedBy: sh.CreatedBy,
+		CreatedAt: sh.CreatedAt,
+	}
+}
@@ -0,0 +1,67 @@
+package shift
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/pdridh/k-line/api"
+	"github.com/pdridh/k-line/config"
+	"github.com/pdridh/k-line/db"
+	"github.com/pdridh/k-line/db/sqlc"
+)
+
+// A store that only knows who is clocked in, anything else panics
+type fakeStore struct {
+	db.Store
+	clockedIn map[pgtype.UUID]bool
+}
+
+func (f *fakeStore) GetOpenTimeEntry(ctx context.Context, userID pgtype.UUID) (sqlc.TimeEntry, error) {
+	if !f.clockedIn[userID] {
+		return sqlc.TimeEntry{}, db.ErrRecordNotFound
+	}
+	return sqlc.TimeEntry{UserID: userID}, nil
+}
+
+func TestCheckClockedIn(t *testing.T) {
+	in := pgtype.UUID{Bytes: [16]byte{15: 1}, Valid: true}
+	out := pgtype.UUID{Bytes: [16]byte{15: 2}, Valid: true}
+
+	s := NewService(validator.New(), &fakeStore{clockedIn: map[pgtype.UUID]bool{in: true}})
+
+	tests := []struct {
+		name     string
+		require  string
+		userID   pgtype.UUID
+		userType sqlc.UserType
+		err      error
+	}{
+		{name: "not required", require: "false", userID: out, userType: sqlc.UserTypeWaiter},
+		{name: "waiter clocked in", require: "true", userID: in, userType: sqlc.UserTypeWaiter},
+		{name: "waiter not clocked in", require: "true", userID: out, userType: sqlc.UserTypeWaiter, err: api.ErrNotClockedIn.Error},
+		{name: "only waiters have to", require: "true", userID: out, userType: sqlc.UserTypeRegister},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("SERVER_ENV", "test")
+			t.Setenv("REQUIRE_CLOCK_IN", tt.require)
+			config.Load()
+
+			err := s.CheckClockedIn(context.Background(), tt.userID, tt.userType)
+			if tt.err == nil {
+				if err != nil {
+					t.Fatalf("unexpected error %v", err)
+				}
+				return
+			}
+
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("got error %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
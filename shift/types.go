@@ -0,0 +1,98 @@
+package shift
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/pdridh/k-line/api"
+	"github.com/pkg/errors"
+)
+
+// Dates in query params (from/to of a period) are sent as plain days
+const DateLayout = "2006-01-02"
+
+type TimeEntry struct {
+	ID       int64            `json:"id"`
+	UserID   pgtype.UUID      `json:"user_id"`
+	ClockIn  pgtype.Timestamp `json:"clock_in"`
+	ClockOut pgtype.Timestamp `json:"clock_out"`
+	Notes    pgtype.Text      `json:"notes"`
+}
+
+type Break struct {
+	ID          int64            `json:"id"`
+	TimeEntryID int64            `json:"time_entry_id"`
+	Paid        bool             `json:"paid"`
+	StartedAt   pgtype.Timestamp `json:"started_at"`
+	EndedAt     pgtype.Timestamp `json:"ended_at"`
+}
+
+// Where the user is right now, Entry is nil when they arent clocked in and Break is nil when they arent on one
+type Status struct {
+	ClockedIn bool       `json:"clocked_in"`
+	Entry     *TimeEntry `json:"entry"`
+	OnBreak   bool       `json:"on_break"`
+	Break     *Break     `json:"break"`
+}
+
+type ScheduledShift struct {
+	ID        int64            `json:"id"`
+	UserID    pgtype.UUID      `json:"user_id"`
+	StartsAt  pgtype.Timestamp `json:"starts_at"`
+	EndsAt    pgtype.Timestamp `json:"ends_at"`
+	Notes     pgtype.Text      `json:"notes"`
+	CreatedBy pgtype.UUID      `json:"created_by"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
+// Query params for the schedule and timesheet routes, From and To are inclusive days in DateLayout
+type PeriodFilters struct {
+	UserID string `json:"user_id"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Format string `json:"format"`
+}
+
+// Parses From and To into the half open range [start, end) so the whole To day is included.
+// If they are not given the period defaults to the last 14 days including today.
+func (f *PeriodFilters) Period(now time.Time) (time.Time, time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	start, end := today.AddDate(0, 0, -13), today
+
+	var err error
+	if f.From != "" {
+		if start, err = time.Parse(DateLayout, f.From); err != nil {
+			return time.Time{}, time.Time{}, errors.Wrap(api.ErrInvalidPeriod.Error, "parse")
+		}
+	}
+
+	if f.To != "" {
+		if end, err = time.Parse(DateLayout, f.To); err != nil {
+			return time.Time{}, time.Time{}, errors.Wrap(api.ErrInvalidPeriod.Error, "parse")
+		}
+	}
+
+	end = end.AddDate(0, 0, 1)
+	if !start.Before(end) {
+		return time.Time{}, time.Time{}, errors.Wrap(api.ErrInvalidPeriod.Error, "period")
+	}
+
+	return start, end, nil
+}
+
+// A single clock in/out with its break time taken out
+type TimesheetEntry struct {
+	ID          int64            `json:"id"`
+	ClockIn     pgtype.Timestamp `json:"clock_in"`
+	ClockOut    pgtype.Timestamp `json:"clock_out"`
+	BreakHours  float64          `json:"break_hours"`  // Unpaid break time only
+	WorkedHours float64          `json:"worked_hours"` // Time on the clock minus unpaid breaks
+}
+
+type Timesheet struct {
+	UserID      pgtype.UUID      `json:"user_id"`
+	PeriodStart string           `json:"period_start"`
+	PeriodEnd   string           `json:"period_end"`
+	Entries     []TimesheetEntry `json:"entries"`
+	TotalHours  float64          `json:"total_hours"`
+}